@@ -4,15 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/applemusic"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/cache"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/deezer"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/kkbox"
@@ -20,12 +23,21 @@ import (
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/musicbrainz"
 	redisGateway "github.com/t1nyb0x/tracktaste/internal/adapter/gateway/redis"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/spotify"
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/ytmusic"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/handler"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/server"
+	appconfig "github.com/t1nyb0x/tracktaste/internal/config"
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+	"github.com/t1nyb0x/tracktaste/internal/usecase/resolver"
 	usecasev1 "github.com/t1nyb0x/tracktaste/internal/usecase/v1"
 	usecasev2 "github.com/t1nyb0x/tracktaste/internal/usecase/v2"
+	"github.com/t1nyb0x/tracktaste/internal/util/health"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
 )
 
 // Build information (set via ldflags)
@@ -35,16 +47,6 @@ var (
 	gitCommit = "unknown"
 )
 
-type config struct {
-	httpAddr          string
-	spotifyID         string
-	spotifySecret     string
-	kkboxID           string
-	kkboxSecret       string
-	lastfmAPIKey      string
-	ytmusicSidecarURL string
-}
-
 // getProjectRoot はプロジェクトルートのパスを取得します。
 // このファイル（cmd/server/main.go）から2階層上がプロジェクトルートです。
 func getProjectRoot() string {
@@ -55,38 +57,171 @@ func getProjectRoot() string {
 	return filepath.Join(filepath.Dir(currentFile), "..", "..")
 }
 
-func loadConfig() (*config, error) {
+// loadConfig loads the configuration: defaults, then the YAML file named by
+// CONFIG_FILE, then environment variables. Variables missing from the process
+// environment are taken from .env, which is read again on every reload so that
+// edits to it take effect without a restart.
+func loadConfig() (*appconfig.Config, error) {
 	// プロジェクトルートの .env を読み込む
 	envPath := filepath.Join(getProjectRoot(), ".env")
-	if err := godotenv.Load(envPath); err != nil {
+	dotenv, err := godotenv.Read(envPath)
+	if err != nil {
 		log.Printf("Warning: Error loading .env file from %s", envPath)
 	}
+	lookupEnv := func(key string) (string, bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		v, ok := dotenv[key]
+		return v, ok
+	}
+	path, _ := lookupEnv("CONFIG_FILE")
+	return appconfig.LoadWithEnv(path, lookupEnv)
+}
 
-	cfg := &config{
-		httpAddr:          getEnv("HTTP_ADDR", ":8080"),
-		spotifyID:         os.Getenv("SPOTIFY_CLIENT_ID"),
-		spotifySecret:     os.Getenv("SPOTIFY_CLIENT_SECRET"),
-		kkboxID:           os.Getenv("KKBOX_ID"),
-		kkboxSecret:       os.Getenv("KKBOX_SECRET"),
-		lastfmAPIKey:      os.Getenv("LASTFM_API_KEY"),
-		ytmusicSidecarURL: os.Getenv("YTMUSIC_SIDECAR_URL"),
+// logConfig converts the configured log settings; they are validated by appconfig.Load.
+func logConfig(c appconfig.Log) logger.Config {
+	format, _ := logger.ParseFormat(c.Format)
+	level, _ := logger.ParseLevel(c.Level)
+	return logger.Config{Format: format, Level: level}
+}
+
+// recommendOptions converts the configured pipeline settings for the recommend use case.
+func recommendOptions(cfg *appconfig.Config) usecasev2.Options {
+	r := cfg.Recommend
+	weights := func(w appconfig.FeatureWeights) usecasev2.FeatureWeights {
+		return usecasev2.FeatureWeights{BPM: w.BPM, Duration: w.Duration, Gain: w.Gain, TagSimilarity: w.TagSimilarity}
+	}
+	return usecasev2.Options{
+		Timeout:               r.Timeout,
+		MaxResults:            r.MaxResults,
+		MaxCandidates:         r.MaxCandidates,
+		KKBOXCandidates:       r.CandidateLimits.KKBOX,
+		LastFMCandidates:      r.CandidateLimits.LastFM,
+		MusicBrainzCandidates: r.CandidateLimits.MusicBrainz,
+		YTMusicCandidates:     r.CandidateLimits.YTMusic,
+		SpotifyConcurrency:    r.SpotifyConcurrency,
+		Weights: map[domain.RecommendMode]usecasev2.FeatureWeights{
+			domain.RecommendModeBalanced: weights(r.Weights.Balanced),
+			domain.RecommendModeSimilar:  weights(r.Weights.Similar),
+			domain.RecommendModeRelated:  weights(r.Weights.Related),
+		},
+		Budget: usecasev2.StageBudget{
+			Collect: r.StageBudget.Collect,
+			Enrich:  r.StageBudget.Enrich,
+			Filter:  r.StageBudget.Filter,
+			Score:   r.StageBudget.Score,
+		},
+		Staleness:       stalenessPolicy(cfg.Cache),
+		DisabledSources: disabledSources(r.Sources),
+		GenreGroups:     genreGroups(r.GenreGroups),
 	}
+}
 
-	if cfg.spotifyID == "" || cfg.spotifySecret == "" {
-		return nil, fmt.Errorf("SPOTIFY credentials not set")
+// disabledSources lists the candidate sources turned off in the configuration.
+func disabledSources(s appconfig.Sources) map[string]bool {
+	return map[string]bool{
+		domain.SourceKKBOX:        !s.KKBOX,
+		domain.SourceLastFM:       !s.LastFM,
+		domain.SourceMusicBrainz:  !s.MusicBrainz,
+		domain.SourceYouTubeMusic: !s.YouTubeMusic,
 	}
-	if cfg.kkboxID == "" || cfg.kkboxSecret == "" {
-		return nil, fmt.Errorf("KKBOX credentials not set")
+}
+
+// genreGroups converts the configured additional genres per group.
+func genreGroups(groups map[string][]string) map[usecase.GenreGroup][]string {
+	result := make(map[usecase.GenreGroup][]string, len(groups))
+	for group, genres := range groups {
+		result[usecase.GenreGroup(group)] = genres
 	}
+	return result
+}
 
-	return cfg, nil
+// similarOptions converts the configured /v1 similar track settings.
+func similarOptions(c appconfig.Similar) usecasev1.SimilarOptions {
+	return usecasev1.SimilarOptions{
+		Timeout:        c.Timeout,
+		RequestTimeout: c.RequestTimeout,
+		Concurrency:    c.Concurrency,
+		MaxResults:     c.MaxResults,
+	}
+}
+
+// stalenessPolicy converts the configured feature cache TTLs.
+func stalenessPolicy(c appconfig.Cache) usecasev2.StalenessPolicy {
+	return usecasev2.StalenessPolicy{
+		DeezerTTL:        c.DeezerTTL,
+		MusicBrainzTTL:   c.MusicBrainzTTL,
+		SpotifyGenresTTL: c.SpotifyGenresTTL,
+		NegativeTTL:      c.NegativeTTL,
+	}
+}
+
+// retryPolicy converts a configured retry policy for the gateways.
+func retryPolicy(p appconfig.RetryPolicy) transport.Policy {
+	return transport.Policy{
+		MaxRetries:    p.MaxRetries,
+		BaseDelay:     p.BaseDelay,
+		MaxDelay:      p.MaxDelay,
+		MaxRetryAfter: p.MaxRetryAfter,
+	}
+}
+
+// newBreaker creates the circuit breaker of one upstream and registers it for /healthz.
+func newBreaker(breakers map[string]handler.CircuitBreaker, name string, cb appconfig.CircuitBreaker) *transport.Breaker {
+	b := transport.NewBreaker(name, transport.BreakerConfig{
+		FailureThreshold: cb.FailureThreshold,
+		OpenTimeout:      cb.OpenTimeout,
+		HalfOpenRequests: cb.HalfOpenRequests,
+	})
+	breakers[name] = b
+	return b
 }
 
-func getEnv(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// rateLimitsByName maps scheduler names to their configured limits.
+func rateLimitsByName(limits appconfig.RateLimits) map[string]appconfig.RateLimit {
+	return map[string]appconfig.RateLimit{
+		"spotify":       limits.Spotify,
+		"kkbox":         limits.KKBOX,
+		"deezer":        limits.Deezer,
+		"musicbrainz":   limits.MusicBrainz,
+		"lastfm":        limits.LastFM,
+		"youtube_music": limits.YTMusic,
+		"apple_music":   limits.AppleMusic,
+	}
+}
+
+// newScheduler creates the process-wide scheduler of one upstream and registers it for reloads.
+// Distributed limits are shared through Redis when it is connected,
+// and fall back to the local token bucket otherwise.
+func newScheduler(schedulers map[string]*scheduler.Scheduler, name string, limit appconfig.RateLimit, redisEnabled bool) *scheduler.Scheduler {
+	cfg := scheduler.Config{Name: name, Rate: limit.Rate, Burst: limit.Burst}
+	var s *scheduler.Scheduler
+	if limit.Distributed && redisEnabled {
+		logger.Info("Main", fmt.Sprintf("%s rate limit: %.1f req/s (burst %d, shared via Redis)", name, limit.Rate, limit.Burst))
+		s = scheduler.NewWithLimiter(name, redisGateway.NewRateLimiter(name, limit.Rate, limit.Burst, scheduler.NewTokenBucket(cfg)))
+	} else {
+		logger.Info("Main", fmt.Sprintf("%s rate limit: %.1f req/s (burst %d)", name, limit.Rate, limit.Burst))
+		s = scheduler.New(cfg)
+	}
+	schedulers[name] = s
+	return s
+}
+
+// reloadOnSIGHUP reloads the configuration each time the process receives SIGHUP.
+func reloadOnSIGHUP(ctx context.Context, store *appconfig.Store) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			logger.Info("Main", "SIGHUP received, reloading configuration")
+			_, _ = store.Reload() // Outcome and diff are logged by the store
+		}
 	}
-	return def
 }
 
 func main() {
@@ -99,6 +234,29 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	logger.Configure(logConfig(cfg.Log))
+	// Route the standard log package (e.g. net/http's server errors) through the same handler
+	slog.SetDefault(logger.Slog())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:       cfg.Tracing.Endpoint,
+		ServiceName:    cfg.Tracing.ServiceName,
+		ServiceVersion: version,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Main", fmt.Sprintf("Tracing shutdown error: %s", err))
+		}
+	}()
+	if cfg.Tracing.Endpoint != "" {
+		logger.Info("Main", fmt.Sprintf("Tracing enabled: %s", cfg.Tracing.Endpoint))
+	}
 
 	// Track enabled services for health check
 	enabledServices := handler.EnabledServices{
@@ -110,11 +268,15 @@ func main() {
 
 	// Initialize Redis (L2 cache)
 	var redisRepo *redisGateway.TokenRepository
-	if err := redisGateway.Init(); err != nil {
+	var redisFeatureRepo repository.FeatureStore
+	var redisLinkRepo repository.LinkStore
+	if err := redisGateway.Init(cfg.Redis.Addr, cfg.Redis.Password); err != nil {
 		logger.Warning("Main", "Redis connection failed - using memory cache only")
 	} else {
 		logger.Info("Main", "Redis connected")
 		redisRepo = redisGateway.NewTokenRepository()
+		redisFeatureRepo = redisGateway.NewFeatureRepository().WithTTL(cfg.Cache.FeatureEntryTTL)
+		redisLinkRepo = redisGateway.NewLinkRepository().WithTTL(cfg.Cache.LinksTTL)
 		enabledServices.Redis = true
 	}
 
@@ -122,23 +284,47 @@ func main() {
 	tokenRepo := cache.NewCachedTokenRepository(redisRepo)
 	logger.Info("Main", "Token cache initialized (L1: memory, L2: Redis)")
 
-	spotifyGW := spotify.NewGateway(cfg.spotifyID, cfg.spotifySecret, tokenRepo)
-	kkboxGW := kkbox.NewGateway(cfg.kkboxID, cfg.kkboxSecret, tokenRepo)
-	deezerGW := deezer.NewGateway()
-	musicbrainzGW := musicbrainz.NewGateway("TrackTaste/1.0 (https://github.com/t1nyb0x/tracktaste)")
+	// One scheduler and circuit breaker per upstream, shared by every request in the process
+	breakers := make(map[string]handler.CircuitBreaker)
+	schedulers := make(map[string]*scheduler.Scheduler)
+	spotifyGW := spotify.NewGateway(cfg.Spotify.APIKey, cfg.Spotify.Secret, tokenRepo).
+		WithScheduler(newScheduler(schedulers, "spotify", cfg.RateLimits.Spotify, enabledServices.Redis)).
+		WithRetryPolicy(retryPolicy(cfg.Retries.Spotify)).
+		WithCircuitBreaker(newBreaker(breakers, "spotify", cfg.CircuitBreaker))
+	kkboxGW := kkbox.NewGateway(cfg.KKBOX.APIKey, cfg.KKBOX.Secret, tokenRepo).
+		WithTerritory(cfg.KKBOX.Territory).
+		WithScheduler(newScheduler(schedulers, "kkbox", cfg.RateLimits.KKBOX, enabledServices.Redis)).
+		WithRetryPolicy(retryPolicy(cfg.Retries.KKBOX)).
+		WithCircuitBreaker(newBreaker(breakers, "kkbox", cfg.CircuitBreaker))
+	deezerGW := deezer.NewGateway().
+		WithScheduler(newScheduler(schedulers, "deezer", cfg.RateLimits.Deezer, enabledServices.Redis)).
+		WithRetryPolicy(retryPolicy(cfg.Retries.Deezer)).
+		WithCircuitBreaker(newBreaker(breakers, "deezer", cfg.CircuitBreaker))
+	musicbrainzGW := musicbrainz.NewGateway("TrackTaste/1.0 (https://github.com/t1nyb0x/tracktaste)").
+		WithScheduler(newScheduler(schedulers, "musicbrainz", cfg.RateLimits.MusicBrainz, enabledServices.Redis)).
+		WithRetryPolicy(retryPolicy(cfg.Retries.MusicBrainz)).
+		WithCircuitBreaker(newBreaker(breakers, "musicbrainz", cfg.CircuitBreaker))
+	applemusicGW := applemusic.NewGateway().
+		WithScheduler(newScheduler(schedulers, "apple_music", cfg.RateLimits.AppleMusic, enabledServices.Redis)).
+		WithRetryPolicy(retryPolicy(cfg.Retries.AppleMusic)).
+		WithCircuitBreaker(newBreaker(breakers, "apple_music", cfg.CircuitBreaker))
 
 	trackUC := usecasev1.NewTrackUseCase(spotifyGW)
 	artistUC := usecasev1.NewArtistUseCase(spotifyGW)
 	albumUC := usecasev1.NewAlbumUseCase(spotifyGW)
-	similarUC := usecasev1.NewSimilarTracksUseCase(spotifyGW, kkboxGW)
+	isrcUC := usecasev1.NewISRCUseCase(spotifyGW, kkboxGW, deezerGW, musicbrainzGW)
+	similarUC := usecasev1.NewSimilarTracksUseCase(spotifyGW, kkboxGW).WithOptions(similarOptions(cfg.Similar))
 
 	// Create recommend use case with optional APIs
 	var recommendUC *usecasev2.RecommendUseCase
 
 	// Initialize optional gateways
 	var lastfmGW *lastfm.Gateway
-	if cfg.lastfmAPIKey != "" {
-		lastfmGW = lastfm.NewGateway(cfg.lastfmAPIKey)
+	if cfg.LastFM.APIKey != "" {
+		lastfmGW = lastfm.NewGateway(cfg.LastFM.APIKey).
+			WithScheduler(newScheduler(schedulers, "lastfm", cfg.RateLimits.LastFM, enabledServices.Redis)).
+			WithRetryPolicy(retryPolicy(cfg.Retries.LastFM)).
+			WithCircuitBreaker(newBreaker(breakers, "lastfm", cfg.CircuitBreaker))
 		logger.Info("Main", "Last.fm enabled")
 		enabledServices.LastFM = true
 	} else {
@@ -146,9 +332,12 @@ func main() {
 	}
 
 	var ytmusicGW *ytmusic.Gateway
-	if cfg.ytmusicSidecarURL != "" {
-		ytmusicGW = ytmusic.NewGateway(cfg.ytmusicSidecarURL)
-		logger.Info("Main", fmt.Sprintf("YouTube Music sidecar enabled: %s", cfg.ytmusicSidecarURL))
+	if cfg.YTMusic.SidecarURL != "" {
+		ytmusicGW = ytmusic.NewGateway(cfg.YTMusic.SidecarURL).
+			WithScheduler(newScheduler(schedulers, "youtube_music", cfg.RateLimits.YTMusic, enabledServices.Redis)).
+			WithRetryPolicy(retryPolicy(cfg.Retries.YTMusic)).
+			WithCircuitBreaker(newBreaker(breakers, "youtube_music", cfg.CircuitBreaker))
+		logger.Info("Main", fmt.Sprintf("YouTube Music sidecar enabled: %s", cfg.YTMusic.SidecarURL))
 		enabledServices.YouTubeMusic = true
 	} else {
 		logger.Warning("Main", "YouTube Music sidecar URL not set - running without YouTube Music")
@@ -160,19 +349,107 @@ func main() {
 	} else {
 		recommendUC = usecasev2.NewRecommendUseCase(spotifyGW, kkboxGW, deezerGW, musicbrainzGW)
 	}
+	recommendUC.WithOptions(recommendOptions(cfg))
+
+	// Track links from other services are mapped to Spotify tracks; YouTube Music needs the sidecar
+	trackResolver := resolver.New(spotifyGW,
+		resolver.NewKKBOX(kkboxGW),
+		resolver.NewDeezer(deezerGW),
+		resolver.NewAppleMusic(applemusicGW),
+		resolver.NewMusicBrainz(musicbrainzGW),
+	)
+	if ytmusicGW != nil {
+		trackResolver.Register(resolver.NewYouTubeMusic(ytmusicGW))
+	}
+
+	// Cross-platform links for /v2/track/links (L1: memory, L2: Redis)
+	linkStore := cache.NewCachedLinkStore(redisLinkRepo).WithMaxEntries(cfg.Cache.MaxLinkEntries)
+	linksUC := usecasev2.NewLinksUseCase(spotifyGW, kkboxGW, deezerGW, musicbrainzGW).WithStore(linkStore, cfg.Cache.LinksTTL)
+	if ytmusicGW != nil {
+		linksUC.WithYouTubeMusic(ytmusicGW)
+	}
+
+	// Persistent feature store (L1: memory, L2: Redis) and its background MusicBrainz worker
+	featureStore := cache.NewCachedFeatureStore(redisFeatureRepo).WithMaxEntries(cfg.Cache.MaxFeatureEntries)
+	featureWorker := usecasev2.NewFeatureWorker(musicbrainzGW, featureStore, stalenessPolicy(cfg.Cache))
+	recommendUC.WithFeatureStore(featureStore, featureWorker)
+	featuresUC := usecasev2.NewFeaturesUseCase(spotifyGW, deezerGW, musicbrainzGW).
+		WithPolicy(stalenessPolicy(cfg.Cache)).
+		WithBudget(recommendOptions(cfg).Budget).
+		WithFeatureStore(featureStore, featureWorker)
+	playlistUC := usecasev2.NewPlaylistUseCase(spotifyGW, featuresUC).WithOptions(recommendOptions(cfg))
+	sequenceUC := usecasev2.NewSequenceUseCase(featuresUC)
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go featureWorker.Run(workerCtx)
+	logger.Info("Main", "Feature store initialized (L1: memory, L2: Redis)")
+
+	// Active dependency probes; Spotify and KKBOX are required, so their repeated failure makes the replica not ready
+	checks := []health.Check{
+		{Name: "spotify", Critical: true, Ping: spotifyGW.Ping},
+		{Name: "kkbox", Critical: true, Ping: kkboxGW.Ping},
+		{Name: "deezer", Ping: deezerGW.Ping},
+	}
+	if ytmusicGW != nil {
+		checks = append(checks, health.Check{Name: "youtube_music", Ping: ytmusicGW.Ping})
+	}
+	if enabledServices.Redis {
+		checks = append(checks, health.Check{Name: "redis", Ping: redisGateway.Ping})
+	}
+	prober := health.NewProber(checks, cfg.Health.ProbeInterval, cfg.Health.ProbeTimeout).
+		WithFailureThreshold(cfg.Health.FailureThreshold)
+	go prober.Run(workerCtx)
+
+	// Hot reload: components pick up the settings they use; the rest needs a restart
+	store := appconfig.NewStore(cfg, loadConfig)
+	store.Subscribe("logger", func(c *appconfig.Config) any { return c.Log }, func(c *appconfig.Config) {
+		logger.Configure(logConfig(c.Log))
+		slog.SetDefault(logger.Slog())
+	})
+	store.Subscribe("schedulers", func(c *appconfig.Config) any { return c.RateLimits }, func(c *appconfig.Config) {
+		for name, limit := range rateLimitsByName(c.RateLimits) {
+			if s, ok := schedulers[name]; ok && s.SetRate(limit.Rate, limit.Burst) {
+				logger.Info("Main", fmt.Sprintf("%s rate limit: %.1f req/s (burst %d)", name, limit.Rate, limit.Burst))
+			}
+		}
+	})
+	store.Subscribe("recommend", func(c *appconfig.Config) any { return []any{c.Recommend, c.Cache} }, func(c *appconfig.Config) {
+		recommendUC.UpdateOptions(recommendOptions(c))
+		featureWorker.SetPolicy(stalenessPolicy(c.Cache))
+		featuresUC.SetPolicy(stalenessPolicy(c.Cache))
+		featuresUC.SetBudget(recommendOptions(c).Budget)
+		playlistUC.UpdateOptions(recommendOptions(c))
+	})
+	store.Subscribe("similar", func(c *appconfig.Config) any { return c.Similar }, func(c *appconfig.Config) {
+		similarUC.UpdateOptions(similarOptions(c.Similar))
+	})
+	go reloadOnSIGHUP(workerCtx, store)
 
-	trackH := handler.NewTrackHandler(trackUC, similarUC)
+	trackH := handler.NewTrackHandler(trackUC, similarUC).WithResolver(trackResolver).WithISRCUseCase(isrcUC)
 	artistH := handler.NewArtistHandler(artistUC)
 	albumH := handler.NewAlbumHandler(albumUC)
-	recommendH := handler.NewRecommendHandler(recommendUC)
-	healthH := handler.NewHealthHandler(enabledServices)
+	recommendH := handler.NewRecommendHandler(recommendUC).WithResolver(trackResolver).WithSequencer(sequenceUC)
+	linksH := handler.NewLinksHandler(linksUC).WithResolver(trackResolver)
+	featuresH := handler.NewFeaturesHandler(featuresUC).WithResolver(trackResolver)
+	compareH := handler.NewCompareHandler(recommendUC).WithResolver(trackResolver)
+	playlistH := handler.NewPlaylistHandler(playlistUC).WithResolver(trackResolver)
+	sequenceH := handler.NewSequenceHandler(sequenceUC).WithResolver(trackResolver)
+	generateH := handler.NewGenerateHandler(recommendUC).WithResolver(trackResolver)
+	healthH := handler.NewHealthHandler(enabledServices).WithCircuitBreakers(breakers).WithProber(prober)
+	adminH := handler.NewAdminHandler(store, func() string { return store.Current().Admin.Token })
 
 	srv := server.New(
-		server.Config{Addr: cfg.httpAddr},
-		server.Handlers{Track: trackH, Artist: artistH, Album: albumH, Recommend: recommendH, Health: healthH},
+		server.Config{
+			Addr:             cfg.HTTP.Addr,
+			RetryBudget:      cfg.Retries.RequestBudget,
+			RequestTimeout:   cfg.HTTP.RequestTimeout,
+			RecommendTimeout: cfg.HTTP.RecommendTimeout,
+			DefaultRegion:    cfg.KKBOX.Territory,
+		},
+		server.Handlers{Track: trackH, Artist: artistH, Album: albumH, Recommend: recommendH, Links: linksH, Features: featuresH, Compare: compareH, Playlist: playlistH, Sequence: sequenceH, Generate: generateH, Health: healthH, Admin: adminH},
 	)
 
-	logger.Info("Main", fmt.Sprintf("Server starting on %s (version: %s)", cfg.httpAddr, version))
+	logger.Info("Main", fmt.Sprintf("Server starting on %s (version: %s)", cfg.HTTP.Addr, version))
 	errCh := make(chan error, 1)
 	go func() { errCh <- srv.ListenAndServe() }()
 
@@ -181,6 +458,7 @@ func main() {
 	select {
 	case sig := <-quit:
 		logger.Info("Main", fmt.Sprintf("Shutting down: %s", sig))
+		stopWorker()
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err := srv.Shutdown(ctx); err != nil {
@@ -0,0 +1,137 @@
+// Package applemusic provides the Apple Music catalog gateway implementation.
+// Songs are looked up through the public iTunes Search API, which needs no developer token.
+package applemusic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+)
+
+const (
+	apiBaseURL = "https://itunes.apple.com"
+	// defaultStorefront is used when a link names no storefront
+	defaultStorefront = "jp"
+)
+
+// Gateway implements the AppleMusicAPI interface.
+type Gateway struct {
+	httpc     *http.Client
+	scheduler *scheduler.Scheduler
+	retry     transport.Policy
+	breaker   *transport.Breaker
+}
+
+// NewGateway creates a new Apple Music gateway.
+func NewGateway() *Gateway {
+	return &Gateway{
+		httpc: &http.Client{Timeout: 10 * time.Second},
+		retry: transport.DefaultPolicy(),
+	}
+}
+
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:      "AppleMusic",
+		HTTP:      g.httpc,
+		Scheduler: g.scheduler,
+		Policy:    g.retry,
+		Breaker:   g.breaker,
+	}
+}
+
+// rawLookupResponse represents the JSON response of the lookup endpoint.
+type rawLookupResponse struct {
+	ResultCount int        `json:"resultCount"`
+	Results     []rawTrack `json:"results"`
+}
+
+// rawTrack represents a song in the lookup response.
+type rawTrack struct {
+	WrapperType     string `json:"wrapperType"`
+	Kind            string `json:"kind"`
+	TrackID         int64  `json:"trackId"`
+	TrackName       string `json:"trackName"`
+	ArtistName      string `json:"artistName"`
+	CollectionName  string `json:"collectionName"`
+	TrackTimeMillis int    `json:"trackTimeMillis"`
+	TrackViewURL    string `json:"trackViewUrl"`
+}
+
+// GetSong retrieves a song by its catalog ID in the given storefront.
+func (g *Gateway) GetSong(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+	if id == "" {
+		return nil, fmt.Errorf("applemusic: song ID is required")
+	}
+	if storefront == "" {
+		storefront = defaultStorefront
+	}
+
+	endpoint := fmt.Sprintf("%s/lookup?id=%s&country=%s&entity=song", apiBaseURL, url.QueryEscape(id), url.QueryEscape(storefront))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("applemusic: failed to create request: %w", err)
+	}
+
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("applemusic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, transport.StatusError(resp, "applemusic")
+	}
+
+	var result rawLookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("applemusic: failed to decode response: %w", err)
+	}
+
+	// An album lookup also returns the collection; pick the song with the ID
+	for _, raw := range result.Results {
+		if raw.Kind == "song" && strconv.FormatInt(raw.TrackID, 10) == id {
+			return raw.toDomain(), nil
+		}
+	}
+	return nil, domain.ErrTrackNotFound
+}
+
+func (r *rawTrack) toDomain() *domain.AppleMusicTrack {
+	return &domain.AppleMusicTrack{
+		ID:         strconv.FormatInt(r.TrackID, 10),
+		Title:      r.TrackName,
+		Artist:     r.ArtistName,
+		Album:      r.CollectionName,
+		DurationMs: r.TrackTimeMillis,
+		URL:        r.TrackViewURL,
+	}
+}
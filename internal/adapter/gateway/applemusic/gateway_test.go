@@ -0,0 +1,104 @@
+package applemusic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// rewriteTransport sends every request to the test server, keeping the path.
+type rewriteTransport struct {
+	target string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(rt.target)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGateway_GetSong(t *testing.T) {
+	song := map[string]interface{}{
+		"wrapperType":     "track",
+		"kind":            "song",
+		"trackId":         1440881047,
+		"trackName":       "Lemon",
+		"artistName":      "米津玄師",
+		"collectionName":  "Lemon - Single",
+		"trackTimeMillis": 255000,
+		"trackViewUrl":    "https://music.apple.com/jp/album/lemon/1440881040?i=1440881047",
+	}
+
+	tests := []struct {
+		name        string
+		storefront  string
+		results     []interface{}
+		status      int
+		wantCountry string
+		wantErr     error
+		wantTitle   string
+	}{
+		{
+			name:        "正常系: 曲を取得",
+			storefront:  "us",
+			results:     []interface{}{song},
+			status:      http.StatusOK,
+			wantCountry: "us",
+			wantTitle:   "Lemon",
+		},
+		{
+			name:        "正常系: ストアフロント未指定はjp",
+			results:     []interface{}{map[string]interface{}{"wrapperType": "collection", "collectionId": 1440881040}, song},
+			status:      http.StatusOK,
+			wantCountry: "jp",
+			wantTitle:   "Lemon",
+		},
+		{
+			name:        "異常系: 見つからない",
+			results:     []interface{}{},
+			status:      http.StatusOK,
+			wantCountry: "jp",
+			wantErr:     domain.ErrTrackNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{"resultCount": len(tt.results), "results": tt.results})
+			}))
+			defer server.Close()
+
+			g := NewGateway()
+			g.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			track, err := g.GetSong(context.Background(), tt.storefront, "1440881047")
+			if query.Get("country") != tt.wantCountry || query.Get("id") != "1440881047" {
+				t.Errorf("unexpected query: %v", query)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if track.Title != tt.wantTitle || track.Artist != "米津玄師" || track.ID != "1440881047" {
+				t.Errorf("unexpected track: %+v", track)
+			}
+		})
+	}
+}
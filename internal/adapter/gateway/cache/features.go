@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+)
+
+// defaultMaxFeatureEntries bounds the number of ISRCs kept in L1.
+const defaultMaxFeatureEntries = 20000
+
+// CachedFeatureStore implements a two-level feature store.
+// L1: In-memory map (fast, volatile, bounded)
+// L2: Redis (persistent, shared across instances)
+type CachedFeatureStore struct {
+	memory     map[string]*domain.StoredFeatures
+	redis      repository.FeatureStore
+	maxEntries int
+	mu         sync.RWMutex
+}
+
+// NewCachedFeatureStore creates a new CachedFeatureStore.
+// If redis is nil, only the in-memory store will be used.
+func NewCachedFeatureStore(redis repository.FeatureStore) *CachedFeatureStore {
+	return &CachedFeatureStore{
+		memory:     make(map[string]*domain.StoredFeatures),
+		redis:      redis,
+		maxEntries: defaultMaxFeatureEntries,
+	}
+}
+
+// WithMaxEntries bounds the number of ISRCs kept in L1.
+func (s *CachedFeatureStore) WithMaxEntries(n int) *CachedFeatureStore {
+	s.maxEntries = n
+	return s
+}
+
+// GetFeatures returns stored features, checking L1 first, then L2.
+func (s *CachedFeatureStore) GetFeatures(ctx context.Context, isrc string) (*domain.StoredFeatures, error) {
+	s.mu.RLock()
+	if f, ok := s.memory[isrc]; ok {
+		s.mu.RUnlock()
+		metrics.CacheRequests.Inc("features", "l1", "hit")
+		return copyStoredFeatures(f), nil
+	}
+	s.mu.RUnlock()
+	metrics.CacheRequests.Inc("features", "l1", "miss")
+
+	if s.redis == nil {
+		return nil, nil
+	}
+
+	f, err := s.redis.GetFeatures(ctx, isrc)
+	if err != nil {
+		logger.WarningContext(ctx, "FeatureStore", "Failed to get features from L2 (Redis): "+err.Error())
+		return nil, nil
+	}
+	metrics.CacheRequests.Inc("features", "l2", metrics.CacheResult(f != nil))
+	if f != nil {
+		s.storeL1(f)
+	}
+	return f, nil
+}
+
+// GetFeaturesBatch returns stored features for multiple ISRCs.
+// ISRCs missing from L1 are looked up in L2 in a single batch.
+func (s *CachedFeatureStore) GetFeaturesBatch(ctx context.Context, isrcs []string) (map[string]*domain.StoredFeatures, error) {
+	result := make(map[string]*domain.StoredFeatures, len(isrcs))
+	missing := make([]string, 0, len(isrcs))
+
+	s.mu.RLock()
+	for _, isrc := range isrcs {
+		if f, ok := s.memory[isrc]; ok {
+			result[isrc] = copyStoredFeatures(f)
+		} else {
+			missing = append(missing, isrc)
+		}
+	}
+	s.mu.RUnlock()
+	metrics.CacheRequests.Add(float64(len(result)), "features", "l1", "hit")
+	metrics.CacheRequests.Add(float64(len(missing)), "features", "l1", "miss")
+
+	if s.redis == nil || len(missing) == 0 {
+		return result, nil
+	}
+
+	fromL2, err := s.redis.GetFeaturesBatch(ctx, missing)
+	if err != nil {
+		logger.WarningContext(ctx, "FeatureStore", "Failed to get features batch from L2 (Redis): "+err.Error())
+		return result, nil
+	}
+	metrics.CacheRequests.Add(float64(len(fromL2)), "features", "l2", "hit")
+	metrics.CacheRequests.Add(float64(len(missing)-len(fromL2)), "features", "l2", "miss")
+	for isrc, f := range fromL2 {
+		result[isrc] = f
+		s.storeL1(f)
+	}
+	return result, nil
+}
+
+// SaveFeatures merges features into L1 and writes them through to L2 (best effort).
+func (s *CachedFeatureStore) SaveFeatures(ctx context.Context, features *domain.StoredFeatures) error {
+	if features == nil || features.ISRC == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	existing, ok := s.memory[features.ISRC]
+	if !ok {
+		s.evictIfFullLocked()
+		existing = &domain.StoredFeatures{ISRC: features.ISRC}
+		s.memory[features.ISRC] = existing
+	}
+	existing.Merge(copyStoredFeatures(features))
+	s.mu.Unlock()
+
+	if s.redis != nil {
+		if err := s.redis.SaveFeatures(ctx, features); err != nil {
+			logger.WarningContext(ctx, "FeatureStore", "Failed to save features to L2 (Redis): "+err.Error())
+		}
+	}
+	return nil
+}
+
+// storeL1 promotes an entry from L2 to L1, merging with anything already there.
+func (s *CachedFeatureStore) storeL1(f *domain.StoredFeatures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.memory[f.ISRC]
+	if !ok {
+		s.evictIfFullLocked()
+		s.memory[f.ISRC] = copyStoredFeatures(f)
+		return
+	}
+	existing.Merge(copyStoredFeatures(f))
+}
+
+// evictIfFullLocked drops an arbitrary entry when L1 is at capacity.
+// Callers must hold s.mu for writing.
+func (s *CachedFeatureStore) evictIfFullLocked() {
+	if s.maxEntries <= 0 || len(s.memory) < s.maxEntries {
+		return
+	}
+	for isrc := range s.memory {
+		delete(s.memory, isrc)
+		return
+	}
+}
+
+// copyStoredFeatures returns a deep copy so callers can't mutate L1 entries.
+func copyStoredFeatures(f *domain.StoredFeatures) *domain.StoredFeatures {
+	c := *f
+	if f.Deezer != nil {
+		g := *f.Deezer
+		c.Deezer = &g
+	}
+	if f.MusicBrainz != nil {
+		g := *f.MusicBrainz
+		g.Tags = append([]string(nil), g.Tags...)
+		c.MusicBrainz = &g
+	}
+	if f.SpotifyGenres != nil {
+		g := *f.SpotifyGenres
+		g.Genres = append([]string(nil), g.Genres...)
+		c.SpotifyGenres = &g
+	}
+	return &c
+}
@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockFeatureRepo is a simple L2 feature store mock for testing
+type mockFeatureRepo struct {
+	features map[string]*domain.StoredFeatures
+	getErr   error
+	saveErr  error
+	saved    int
+}
+
+func newMockFeatureRepo() *mockFeatureRepo {
+	return &mockFeatureRepo{features: make(map[string]*domain.StoredFeatures)}
+}
+
+func (m *mockFeatureRepo) GetFeatures(ctx context.Context, isrc string) (*domain.StoredFeatures, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	return m.features[isrc], nil
+}
+
+func (m *mockFeatureRepo) GetFeaturesBatch(ctx context.Context, isrcs []string) (map[string]*domain.StoredFeatures, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	result := make(map[string]*domain.StoredFeatures)
+	for _, isrc := range isrcs {
+		if f, ok := m.features[isrc]; ok {
+			result[isrc] = f
+		}
+	}
+	return result, nil
+}
+
+func (m *mockFeatureRepo) SaveFeatures(ctx context.Context, features *domain.StoredFeatures) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved++
+	existing, ok := m.features[features.ISRC]
+	if !ok {
+		existing = &domain.StoredFeatures{ISRC: features.ISRC}
+		m.features[features.ISRC] = existing
+	}
+	existing.Merge(features)
+	return nil
+}
+
+func deezerGroup(bpm float64) *domain.DeezerFeatureGroup {
+	return &domain.DeezerFeatureGroup{
+		FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: time.Now(), Found: true},
+		BPM:              bpm,
+	}
+}
+
+func TestCachedFeatureStore_SaveFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		redis    *mockFeatureRepo
+		wantL2   bool
+		redisErr error
+	}{
+		{
+			name:   "正常系: L1とL2に保存",
+			redis:  newMockFeatureRepo(),
+			wantL2: true,
+		},
+		{
+			name:     "正常系: L2エラーでもL1に保存成功",
+			redis:    newMockFeatureRepo(),
+			redisErr: errors.New("redis error"),
+		},
+		{
+			name:  "正常系: L2なし",
+			redis: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store *CachedFeatureStore
+			if tt.redis != nil {
+				tt.redis.saveErr = tt.redisErr
+				store = NewCachedFeatureStore(tt.redis)
+			} else {
+				store = NewCachedFeatureStore(nil)
+			}
+			ctx := context.Background()
+
+			err := store.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: "ISRC1", Deezer: deezerGroup(120)})
+			if err != nil {
+				t.Fatalf("SaveFeatures() error = %v", err)
+			}
+
+			got, err := store.GetFeatures(ctx, "ISRC1")
+			if err != nil {
+				t.Fatalf("GetFeatures() error = %v", err)
+			}
+			if got == nil || got.Deezer == nil || got.Deezer.BPM != 120 {
+				t.Errorf("GetFeatures() = %+v, want Deezer BPM 120", got)
+			}
+
+			if tt.redis != nil {
+				_, inL2 := tt.redis.features["ISRC1"]
+				if inL2 != tt.wantL2 {
+					t.Errorf("saved to L2 = %v, want %v", inL2, tt.wantL2)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedFeatureStore_SaveFeatures_MergesGroups(t *testing.T) {
+	store := NewCachedFeatureStore(nil)
+	ctx := context.Background()
+
+	_ = store.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: "ISRC1", Deezer: deezerGroup(120)})
+	_ = store.SaveFeatures(ctx, &domain.StoredFeatures{
+		ISRC: "ISRC1",
+		MusicBrainz: &domain.MusicBrainzFeatureGroup{
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: time.Now(), Found: true},
+			Tags:             []string{"rock"},
+		},
+	})
+
+	got, _ := store.GetFeatures(ctx, "ISRC1")
+	if got.Deezer == nil || got.Deezer.BPM != 120 {
+		t.Errorf("Deezer group lost after merge: %+v", got.Deezer)
+	}
+	if got.MusicBrainz == nil || len(got.MusicBrainz.Tags) != 1 {
+		t.Errorf("MusicBrainz group not merged: %+v", got.MusicBrainz)
+	}
+}
+
+func TestCachedFeatureStore_GetFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		l2       map[string]*domain.StoredFeatures
+		redisErr error
+		wantNil  bool
+	}{
+		{
+			name: "正常系: L2から取得",
+			l2: map[string]*domain.StoredFeatures{
+				"ISRC1": {ISRC: "ISRC1", Deezer: deezerGroup(128)},
+			},
+		},
+		{
+			name:    "正常系: どこにもない",
+			l2:      map[string]*domain.StoredFeatures{},
+			wantNil: true,
+		},
+		{
+			name:     "正常系: L2エラーはnilとして扱う",
+			redisErr: errors.New("redis error"),
+			wantNil:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redis := newMockFeatureRepo()
+			if tt.l2 != nil {
+				redis.features = tt.l2
+			}
+			redis.getErr = tt.redisErr
+			store := NewCachedFeatureStore(redis)
+
+			got, err := store.GetFeatures(context.Background(), "ISRC1")
+			if err != nil {
+				t.Fatalf("GetFeatures() error = %v", err)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("GetFeatures() = %+v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+
+			// Promoted to L1: L2 is no longer consulted
+			redis.getErr = errors.New("redis down")
+			again, _ := store.GetFeatures(context.Background(), "ISRC1")
+			if again == nil || again.Deezer.BPM != got.Deezer.BPM {
+				t.Errorf("GetFeatures() after promotion = %+v, want L1 hit", again)
+			}
+		})
+	}
+}
+
+func TestCachedFeatureStore_GetFeaturesBatch(t *testing.T) {
+	redis := newMockFeatureRepo()
+	redis.features["ISRC2"] = &domain.StoredFeatures{ISRC: "ISRC2", Deezer: deezerGroup(90)}
+	store := NewCachedFeatureStore(redis)
+	ctx := context.Background()
+	_ = store.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: "ISRC1", Deezer: deezerGroup(120)})
+
+	got, err := store.GetFeaturesBatch(ctx, []string{"ISRC1", "ISRC2", "ISRC3"})
+	if err != nil {
+		t.Fatalf("GetFeaturesBatch() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("GetFeaturesBatch() returned %d entries, want 2", len(got))
+	}
+	if got["ISRC1"].Deezer.BPM != 120 || got["ISRC2"].Deezer.BPM != 90 {
+		t.Errorf("GetFeaturesBatch() = %+v", got)
+	}
+	if _, ok := got["ISRC3"]; ok {
+		t.Error("GetFeaturesBatch() should omit unknown ISRCs")
+	}
+}
+
+func TestCachedFeatureStore_Eviction(t *testing.T) {
+	store := NewCachedFeatureStore(nil)
+	store.maxEntries = 2
+	ctx := context.Background()
+
+	for _, isrc := range []string{"A", "B", "C"} {
+		_ = store.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: isrc, Deezer: deezerGroup(100)})
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if len(store.memory) != 2 {
+		t.Errorf("L1 size = %d, want 2", len(store.memory))
+	}
+	if _, ok := store.memory["C"]; !ok {
+		t.Error("latest entry should be kept")
+	}
+}
+
+func TestCachedFeatureStore_CallersCannotMutateL1(t *testing.T) {
+	store := NewCachedFeatureStore(nil)
+	ctx := context.Background()
+
+	saved := &domain.StoredFeatures{
+		ISRC:   "ISRC1",
+		Deezer: deezerGroup(120),
+		MusicBrainz: &domain.MusicBrainzFeatureGroup{
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: time.Now(), Found: true},
+			Tags:             []string{"rock"},
+		},
+		SpotifyGenres: &domain.SpotifyGenresFeatureGroup{
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: time.Now(), Found: true},
+			Genres:           []string{"j-rock"},
+		},
+	}
+	_ = store.SaveFeatures(ctx, saved)
+
+	// Mutating what was saved and what was read must leave L1 alone
+	saved.Deezer.BPM = 0
+	saved.MusicBrainz.Tags[0] = "saved"
+	got, _ := store.GetFeatures(ctx, "ISRC1")
+	got.Deezer.BPM = 1
+	got.MusicBrainz.Tags[0] = "got"
+	got.SpotifyGenres.Genres[0] = "got"
+	batch, _ := store.GetFeaturesBatch(ctx, []string{"ISRC1"})
+	batch["ISRC1"].MusicBrainz.Tags = append(batch["ISRC1"].MusicBrainz.Tags[:0], "batch")
+
+	got, _ = store.GetFeatures(ctx, "ISRC1")
+	if got.Deezer.BPM != 120 || got.MusicBrainz.Tags[0] != "rock" || got.SpotifyGenres.Genres[0] != "j-rock" {
+		t.Errorf("L1 entry was mutated: %+v %+v %+v", got.Deezer, got.MusicBrainz, got.SpotifyGenres)
+	}
+}
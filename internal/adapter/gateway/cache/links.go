@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+)
+
+// defaultMaxLinkEntries bounds the number of tracks kept in L1.
+const defaultMaxLinkEntries = 5000
+
+// CachedLinkStore implements a two-level cache of cross-platform links.
+// L1: In-memory map (fast, volatile, bounded)
+// L2: Redis (persistent, shared across instances)
+// Freshness is decided by the use case from TrackLinks.FetchedAt.
+type CachedLinkStore struct {
+	memory     map[string]*domain.TrackLinks
+	redis      repository.LinkStore
+	maxEntries int
+	mu         sync.RWMutex
+}
+
+// NewCachedLinkStore creates a new CachedLinkStore.
+// If redis is nil, only the in-memory store will be used.
+func NewCachedLinkStore(redis repository.LinkStore) *CachedLinkStore {
+	return &CachedLinkStore{
+		memory:     make(map[string]*domain.TrackLinks),
+		redis:      redis,
+		maxEntries: defaultMaxLinkEntries,
+	}
+}
+
+// WithMaxEntries bounds the number of tracks kept in L1.
+func (s *CachedLinkStore) WithMaxEntries(n int) *CachedLinkStore {
+	s.maxEntries = n
+	return s
+}
+
+func linkKey(trackID, region string) string {
+	return region + ":" + trackID
+}
+
+// GetLinks returns stored links, checking L1 first, then L2.
+func (s *CachedLinkStore) GetLinks(ctx context.Context, trackID, region string) (*domain.TrackLinks, error) {
+	key := linkKey(trackID, region)
+	s.mu.RLock()
+	if l, ok := s.memory[key]; ok {
+		s.mu.RUnlock()
+		metrics.CacheRequests.Inc("links", "l1", "hit")
+		return copyTrackLinks(l), nil
+	}
+	s.mu.RUnlock()
+	metrics.CacheRequests.Inc("links", "l1", "miss")
+
+	if s.redis == nil {
+		return nil, nil
+	}
+
+	l, err := s.redis.GetLinks(ctx, trackID, region)
+	if err != nil {
+		logger.WarningContext(ctx, "LinkStore", "Failed to get links from L2 (Redis): "+err.Error())
+		return nil, nil
+	}
+	metrics.CacheRequests.Inc("links", "l2", metrics.CacheResult(l != nil))
+	if l != nil {
+		s.storeL1(key, l)
+	}
+	return l, nil
+}
+
+// SaveLinks stores links in L1 and writes them through to L2 (best effort).
+func (s *CachedLinkStore) SaveLinks(ctx context.Context, links *domain.TrackLinks) error {
+	if links == nil || links.TrackID == "" {
+		return nil
+	}
+	s.storeL1(linkKey(links.TrackID, links.Region), links)
+
+	if s.redis != nil {
+		if err := s.redis.SaveLinks(ctx, links); err != nil {
+			logger.WarningContext(ctx, "LinkStore", "Failed to save links to L2 (Redis): "+err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *CachedLinkStore) storeL1(key string, links *domain.TrackLinks) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.memory[key]; !ok && s.maxEntries > 0 && len(s.memory) >= s.maxEntries {
+		// Drop an arbitrary entry when L1 is at capacity
+		for k := range s.memory {
+			delete(s.memory, k)
+			break
+		}
+	}
+	s.memory[key] = copyTrackLinks(links)
+}
+
+// copyTrackLinks returns a copy so callers can't mutate L1 entries.
+func copyTrackLinks(l *domain.TrackLinks) *domain.TrackLinks {
+	c := *l
+	c.Links = make(map[string]domain.PlatformLink, len(l.Links))
+	for platform, link := range l.Links {
+		c.Links[platform] = link
+	}
+	return &c
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockLinkRepo is a simple L2 link store mock for testing
+type mockLinkRepo struct {
+	links  map[string]*domain.TrackLinks
+	getErr error
+	saved  int
+}
+
+func newMockLinkRepo() *mockLinkRepo {
+	return &mockLinkRepo{links: make(map[string]*domain.TrackLinks)}
+}
+
+func (m *mockLinkRepo) GetLinks(ctx context.Context, trackID, region string) (*domain.TrackLinks, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	return m.links[region+":"+trackID], nil
+}
+
+func (m *mockLinkRepo) SaveLinks(ctx context.Context, links *domain.TrackLinks) error {
+	m.saved++
+	m.links[links.Region+":"+links.TrackID] = links
+	return nil
+}
+
+func testTrackLinks(trackID, region string) *domain.TrackLinks {
+	return &domain.TrackLinks{
+		TrackID:   trackID,
+		Region:    region,
+		FetchedAt: time.Now(),
+		Links: map[string]domain.PlatformLink{
+			domain.PlatformDeezer: {ID: "3135556", Confidence: 1, Method: domain.LinkMethodISRC},
+		},
+	}
+}
+
+func TestCachedLinkStore_GetLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		l2       map[string]*domain.TrackLinks
+		redisErr error
+		wantNil  bool
+	}{
+		{
+			name: "正常系: L2から取得",
+			l2:   map[string]*domain.TrackLinks{"JP:track1": testTrackLinks("track1", "JP")},
+		},
+		{
+			name:    "正常系: 別の地域はキャッシュされていない",
+			l2:      map[string]*domain.TrackLinks{"TW:track1": testTrackLinks("track1", "TW")},
+			wantNil: true,
+		},
+		{
+			name:     "正常系: L2エラーはnilとして扱う",
+			redisErr: errors.New("redis error"),
+			wantNil:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redis := newMockLinkRepo()
+			if tt.l2 != nil {
+				redis.links = tt.l2
+			}
+			redis.getErr = tt.redisErr
+			store := NewCachedLinkStore(redis)
+
+			got, err := store.GetLinks(context.Background(), "track1", "JP")
+			if err != nil {
+				t.Fatalf("GetLinks() error = %v", err)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("GetLinks() = %+v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+
+			// Promoted to L1: L2 is no longer consulted
+			redis.getErr = errors.New("redis down")
+			again, _ := store.GetLinks(context.Background(), "track1", "JP")
+			if again == nil || again.Links[domain.PlatformDeezer].ID != "3135556" {
+				t.Errorf("GetLinks() after promotion = %+v, want L1 hit", again)
+			}
+		})
+	}
+}
+
+func TestCachedLinkStore_SaveLinks(t *testing.T) {
+	redis := newMockLinkRepo()
+	store := NewCachedLinkStore(redis).WithMaxEntries(1)
+
+	if err := store.SaveLinks(context.Background(), testTrackLinks("track1", "JP")); err != nil {
+		t.Fatalf("SaveLinks() error = %v", err)
+	}
+	if redis.saved != 1 {
+		t.Errorf("L2 saved = %d, want 1", redis.saved)
+	}
+
+	// Callers can't mutate the stored entry
+	got, _ := store.GetLinks(context.Background(), "track1", "JP")
+	got.Links[domain.PlatformDeezer] = domain.PlatformLink{ID: "changed"}
+	again, _ := store.GetLinks(context.Background(), "track1", "JP")
+	if again.Links[domain.PlatformDeezer].ID != "3135556" {
+		t.Errorf("stored entry was mutated: %+v", again)
+	}
+
+	// L1 is bounded
+	_ = store.SaveLinks(context.Background(), testTrackLinks("track2", "JP"))
+	if len(store.memory) != 1 {
+		t.Errorf("L1 entries = %d, want 1", len(store.memory))
+	}
+}
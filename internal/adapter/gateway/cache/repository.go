@@ -9,6 +9,7 @@ import (
 
 	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
 )
 
 // tokenEntry represents a cached token with its expiration time.
@@ -57,15 +58,15 @@ func (r *CachedTokenRepository) SaveToken(ctx context.Context, key string, token
 		expiresAt: expiresAt,
 	}
 	r.mu.Unlock()
-	logger.Debug("Cache", "Token saved to L1 (memory) for "+key)
+	logger.DebugContext(ctx, "Cache", "Token saved to L1 (memory) for "+key)
 
 	// Save to L2 (Redis) - best effort
 	if r.redis != nil {
 		if err := r.redis.SaveToken(ctx, key, token, ttlSeconds); err != nil {
-			logger.Warning("Cache", "Failed to save token to L2 (Redis): "+err.Error())
+			logger.WarningContext(ctx, "Cache", "Failed to save token to L2 (Redis): "+err.Error())
 			// Don't return error - L1 cache is sufficient
 		} else {
-			logger.Debug("Cache", "Token saved to L2 (Redis) for "+key)
+			logger.DebugContext(ctx, "Cache", "Token saved to L2 (Redis) for "+key)
 		}
 	}
 
@@ -78,16 +79,20 @@ func (r *CachedTokenRepository) GetToken(ctx context.Context, key string) (strin
 	r.mu.RLock()
 	if entry, ok := r.memory[key]; ok && entry.isValid() {
 		r.mu.RUnlock()
-		logger.Debug("Cache", "Token retrieved from L1 (memory) for "+key)
+		metrics.CacheRequests.Inc("token", "l1", "hit")
+		logger.DebugContext(ctx, "Cache", "Token retrieved from L1 (memory) for "+key)
 		return entry.token, nil
 	}
 	r.mu.RUnlock()
+	metrics.CacheRequests.Inc("token", "l1", "miss")
 
 	// Check L2 (Redis) if available
 	if r.redis != nil {
 		token, err := r.redis.GetToken(ctx, key)
-		if err == nil && token != "" {
-			logger.Debug("Cache", "Token retrieved from L2 (Redis) for "+key)
+		hit := err == nil && token != ""
+		metrics.CacheRequests.Inc("token", "l2", metrics.CacheResult(hit))
+		if hit {
+			logger.DebugContext(ctx, "Cache", "Token retrieved from L2 (Redis) for "+key)
 			// Promote to L1 cache (use default TTL of 1 hour for promoted tokens)
 			r.promoteToL1(key, token, 3600)
 			return token, nil
@@ -139,15 +144,15 @@ func (r *CachedTokenRepository) InvalidateToken(ctx context.Context, key string)
 	r.mu.Lock()
 	delete(r.memory, key)
 	r.mu.Unlock()
-	logger.Debug("Cache", "Token invalidated from L1 (memory) for "+key)
+	logger.DebugContext(ctx, "Cache", "Token invalidated from L1 (memory) for "+key)
 
 	// Remove from L2 (Redis) if available
 	if r.redis != nil {
 		if err := r.redis.InvalidateToken(ctx, key); err != nil {
-			logger.Warning("Cache", "Failed to invalidate token from L2 (Redis): "+err.Error())
+			logger.WarningContext(ctx, "Cache", "Failed to invalidate token from L2 (Redis): "+err.Error())
 			// Don't return error - L1 invalidation is sufficient
 		} else {
-			logger.Debug("Cache", "Token invalidated from L2 (Redis) for "+key)
+			logger.DebugContext(ctx, "Cache", "Token invalidated from L2 (Redis) for "+key)
 		}
 	}
 
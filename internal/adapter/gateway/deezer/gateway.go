@@ -4,26 +4,34 @@ package deezer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const (
 	apiBaseURL = "https://api.deezer.com"
-	// Deezer rate limit: 50 requests per 5 seconds
-	// We use a conservative limit to avoid hitting rate limits
+	// Maximum concurrent requests in a batch lookup.
+	// This only bounds parallelism; the Deezer rate limit (50 requests per 5 seconds)
+	// is enforced by the shared scheduler.
 	maxConcurrentRequests = 10
 )
 
 // Gateway implements the DeezerAPI interface.
 type Gateway struct {
-	httpc *http.Client
+	httpc     *http.Client
+	scheduler *scheduler.Scheduler
+	retry     transport.Policy
+	breaker   *transport.Breaker
 }
 
 // NewGateway creates a new Deezer API gateway.
@@ -31,9 +39,45 @@ type Gateway struct {
 func NewGateway() *Gateway {
 	return &Gateway{
 		httpc: &http.Client{Timeout: 10 * time.Second},
+		retry: transport.DefaultPolicy(),
 	}
 }
 
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:      "Deezer",
+		HTTP:      g.httpc,
+		Scheduler: g.scheduler,
+		Policy:    g.retry,
+		Breaker:   g.breaker,
+	}
+}
+
+// Ping checks that the Deezer API answers.
+func (g *Gateway) Ping(ctx context.Context) error {
+	return g.client().Probe(ctx, apiBaseURL+"/infos")
+}
+
 // rawTrack represents the raw JSON response from Deezer API.
 type rawTrack struct {
 	ID             int64   `json:"id"`
@@ -72,14 +116,25 @@ func (g *Gateway) GetTrackByISRC(ctx context.Context, isrc string) (*domain.Deez
 	}
 
 	// Deezer supports ISRC lookup via /track/isrc:{isrc}
-	endpoint := fmt.Sprintf("%s/track/isrc:%s", apiBaseURL, isrc)
+	return g.getTrack(ctx, fmt.Sprintf("%s/track/isrc:%s", apiBaseURL, isrc))
+}
 
+// GetTrackByID retrieves a track by its Deezer track ID.
+func (g *Gateway) GetTrackByID(ctx context.Context, id string) (*domain.DeezerTrack, error) {
+	if id == "" {
+		return nil, fmt.Errorf("deezer: track ID is required")
+	}
+	return g.getTrack(ctx, fmt.Sprintf("%s/track/%s", apiBaseURL, url.PathEscape(id)))
+}
+
+// getTrack retrieves a single track from endpoint.
+func (g *Gateway) getTrack(ctx context.Context, endpoint string) (*domain.DeezerTrack, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("deezer: failed to create request: %w", err)
 	}
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("deezer: request failed: %w", err)
 	}
@@ -90,7 +145,7 @@ func (g *Gateway) GetTrackByISRC(ctx context.Context, isrc string) (*domain.Deez
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("deezer: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "deezer")
 	}
 
 	var raw rawTrack
@@ -128,14 +183,14 @@ func (g *Gateway) SearchTrack(ctx context.Context, title, artist string) (*domai
 		return nil, fmt.Errorf("deezer: failed to create request: %w", err)
 	}
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("deezer: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("deezer: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "deezer")
 	}
 
 	var raw rawSearchResponse
@@ -164,34 +219,29 @@ func (g *Gateway) GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map
 
 	result := make(map[string]*domain.DeezerTrack)
 	var mu sync.Mutex
-	var wg sync.WaitGroup
+	group := safego.NewGroup("Deezer")
 
 	// Use a semaphore to limit concurrent requests
 	sem := make(chan struct{}, maxConcurrentRequests)
 
 	for _, isrc := range isrcs {
-		wg.Add(1)
-		go func(isrc string) {
-			defer wg.Done()
-
+		group.Go("ISRC lookup", func() {
 			sem <- struct{}{}        // Acquire semaphore
 			defer func() { <-sem }() // Release semaphore
 
 			track, err := g.GetTrackByISRC(ctx, isrc)
-			if err != nil {
-				if err != domain.ErrNotFound {
-					logger.Warning("Deezer", fmt.Sprintf("Failed to get track by ISRC %s: %v", isrc, err))
-				}
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "Deezer", fmt.Sprintf("Failed to get track by ISRC %s: %v", isrc, err))
 				return
 			}
 
 			mu.Lock()
 			result[isrc] = track
 			mu.Unlock()
-		}(isrc)
+		})
 	}
 
-	wg.Wait()
+	group.Wait()
 
 	return result, nil
 }
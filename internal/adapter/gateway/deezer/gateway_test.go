@@ -3,8 +3,10 @@ package deezer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/t1nyb0x/tracktaste/internal/domain"
@@ -202,6 +204,37 @@ func TestGetTracksByISRCBatch(t *testing.T) {
 			t.Errorf("GetTracksByISRCBatch() expected empty result, got %d items", len(result))
 		}
 	})
+
+	t.Run("not found maps to nil, failures are omitted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/track/isrc:FOUND0000001":
+				_, _ = w.Write([]byte(`{"id":1,"title":"Found","isrc":"FOUND0000001","bpm":120}`))
+			case "/track/isrc:MISSING00001":
+				_, _ = w.Write([]byte(`{"error":{"type":"DataException","message":"no data","code":800}}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer server.Close()
+
+		g := NewGateway()
+		g.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+		result, err := g.GetTracksByISRCBatch(context.Background(), []string{"FOUND0000001", "MISSING00001", "BROKEN00001"})
+		if err != nil {
+			t.Fatalf("GetTracksByISRCBatch() unexpected error: %v", err)
+		}
+		if track := result["FOUND0000001"]; track == nil || track.Title != "Found" {
+			t.Errorf("result[FOUND0000001] = %+v, want track", track)
+		}
+		if track, ok := result["MISSING00001"]; !ok || track != nil {
+			t.Errorf("result[MISSING00001] = %+v (present %v), want nil entry", track, ok)
+		}
+		if _, ok := result["BROKEN00001"]; ok {
+			t.Error("result[BROKEN00001] present, want omitted")
+		}
+	})
 }
 
 func TestConvertToTrack(t *testing.T) {
@@ -270,3 +303,72 @@ func TestConvertToTrackNilArtist(t *testing.T) {
 		t.Errorf("ArtistName should be empty when Artist is nil, got %s", track.ArtistName)
 	}
 }
+
+// rewriteTransport sends every request to the test server, keeping the path.
+type rewriteTransport struct {
+	target string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(rt.target)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGetTrackByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		response   string
+		statusCode int
+		wantErr    error
+		wantTitle  string
+	}{
+		{
+			name:       "success",
+			id:         "3135556",
+			response:   `{"id":3135556,"title":"Harder, Better, Faster, Stronger","isrc":"GBDUW0000059","duration":224,"bpm":123.4,"artist":{"id":27,"name":"Daft Punk"}}`,
+			statusCode: http.StatusOK,
+			wantTitle:  "Harder, Better, Faster, Stronger",
+		},
+		{
+			name:       "no data",
+			id:         "1",
+			response:   `{"error":{"type":"DataException","message":"no data","code":800}}`,
+			statusCode: http.StatusOK,
+			wantErr:    domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/track/"+tt.id {
+					t.Errorf("path = %s, want /track/%s", r.URL.Path, tt.id)
+				}
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			g := NewGateway()
+			g.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			track, err := g.GetTrackByID(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetTrackByID() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTrackByID() unexpected error = %v", err)
+			}
+			if track.Title != tt.wantTitle || track.ISRC != "GBDUW0000059" || track.ArtistName != "Daft Punk" {
+				t.Errorf("GetTrackByID() = %+v", track)
+			}
+		})
+	}
+}
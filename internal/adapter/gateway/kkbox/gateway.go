@@ -9,15 +9,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
 	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const (
 	tokenEndpoint = "https://account.kkbox.com/oauth2/token"
 	apiBaseURL    = "https://api.kkbox.com/v1.1"
-	territory     = "JP"
+	// defaultTerritory is the catalog searched when none is configured
+	defaultTerritory = "JP"
 )
 
 // isAuthError checks if the status code indicates an authentication error.
@@ -32,6 +36,10 @@ type Gateway struct {
 	clientSecret string
 	httpc        *http.Client
 	tokenRepo    repository.TokenRepository
+	scheduler    *scheduler.Scheduler
+	retry        transport.Policy
+	breaker      *transport.Breaker
+	territory    string
 }
 
 func NewGateway(clientID, clientSecret string, tokenRepo repository.TokenRepository) *Gateway {
@@ -40,9 +48,63 @@ func NewGateway(clientID, clientSecret string, tokenRepo repository.TokenReposit
 		clientSecret: clientSecret,
 		httpc:        &http.Client{Timeout: 10 * time.Second},
 		tokenRepo:    tokenRepo,
+		retry:        transport.DefaultPolicy(),
+		territory:    defaultTerritory,
 	}
 }
 
+// WithTerritory sets the KKBOX catalog territory (e.g. "JP", "TW").
+func (g *Gateway) WithTerritory(territory string) *Gateway {
+	g.territory = territory
+	return g
+}
+
+// territoryFor returns the territory requested in ctx, or the configured one.
+func (g *Gateway) territoryFor(ctx context.Context) string {
+	if region := domain.RegionFrom(ctx); region != "" {
+		return region
+	}
+	return g.territory
+}
+
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:        "KKBOX",
+		HTTP:        g.httpc,
+		Scheduler:   g.scheduler,
+		Policy:      g.retry,
+		Breaker:     g.breaker,
+		Auth:        tokenAuth{g},
+		IsAuthError: isAuthError,
+	}
+}
+
+// tokenAuth exposes the gateway's token cache to the transport.
+type tokenAuth struct{ g *Gateway }
+
+func (a tokenAuth) Token(ctx context.Context) (string, error) { return a.g.getToken(ctx) }
+func (a tokenAuth) Invalidate(ctx context.Context)            { a.g.invalidateToken(ctx) }
+
 func (g *Gateway) getToken(ctx context.Context) (string, error) {
 	if g.tokenRepo != nil && g.tokenRepo.IsTokenValid(ctx, "kkbox") {
 		token, err := g.tokenRepo.GetToken(ctx, "kkbox")
@@ -58,7 +120,7 @@ func (g *Gateway) getToken(ctx context.Context) (string, error) {
 
 	if g.tokenRepo != nil {
 		if err := g.tokenRepo.SaveToken(ctx, "kkbox", token, expiresIn); err != nil {
-			logger.Warning("KKBOX", fmt.Sprintf("Failed to save token: %v", err))
+			logger.WarningContext(ctx, "KKBOX", fmt.Sprintf("Failed to save token: %v", err))
 		}
 	}
 
@@ -84,7 +146,7 @@ func (g *Gateway) fetchToken(ctx context.Context) (string, int, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return "", 0, fmt.Errorf("kkbox token: status %d", res.StatusCode)
+		return "", 0, transport.StatusError(res, "kkbox token")
 	}
 
 	var resp struct {
@@ -97,50 +159,41 @@ func (g *Gateway) fetchToken(ctx context.Context) (string, int, error) {
 	return resp.AccessToken, resp.ExpiresIn, nil
 }
 
+// Ping checks that the client credentials are still accepted by fetching a new token.
+// The token cache is bypassed, so revoked credentials show up before the cached token expires.
+func (g *Gateway) Ping(ctx context.Context) error {
+	_, _, err := g.fetchToken(ctx)
+	return err
+}
+
 // invalidateToken removes the cached token when API returns an auth error.
 func (g *Gateway) invalidateToken(ctx context.Context) {
 	if g.tokenRepo != nil {
 		if err := g.tokenRepo.InvalidateToken(ctx, "kkbox"); err != nil {
-			logger.Warning("KKBOX", fmt.Sprintf("Failed to invalidate token: %v", err))
+			logger.WarningContext(ctx, "KKBOX", fmt.Sprintf("Failed to invalidate token: %v", err))
 		} else {
-			logger.Info("KKBOX", "Token invalidated due to auth error, will fetch new token on next request")
+			logger.InfoContext(ctx, "KKBOX", "Token invalidated due to auth error, will fetch new token on next request")
 		}
 	}
 }
 
 func (g *Gateway) SearchByISRC(ctx context.Context, isrc string) (*external.KKBOXTrackInfo, error) {
-	return g.searchByISRCWithRetry(ctx, isrc, false)
-}
-
-func (g *Gateway) searchByISRCWithRetry(ctx context.Context, isrc string, isRetry bool) (*external.KKBOXTrackInfo, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// KKBOX APIではISRCで検索する場合、"isrc:" プレフィックスが必要
 	query := fmt.Sprintf("isrc:%s", isrc)
-	u := fmt.Sprintf("%s/search?q=%s&type=track&territory=%s&limit=1", apiBaseURL, url.QueryEscape(query), territory)
+	u := fmt.Sprintf("%s/search?q=%s&type=track&territory=%s&limit=1", apiBaseURL, url.QueryEscape(query), g.territoryFor(ctx))
 	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.searchByISRCWithRetry(ctx, isrc, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("kkbox search: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "kkbox search")
 	}
 
 	var result struct {
@@ -149,6 +202,7 @@ func (g *Gateway) searchByISRCWithRetry(ctx context.Context, isrc string, isRetr
 				ID   string `json:"id"`
 				Name string `json:"name"`
 				ISRC string `json:"isrc"`
+				URL  string `json:"url"`
 			} `json:"data"`
 		} `json:"tracks"`
 	}
@@ -161,40 +215,24 @@ func (g *Gateway) searchByISRCWithRetry(ctx context.Context, isrc string, isRetr
 	}
 
 	t := result.Tracks.Data[0]
-	return &external.KKBOXTrackInfo{ID: t.ID, Name: t.Name, ISRC: t.ISRC}, nil
+	return &external.KKBOXTrackInfo{ID: t.ID, Name: t.Name, ISRC: t.ISRC, URL: t.URL}, nil
 }
 
 func (g *Gateway) GetRecommendedTracks(ctx context.Context, trackID string) ([]external.KKBOXTrackInfo, error) {
-	return g.getRecommendedTracksWithRetry(ctx, trackID, false)
-}
-
-func (g *Gateway) getRecommendedTracksWithRetry(ctx context.Context, trackID string, isRetry bool) ([]external.KKBOXTrackInfo, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	u := fmt.Sprintf("%s/tracks/%s/recommended-tracks?territory=%s&limit=50", apiBaseURL, trackID, territory)
+	u := fmt.Sprintf("%s/tracks/%s/recommended-tracks?territory=%s&limit=50", apiBaseURL, trackID, g.territoryFor(ctx))
 	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getRecommendedTracksWithRetry(ctx, trackID, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("kkbox recommend: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "kkbox recommend")
 	}
 
 	var result struct {
@@ -203,6 +241,7 @@ func (g *Gateway) getRecommendedTracksWithRetry(ctx context.Context, trackID str
 				ID   string `json:"id"`
 				Name string `json:"name"`
 				ISRC string `json:"isrc"`
+				URL  string `json:"url"`
 			} `json:"data"`
 		} `json:"tracks"`
 	}
@@ -212,52 +251,42 @@ func (g *Gateway) getRecommendedTracksWithRetry(ctx context.Context, trackID str
 
 	tracks := make([]external.KKBOXTrackInfo, len(result.Tracks.Data))
 	for i, t := range result.Tracks.Data {
-		tracks[i] = external.KKBOXTrackInfo{ID: t.ID, Name: t.Name, ISRC: t.ISRC}
+		tracks[i] = external.KKBOXTrackInfo{ID: t.ID, Name: t.Name, ISRC: t.ISRC, URL: t.URL}
 	}
 	return tracks, nil
 }
 
 func (g *Gateway) GetTrackDetail(ctx context.Context, trackID string) (*external.KKBOXTrackInfo, error) {
-	return g.getTrackDetailWithRetry(ctx, trackID, false)
-}
-
-func (g *Gateway) getTrackDetailWithRetry(ctx context.Context, trackID string, isRetry bool) (*external.KKBOXTrackInfo, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	u := fmt.Sprintf("%s/tracks/%s?territory=%s", apiBaseURL, trackID, territory)
+	u := fmt.Sprintf("%s/tracks/%s?territory=%s", apiBaseURL, trackID, g.territoryFor(ctx))
 	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getTrackDetailWithRetry(ctx, trackID, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("kkbox detail: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "kkbox detail")
 	}
 
 	var result struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-		ISRC string `json:"isrc"`
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		ISRC  string `json:"isrc"`
+		URL   string `json:"url"`
+		Album struct {
+			Artist struct {
+				Name string `json:"name"`
+			} `json:"artist"`
+		} `json:"album"`
 	}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	return &external.KKBOXTrackInfo{ID: result.ID, Name: result.Name, ISRC: result.ISRC}, nil
+	return &external.KKBOXTrackInfo{ID: result.ID, Name: result.Name, ISRC: result.ISRC, URL: result.URL, Artist: result.Album.Artist.Name}, nil
 }
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 )
 
@@ -330,3 +332,95 @@ func TestGateway_GetTrackDetail_ResponseParsing(t *testing.T) {
 		})
 	}
 }
+
+// rewriteTransport sends every request to the test server, keeping the path.
+type rewriteTransport struct {
+	target string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(rt.target)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGateway_Territory(t *testing.T) {
+	tests := []struct {
+		name      string
+		territory string // Configured; "" keeps the default
+		region    string // Requested
+		want      string
+	}{
+		{name: "正常系: デフォルトはJP", want: "JP"},
+		{name: "正常系: 設定したterritory", territory: "TW", want: "TW"},
+		{name: "正常系: リクエストの地域が優先", territory: "TW", region: "SG", want: "SG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var territories []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				territories = append(territories, r.URL.Query().Get("territory"))
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "t1", "tracks": map[string]interface{}{"data": []interface{}{}}})
+			}))
+			defer server.Close()
+
+			repo := newMockTokenRepo()
+			repo.tokens["kkbox"] = "cached_token"
+			gw := NewGateway("id", "secret", repo)
+			if tt.territory != "" {
+				gw = gw.WithTerritory(tt.territory)
+			}
+			gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			ctx := context.Background()
+			if tt.region != "" {
+				ctx = domain.WithRegion(ctx, tt.region)
+			}
+			if _, err := gw.SearchByISRC(ctx, "JPSO00123456"); err != nil {
+				t.Fatalf("SearchByISRC() error: %v", err)
+			}
+			if _, err := gw.GetRecommendedTracks(ctx, "t1"); err != nil {
+				t.Fatalf("GetRecommendedTracks() error: %v", err)
+			}
+			if _, err := gw.GetTrackDetail(ctx, "t1"); err != nil {
+				t.Fatalf("GetTrackDetail() error: %v", err)
+			}
+			if len(territories) != 3 {
+				t.Fatalf("API hits = %d, want 3", len(territories))
+			}
+			for i, got := range territories {
+				if got != tt.want {
+					t.Errorf("call %d: territory = %q, want %q", i, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGateway_GetTrackDetail_Artist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "t1",
+			"name":  "Lemon",
+			"isrc":  "JPU901800200",
+			"album": map[string]interface{}{"name": "Lemon", "artist": map[string]interface{}{"name": "米津玄師"}},
+		})
+	}))
+	defer server.Close()
+
+	repo := newMockTokenRepo()
+	repo.tokens["kkbox"] = "cached_token"
+	gw := NewGateway("id", "secret", repo)
+	gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+	got, err := gw.GetTrackDetail(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("GetTrackDetail() error: %v", err)
+	}
+	if got.Name != "Lemon" || got.Artist != "米津玄師" {
+		t.Errorf("GetTrackDetail() = %+v, want Lemon by 米津玄師", got)
+	}
+}
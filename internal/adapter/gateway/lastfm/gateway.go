@@ -10,8 +10,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const (
@@ -23,6 +25,9 @@ const (
 type Gateway struct {
 	apiKey     string
 	httpClient *http.Client
+	scheduler  *scheduler.Scheduler
+	retry      transport.Policy
+	breaker    *transport.Breaker
 }
 
 // NewGateway creates a new Last.fm API gateway.
@@ -32,6 +37,37 @@ func NewGateway(apiKey string) *Gateway {
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
+		retry: transport.DefaultPolicy(),
+	}
+}
+
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:      "LastFM",
+		HTTP:      g.httpClient,
+		Scheduler: g.scheduler,
+		Policy:    g.retry,
+		Breaker:   g.breaker,
 	}
 }
 
@@ -83,14 +119,14 @@ func (g *Gateway) GetSimilarTracks(ctx context.Context, artist, track string, li
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := g.httpClient.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "lastfm")
 	}
 
 	var result similarTracksResponse
@@ -101,7 +137,7 @@ func (g *Gateway) GetSimilarTracks(ctx context.Context, artist, track string, li
 	// Check for API error
 	if result.Error != 0 {
 		if result.Error == 6 { // Track not found
-			logger.Debug("LastFM", fmt.Sprintf("Track not found: %s - %s", artist, track))
+			logger.DebugContext(ctx, "LastFM", fmt.Sprintf("Track not found: %s - %s", artist, track))
 			return []domain.LastFMTrack{}, nil
 		}
 		return nil, fmt.Errorf("Last.fm API error %d: %s", result.Error, result.Message)
@@ -128,14 +164,14 @@ func (g *Gateway) GetSimilarTracksByMBID(ctx context.Context, mbid string, limit
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := g.httpClient.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "lastfm")
 	}
 
 	var result similarTracksResponse
@@ -146,7 +182,7 @@ func (g *Gateway) GetSimilarTracksByMBID(ctx context.Context, mbid string, limit
 	// Check for API error
 	if result.Error != 0 {
 		if result.Error == 6 { // Track not found
-			logger.Debug("LastFM", fmt.Sprintf("Track not found by MBID: %s", mbid))
+			logger.DebugContext(ctx, "LastFM", fmt.Sprintf("Track not found by MBID: %s", mbid))
 			return []domain.LastFMTrack{}, nil
 		}
 		return nil, fmt.Errorf("Last.fm API error %d: %s", result.Error, result.Message)
@@ -4,6 +4,7 @@ package musicbrainz
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -11,8 +12,11 @@ import (
 
 	"golang.org/x/time/rate"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const (
@@ -20,6 +24,8 @@ const (
 	// MusicBrainz rate limit: 1 request per second
 	// User-Agent is required
 	defaultUserAgent = "TrackTaste/1.0 (https://github.com/t1nyb0x/tracktaste)"
+	// Maximum batch lookups waiting on the rate limit at once
+	maxConcurrentRequests = 5
 )
 
 // Gateway implements the MusicBrainzAPI interface.
@@ -27,6 +33,9 @@ type Gateway struct {
 	httpc     *http.Client
 	limiter   *rate.Limiter
 	userAgent string
+	scheduler *scheduler.Scheduler
+	retry     transport.Policy
+	breaker   *transport.Breaker
 }
 
 // NewGateway creates a new MusicBrainz API gateway.
@@ -39,9 +48,49 @@ func NewGateway(userAgent string) *Gateway {
 		httpc:     &http.Client{Timeout: 30 * time.Second},
 		limiter:   rate.NewLimiter(rate.Every(time.Second), 1), // 1 req/sec
 		userAgent: userAgent,
+		retry:     transport.DefaultPolicy(),
 	}
 }
 
+// WithScheduler routes API calls through the shared upstream scheduler.
+// The scheduler then enforces the rate limit instead of the gateway's local limiter.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:      "MusicBrainz",
+		HTTP:      g.httpc,
+		Scheduler: g.scheduler,
+		Policy:    g.retry,
+		Breaker:   g.breaker,
+	}
+}
+
+// wait blocks on the local 1 req/s limiter.
+// When a scheduler is set, it enforces the limit process-wide instead.
+func (g *Gateway) wait(ctx context.Context) error {
+	if g.scheduler != nil {
+		return nil
+	}
+	return g.limiter.Wait(ctx)
+}
+
 // rawISRCResponse represents the response from ISRC lookup.
 type rawISRCResponse struct {
 	ISRC       string         `json:"isrc"`
@@ -97,7 +146,7 @@ func (g *Gateway) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.
 	}
 
 	// Wait for rate limiter
-	if err := g.limiter.Wait(ctx); err != nil {
+	if err := g.wait(ctx); err != nil {
 		return nil, fmt.Errorf("musicbrainz: rate limiter error: %w", err)
 	}
 
@@ -110,7 +159,7 @@ func (g *Gateway) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("musicbrainz: request failed: %w", err)
 	}
@@ -121,7 +170,7 @@ func (g *Gateway) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("musicbrainz: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "musicbrainz")
 	}
 
 	var raw rawISRCResponse
@@ -137,18 +186,18 @@ func (g *Gateway) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.
 	return g.convertRecording(&raw.Recordings[0], isrc), nil
 }
 
-// GetRecordingWithTags retrieves recording details including tags.
+// GetRecordingWithTags retrieves recording details including tags and its first ISRC.
 func (g *Gateway) GetRecordingWithTags(ctx context.Context, mbid string) (*domain.MBRecording, error) {
 	if mbid == "" {
 		return nil, fmt.Errorf("musicbrainz: MBID is required")
 	}
 
 	// Wait for rate limiter
-	if err := g.limiter.Wait(ctx); err != nil {
+	if err := g.wait(ctx); err != nil {
 		return nil, fmt.Errorf("musicbrainz: rate limiter error: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("%s/recording/%s?inc=tags+artists&fmt=json", apiBaseURL, mbid)
+	endpoint := fmt.Sprintf("%s/recording/%s?inc=tags+artists+isrcs&fmt=json", apiBaseURL, mbid)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -157,7 +206,7 @@ func (g *Gateway) GetRecordingWithTags(ctx context.Context, mbid string) (*domai
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("musicbrainz: request failed: %w", err)
 	}
@@ -168,7 +217,7 @@ func (g *Gateway) GetRecordingWithTags(ctx context.Context, mbid string) (*domai
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("musicbrainz: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "musicbrainz")
 	}
 
 	var raw rawRecording
@@ -176,7 +225,11 @@ func (g *Gateway) GetRecordingWithTags(ctx context.Context, mbid string) (*domai
 		return nil, fmt.Errorf("musicbrainz: failed to decode response: %w", err)
 	}
 
-	return g.convertRecording(&raw, ""), nil
+	isrc := ""
+	if len(raw.ISRCs) > 0 {
+		isrc = raw.ISRCs[0]
+	}
+	return g.convertRecording(&raw, isrc), nil
 }
 
 // GetArtistWithRelations retrieves artist details including tags and relations.
@@ -186,7 +239,7 @@ func (g *Gateway) GetArtistWithRelations(ctx context.Context, mbid string) (*dom
 	}
 
 	// Wait for rate limiter
-	if err := g.limiter.Wait(ctx); err != nil {
+	if err := g.wait(ctx); err != nil {
 		return nil, fmt.Errorf("musicbrainz: rate limiter error: %w", err)
 	}
 
@@ -199,7 +252,7 @@ func (g *Gateway) GetArtistWithRelations(ctx context.Context, mbid string) (*dom
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("musicbrainz: request failed: %w", err)
 	}
@@ -210,7 +263,7 @@ func (g *Gateway) GetArtistWithRelations(ctx context.Context, mbid string) (*dom
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("musicbrainz: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "musicbrainz")
 	}
 
 	var raw rawArtistResponse
@@ -222,8 +275,8 @@ func (g *Gateway) GetArtistWithRelations(ctx context.Context, mbid string) (*dom
 }
 
 // GetRecordingsByISRCBatch retrieves multiple recordings by their ISRCs.
-// MusicBrainz does not have a batch endpoint, so we make sequential requests
-// due to the strict rate limit.
+// MusicBrainz does not have a batch endpoint, so we make parallel requests;
+// the rate limiter (or scheduler) spaces them to 1 req/sec.
 func (g *Gateway) GetRecordingsByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error) {
 	if len(isrcs) == 0 {
 		return make(map[string]*domain.MBRecording), nil
@@ -231,23 +284,30 @@ func (g *Gateway) GetRecordingsByISRCBatch(ctx context.Context, isrcs []string)
 
 	result := make(map[string]*domain.MBRecording)
 	var mu sync.Mutex
+	group := safego.NewGroup("MusicBrainz")
+
+	// Use a semaphore to limit goroutines waiting on the rate limit
+	sem := make(chan struct{}, maxConcurrentRequests)
 
-	// MusicBrainz has a strict rate limit of 1 req/sec, so we process sequentially
-	// but we can parallelize up to the rate limit with proper spacing
 	for _, isrc := range isrcs {
-		recording, err := g.GetRecordingByISRC(ctx, isrc)
-		if err != nil {
-			if err != domain.ErrNotFound {
-				logger.Warning("MusicBrainz", fmt.Sprintf("Failed to get recording by ISRC %s: %v", isrc, err))
+		group.Go("ISRC lookup", func() {
+			sem <- struct{}{}        // Acquire semaphore
+			defer func() { <-sem }() // Release semaphore
+
+			recording, err := g.GetRecordingByISRC(ctx, isrc)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "MusicBrainz", fmt.Sprintf("Failed to get recording by ISRC %s: %v", isrc, err))
+				return
 			}
-			continue
-		}
 
-		mu.Lock()
-		result[isrc] = recording
-		mu.Unlock()
+			mu.Lock()
+			result[isrc] = recording
+			mu.Unlock()
+		})
 	}
 
+	group.Wait()
+
 	return result, nil
 }
 
@@ -329,7 +389,7 @@ func (g *Gateway) GetArtistRecordings(ctx context.Context, artistMBID string, li
 	}
 
 	// Wait for rate limiter
-	if err := g.limiter.Wait(ctx); err != nil {
+	if err := g.wait(ctx); err != nil {
 		return nil, fmt.Errorf("musicbrainz: rate limiter error: %w", err)
 	}
 
@@ -344,7 +404,7 @@ func (g *Gateway) GetArtistRecordings(ctx context.Context, artistMBID string, li
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := g.httpc.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("musicbrainz: request failed: %w", err)
 	}
@@ -355,7 +415,7 @@ func (g *Gateway) GetArtistRecordings(ctx context.Context, artistMBID string, li
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("musicbrainz: unexpected status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "musicbrainz")
 	}
 
 	var raw rawBrowseRecordingsResponse
@@ -374,6 +434,6 @@ func (g *Gateway) GetArtistRecordings(ctx context.Context, artistMBID string, li
 		result = append(result, *converted)
 	}
 
-	logger.Debug("MusicBrainz", fmt.Sprintf("Got %d recordings for artist %s", len(result), artistMBID))
+	logger.DebugContext(ctx, "MusicBrainz", fmt.Sprintf("Got %d recordings for artist %s", len(result), artistMBID))
 	return result, nil
 }
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 func TestGetRecordingByISRC(t *testing.T) {
@@ -277,6 +279,30 @@ func TestNewGateway(t *testing.T) {
 	})
 }
 
+func TestWait(t *testing.T) {
+	t.Run("local limiter without scheduler", func(t *testing.T) {
+		g := NewGateway("")
+		// The first token is available immediately; the second must wait ~1s
+		if err := g.wait(context.Background()); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := g.wait(ctx); err == nil {
+			t.Error("wait() should block on the local limiter")
+		}
+	})
+
+	t.Run("scheduler replaces local limiter", func(t *testing.T) {
+		g := NewGateway("").WithScheduler(scheduler.New(scheduler.Config{Name: "musicbrainz", Rate: 1, Burst: 1}))
+		for i := 0; i < 3; i++ {
+			if err := g.wait(context.Background()); err != nil {
+				t.Fatalf("wait() error = %v", err)
+			}
+		}
+	})
+}
+
 // Integration test (skipped by default, run with -tags=integration)
 func TestIntegration(t *testing.T) {
 	if testing.Short() {
@@ -0,0 +1,168 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// defaultFeatureTTL is how long a feature entry survives without being written.
+// Staleness of each group is decided by the use case, not by this TTL.
+const defaultFeatureTTL = 30 * 24 * time.Hour
+
+// FeatureRepository implements port/repository.FeatureStore using Redis.
+// Each ISRC is stored as a hash with one JSON field per feature group.
+type FeatureRepository struct {
+	ttl time.Duration
+}
+
+// NewFeatureRepository creates a new FeatureRepository.
+func NewFeatureRepository() *FeatureRepository {
+	return &FeatureRepository{ttl: defaultFeatureTTL}
+}
+
+// WithTTL sets how long an entry survives without being written.
+func (r *FeatureRepository) WithTTL(ttl time.Duration) *FeatureRepository {
+	r.ttl = ttl
+	return r
+}
+
+func featureKey(isrc string) string {
+	return fmt.Sprintf("features:%s", isrc)
+}
+
+// GetFeatures retrieves stored features for an ISRC.
+func (r *FeatureRepository) GetFeatures(ctx context.Context, isrc string) (*domain.StoredFeatures, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+	fields, err := client.HGetAll(ctx, featureKey(isrc)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return decodeFeatures(isrc, fields)
+}
+
+// GetFeaturesBatch retrieves stored features for multiple ISRCs using a pipeline.
+func (r *FeatureRepository) GetFeaturesBatch(ctx context.Context, isrcs []string) (map[string]*domain.StoredFeatures, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+	result := make(map[string]*domain.StoredFeatures, len(isrcs))
+	if len(isrcs) == 0 {
+		return result, nil
+	}
+
+	pipe := client.Pipeline()
+	cmds := make(map[string]*redis.MapStringStringCmd, len(isrcs))
+	for _, isrc := range isrcs {
+		cmds[isrc] = pipe.HGetAll(ctx, featureKey(isrc))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get features: %w", err)
+	}
+
+	for isrc, cmd := range cmds {
+		f, err := decodeFeatures(isrc, cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			result[isrc] = f
+		}
+	}
+	return result, nil
+}
+
+// saveFeaturesScript writes feature groups unless the stored group was fetched later,
+// the same rule as domain.StoredFeatures.Merge, so that a late write from a slow
+// request cannot replace a fresher group written by another replica or the worker.
+// KEYS[1]: feature key
+// ARGV[1]: entry TTL in milliseconds
+// ARGV[2..]: triples of group field, group JSON and fetch time in Unix microseconds
+// The fetch time of each group is kept in the "<field>:fetched_at" hash field.
+var saveFeaturesScript = redis.NewScript(`
+for i = 2, #ARGV, 3 do
+	local at_field = ARGV[i] .. ':fetched_at'
+	local stored_at = tonumber(redis.call('HGET', KEYS[1], at_field))
+	local fetched_at = tonumber(ARGV[i + 2])
+	if stored_at == nil or stored_at <= fetched_at then
+		redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1], at_field, ARGV[i + 2])
+	end
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return 0
+`)
+
+// SaveFeatures writes the non-nil groups of features that are not older than the
+// stored ones, and refreshes the entry TTL.
+func (r *FeatureRepository) SaveFeatures(ctx context.Context, features *domain.StoredFeatures) error {
+	if client == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+
+	args := []interface{}{r.ttl.Milliseconds()}
+	encode := func(source domain.FeatureSource, meta domain.FeatureGroupMeta, group interface{}) error {
+		data, err := json.Marshal(group)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s features: %w", source, err)
+		}
+		args = append(args, string(source), data, meta.FetchedAt.UnixMicro())
+		return nil
+	}
+	if features.Deezer != nil {
+		if err := encode(domain.FeatureSourceDeezer, features.Deezer.FeatureGroupMeta, features.Deezer); err != nil {
+			return err
+		}
+	}
+	if features.MusicBrainz != nil {
+		if err := encode(domain.FeatureSourceMusicBrainz, features.MusicBrainz.FeatureGroupMeta, features.MusicBrainz); err != nil {
+			return err
+		}
+	}
+	if features.SpotifyGenres != nil {
+		if err := encode(domain.FeatureSourceSpotifyGenres, features.SpotifyGenres.FeatureGroupMeta, features.SpotifyGenres); err != nil {
+			return err
+		}
+	}
+	if len(args) == 1 {
+		return nil
+	}
+
+	if err := saveFeaturesScript.Run(ctx, client, []string{featureKey(features.ISRC)}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to save features: %w", err)
+	}
+	return nil
+}
+
+// decodeFeatures converts a Redis hash into StoredFeatures. Returns nil for an empty hash.
+func decodeFeatures(isrc string, fields map[string]string) (*domain.StoredFeatures, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	f := &domain.StoredFeatures{ISRC: isrc}
+	if raw, ok := fields[string(domain.FeatureSourceDeezer)]; ok {
+		f.Deezer = &domain.DeezerFeatureGroup{}
+		if err := json.Unmarshal([]byte(raw), f.Deezer); err != nil {
+			return nil, fmt.Errorf("failed to decode deezer features: %w", err)
+		}
+	}
+	if raw, ok := fields[string(domain.FeatureSourceMusicBrainz)]; ok {
+		f.MusicBrainz = &domain.MusicBrainzFeatureGroup{}
+		if err := json.Unmarshal([]byte(raw), f.MusicBrainz); err != nil {
+			return nil, fmt.Errorf("failed to decode musicbrainz features: %w", err)
+		}
+	}
+	if raw, ok := fields[string(domain.FeatureSourceSpotifyGenres)]; ok {
+		f.SpotifyGenres = &domain.SpotifyGenresFeatureGroup{}
+		if err := json.Unmarshal([]byte(raw), f.SpotifyGenres); err != nil {
+			return nil, fmt.Errorf("failed to decode spotify genres: %w", err)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func deezerGroup(bpm float64, fetchedAt time.Time) *domain.DeezerFeatureGroup {
+	return &domain.DeezerFeatureGroup{
+		FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: fetchedAt, Found: true},
+		BPM:              bpm,
+	}
+}
+
+func TestFeatureRepository_SaveFeatures(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	isrc := "JPAB00000001"
+
+	tests := []struct {
+		name    string
+		stored  *domain.DeezerFeatureGroup
+		save    *domain.DeezerFeatureGroup
+		wantBPM float64
+	}{
+		{name: "正常系: 新規保存", save: deezerGroup(120, now), wantBPM: 120},
+		{name: "正常系: 新しいグループで上書き", stored: deezerGroup(120, now), save: deezerGroup(130, now.Add(time.Hour)), wantBPM: 130},
+		{name: "正常系: 古いグループでは上書きしない", stored: deezerGroup(120, now), save: deezerGroup(110, now.Add(-time.Hour)), wantBPM: 120},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := useMiniredis(t)
+			repo := NewFeatureRepository()
+			ctx := context.Background()
+
+			if tt.stored != nil {
+				if err := repo.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: isrc, Deezer: tt.stored}); err != nil {
+					t.Fatalf("SaveFeatures() error = %v", err)
+				}
+			}
+			genres := &domain.SpotifyGenresFeatureGroup{
+				FeatureGroupMeta: domain.FeatureGroupMeta{Version: 1, FetchedAt: now, Found: true},
+				Genres:           []string{"j-pop"},
+			}
+			if err := repo.SaveFeatures(ctx, &domain.StoredFeatures{ISRC: isrc, Deezer: tt.save, SpotifyGenres: genres}); err != nil {
+				t.Fatalf("SaveFeatures() error = %v", err)
+			}
+
+			got, err := repo.GetFeatures(ctx, isrc)
+			if err != nil {
+				t.Fatalf("GetFeatures() error = %v", err)
+			}
+			if got == nil || got.Deezer == nil || got.Deezer.BPM != tt.wantBPM {
+				t.Fatalf("Deezer = %+v, want BPM %v", got, tt.wantBPM)
+			}
+			if got.SpotifyGenres == nil || got.SpotifyGenres.Genres[0] != "j-pop" {
+				t.Errorf("SpotifyGenres = %+v, want [j-pop]", got.SpotifyGenres)
+			}
+			if ttl := m.TTL(featureKey(isrc)); ttl != defaultFeatureTTL {
+				t.Errorf("TTL = %v, want %v", ttl, defaultFeatureTTL)
+			}
+		})
+	}
+}
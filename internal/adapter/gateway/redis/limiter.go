@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+)
+
+// gcraScript implements the Generic Cell Rate Algorithm atomically.
+// KEYS[1]: limiter key
+// ARGV[1]: emission interval in microseconds (1 / rate)
+// ARGV[2]: burst size
+// Returns {allowed (0/1), wait in microseconds}.
+// Redis server time is used so that instance clocks need not agree.
+var gcraScript = redis.NewScript(`
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if tat == nil or tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - burst * emission
+if now < allow_at then
+	return {0, allow_at - now}
+end
+
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000) + 1)
+return {1, 0}
+`)
+
+// RateLimiter is a rate limiter shared by every instance through Redis (GCRA).
+// It implements scheduler.Limiter. When Redis is unavailable it falls back to
+// the local limiter, so each instance keeps limiting on its own.
+type RateLimiter struct {
+	key      string
+	emission atomic.Int64 // time.Duration; 0 disables the limit
+	burst    atomic.Int64
+	fallback scheduler.Limiter
+	degraded atomic.Bool
+}
+
+// NewRateLimiter creates a distributed limiter for the named upstream.
+// fallback is used while Redis cannot be reached.
+func NewRateLimiter(name string, ratePerSec float64, burst int, fallback scheduler.Limiter) *RateLimiter {
+	l := &RateLimiter{
+		key:      fmt.Sprintf("ratelimit:%s", name),
+		fallback: fallback,
+	}
+	l.setRate(ratePerSec, burst)
+	return l
+}
+
+// SetRate changes the shared limit and that of the fallback limiter.
+// It implements scheduler.RateSetter.
+func (l *RateLimiter) SetRate(ratePerSec float64, burst int) {
+	l.setRate(ratePerSec, burst)
+	scheduler.SetLimiterRate(l.fallback, ratePerSec, burst)
+}
+
+func (l *RateLimiter) setRate(ratePerSec float64, burst int) {
+	if burst <= 0 {
+		burst = 1
+	}
+	emission := time.Duration(0)
+	if ratePerSec > 0 {
+		emission = time.Duration(float64(time.Second) / ratePerSec)
+	}
+	l.emission.Store(int64(emission))
+	l.burst.Store(int64(burst))
+}
+
+// Wait blocks until the shared limit allows one more request.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.emission.Load() == 0 {
+		return nil
+	}
+	for {
+		wait, err := l.reserve(ctx)
+		if err != nil {
+			// A cancelled caller says nothing about Redis
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if l.degraded.CompareAndSwap(false, true) {
+				logger.WarningContext(ctx, "RateLimiter", fmt.Sprintf("Redis unavailable for %s, using local limiter: %v", l.key, err))
+			}
+			return l.fallback.Wait(ctx)
+		}
+		if l.degraded.CompareAndSwap(true, false) {
+			logger.InfoContext(ctx, "RateLimiter", fmt.Sprintf("Redis recovered for %s", l.key))
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve asks Redis for a slot. It returns 0 when allowed, or how long to wait.
+func (l *RateLimiter) reserve(ctx context.Context) (time.Duration, error) {
+	if client == nil {
+		return 0, fmt.Errorf("redis client not initialized")
+	}
+	res, err := gcraScript.Run(ctx, client, []string{l.key},
+		time.Duration(l.emission.Load()).Microseconds(), l.burst.Load()).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	if len(res) != 2 {
+		return 0, fmt.Errorf("unexpected limiter response: %v", res)
+	}
+	if res[0] == 1 {
+		return 0, nil
+	}
+	wait := time.Duration(res[1]) * time.Microsecond
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return wait, nil
+}
@@ -0,0 +1,221 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+)
+
+// countingLimiter records how often the fallback is used.
+type countingLimiter struct {
+	calls int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.calls++
+	return nil
+}
+
+// useMiniredis points the package client at an in-memory Redis with a fixed clock.
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	m := miniredis.RunT(t)
+	m.SetTime(time.Unix(1700000000, 0))
+
+	prev := client
+	client = redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+		client = prev
+	})
+	return m
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name         string
+		rate         float64
+		burst        int
+		wantEmission time.Duration
+		wantBurst    int
+	}{
+		{name: "1 req/s", rate: 1, burst: 1, wantEmission: time.Second, wantBurst: 1},
+		{name: "10 req/s", rate: 10, burst: 50, wantEmission: 100 * time.Millisecond, wantBurst: 50},
+		{name: "バースト未指定", rate: 2, burst: 0, wantEmission: 500 * time.Millisecond, wantBurst: 1},
+		{name: "無制限", rate: 0, burst: 1, wantEmission: 0, wantBurst: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter("musicbrainz", tt.rate, tt.burst, &countingLimiter{})
+			if got := time.Duration(l.emission.Load()); got != tt.wantEmission {
+				t.Errorf("emission = %v, want %v", got, tt.wantEmission)
+			}
+			if got := l.burst.Load(); got != int64(tt.wantBurst) {
+				t.Errorf("burst = %d, want %d", got, tt.wantBurst)
+			}
+			if l.key != "ratelimit:musicbrainz" {
+				t.Errorf("key = %s, want ratelimit:musicbrainz", l.key)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_SetRate(t *testing.T) {
+	fallback := scheduler.NewTokenBucket(scheduler.Config{Rate: 1, Burst: 1})
+	l := NewRateLimiter("musicbrainz", 1, 1, fallback)
+
+	l.SetRate(4, 3)
+
+	if got := time.Duration(l.emission.Load()); got != 250*time.Millisecond {
+		t.Errorf("emission = %v, want 250ms", got)
+	}
+	if got := l.burst.Load(); got != 3 {
+		t.Errorf("burst = %d, want 3", got)
+	}
+	if fallback.Limit() != 4 || fallback.Burst() != 3 {
+		t.Errorf("fallback = %v/%d, want 4/3", fallback.Limit(), fallback.Burst())
+	}
+}
+
+func TestRateLimiter_FallbackWithoutRedis(t *testing.T) {
+	prev := client
+	client = nil
+	defer func() { client = prev }()
+
+	fallback := &countingLimiter{}
+	l := NewRateLimiter("musicbrainz", 1, 1, fallback)
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if fallback.calls != 2 {
+		t.Errorf("fallback calls = %d, want 2", fallback.calls)
+	}
+	if !l.degraded.Load() {
+		t.Error("limiter should be marked degraded")
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	fallback := &countingLimiter{}
+	l := NewRateLimiter("ytmusic", 0, 1, fallback)
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if fallback.calls != 0 {
+		t.Error("unlimited limiter should not consult Redis or the fallback")
+	}
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration // Clock advance after the burst is used up
+		want    []time.Duration
+	}{
+		{name: "正常系: バーストまで待たずに通す", want: []time.Duration{0, 0, 0, time.Second}},
+		{name: "正常系: 時間が経つと1枠ずつ戻る", advance: time.Second, want: []time.Duration{0, time.Second}},
+		{name: "正常系: 待ち時間は残りの分だけ", advance: 400 * time.Millisecond, want: []time.Duration{600 * time.Millisecond}},
+		{name: "正常系: バーストより多くは貯まらない", advance: time.Minute, want: []time.Duration{0, 0, 0, time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := useMiniredis(t)
+			l := NewRateLimiter("test", 1, 3, &countingLimiter{})
+			ctx := context.Background()
+
+			if tt.advance > 0 {
+				for i := 0; i < 3; i++ {
+					if _, err := l.reserve(ctx); err != nil {
+						t.Fatalf("reserve() error = %v", err)
+					}
+				}
+				m.SetTime(time.Unix(1700000000, 0).Add(tt.advance))
+				m.FastForward(tt.advance)
+			}
+
+			for i, want := range tt.want {
+				got, err := l.reserve(ctx)
+				if err != nil {
+					t.Fatalf("reserve() error = %v", err)
+				}
+				if got != want {
+					t.Errorf("reserve() #%d = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ReserveConcurrent(t *testing.T) {
+	useMiniredis(t)
+	const callers, burst = 20, 5
+
+	// Separate limiters stand for separate instances sharing the key
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l := NewRateLimiter("shared", 1, burst, &countingLimiter{})
+			wait, err := l.reserve(context.Background())
+			if err != nil {
+				t.Errorf("reserve() error = %v", err)
+				return
+			}
+			if wait == 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != burst {
+		t.Errorf("allowed = %d, want %d", allowed, burst)
+	}
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	useMiniredis(t)
+	fallback := &countingLimiter{}
+	l := NewRateLimiter("test", 1, 1, fallback)
+
+	// Use up the burst, so the next Wait has to wait about a second
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Cancelled before reaching Redis
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if err := l.Wait(cancelled); err != context.Canceled {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+
+	if l.degraded.Load() {
+		t.Error("cancellation should not mark the limiter degraded")
+	}
+	if fallback.calls != 0 {
+		t.Errorf("fallback calls = %d, want 0", fallback.calls)
+	}
+}
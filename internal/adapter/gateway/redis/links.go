@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// defaultLinksTTL is how long cross-platform links are kept.
+const defaultLinksTTL = 7 * 24 * time.Hour
+
+// LinkRepository implements port/repository.LinkStore using Redis.
+// Each entry is stored as a JSON string that expires after the TTL.
+type LinkRepository struct {
+	ttl time.Duration
+}
+
+// NewLinkRepository creates a new LinkRepository.
+func NewLinkRepository() *LinkRepository {
+	return &LinkRepository{ttl: defaultLinksTTL}
+}
+
+// WithTTL sets how long an entry is kept.
+func (r *LinkRepository) WithTTL(ttl time.Duration) *LinkRepository {
+	r.ttl = ttl
+	return r
+}
+
+func linksKey(trackID, region string) string {
+	return fmt.Sprintf("links:%s:%s", region, trackID)
+}
+
+// GetLinks retrieves the stored links of a track.
+func (r *LinkRepository) GetLinks(ctx context.Context, trackID, region string) (*domain.TrackLinks, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+	data, err := client.Get(ctx, linksKey(trackID, region)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var links domain.TrackLinks
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode links: %w", err)
+	}
+	return &links, nil
+}
+
+// SaveLinks stores the links of a track with the configured TTL.
+func (r *LinkRepository) SaveLinks(ctx context.Context, links *domain.TrackLinks) error {
+	if client == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+	data, err := json.Marshal(links)
+	if err != nil {
+		return fmt.Errorf("failed to encode links: %w", err)
+	}
+	if err := client.Set(ctx, linksKey(links.TrackID, links.Region), data, r.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save links: %w", err)
+	}
+	return nil
+}
@@ -4,7 +4,6 @@ package redis
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,16 +13,11 @@ import (
 
 var client *redis.Client
 
-// Init initializes the Redis connection.
-func Init() error {
-	redisURL := os.Getenv("REDIS_URL")
-	if redisURL == "" {
-		redisURL = "localhost:6379"
-	}
-
+// Init initializes the Redis connection to addr (host:port).
+func Init(addr, password string) error {
 	client = redis.NewClient(&redis.Options{
-		Addr:     redisURL,
-		Password: os.Getenv("REDIS_PASSWORD"),
+		Addr:     addr,
+		Password: password,
 		DB:       0,
 	})
 
@@ -37,6 +31,14 @@ func Init() error {
 	return nil
 }
 
+// Ping checks the Redis connection.
+func Ping(ctx context.Context) error {
+	if client == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+	return client.Ping(ctx).Err()
+}
+
 // TokenRepository implements port/repository.TokenRepository using Redis.
 type TokenRepository struct{}
 
@@ -58,7 +60,7 @@ func (r *TokenRepository) SaveToken(ctx context.Context, key string, token strin
 	if err := client.Set(ctx, redisKey, token, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to save token: %w", err)
 	}
-	logger.Debug("Redis", fmt.Sprintf("Token saved for %s with TTL %v", key, ttl))
+	logger.DebugContext(ctx, "Redis", fmt.Sprintf("Token saved for %s with TTL %v", key, ttl))
 	return nil
 }
 
@@ -95,6 +97,6 @@ func (r *TokenRepository) InvalidateToken(ctx context.Context, key string) error
 	if err := client.Del(ctx, redisKey).Err(); err != nil {
 		return fmt.Errorf("failed to delete token: %w", err)
 	}
-	logger.Debug("Redis", fmt.Sprintf("Token invalidated for %s", key))
+	logger.DebugContext(ctx, "Redis", fmt.Sprintf("Token invalidated for %s", key))
 	return nil
 }
@@ -9,10 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
 	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const (
@@ -32,6 +34,9 @@ type Gateway struct {
 	secret    string
 	httpc     *http.Client
 	tokenRepo repository.TokenRepository
+	scheduler *scheduler.Scheduler
+	retry     transport.Policy
+	breaker   *transport.Breaker
 }
 
 func NewGateway(clientID, secret string, tokenRepo repository.TokenRepository) *Gateway {
@@ -40,9 +45,48 @@ func NewGateway(clientID, secret string, tokenRepo repository.TokenRepository) *
 		secret:    secret,
 		httpc:     &http.Client{Timeout: 10 * time.Second},
 		tokenRepo: tokenRepo,
+		retry:     transport.DefaultPolicy(),
 	}
 }
 
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:        "Spotify",
+		HTTP:        g.httpc,
+		Scheduler:   g.scheduler,
+		Policy:      g.retry,
+		Breaker:     g.breaker,
+		Auth:        tokenAuth{g},
+		IsAuthError: isAuthError,
+	}
+}
+
+// tokenAuth exposes the gateway's token cache to the transport.
+type tokenAuth struct{ g *Gateway }
+
+func (a tokenAuth) Token(ctx context.Context) (string, error) { return a.g.getToken(ctx) }
+func (a tokenAuth) Invalidate(ctx context.Context)            { a.g.invalidateToken(ctx) }
+
 func (g *Gateway) getToken(ctx context.Context) (string, error) {
 	if g.tokenRepo != nil && g.tokenRepo.IsTokenValid(ctx, "spotify") {
 		token, err := g.tokenRepo.GetToken(ctx, "spotify")
@@ -58,7 +102,7 @@ func (g *Gateway) getToken(ctx context.Context) (string, error) {
 
 	if g.tokenRepo != nil {
 		if err := g.tokenRepo.SaveToken(ctx, "spotify", token, expiresIn); err != nil {
-			logger.Warning("Spotify", fmt.Sprintf("Failed to save token: %v", err))
+			logger.WarningContext(ctx, "Spotify", fmt.Sprintf("Failed to save token: %v", err))
 		}
 	}
 
@@ -84,7 +128,7 @@ func (g *Gateway) fetchToken(ctx context.Context) (string, int, error) {
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return "", 0, fmt.Errorf("spotify token: status %d", res.StatusCode)
+		return "", 0, transport.StatusError(res, "spotify token")
 	}
 
 	var resp struct {
@@ -97,47 +141,52 @@ func (g *Gateway) fetchToken(ctx context.Context) (string, int, error) {
 	return resp.AccessToken, resp.ExpiresIn, nil
 }
 
+// Ping checks that the client credentials are still accepted by fetching a new token.
+// The token cache is bypassed, so revoked credentials show up before the cached token expires.
+func (g *Gateway) Ping(ctx context.Context) error {
+	_, _, err := g.fetchToken(ctx)
+	return err
+}
+
 // invalidateToken removes the cached token when API returns an auth error.
 func (g *Gateway) invalidateToken(ctx context.Context) {
 	if g.tokenRepo != nil {
 		if err := g.tokenRepo.InvalidateToken(ctx, "spotify"); err != nil {
-			logger.Warning("Spotify", fmt.Sprintf("Failed to invalidate token: %v", err))
+			logger.WarningContext(ctx, "Spotify", fmt.Sprintf("Failed to invalidate token: %v", err))
 		} else {
-			logger.Info("Spotify", "Token invalidated due to auth error, will fetch new token on next request")
+			logger.InfoContext(ctx, "Spotify", "Token invalidated due to auth error, will fetch new token on next request")
 		}
 	}
 }
 
-func (g *Gateway) GetTrackByID(ctx context.Context, id string) (*domain.Track, error) {
-	return g.getTrackByIDWithRetry(ctx, id, false)
-}
-
-func (g *Gateway) getTrackByIDWithRetry(ctx context.Context, id string, isRetry bool) (*domain.Track, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
+// marketQuery returns the market parameter for the region requested in ctx,
+// prefixed with sep, or "" when no region was requested. With a market,
+// Spotify relinks tracks to their local version and reports is_playable.
+func marketQuery(ctx context.Context, sep string) string {
+	region := domain.RegionFrom(ctx)
+	if region == "" {
+		return ""
 	}
+	return sep + "market=" + url.QueryEscape(region)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/tracks/"+id, nil)
+func (g *Gateway) GetTrackByID(ctx context.Context, id string) (*domain.Track, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/tracks/"+id+marketQuery(ctx, "?"), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getTrackByIDWithRetry(ctx, id, true)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrTrackNotFound
 	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify")
 	}
 
 	var raw rawTrack
@@ -149,35 +198,22 @@ func (g *Gateway) getTrackByIDWithRetry(ctx context.Context, id string, isRetry
 }
 
 func (g *Gateway) GetArtistByID(ctx context.Context, id string) (*domain.Artist, error) {
-	return g.getArtistByIDWithRetry(ctx, id, false)
-}
-
-func (g *Gateway) getArtistByIDWithRetry(ctx context.Context, id string, isRetry bool) (*domain.Artist, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/artists/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getArtistByIDWithRetry(ctx, id, true)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrArtistNotFound
 	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify")
 	}
 
 	var raw rawArtist
@@ -189,35 +225,22 @@ func (g *Gateway) getArtistByIDWithRetry(ctx context.Context, id string, isRetry
 }
 
 func (g *Gateway) GetAlbumByID(ctx context.Context, id string) (*domain.Album, error) {
-	return g.getAlbumByIDWithRetry(ctx, id, false)
-}
-
-func (g *Gateway) getAlbumByIDWithRetry(ctx context.Context, id string, isRetry bool) (*domain.Album, error) {
-	token, err := g.getToken(ctx)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/albums/"+id+marketQuery(ctx, "?"), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/albums/"+id, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getAlbumByIDWithRetry(ctx, id, true)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrAlbumNotFound
 	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify")
 	}
 
 	var raw rawAlbum
@@ -229,36 +252,20 @@ func (g *Gateway) getAlbumByIDWithRetry(ctx context.Context, id string, isRetry
 }
 
 func (g *Gateway) SearchTracks(ctx context.Context, query string) ([]domain.Track, error) {
-	return g.searchTracksWithRetry(ctx, query, false)
-}
-
-func (g *Gateway) searchTracksWithRetry(ctx context.Context, query string, isRetry bool) ([]domain.Track, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	searchURL := fmt.Sprintf("%s/search?q=%s&type=track&limit=20", apiBaseURL, url.QueryEscape(query))
+	searchURL := fmt.Sprintf("%s/search?q=%s&type=track&limit=20%s", apiBaseURL, url.QueryEscape(query), marketQuery(ctx, "&"))
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.searchTracksWithRetry(ctx, query, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify search: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify search")
 	}
 
 	var result struct {
@@ -278,36 +285,20 @@ func (g *Gateway) searchTracksWithRetry(ctx context.Context, query string, isRet
 }
 
 func (g *Gateway) SearchByISRC(ctx context.Context, isrc string) (*domain.Track, error) {
-	return g.searchByISRCWithRetry(ctx, isrc, false)
-}
-
-func (g *Gateway) searchByISRCWithRetry(ctx context.Context, isrc string, isRetry bool) (*domain.Track, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	searchURL := fmt.Sprintf("%s/search?q=isrc:%s&type=track&limit=1", apiBaseURL, url.QueryEscape(isrc))
+	searchURL := fmt.Sprintf("%s/search?q=isrc:%s&type=track&limit=1%s", apiBaseURL, url.QueryEscape(isrc), marketQuery(ctx, "&"))
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Retry once with fresh token if auth error
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.searchByISRCWithRetry(ctx, isrc, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify search: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify search")
 	}
 
 	var result struct {
@@ -326,36 +317,131 @@ func (g *Gateway) searchByISRCWithRetry(ctx context.Context, isrc string, isRetr
 	return result.Tracks.Items[0].toDomain(), nil
 }
 
-// GetAudioFeatures retrieves audio features for a single track.
-func (g *Gateway) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
-	return g.getAudioFeaturesWithRetry(ctx, trackID, false)
+// SearchAlbumByUPC finds an album by its UPC (or EAN) and returns its full details.
+// It returns nil when no album has the code.
+func (g *Gateway) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	searchURL := fmt.Sprintf("%s/search?q=upc:%s&type=album&limit=1%s", apiBaseURL, url.QueryEscape(upc), marketQuery(ctx, "&"))
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := g.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, transport.StatusError(res, "spotify album search")
+	}
+
+	var result struct {
+		Albums struct {
+			Items []rawAlbum `json:"items"`
+		} `json:"albums"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Albums.Items) == 0 {
+		return nil, nil
+	}
+
+	// Search results are simplified albums without tracks, UPC or popularity
+	return g.GetAlbumByID(ctx, result.Albums.Items[0].ID)
 }
 
-func (g *Gateway) getAudioFeaturesWithRetry(ctx context.Context, trackID string, isRetry bool) (*domain.AudioFeatures, error) {
-	token, err := g.getToken(ctx)
+// GetPlaylist returns a playlist with at most limit of its tracks, following the
+// track pages as needed. Local files and podcast episodes are skipped.
+func (g *Gateway) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/playlists/"+url.PathEscape(id)+marketQuery(ctx, "?"), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/audio-features/"+trackID, nil)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
+	defer res.Body.Close()
 
-	res, err := g.httpc.Do(req)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrPlaylistNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, transport.StatusError(res, "spotify playlist")
+	}
+
+	var raw rawPlaylist
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	playlist := &domain.Playlist{
+		ID:          raw.ID,
+		Name:        raw.Name,
+		Owner:       raw.Owner.DisplayName,
+		URL:         raw.ExternalURLs["spotify"],
+		TotalTracks: raw.Tracks.Total,
+	}
+	playlist.Tracks = raw.Tracks.appendTracks(nil, limit)
+
+	next := raw.Tracks.Next
+	for next != "" && len(playlist.Tracks) < limit {
+		page, err := g.getPlaylistTracksPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		playlist.Tracks = page.appendTracks(playlist.Tracks, limit)
+		next = page.Next
+	}
+	return playlist, nil
+}
+
+// getPlaylistTracksPage fetches the page of playlist tracks at pageURL (a "next" link).
+func (g *Gateway) getPlaylistTracksPage(ctx context.Context, pageURL string) (*rawPlaylistTracks, error) {
+	if !strings.HasPrefix(pageURL, apiBaseURL+"/") {
+		return nil, fmt.Errorf("unexpected playlist page URL: %s", pageURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getAudioFeaturesWithRetry(ctx, trackID, true)
+	if res.StatusCode != http.StatusOK {
+		return nil, transport.StatusError(res, "spotify playlist")
 	}
 
+	var page rawPlaylistTracks
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetAudioFeatures retrieves audio features for a single track.
+func (g *Gateway) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+"/audio-features/"+trackID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := g.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify audio-features: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify audio-features")
 	}
 
 	var raw rawAudioFeatures
@@ -368,10 +454,6 @@ func (g *Gateway) getAudioFeaturesWithRetry(ctx context.Context, trackID string,
 
 // GetAudioFeaturesBatch retrieves audio features for multiple tracks (max 100).
 func (g *Gateway) GetAudioFeaturesBatch(ctx context.Context, trackIDs []string) ([]domain.AudioFeatures, error) {
-	return g.getAudioFeaturesBatchWithRetry(ctx, trackIDs, false)
-}
-
-func (g *Gateway) getAudioFeaturesBatchWithRetry(ctx context.Context, trackIDs []string, isRetry bool) ([]domain.AudioFeatures, error) {
 	if len(trackIDs) == 0 {
 		return []domain.AudioFeatures{}, nil
 	}
@@ -379,32 +461,21 @@ func (g *Gateway) getAudioFeaturesBatchWithRetry(ctx context.Context, trackIDs [
 		trackIDs = trackIDs[:100]
 	}
 
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	ids := strings.Join(trackIDs, ",")
 	reqURL := fmt.Sprintf("%s/audio-features?ids=%s", apiBaseURL, url.QueryEscape(ids))
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getAudioFeaturesBatchWithRetry(ctx, trackIDs, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify audio-features batch: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify audio-features batch")
 	}
 
 	var result struct {
@@ -426,15 +497,6 @@ func (g *Gateway) getAudioFeaturesBatchWithRetry(ctx context.Context, trackIDs [
 
 // GetRecommendations retrieves track recommendations based on seed tracks/artists/genres.
 func (g *Gateway) GetRecommendations(ctx context.Context, params external.RecommendationParams) ([]domain.Track, error) {
-	return g.getRecommendationsWithRetry(ctx, params, false)
-}
-
-func (g *Gateway) getRecommendationsWithRetry(ctx context.Context, params external.RecommendationParams, isRetry bool) ([]domain.Track, error) {
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// Build query parameters
 	q := url.Values{}
 	if len(params.SeedTracks) > 0 {
@@ -488,21 +550,15 @@ func (g *Gateway) getRecommendationsWithRetry(ctx context.Context, params extern
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getRecommendationsWithRetry(ctx, params, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify recommendations: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify recommendations")
 	}
 
 	var result struct {
@@ -531,10 +587,6 @@ func (g *Gateway) GetArtistGenres(ctx context.Context, artistID string) ([]strin
 
 // GetArtistGenresBatch retrieves genres for multiple artists (max 50).
 func (g *Gateway) GetArtistGenresBatch(ctx context.Context, artistIDs []string) (map[string][]string, error) {
-	return g.getArtistGenresBatchWithRetry(ctx, artistIDs, false)
-}
-
-func (g *Gateway) getArtistGenresBatchWithRetry(ctx context.Context, artistIDs []string, isRetry bool) (map[string][]string, error) {
 	if len(artistIDs) == 0 {
 		return map[string][]string{}, nil
 	}
@@ -542,32 +594,21 @@ func (g *Gateway) getArtistGenresBatchWithRetry(ctx context.Context, artistIDs [
 		artistIDs = artistIDs[:50]
 	}
 
-	token, err := g.getToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	ids := strings.Join(artistIDs, ",")
 	reqURL := fmt.Sprintf("%s/artists?ids=%s", apiBaseURL, url.QueryEscape(ids))
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
 
-	res, err := g.httpc.Do(req)
+	res, err := g.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if isAuthError(res.StatusCode) && !isRetry {
-		g.invalidateToken(ctx)
-		return g.getArtistGenresBatchWithRetry(ctx, artistIDs, true)
-	}
-
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("spotify artists batch: status %d", res.StatusCode)
+		return nil, transport.StatusError(res, "spotify artists batch")
 	}
 
 	var result struct {
@@ -3,11 +3,16 @@ package spotify
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 )
 
@@ -321,3 +326,343 @@ func TestGateway_GetToken_WithCache(t *testing.T) {
 		t.Errorf("expected 'cached_token', got '%s'", token)
 	}
 }
+
+// rewriteTransport sends every request to the test server, keeping the path.
+type rewriteTransport struct {
+	target string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(rt.target)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGateway_GetTrackByID_Transport(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		wantErr     bool
+		wantHits    int
+		wantRefresh bool
+	}{
+		{
+			name:     "正常系: 503の後にリトライで成功",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantHits: 2,
+		},
+		{
+			name:        "正常系: 401でトークンを再取得",
+			statuses:    []int{http.StatusUnauthorized, http.StatusOK},
+			wantHits:    2,
+			wantRefresh: true,
+		},
+		{
+			name:     "異常系: 404はリトライしない",
+			statuses: []int{http.StatusNotFound},
+			wantErr:  true,
+			wantHits: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/token" {
+					json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "fresh_token", "expires_in": 3600})
+					return
+				}
+				status := tt.statuses[min(hits, len(tt.statuses)-1)]
+				hits++
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					json.NewEncoder(w).Encode(map[string]interface{}{"id": "track1", "name": "Song"})
+				}
+			}))
+			defer server.Close()
+
+			repo := newMockTokenRepo()
+			repo.tokens["spotify"] = "cached_token"
+			gw := NewGateway("id", "secret", repo).WithRetryPolicy(transport.Policy{
+				MaxRetries: 2,
+				BaseDelay:  time.Millisecond,
+				MaxDelay:   time.Millisecond,
+			})
+			gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			track, err := gw.GetTrackByID(context.Background(), "track1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTrackByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && track.ID != "track1" {
+				t.Errorf("track ID = %s, want track1", track.ID)
+			}
+			if hits != tt.wantHits {
+				t.Errorf("API hits = %d, want %d", hits, tt.wantHits)
+			}
+			if tt.wantRefresh && repo.tokens["spotify"] != "fresh_token" {
+				t.Errorf("token = %s, want fresh_token", repo.tokens["spotify"])
+			}
+		})
+	}
+}
+
+func TestGateway_Market(t *testing.T) {
+	tests := []struct {
+		name       string
+		region     string
+		call       func(g *Gateway, ctx context.Context) error
+		wantMarket string
+	}{
+		{
+			name:       "正常系: 地域指定でmarketを付与（曲）",
+			region:     "TW",
+			call:       func(g *Gateway, ctx context.Context) error { _, err := g.GetTrackByID(ctx, "track1"); return err },
+			wantMarket: "TW",
+		},
+		{
+			name:       "正常系: 地域指定でmarketを付与（ISRC検索）",
+			region:     "HK",
+			call:       func(g *Gateway, ctx context.Context) error { _, err := g.SearchByISRC(ctx, "JPSO00123456"); return err },
+			wantMarket: "HK",
+		},
+		{
+			name:       "正常系: 地域指定でmarketを付与（検索）",
+			region:     "SG",
+			call:       func(g *Gateway, ctx context.Context) error { _, err := g.SearchTracks(ctx, "song"); return err },
+			wantMarket: "SG",
+		},
+		{
+			name:       "正常系: 地域指定でmarketを付与（アルバム）",
+			region:     "MY",
+			call:       func(g *Gateway, ctx context.Context) error { _, err := g.GetAlbumByID(ctx, "album1"); return err },
+			wantMarket: "MY",
+		},
+		{
+			name:       "正常系: 地域指定なしはmarketなし",
+			call:       func(g *Gateway, ctx context.Context) error { _, err := g.GetTrackByID(ctx, "track1"); return err },
+			wantMarket: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				if strings.HasPrefix(r.URL.Path, "/v1/search") {
+					json.NewEncoder(w).Encode(map[string]interface{}{"tracks": map[string]interface{}{"items": []interface{}{}}})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "id1", "name": "Name"})
+			}))
+			defer server.Close()
+
+			repo := newMockTokenRepo()
+			repo.tokens["spotify"] = "cached_token"
+			gw := NewGateway("id", "secret", repo)
+			gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			ctx := context.Background()
+			if tt.region != "" {
+				ctx = domain.WithRegion(ctx, tt.region)
+			}
+			if err := tt.call(gw, ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := query.Get("market"); got != tt.wantMarket {
+				t.Errorf("market = %q, want %q", got, tt.wantMarket)
+			}
+			if query.Has("market") != (tt.wantMarket != "") {
+				t.Errorf("market parameter present = %v, want %v", query.Has("market"), tt.wantMarket != "")
+			}
+		})
+	}
+}
+
+func TestGateway_SearchAlbumByUPC(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []interface{}
+		wantAlbum string
+	}{
+		{
+			name:      "正常系: UPCで一致したアルバムの詳細を取得",
+			items:     []interface{}{map[string]interface{}{"id": "album1", "name": "STRAY SHEEP"}},
+			wantAlbum: "album1",
+		},
+		{
+			name:  "正常系: 一致なしはnil",
+			items: []interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searchQuery url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/v1/search") {
+					searchQuery = r.URL.Query()
+					json.NewEncoder(w).Encode(map[string]interface{}{"albums": map[string]interface{}{"items": tt.items}})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":           strings.TrimPrefix(r.URL.Path, "/v1/albums/"),
+					"name":         "STRAY SHEEP",
+					"external_ids": map[string]string{"upc": "4547366473086"},
+				})
+			}))
+			defer server.Close()
+
+			repo := newMockTokenRepo()
+			repo.tokens["spotify"] = "cached_token"
+			gw := NewGateway("id", "secret", repo)
+			gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			album, err := gw.SearchAlbumByUPC(context.Background(), "4547366473086")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := searchQuery.Get("q"); got != "upc:4547366473086" {
+				t.Errorf("q = %q, want upc:4547366473086", got)
+			}
+			if got := searchQuery.Get("type"); got != "album" {
+				t.Errorf("type = %q, want album", got)
+			}
+			if tt.wantAlbum == "" {
+				if album != nil {
+					t.Errorf("expected nil album, got %+v", album)
+				}
+				return
+			}
+			if album == nil || album.ID != tt.wantAlbum {
+				t.Fatalf("album = %+v, want %s", album, tt.wantAlbum)
+			}
+			if album.UPC == nil || *album.UPC != "4547366473086" {
+				t.Errorf("UPC = %v, want 4547366473086", album.UPC)
+			}
+		})
+	}
+}
+
+func TestGateway_GetPlaylist(t *testing.T) {
+	track := func(id, typ string) map[string]interface{} {
+		return map[string]interface{}{"track": map[string]interface{}{"id": id, "name": "Track " + id, "type": typ}}
+	}
+	tests := []struct {
+		name      string
+		status    int
+		limit     int
+		wantIDs   []string
+		wantPages int
+		wantErr   error
+	}{
+		{
+			name:      "正常系: ページをたどりローカルファイルとエピソードを除外",
+			status:    http.StatusOK,
+			limit:     10,
+			wantIDs:   []string{"t1", "t2", "t3"},
+			wantPages: 2,
+		},
+		{
+			name:      "正常系: limitで打ち切り次のページは取得しない",
+			status:    http.StatusOK,
+			limit:     2,
+			wantIDs:   []string{"t1", "t2"},
+			wantPages: 1,
+		},
+		{
+			name:    "異常系: 見つからない",
+			status:  http.StatusNotFound,
+			limit:   10,
+			wantErr: domain.ErrPlaylistNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pages++
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+				if r.URL.Path == "/v1/playlists/pl1/tracks" {
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"items": []interface{}{track("t3", "track"), map[string]interface{}{"track": nil}},
+						"total": 5,
+					})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":    "pl1",
+					"name":  "My Playlist",
+					"owner": map[string]string{"display_name": "curator"},
+					"tracks": map[string]interface{}{
+						"items": []interface{}{
+							track("t1", "track"),
+							map[string]interface{}{"is_local": true, "track": map[string]interface{}{"id": "", "type": "track"}},
+							track("ep1", "episode"),
+							track("t2", "track"),
+						},
+						"next":  "https://api.spotify.com/v1/playlists/pl1/tracks?offset=4&limit=4",
+						"total": 6,
+					},
+				})
+			}))
+			defer server.Close()
+
+			repo := newMockTokenRepo()
+			repo.tokens["spotify"] = "cached_token"
+			gw := NewGateway("id", "secret", repo)
+			gw.httpc = &http.Client{Transport: rewriteTransport{target: server.URL}}
+
+			playlist, err := gw.GetPlaylist(context.Background(), "pl1", tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if playlist.Name != "My Playlist" || playlist.Owner != "curator" || playlist.TotalTracks != 6 {
+				t.Errorf("unexpected playlist: %+v", playlist)
+			}
+			var ids []string
+			for _, tr := range playlist.Tracks {
+				ids = append(ids, tr.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("tracks = %v, want %v", ids, tt.wantIDs)
+			}
+			if pages != tt.wantPages {
+				t.Errorf("requested %d pages, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestRawTrack_ToDomain_IsPlayable(t *testing.T) {
+	var raw rawTrack
+	if err := json.Unmarshal([]byte(`{"id":"t1","is_playable":false}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	track := raw.toDomain()
+	if track.IsPlayable == nil || *track.IsPlayable || track.Playable() {
+		t.Errorf("expected unplayable track, got %v", track.IsPlayable)
+	}
+
+	raw = rawTrack{}
+	if err := json.Unmarshal([]byte(`{"id":"t2"}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if track := raw.toDomain(); track.IsPlayable != nil || !track.Playable() {
+		t.Errorf("expected unknown availability to count as playable, got %v", track.IsPlayable)
+	}
+}
@@ -13,6 +13,7 @@ type rawTrack struct {
 	} `json:"external_ids"`
 	ExternalURLs map[string]string `json:"external_urls"`
 	ID           string            `json:"id"`
+	IsPlayable   *bool             `json:"is_playable"` // Only present when a market was given
 	Name         string            `json:"name"`
 	Popularity   int               `json:"popularity"`
 	TrackNumber  int               `json:"track_number"`
@@ -40,6 +41,7 @@ func (r *rawTrack) toDomain() *domain.Track {
 		Explicit:    r.Explicit,
 		Artists:     artists,
 		Album:       *album,
+		IsPlayable:  r.IsPlayable,
 	}
 
 	if r.Popularity > 0 {
@@ -220,3 +222,39 @@ func (r *rawAudioFeatures) toDomain() *domain.AudioFeatures {
 		DurationMs:       r.DurationMs,
 	}
 }
+
+type rawPlaylist struct {
+	ExternalURLs map[string]string `json:"external_urls"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Owner        struct {
+		DisplayName string `json:"display_name"`
+	} `json:"owner"`
+	Tracks rawPlaylistTracks `json:"tracks"`
+}
+
+type rawPlaylistTracks struct {
+	Items []struct {
+		IsLocal bool `json:"is_local"`
+		Track   *struct {
+			rawTrack
+			Type string `json:"type"` // "track" or "episode"
+		} `json:"track"` // Null for removed tracks
+	} `json:"items"`
+	Next  string `json:"next"`
+	Total int    `json:"total"`
+}
+
+// appendTracks adds the playable tracks of the page to tracks, up to limit.
+func (r *rawPlaylistTracks) appendTracks(tracks []domain.Track, limit int) []domain.Track {
+	for _, item := range r.Items {
+		if len(tracks) >= limit {
+			break
+		}
+		if item.IsLocal || item.Track == nil || item.Track.Type != "track" || item.Track.ID == "" {
+			continue
+		}
+		tracks = append(tracks, *item.Track.toDomain())
+	}
+	return tracks
+}
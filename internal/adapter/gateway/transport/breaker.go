@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// ErrCircuitOpen is returned without calling the upstream while its breaker is open.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker open: %w", domain.ErrUpstreamUnavailable)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every request until OpenTimeout has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets a few probe requests through to test recovery.
+	BreakerHalfOpen
+)
+
+// String returns the state name used in logs and /healthz.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig configures a circuit breaker.
+type BreakerConfig struct {
+	FailureThreshold int           // Consecutive failures that open the breaker
+	OpenTimeout      time.Duration // Time to stay open before probing
+	HalfOpenRequests int           // Probe requests allowed while half-open
+}
+
+// DefaultBreakerConfig returns the default circuit breaker settings.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// Breaker is a circuit breaker for one upstream.
+// It opens after consecutive failures (transport errors and 5xx) so callers
+// fail fast instead of waiting for timeouts, and closes again once a probe
+// succeeds. A nil *Breaker lets every request through.
+type Breaker struct {
+	name string
+	cfg  BreakerConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	gen      uint64 // Incremented on every state change
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// Permit is handed out by Allow for one request and passed back to Record.
+// Outcomes of requests admitted before the last state change are ignored, so
+// only the admitted probes decide whether a half-open breaker closes or reopens.
+type Permit struct {
+	gen   uint64
+	probe bool
+}
+
+// NewBreaker creates a closed breaker for the named upstream.
+func NewBreaker(name string, cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	return &Breaker{name: name, cfg: cfg, now: time.Now}
+}
+
+// Name returns the upstream name.
+func (b *Breaker) Name() string {
+	if b == nil {
+		return ""
+	}
+	return b.name
+}
+
+// State returns the current state.
+func (b *Breaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Status returns the state name.
+func (b *Breaker) Status() string {
+	return b.State().String()
+}
+
+// Allow reports whether a request may be sent.
+// Every allowed request must be followed by exactly one call to Record with the permit.
+func (b *Breaker) Allow() (Permit, error) {
+	if b == nil {
+		return Permit{}, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if elapsed := b.now().Sub(b.openedAt); elapsed < b.cfg.OpenTimeout {
+			return Permit{}, circuitOpenError(b.name, b.cfg.OpenTimeout-elapsed)
+		}
+		b.setStateLocked(BreakerHalfOpen)
+		b.probes = 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= b.cfg.HalfOpenRequests {
+			return Permit{}, circuitOpenError(b.name, 0)
+		}
+		b.probes++
+		return Permit{gen: b.gen, probe: true}, nil
+	}
+	return Permit{gen: b.gen}, nil
+}
+
+// Record reports the outcome of the request allowed with p.
+// Errors caused by the caller's own context and 429 responses count neither way:
+// a rate limited upstream is up, but its answer says nothing about recovery.
+func (b *Breaker) Record(ctx context.Context, p Permit, resp *http.Response, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p.gen != b.gen {
+		return // Admitted before the last state change
+	}
+
+	switch {
+	case err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)),
+		err == nil && resp.StatusCode == http.StatusTooManyRequests:
+		if p.probe && b.probes > 0 {
+			b.probes-- // Free the slot for another probe
+		}
+	case err != nil || resp.StatusCode >= 500:
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+			b.openedAt = b.now()
+			b.setStateLocked(BreakerOpen)
+		}
+	default:
+		b.failures = 0
+		b.setStateLocked(BreakerClosed)
+	}
+}
+
+// setStateLocked changes the state and logs the transition.
+// Callers must hold b.mu.
+func (b *Breaker) setStateLocked(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	msg := fmt.Sprintf("Circuit breaker %s -> %s", b.state, state)
+	if state == BreakerOpen {
+		logger.Warning(b.name, fmt.Sprintf("%s after %d consecutive failures", msg, b.failures))
+	} else {
+		logger.Info(b.name, msg)
+	}
+	b.state = state
+	b.gen++
+}
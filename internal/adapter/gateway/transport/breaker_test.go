@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// fakeClock is a manually advanced clock for breaker tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(threshold int) (*Breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := NewBreaker("test", BreakerConfig{FailureThreshold: threshold, OpenTimeout: time.Minute, HalfOpenRequests: 1})
+	b.now = clock.now
+	return b, clock
+}
+
+// allow asks b for a permit and fails the test if the request is rejected.
+func allow(t *testing.T, b *Breaker) Permit {
+	t.Helper()
+	p, err := b.Allow()
+	if err != nil {
+		t.Fatalf("unexpected reject in %s: %v", b.State(), err)
+	}
+	return p
+}
+
+func record(b *Breaker, p Permit, status int) {
+	b.Record(context.Background(), p, &http.Response{StatusCode: status}, nil)
+}
+
+func TestBreaker_Transitions(t *testing.T) {
+	b, clock := newTestBreaker(3)
+
+	// Failures below the threshold keep it closed, and a success resets the count
+	for _, status := range []int{500, 502, 200, 500, 503} {
+		record(b, allow(t, b), status)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed, got %s", b.State())
+	}
+
+	// 4xx is the caller's problem, not an outage
+	record(b, allow(t, b), 404)
+	for i := 0; i < 3; i++ {
+		record(b, allow(t, b), 500)
+	}
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open, got %s", b.State())
+	}
+
+	_, err := b.Allow()
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+	var de *domain.Error
+	if !errors.As(err, &de) || de.RetryAfter != time.Minute {
+		t.Fatalf("expected Retry-After of the remaining open time, got %v", err)
+	}
+
+	// After the timeout a single probe is let through
+	clock.advance(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half_open, got %s", b.State())
+	}
+	probe := allow(t, b)
+	if _, err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe should be rejected, got %v", err)
+	}
+
+	// A failed probe opens it again
+	record(b, probe, 500)
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after failed probe, got %s", b.State())
+	}
+
+	// A successful probe closes it
+	clock.advance(time.Minute)
+	record(b, allow(t, b), 200)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed after successful probe, got %s", b.State())
+	}
+}
+
+func TestBreaker_HalfOpenDecidedByProbes(t *testing.T) {
+	tests := []struct {
+		name      string
+		late      int // Status of a request admitted before the breaker opened, 0 for none
+		probe     int
+		wantState BreakerState
+	}{
+		{name: "正常系: 開く前の成功では閉じない", late: 200, wantState: BreakerHalfOpen},
+		{name: "正常系: 開く前の失敗では開き直さない", late: 500, wantState: BreakerHalfOpen},
+		{name: "正常系: 429のプローブでは閉じない", probe: 429, wantState: BreakerHalfOpen},
+		{name: "正常系: プローブの成功で閉じる", late: 200, probe: 200, wantState: BreakerClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clock := newTestBreaker(1)
+			late := allow(t, b)
+			record(b, allow(t, b), 500)
+			clock.advance(time.Minute)
+
+			probe := allow(t, b)
+			if tt.late != 0 {
+				record(b, late, tt.late)
+			}
+			if tt.probe != 0 {
+				record(b, probe, tt.probe)
+			}
+			if b.State() != tt.wantState {
+				t.Fatalf("expected %s, got %s", tt.wantState, b.State())
+			}
+		})
+	}
+}
+
+func TestBreaker_TooManyRequestsCountsNeitherWay(t *testing.T) {
+	b, _ := newTestBreaker(2)
+	record(b, allow(t, b), 500)
+	record(b, allow(t, b), 429)
+	record(b, allow(t, b), 500)
+	if b.State() != BreakerOpen {
+		t.Fatalf("429 should not reset the failure count, got %s", b.State())
+	}
+}
+
+func TestBreaker_CallerCancelReleasesProbe(t *testing.T) {
+	b, clock := newTestBreaker(1)
+	record(b, allow(t, b), 500)
+	clock.advance(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Record(ctx, allow(t, b), nil, context.Canceled)
+
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half_open, got %s", b.State())
+	}
+	if _, err := b.Allow(); err != nil {
+		t.Fatalf("probe slot should have been released: %v", err)
+	}
+}
+
+func TestBreaker_Nil(t *testing.T) {
+	var b *Breaker
+	p, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Record(context.Background(), p, nil, errors.New("boom"))
+	if b.Status() != "closed" {
+		t.Errorf("expected closed, got %s", b.Status())
+	}
+}
+
+func TestClient_Do_CircuitOpen(t *testing.T) {
+	srv, hits := sequenceServer(t, []int{http.StatusServiceUnavailable}, nil)
+	b, _ := newTestBreaker(2)
+	c := &Client{Name: "test", HTTP: srv.Client(), Breaker: b}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+	if *hits != 2 {
+		t.Errorf("expected 2 upstream hits, got %d", *hits)
+	}
+}
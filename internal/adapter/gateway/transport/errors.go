@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// StatusError returns a typed error for an unexpected upstream status.
+// prefix names the call, e.g. "spotify search".
+//
+//   - 429 is domain.KindRateLimited and 5xx is domain.KindUnavailable,
+//     both carrying the upstream's Retry-After, if any.
+//   - 404 is domain.KindNotFound; callers that know what was missing
+//     should return a more specific sentinel instead.
+//   - Any other status is left unclassified.
+func StatusError(resp *http.Response, prefix string) error {
+	msg := fmt.Sprintf("%s: status %d", prefix, resp.StatusCode)
+	delay, _ := retryAfter(resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &domain.Error{
+			Kind:       domain.KindRateLimited,
+			Code:       domain.ErrUpstreamRateLimited.Code,
+			Message:    msg,
+			RetryAfter: delay,
+		}
+	case resp.StatusCode >= 500:
+		return &domain.Error{
+			Kind:       domain.KindUnavailable,
+			Code:       domain.ErrUpstreamUnavailable.Code,
+			Message:    msg,
+			RetryAfter: delay,
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return &domain.Error{Kind: domain.KindNotFound, Message: msg}
+	default:
+		return fmt.Errorf("%s", msg)
+	}
+}
+
+// circuitOpenError is returned by Allow while the breaker rejects requests.
+func circuitOpenError(name string, retryAfter time.Duration) error {
+	return &domain.Error{
+		Kind:       domain.KindUnavailable,
+		Code:       domain.ErrUpstreamUnavailable.Code,
+		Message:    name,
+		RetryAfter: retryAfter,
+		Err:        ErrCircuitOpen,
+	}
+}
@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		wantKind   domain.ErrorKind
+		wantIs     error
+		wantDelay  time.Duration
+	}{
+		{name: "429 with Retry-After", status: 429, retryAfter: "3", wantKind: domain.KindRateLimited, wantIs: domain.ErrUpstreamRateLimited, wantDelay: 3 * time.Second},
+		{name: "503", status: 503, wantKind: domain.KindUnavailable, wantIs: domain.ErrUpstreamUnavailable},
+		{name: "404", status: 404, wantKind: domain.KindNotFound, wantIs: domain.ErrNotFound},
+		{name: "400 is unclassified", status: 400, wantKind: domain.KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := StatusError(resp, "test")
+			if got := domain.KindOf(err); got != tt.wantKind {
+				t.Errorf("KindOf() = %s, want %s", got, tt.wantKind)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("expected errors.Is(%v, %v)", err, tt.wantIs)
+			}
+			if got := domain.AsError(err).RetryAfter; got != tt.wantDelay {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantDelay)
+			}
+		})
+	}
+}
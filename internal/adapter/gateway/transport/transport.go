@@ -0,0 +1,330 @@
+// Package transport provides the shared HTTP layer used by every gateway.
+// It sends requests through the upstream scheduler and retries transient
+// failures (429, 5xx, timeouts) with exponential backoff and jitter,
+// honouring Retry-After. It also refreshes the access token once on an auth error,
+// and fails fast while the upstream's circuit breaker is open.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
+)
+
+// Policy configures retries for one upstream.
+// The zero value disables retries.
+type Policy struct {
+	MaxRetries    int           // Retries after the first attempt
+	BaseDelay     time.Duration // Backoff before the first retry
+	MaxDelay      time.Duration // Upper bound of a single backoff
+	MaxRetryAfter time.Duration // A longer Retry-After is not waited for
+}
+
+// DefaultPolicy returns the default retry policy.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxRetries:    2,
+		BaseDelay:     200 * time.Millisecond,
+		MaxDelay:      2 * time.Second,
+		MaxRetryAfter: 5 * time.Second,
+	}
+}
+
+// Authenticator supplies access tokens for an upstream.
+type Authenticator interface {
+	// Token returns a (possibly cached) access token.
+	Token(ctx context.Context) (string, error)
+	// Invalidate drops the cached token so the next Token call fetches a new one.
+	Invalidate(ctx context.Context)
+}
+
+// Client sends gateway requests. It is cheap to construct per call.
+type Client struct {
+	Name      string // Upstream name used in logs
+	HTTP      *http.Client
+	Scheduler *scheduler.Scheduler // Optional
+	Policy    Policy
+	Auth      Authenticator // Optional: sets "Authorization: Bearer <token>"
+	Breaker   *Breaker      // Optional
+
+	// IsAuthError reports whether a status means the token was rejected.
+	// Defaults to 401 Unauthorized.
+	IsAuthError func(statusCode int) bool
+
+	// PropagateTrace sends the W3C trace context headers (traceparent) so the
+	// upstream can join the trace. Only set it for our own services.
+	PropagateTrace bool
+}
+
+// Do sends req, retrying according to the policy.
+// The request must be replayable (no body, or GetBody set).
+// When retries are exhausted the last response is returned as-is,
+// so callers keep handling non-2xx statuses themselves.
+// While the breaker is open, Do returns an error wrapping ErrCircuitOpen
+// without sending anything.
+// Log records written with the request's context carry the upstream name,
+// and each call is traced as one client span; retries are span events.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(logger.WithUpstream(req.Context(), c.Name), "HTTP "+req.Method+" "+c.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			// The query is left out: some upstreams take the API key there
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("tracktaste.upstream", c.upstream()),
+		))
+	req = req.WithContext(ctx)
+
+	permit, err := c.Breaker.Allow()
+	if err != nil {
+		metrics.UpstreamRequests.Inc(c.upstream(), "circuit_open")
+		tracing.End(span, err)
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := c.do(req)
+	c.Breaker.Record(req.Context(), permit, resp, err)
+
+	metrics.UpstreamDuration.Observe(metrics.Since(start), c.upstream())
+	if err != nil {
+		metrics.UpstreamRequests.Inc(c.upstream(), "error")
+		tracing.End(span, err)
+		return nil, err
+	}
+	metrics.UpstreamRequests.Inc(c.upstream(), metrics.StatusClass(resp.StatusCode))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		tracing.End(span, fmt.Errorf("%s: status %d", c.Name, resp.StatusCode))
+		return resp, nil
+	}
+	span.End()
+	return resp, nil
+}
+
+// Probe sends one GET to url and returns an error unless the upstream answers 2xx.
+// It neither retries nor consults the circuit breaker, so health checks see
+// the upstream as it is; the scheduler still applies.
+func (c Client) Probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	c.Policy = Policy{}
+	c.Breaker = nil
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return StatusError(resp, strings.ToLower(c.Name)+" probe")
+	}
+	return nil
+}
+
+// upstream returns the upstream label of metrics: the breaker or scheduler
+// name shared with /healthz and the rate limiter, or the lowercased Name.
+func (c *Client) upstream() string {
+	if name := c.Breaker.Name(); name != "" {
+		return name
+	}
+	if name := c.Scheduler.Name(); name != "" {
+		return name
+	}
+	return strings.ToLower(c.Name)
+}
+
+// do sends req with retries.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	authRetried := false
+
+	for attempt := 0; ; attempt++ {
+		r, err := c.prepare(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.Scheduler.DoRequest(c.HTTP, r)
+		if err != nil {
+			if !isRetryableError(ctx, err) || !c.wait(ctx, attempt, c.backoff(attempt)) {
+				return nil, err
+			}
+			logger.DebugContext(ctx, c.Name, fmt.Sprintf("Retrying after error: %v", err))
+			metrics.UpstreamRetries.Inc(c.upstream(), "error")
+			trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attribute.String("reason", "error")))
+			continue
+		}
+
+		if c.Auth != nil && c.isAuthError(resp.StatusCode) && !authRetried {
+			// Refresh the token once; this does not count against the retry budget
+			resp.Body.Close()
+			c.Auth.Invalidate(ctx)
+			authRetried = true
+			attempt--
+			continue
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay, ok := retryAfter(resp.Header)
+		if ok && delay > c.Policy.MaxRetryAfter {
+			return resp, nil
+		}
+		if !ok {
+			delay = c.backoff(attempt)
+		}
+		if !c.wait(ctx, attempt, delay) {
+			return resp, nil
+		}
+		logger.DebugContext(ctx, c.Name, fmt.Sprintf("Retrying after status %d (wait %v)", resp.StatusCode, delay))
+		metrics.UpstreamRetries.Inc(c.upstream(), strconv.Itoa(resp.StatusCode))
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.String("reason", strconv.Itoa(resp.StatusCode)),
+			attribute.String("wait", delay.String()),
+		))
+		resp.Body.Close()
+	}
+}
+
+// prepare clones req for one attempt and sets the current token.
+func (c *Client) prepare(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	if c.Auth != nil {
+		token, err := c.Auth.Token(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.PropagateTrace {
+		tracing.Inject(r.Context(), propagation.HeaderCarrier(r.Header))
+	}
+	return r, nil
+}
+
+func (c *Client) isAuthError(statusCode int) bool {
+	if c.IsAuthError != nil {
+		return c.IsAuthError(statusCode)
+	}
+	return statusCode == http.StatusUnauthorized
+}
+
+// backoff returns the exponential backoff with full jitter for attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.Policy.BaseDelay <= 0 {
+		return 0
+	}
+	d := c.Policy.BaseDelay << uint(attempt)
+	if c.Policy.MaxDelay > 0 && (d > c.Policy.MaxDelay || d <= 0) {
+		d = c.Policy.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// wait sleeps before retry number attempt+1.
+// It returns false when no retry should be made: the policy or the request's
+// retry budget is exhausted, or the wait would outlive the context.
+func (c *Client) wait(ctx context.Context, attempt int, delay time.Duration) bool {
+	if attempt >= c.Policy.MaxRetries {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	if !takeBudget(ctx) {
+		logger.DebugContext(ctx, c.Name, "Retry budget exhausted")
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// isRetryableStatus reports whether a status is worth retrying.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// isRetryableError reports whether a transport error is a timeout
+// that was not caused by the caller's own context.
+func isRetryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryAfter parses a Retry-After header (seconds or HTTP date).
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+type budgetKey struct{}
+
+// budget is the number of retries left for one incoming request.
+type budget struct {
+	remaining int64
+}
+
+// WithRetryBudget limits the total retries of all upstream calls made with ctx.
+// Without a budget, retries are bounded only by each upstream's policy.
+func WithRetryBudget(ctx context.Context, retries int) context.Context {
+	return context.WithValue(ctx, budgetKey{}, &budget{remaining: int64(retries)})
+}
+
+// takeBudget consumes one retry from the request budget, if any.
+func takeBudget(ctx context.Context) bool {
+	b, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return true
+	}
+	return atomic.AddInt64(&b.remaining, -1) >= 0
+}
@@ -0,0 +1,461 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
+)
+
+// fastPolicy retries quickly so tests stay fast.
+func fastPolicy(retries int) Policy {
+	return Policy{
+		MaxRetries:    retries,
+		BaseDelay:     time.Millisecond,
+		MaxDelay:      5 * time.Millisecond,
+		MaxRetryAfter: time.Second,
+	}
+}
+
+// sequenceServer replies with the given statuses in order, repeating the last one.
+func sequenceServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&hits, 1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statuses[n])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+type mockAuth struct {
+	tokens      []string
+	calls       int
+	invalidated int
+}
+
+func (a *mockAuth) Token(ctx context.Context) (string, error) {
+	token := a.tokens[a.calls]
+	if a.calls < len(a.tokens)-1 {
+		a.calls++
+	}
+	return token, nil
+}
+
+func (a *mockAuth) Invalidate(ctx context.Context) {
+	a.invalidated++
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		header     http.Header
+		policy     Policy
+		wantStatus int
+		wantHits   int32
+	}{
+		{
+			name:       "正常系: 503の後に成功",
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusOK},
+			policy:     fastPolicy(2),
+			wantStatus: http.StatusOK,
+			wantHits:   2,
+		},
+		{
+			name:       "正常系: 429をRetry-Afterに従ってリトライ",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			header:     http.Header{"Retry-After": []string{"0"}},
+			policy:     fastPolicy(2),
+			wantStatus: http.StatusOK,
+			wantHits:   2,
+		},
+		{
+			name:       "異常系: Retry-Afterが長すぎる場合は待たない",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			header:     http.Header{"Retry-After": []string{"120"}},
+			policy:     fastPolicy(2),
+			wantStatus: http.StatusTooManyRequests,
+			wantHits:   1,
+		},
+		{
+			name:       "異常系: リトライ回数を使い切る",
+			statuses:   []int{http.StatusInternalServerError},
+			policy:     fastPolicy(2),
+			wantStatus: http.StatusInternalServerError,
+			wantHits:   3,
+		},
+		{
+			name:       "正常系: 4xxはリトライしない",
+			statuses:   []int{http.StatusNotFound, http.StatusOK},
+			policy:     fastPolicy(2),
+			wantStatus: http.StatusNotFound,
+			wantHits:   1,
+		},
+		{
+			name:       "正常系: ゼロ値のポリシーはリトライしない",
+			statuses:   []int{http.StatusBadGateway, http.StatusOK},
+			policy:     Policy{},
+			wantStatus: http.StatusBadGateway,
+			wantHits:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := sequenceServer(t, tt.statuses, tt.header)
+			c := &Client{Name: "test", HTTP: srv.Client(), Policy: tt.policy}
+
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(hits); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RefreshTokenOnce(t *testing.T) {
+	var gotTokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		gotTokens = append(gotTokens, auth)
+		if auth != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("refreshes an expired token", func(t *testing.T) {
+		gotTokens = nil
+		auth := &mockAuth{tokens: []string{"expired", "fresh"}}
+		c := &Client{Name: "test", HTTP: srv.Client(), Policy: fastPolicy(0), Auth: auth}
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+		}
+		if auth.invalidated != 1 {
+			t.Errorf("invalidated = %d, want 1", auth.invalidated)
+		}
+		if len(gotTokens) != 2 || gotTokens[1] != "Bearer fresh" {
+			t.Errorf("tokens sent = %v", gotTokens)
+		}
+	})
+
+	t.Run("gives up after one refresh", func(t *testing.T) {
+		gotTokens = nil
+		auth := &mockAuth{tokens: []string{"bad", "still-bad"}}
+		c := &Client{Name: "test", HTTP: srv.Client(), Policy: fastPolicy(2), Auth: auth}
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("StatusCode = %d, want 401", resp.StatusCode)
+		}
+		if len(gotTokens) != 2 {
+			t.Errorf("requests = %d, want 2", len(gotTokens))
+		}
+	})
+
+	t.Run("custom auth error check", func(t *testing.T) {
+		badRequest, hits := sequenceServer(t, []int{http.StatusBadRequest, http.StatusOK}, nil)
+		auth := &mockAuth{tokens: []string{"a", "b"}}
+		c := &Client{
+			Name:        "test",
+			HTTP:        badRequest.Client(),
+			Auth:        auth,
+			IsAuthError: func(code int) bool { return code == http.StatusUnauthorized || code == http.StatusBadRequest },
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, badRequest.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || atomic.LoadInt32(hits) != 2 {
+			t.Errorf("StatusCode = %d, hits = %d", resp.StatusCode, atomic.LoadInt32(hits))
+		}
+	})
+}
+
+func TestClient_Do_RetryBudget(t *testing.T) {
+	srv, hits := sequenceServer(t, []int{http.StatusServiceUnavailable}, nil)
+	c := &Client{Name: "test", HTTP: srv.Client(), Policy: fastPolicy(5)}
+	ctx := WithRetryBudget(context.Background(), 2)
+
+	// First call spends the whole budget
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if got := atomic.LoadInt32(hits); got != 3 {
+		t.Errorf("first call hits = %d, want 3", got)
+	}
+
+	// Second call in the same request gets no retries
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if got := atomic.LoadInt32(hits); got != 4 {
+		t.Errorf("total hits = %d, want 4", got)
+	}
+}
+
+func TestClient_Do_RetryTimeout(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	httpc := srv.Client()
+	httpc.Timeout = 30 * time.Millisecond
+	c := &Client{Name: "test", HTTP: httpc, Policy: fastPolicy(1)}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("server hits = %d, want 2", got)
+	}
+}
+
+func TestClient_Do_StopsAtDeadline(t *testing.T) {
+	// Retry-After fixes the wait; a jittered backoff could be shorter than the deadline
+	srv, hits := sequenceServer(t, []int{http.StatusServiceUnavailable}, http.Header{"Retry-After": {"1"}})
+	policy := Policy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Second, MaxRetryAfter: 2 * time.Second}
+	c := &Client{Name: "test", HTTP: srv.Client(), Policy: policy}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	start := time.Now()
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Do() took %v, should not wait past the deadline", elapsed)
+	}
+	if got := atomic.LoadInt32(hits); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "未設定", value: "", wantOK: false},
+		{name: "秒数", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "過去の日時", value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOK: true},
+		{name: "不正な値", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.value != "" {
+				h.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfter(h)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestClient_Backoff(t *testing.T) {
+	c := &Client{Policy: Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		d := c.backoff(attempt)
+		if d <= 0 || d > 300*time.Millisecond {
+			t.Errorf("backoff(%d) = %v, want within (0, 300ms]", attempt, d)
+		}
+	}
+}
+
+func TestClient_Do_RecordsMetrics(t *testing.T) {
+	srv, _ := sequenceServer(t, []int{http.StatusServiceUnavailable, http.StatusOK}, nil)
+	c := &Client{Name: "MetricsTest", HTTP: srv.Client(), Policy: fastPolicy(1)}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := metrics.UpstreamRequests.Value("metricstest", "2xx"); got != 1 {
+		t.Errorf("expected 1 request with 2xx, got %v", got)
+	}
+	if got := metrics.UpstreamRetries.Value("metricstest", "503"); got != 1 {
+		t.Errorf("expected 1 retry after 503, got %v", got)
+	}
+	if got := metrics.UpstreamDuration.Count("metricstest"); got != 1 {
+		t.Errorf("expected 1 latency observation, got %d", got)
+	}
+}
+
+func TestClient_Do_Tracing(t *testing.T) {
+	tests := []struct {
+		name            string
+		propagate       bool
+		wantTraceparent bool
+	}{
+		{name: "正常系: 外部APIにはtraceparentを送らない", propagate: false, wantTraceparent: false},
+		{name: "正常系: PropagateTraceならtraceparentを送る", propagate: true, wantTraceparent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, restore := tracing.SetupInMemory()
+			defer restore()
+
+			var statuses int32
+			var traceparent atomic.Value
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceparent.Store(r.Header.Get("traceparent"))
+				if atomic.AddInt32(&statuses, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			c := &Client{Name: "TraceTest", HTTP: srv.Client(), Policy: fastPolicy(1), PropagateTrace: tt.propagate}
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/2.0/?api_key=secret", nil)
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			span := spans[0]
+			attrs := make(map[string]string)
+			for _, kv := range span.Attributes {
+				attrs[string(kv.Key)] = kv.Value.Emit()
+			}
+			if attrs["url.path"] != "/2.0/" {
+				t.Errorf("expected url.path /2.0/, got %q", attrs["url.path"])
+			}
+			if attrs["http.response.status_code"] != "200" {
+				t.Errorf("expected status 200, got %q", attrs["http.response.status_code"])
+			}
+			for _, v := range attrs {
+				if strings.Contains(v, "secret") {
+					t.Errorf("span attribute leaks the query: %q", v)
+				}
+			}
+			if len(span.Events) != 1 || span.Events[0].Name != "retry" {
+				t.Errorf("expected one retry event, got %v", span.Events)
+			}
+
+			got, _ := traceparent.Load().(string)
+			if (got != "") != tt.wantTraceparent {
+				t.Errorf("traceparent = %q, want sent = %v", got, tt.wantTraceparent)
+			}
+			if tt.wantTraceparent && !strings.Contains(got, span.SpanContext.TraceID().String()) {
+				t.Errorf("traceparent %q does not carry trace %s", got, span.SpanContext.TraceID())
+			}
+		})
+	}
+}
+
+func TestClient_Probe(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantErr  bool
+		wantHits int32
+	}{
+		{name: "正常系: 200", statuses: []int{http.StatusOK}, wantErr: false, wantHits: 1},
+		{name: "異常系: 503はリトライしない", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, wantErr: true, wantHits: 1},
+		{name: "異常系: 404", statuses: []int{http.StatusNotFound}, wantErr: true, wantHits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := sequenceServer(t, tt.statuses, nil)
+			// An open breaker must not hide the upstream from health checks
+			b := NewBreaker("probetest", BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour, HalfOpenRequests: 1})
+			p, _ := b.Allow()
+			b.Record(context.Background(), p, nil, errors.New("down"))
+			c := Client{Name: "ProbeTest", HTTP: srv.Client(), Policy: fastPolicy(3), Breaker: b}
+
+			err := c.Probe(context.Background(), srv.URL+"/health")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(hits); got != tt.wantHits {
+				t.Errorf("expected %d request(s), got %d", tt.wantHits, got)
+			}
+		})
+	}
+}
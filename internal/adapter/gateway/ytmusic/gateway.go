@@ -9,8 +9,10 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
 )
 
 const featureName = "YTMusic"
@@ -19,6 +21,9 @@ const featureName = "YTMusic"
 type Gateway struct {
 	baseURL    string
 	httpClient *http.Client
+	scheduler  *scheduler.Scheduler
+	retry      transport.Policy
+	breaker    *transport.Breaker
 }
 
 // NewGateway creates a new YouTube Music gateway.
@@ -29,9 +34,47 @@ func NewGateway(baseURL string) *Gateway {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: transport.DefaultPolicy(),
 	}
 }
 
+// WithScheduler routes API calls through the shared upstream scheduler.
+// Without a scheduler, calls are sent immediately.
+func (g *Gateway) WithScheduler(s *scheduler.Scheduler) *Gateway {
+	g.scheduler = s
+	return g
+}
+
+// WithRetryPolicy sets how transient failures (429, 5xx, timeouts) are retried.
+func (g *Gateway) WithRetryPolicy(p transport.Policy) *Gateway {
+	g.retry = p
+	return g
+}
+
+// WithCircuitBreaker sets the circuit breaker shared by calls to this upstream.
+func (g *Gateway) WithCircuitBreaker(b *transport.Breaker) *Gateway {
+	g.breaker = b
+	return g
+}
+
+// client returns the transport used for API calls.
+func (g *Gateway) client() *transport.Client {
+	return &transport.Client{
+		Name:      featureName,
+		HTTP:      g.httpClient,
+		Scheduler: g.scheduler,
+		Policy:    g.retry,
+		Breaker:   g.breaker,
+		// The sidecar is ours, so it joins the trace
+		PropagateTrace: true,
+	}
+}
+
+// Ping checks the sidecar's /health endpoint.
+func (g *Gateway) Ping(ctx context.Context) error {
+	return g.client().Probe(ctx, g.baseURL+"/health")
+}
+
 // similarResponse represents the JSON response from /similar endpoint.
 type similarResponse struct {
 	VideoID string      `json:"video_id"`
@@ -59,7 +102,7 @@ type trackJSON struct {
 
 // GetSimilarTracks retrieves similar tracks for a given YouTube video ID.
 func (g *Gateway) GetSimilarTracks(ctx context.Context, videoID string, limit int) ([]domain.YTMusicTrack, error) {
-	logger.Debug(featureName, fmt.Sprintf("getting similar tracks for videoID=%s, limit=%d", videoID, limit))
+	logger.DebugContext(ctx, featureName, fmt.Sprintf("getting similar tracks for videoID=%s, limit=%d", videoID, limit))
 
 	reqURL := fmt.Sprintf("%s/similar/%s?limit=%d", g.baseURL, url.PathEscape(videoID), limit)
 
@@ -68,14 +111,14 @@ func (g *Gateway) GetSimilarTracks(ctx context.Context, videoID string, limit in
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := g.httpClient.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get similar tracks: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("sidecar returned status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "sidecar")
 	}
 
 	var result similarResponse
@@ -88,13 +131,13 @@ func (g *Gateway) GetSimilarTracks(ctx context.Context, videoID string, limit in
 		tracks = append(tracks, convertTrack(t))
 	}
 
-	logger.Debug(featureName, fmt.Sprintf("found %d similar tracks", len(tracks)))
+	logger.DebugContext(ctx, featureName, fmt.Sprintf("found %d similar tracks", len(tracks)))
 	return tracks, nil
 }
 
 // SearchTracks searches for tracks on YouTube Music.
 func (g *Gateway) SearchTracks(ctx context.Context, query string, limit int) ([]domain.YTMusicTrack, error) {
-	logger.Debug(featureName, fmt.Sprintf("searching for query=%s, limit=%d", query, limit))
+	logger.DebugContext(ctx, featureName, fmt.Sprintf("searching for query=%s, limit=%d", query, limit))
 
 	reqURL := fmt.Sprintf("%s/search?q=%s&limit=%d", g.baseURL, url.QueryEscape(query), limit)
 
@@ -103,14 +146,14 @@ func (g *Gateway) SearchTracks(ctx context.Context, query string, limit int) ([]
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := g.httpClient.Do(req)
+	resp, err := g.client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tracks: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("sidecar returned status %d", resp.StatusCode)
+		return nil, transport.StatusError(resp, "sidecar")
 	}
 
 	var result searchResponse
@@ -123,10 +166,43 @@ func (g *Gateway) SearchTracks(ctx context.Context, query string, limit int) ([]
 		tracks = append(tracks, convertTrack(t))
 	}
 
-	logger.Debug(featureName, fmt.Sprintf("found %d tracks for query", len(tracks)))
+	logger.DebugContext(ctx, featureName, fmt.Sprintf("found %d tracks for query", len(tracks)))
 	return tracks, nil
 }
 
+// GetTrack retrieves a single track by its YouTube video ID.
+func (g *Gateway) GetTrack(ctx context.Context, videoID string) (*domain.YTMusicTrack, error) {
+	logger.DebugContext(ctx, featureName, fmt.Sprintf("getting track for videoID=%s", videoID))
+
+	reqURL := fmt.Sprintf("%s/track/%s", g.baseURL, url.PathEscape(videoID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrTrackNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, transport.StatusError(resp, "sidecar")
+	}
+
+	var result trackJSON
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	track := convertTrack(result)
+	return &track, nil
+}
+
 // convertTrack converts a trackJSON to domain.YTMusicTrack.
 func convertTrack(t trackJSON) domain.YTMusicTrack {
 	track := domain.YTMusicTrack{
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/t1nyb0x/tracktaste/internal/config"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// ConfigReloader は設定を再読み込みします
+// *config.Store がこのインターフェースを満たします
+type ConfigReloader interface {
+	// Reload は設定を読み込み・検証して差し替え、変更点を返します
+	// 不正な設定の場合はエラーを返し、現在の設定を維持します
+	Reload() ([]config.Change, error)
+}
+
+// AdminHandler は管理用エンドポイントのハンドラーです
+type AdminHandler struct {
+	reloader ConfigReloader
+	token    func() string
+}
+
+// ReloadResponse は設定再読み込みのレスポンス
+type ReloadResponse struct {
+	Changes []config.Change `json:"changes"`
+}
+
+// NewAdminHandler は新しいAdminHandlerを作成します
+// token は現在の管理トークンを返します。空の場合、管理エンドポイントは無効（404）です
+func NewAdminHandler(reloader ConfigReloader, token func() string) *AdminHandler {
+	return &AdminHandler{reloader: reloader, token: token}
+}
+
+// ReloadConfig は設定を再読み込みします（POST /admin/config/reload）
+// Authorization: Bearer <管理トークン> が必要です
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	changes, err := h.reloader.Reload()
+	if err != nil {
+		lang := requestLang(r)
+		message, _ := localize(lang, "CONFIG_INVALID", nil)
+		writeErrorBody(w, r, http.StatusUnprocessableEntity, "CONFIG_INVALID", message, err.Error())
+		return
+	}
+	if changes == nil {
+		changes = []config.Change{}
+	}
+	logger.InfoContext(r.Context(), "Admin", "設定を再読み込みしました")
+	success(w, ReloadResponse{Changes: changes})
+}
+
+// authorize は管理トークンを検証し、失敗した場合はエラーを書き込みます
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	lang := requestLang(r)
+	token := h.token()
+	if token == "" {
+		message, _ := localize(lang, "NOT_FOUND", nil)
+		writeErrorBody(w, r, http.StatusNotFound, "NOT_FOUND", message, message)
+		return false
+	}
+
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		logger.WarningContext(r.Context(), "Admin", "管理トークンが不正です")
+		message, _ := localize(lang, "UNAUTHORIZED", nil)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="tracktaste-admin"`)
+		writeErrorBody(w, r, http.StatusUnauthorized, "UNAUTHORIZED", message, message)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/config"
+)
+
+type mockConfigReloader struct {
+	changes []config.Change
+	err     error
+	calls   int
+}
+
+func (m *mockConfigReloader) Reload() ([]config.Change, error) {
+	m.calls++
+	return m.changes, m.err
+}
+
+func TestAdminHandler_ReloadConfig(t *testing.T) {
+	changes := []config.Change{{Key: "recommend.max_results", Old: "30", New: "20"}}
+
+	tests := []struct {
+		name        string
+		token       string
+		auth        string
+		reloader    *mockConfigReloader
+		wantStatus  int
+		wantCode    string
+		wantReloads int
+	}{
+		{
+			name:        "正常系: 再読み込み",
+			token:       "secret",
+			auth:        "Bearer secret",
+			reloader:    &mockConfigReloader{changes: changes},
+			wantStatus:  http.StatusOK,
+			wantReloads: 1,
+		},
+		{
+			name:        "正常系: 変更なし",
+			token:       "secret",
+			auth:        "Bearer secret",
+			reloader:    &mockConfigReloader{},
+			wantStatus:  http.StatusOK,
+			wantReloads: 1,
+		},
+		{
+			name:        "異常系: 不正な設定",
+			token:       "secret",
+			auth:        "Bearer secret",
+			reloader:    &mockConfigReloader{err: errors.New("invalid configuration:\n  - recommend.max_results: must be at least 1, got 0")},
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantCode:    "CONFIG_INVALID",
+			wantReloads: 1,
+		},
+		{
+			name:       "異常系: トークンなし",
+			token:      "secret",
+			reloader:   &mockConfigReloader{},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "UNAUTHORIZED",
+		},
+		{
+			name:       "異常系: トークン不一致",
+			token:      "secret",
+			auth:       "Bearer wrong",
+			reloader:   &mockConfigReloader{},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "UNAUTHORIZED",
+		},
+		{
+			name:       "異常系: 管理トークン未設定",
+			token:      "",
+			auth:       "Bearer ",
+			reloader:   &mockConfigReloader{},
+			wantStatus: http.StatusNotFound,
+			wantCode:   "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdminHandler(tt.reloader, func() string { return tt.token })
+			req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+
+			h.ReloadConfig(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.reloader.calls != tt.wantReloads {
+				t.Errorf("reloads = %d, want %d", tt.reloader.calls, tt.wantReloads)
+			}
+			if tt.wantCode != "" {
+				var resp errorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %s, want %s", resp.Code, tt.wantCode)
+				}
+				return
+			}
+
+			var resp struct {
+				Result ReloadResponse `json:"result"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Result.Changes == nil || len(resp.Result.Changes) != len(tt.reloader.changes) {
+				t.Errorf("changes = %v, want %v", resp.Result.Changes, tt.reloader.changes)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ReloadConfig_InvalidDetail(t *testing.T) {
+	reloader := &mockConfigReloader{err: errors.New("recommend.max_results: must be at least 1")}
+	h := NewAdminHandler(reloader, func() string { return "secret" })
+	req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", problemContentType)
+	rec := httptest.NewRecorder()
+
+	h.ReloadConfig(rec, req)
+
+	var resp problemResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resp.Detail, "recommend.max_results") {
+		t.Errorf("detail should name the invalid setting, got %q", resp.Detail)
+	}
+}
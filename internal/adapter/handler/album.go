@@ -3,16 +3,26 @@ package handler
 import (
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 	usecasev1 "github.com/t1nyb0x/tracktaste/internal/usecase/v1"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
 )
 
 type AlbumHandler struct {
 	albumUC *usecasev1.AlbumUseCase
+	links   *LinkExpander
 }
 
 func NewAlbumHandler(albumUC *usecasev1.AlbumUseCase) *AlbumHandler {
-	return &AlbumHandler{albumUC: albumUC}
+	return &AlbumHandler{albumUC: albumUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander は短縮リンクの展開に使う LinkExpander を差し替えます
+func (h *AlbumHandler) WithLinkExpander(links *LinkExpander) *AlbumHandler {
+	h.links = links
+	return h
 }
 
 type albumResult struct {
@@ -46,27 +56,40 @@ type albumTrackItem struct {
 }
 
 func (h *AlbumHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
-	logger.Info("AlbumFetch", "リクエスト開始")
+	logger.InfoContext(r.Context(), "AlbumFetch", "リクエスト開始")
 
 	rawURL := r.URL.Query().Get("url")
-	albumID, err := extractSpotifyAlbumID(rawURL)
+	albumID, err := spotifyID(r.Context(), h.links, rawURL, "album")
 	if err != nil {
-		if e, ok := err.(*extractError); ok {
-			logger.Warning("AlbumFetch", e.Message)
-			badRequest(w, e.Message, e.Code)
-			return
-		}
-		badRequest(w, "パラメータが不正です", "INVALID_PARAM")
+		writeError(w, r, "AlbumFetch", err, "INVALID_PARAM")
 		return
 	}
 
 	album, err := h.albumUC.FetchByID(r.Context(), albumID)
 	if err != nil {
-		logger.Error("AlbumFetch", "Spotify API エラー: "+err.Error())
-		serviceUnavailable(w, "Spotify APIで問題が発生しているようです", "SOMETHING_SPOTIFY_ERROR")
+		writeError(w, r, "AlbumFetch", err, "SOMETHING_SPOTIFY_ERROR")
+		return
+	}
+
+	logger.InfoContext(r.Context(), "AlbumFetch", "リクエスト完了")
+	success(w, convertAlbumToResult(album))
+}
+
+// FetchByUPC は UPC（または EAN）に一致するアルバムを返します
+func (h *AlbumHandler) FetchByUPC(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "AlbumUPC", "リクエスト開始")
+
+	album, err := h.albumUC.FetchByUPC(r.Context(), chi.URLParam(r, "upc"))
+	if err != nil {
+		writeError(w, r, "AlbumUPC", err, "SOMETHING_SPOTIFY_ERROR")
 		return
 	}
 
+	logger.InfoContext(r.Context(), "AlbumUPC", "リクエスト完了")
+	success(w, convertAlbumToResult(album))
+}
+
+func convertAlbumToResult(album *domain.Album) albumResult {
 	images := make([]imageResult, len(album.Images))
 	for i, img := range album.Images {
 		images[i] = imageResult{URL: img.URL, Height: img.Height, Width: img.Width}
@@ -92,7 +115,7 @@ func (h *AlbumHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result := albumResult{
+	return albumResult{
 		URL:         album.URL,
 		ID:          album.ID,
 		Images:      images,
@@ -104,7 +127,4 @@ func (h *AlbumHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
 		UPC:         album.UPC,
 		Genres:      album.Genres,
 	}
-
-	logger.Info("AlbumFetch", "リクエスト完了")
-	success(w, result)
 }
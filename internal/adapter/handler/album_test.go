@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
 	usecasev1 "github.com/t1nyb0x/tracktaste/internal/usecase/v1"
@@ -15,7 +17,8 @@ import (
 
 // mockSpotifyAPIForAlbum for album handler tests
 type mockSpotifyAPIForAlbum struct {
-	GetAlbumByIDFunc func(ctx context.Context, id string) (*domain.Album, error)
+	GetAlbumByIDFunc     func(ctx context.Context, id string) (*domain.Album, error)
+	SearchAlbumByUPCFunc func(ctx context.Context, upc string) (*domain.Album, error)
 }
 
 func (m *mockSpotifyAPIForAlbum) GetTrackByID(ctx context.Context, id string) (*domain.Track, error) {
@@ -41,6 +44,17 @@ func (m *mockSpotifyAPIForAlbum) SearchByISRC(ctx context.Context, isrc string)
 	return nil, nil
 }
 
+func (m *mockSpotifyAPIForAlbum) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	if m.SearchAlbumByUPCFunc != nil {
+		return m.SearchAlbumByUPCFunc(ctx, upc)
+	}
+	return nil, nil
+}
+
+func (m *mockSpotifyAPIForAlbum) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	return nil, nil
+}
+
 func (m *mockSpotifyAPIForAlbum) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	return nil, nil
 }
@@ -270,3 +284,81 @@ func TestAlbumHandler_FetchByURL_EmptyTracks(t *testing.T) {
 		t.Errorf("expected 0 tracks, got %d", len(items))
 	}
 }
+
+func TestAlbumHandler_FetchByUPC(t *testing.T) {
+	tests := []struct {
+		name           string
+		upc            string
+		mockFunc       func(ctx context.Context, upc string) (*domain.Album, error)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "正常系: 有効なUPC",
+			upc:  "4547366473086",
+			mockFunc: func(ctx context.Context, upc string) (*domain.Album, error) {
+				return createTestAlbum(), nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 不正なUPC",
+			upc:            "12345",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_UPC",
+		},
+		{
+			name: "異常系: 該当なし",
+			upc:  "4547366473086",
+			mockFunc: func(ctx context.Context, upc string) (*domain.Album, error) {
+				return nil, nil
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "ALBUM_NOT_FOUND",
+		},
+		{
+			name: "異常系: APIエラー",
+			upc:  "4547366473086",
+			mockFunc: func(ctx context.Context, upc string) (*domain.Album, error) {
+				return nil, errors.New("API error")
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   "SOMETHING_SPOTIFY_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &mockSpotifyAPIForAlbum{SearchAlbumByUPCFunc: tt.mockFunc}
+			handler := NewAlbumHandler(usecasev1.NewAlbumUseCase(mockAPI))
+
+			r := chi.NewRouter()
+			r.Get("/v1/album/upc/{upc}", handler.FetchByUPC)
+			req := httptest.NewRequest(http.MethodGet, "/v1/album/upc/"+tt.upc, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+			result, ok := resp["result"].(map[string]interface{})
+			if !ok {
+				t.Fatal("expected result to be object")
+			}
+			if result["id"] != "test-album-id" {
+				t.Errorf("expected id test-album-id, got %v", result["id"])
+			}
+		})
+	}
+}
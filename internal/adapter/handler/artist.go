@@ -10,10 +10,17 @@ import (
 
 type ArtistHandler struct {
 	artistUC *usecasev1.ArtistUseCase
+	links    *LinkExpander
 }
 
 func NewArtistHandler(artistUC *usecasev1.ArtistUseCase) *ArtistHandler {
-	return &ArtistHandler{artistUC: artistUC}
+	return &ArtistHandler{artistUC: artistUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander は短縮リンクの展開に使う LinkExpander を差し替えます
+func (h *ArtistHandler) WithLinkExpander(links *LinkExpander) *ArtistHandler {
+	h.links = links
+	return h
 }
 
 type artistResult struct {
@@ -27,24 +34,18 @@ type artistResult struct {
 }
 
 func (h *ArtistHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
-	logger.Info("ArtistFetch", "リクエスト開始")
+	logger.InfoContext(r.Context(), "ArtistFetch", "リクエスト開始")
 
 	rawURL := r.URL.Query().Get("url")
-	artistID, err := extractSpotifyArtistID(rawURL)
+	artistID, err := spotifyID(r.Context(), h.links, rawURL, "artist")
 	if err != nil {
-		if e, ok := err.(*extractError); ok {
-			logger.Warning("ArtistFetch", e.Message)
-			badRequest(w, e.Message, e.Code)
-			return
-		}
-		badRequest(w, "パラメータが不正です", "INVALID_PARAM")
+		writeError(w, r, "ArtistFetch", err, "INVALID_PARAM")
 		return
 	}
 
 	artist, err := h.artistUC.FetchByID(r.Context(), artistID)
 	if err != nil {
-		logger.Error("ArtistFetch", "Spotify API エラー: "+err.Error())
-		serviceUnavailable(w, "Spotify APIで問題が発生しているようです", "SOMETHING_SPOTIFY_ERROR")
+		writeError(w, r, "ArtistFetch", err, "SOMETHING_SPOTIFY_ERROR")
 		return
 	}
 
@@ -68,6 +69,6 @@ func (h *ArtistHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
 		Popularity: artist.Popularity,
 	}
 
-	logger.Info("ArtistFetch", "リクエスト完了")
+	logger.InfoContext(r.Context(), "ArtistFetch", "リクエスト完了")
 	success(w, result)
 }
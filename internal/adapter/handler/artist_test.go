@@ -41,6 +41,14 @@ func (m *mockSpotifyAPIForArtist) SearchByISRC(ctx context.Context, isrc string)
 	return nil, nil
 }
 
+func (m *mockSpotifyAPIForArtist) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	return nil, nil
+}
+
+func (m *mockSpotifyAPIForArtist) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	return nil, nil
+}
+
 func (m *mockSpotifyAPIForArtist) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	return nil, nil
 }
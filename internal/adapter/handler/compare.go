@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// CompareUseCase explains the recommender's score between two Spotify tracks.
+type CompareUseCase interface {
+	Compare(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error)
+}
+
+// CompareHandler handles track comparison requests.
+type CompareHandler struct {
+	compareUC CompareUseCase
+	resolver  TrackResolver // Optional: without it only Spotify URLs are accepted
+	links     *LinkExpander
+}
+
+// NewCompareHandler creates a new CompareHandler.
+func NewCompareHandler(compareUC CompareUseCase) *CompareHandler {
+	return &CompareHandler{compareUC: compareUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *CompareHandler) WithLinkExpander(links *LinkExpander) *CompareHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *CompareHandler) WithResolver(r TrackResolver) *CompareHandler {
+	h.resolver = r
+	return h
+}
+
+type compareResponse struct {
+	A                 comparedTrackResult        `json:"a"`
+	B                 comparedTrackResult        `json:"b"`
+	GenreFilterBonus  float64                    `json:"genre_filter_bonus"`
+	PassesGenreFilter bool                       `json:"passes_genre_filter"`
+	SameArtist        bool                       `json:"same_artist"`
+	Series            string                     `json:"series,omitempty"`
+	MatchReasons      []string                   `json:"match_reasons"`
+	Scores            map[string]modeScoreResult `json:"scores"`
+}
+
+type comparedTrackResult struct {
+	Track      trackSummaryResult `json:"track"`
+	Features   featuresResult     `json:"features"`
+	Genres     []string           `json:"genres"`
+	Resolution *resolutionResult  `json:"resolution,omitempty"`
+}
+
+type modeScoreResult struct {
+	Components      []componentResult `json:"components"`
+	BaseSimilarity  float64           `json:"base_similarity"`
+	GenreBonus      float64           `json:"genre_bonus"`
+	ArtistBonus     float64           `json:"artist_bonus"`
+	SameArtistBonus float64           `json:"same_artist_bonus"`
+	SeriesBonus     float64           `json:"series_bonus"`
+	FinalScore      float64           `json:"final_score"`
+}
+
+type componentResult struct {
+	Name         string  `json:"name"`
+	Similarity   float64 `json:"similarity"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"` // Share of base_similarity
+}
+
+// Compare handles GET /v2/track/compare.
+func (h *CompareHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "Compare", "リクエスト開始")
+
+	trackIDA, resolutionA, err := resolveTrackURL(r.Context(), h.links, h.resolver, r.URL.Query().Get("a"))
+	if err != nil {
+		writeError(w, r, "Compare", err, "INVALID_PARAM")
+		return
+	}
+	trackIDB, resolutionB, err := resolveTrackURL(r.Context(), h.links, h.resolver, r.URL.Query().Get("b"))
+	if err != nil {
+		writeError(w, r, "Compare", err, "INVALID_PARAM")
+		return
+	}
+
+	result, err := h.compareUC.Compare(r.Context(), trackIDA, trackIDB)
+	if err != nil {
+		writeError(w, r, "Compare", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	resp := convertComparison(result)
+	resp.A.Resolution = resolutionA
+	resp.B.Resolution = resolutionB
+	logger.InfoContext(r.Context(), "Compare", "リクエスト完了")
+	success(w, resp)
+}
+
+// convertComparison converts a comparison into its response form.
+func convertComparison(c *domain.TrackComparison) compareResponse {
+	resp := compareResponse{
+		A:                 convertComparedTrack(&c.A),
+		B:                 convertComparedTrack(&c.B),
+		GenreFilterBonus:  c.GenreFilterBonus,
+		PassesGenreFilter: c.PassesGenreFilter,
+		SameArtist:        c.SameArtist,
+		Series:            c.Series,
+		MatchReasons:      c.MatchReasons,
+		Scores:            make(map[string]modeScoreResult, len(c.Scores)),
+	}
+	if resp.MatchReasons == nil {
+		resp.MatchReasons = []string{}
+	}
+
+	for _, s := range c.Scores {
+		var totalWeight float64
+		for _, comp := range s.Components {
+			totalWeight += comp.Weight
+		}
+		components := make([]componentResult, len(s.Components))
+		for i, comp := range s.Components {
+			components[i] = componentResult{Name: comp.Name, Similarity: comp.Similarity, Weight: comp.Weight}
+			if totalWeight > 0 {
+				components[i].Contribution = comp.Weight * comp.Similarity / totalWeight
+			}
+		}
+		resp.Scores[string(s.Mode)] = modeScoreResult{
+			Components:      components,
+			BaseSimilarity:  s.BaseSimilarity,
+			GenreBonus:      s.GenreBonus,
+			ArtistBonus:     s.ArtistBonus,
+			SameArtistBonus: s.SameArtistBonus,
+			SeriesBonus:     s.SeriesBonus,
+			FinalScore:      s.FinalScore,
+		}
+	}
+	return resp
+}
+
+// convertComparedTrack converts one side of a comparison.
+func convertComparedTrack(t *domain.ComparedTrack) comparedTrackResult {
+	set := convertFeatureSet(&domain.TrackFeatureSet{Track: &t.Track, Features: t.Features, Genres: t.Genres})
+	return comparedTrackResult{Track: set.Track, Features: set.Features, Genres: set.Genres}
+}
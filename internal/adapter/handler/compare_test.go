@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockCompareUseCase for compare handler tests
+type mockCompareUseCase struct {
+	compareFunc func(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error)
+}
+
+func (m *mockCompareUseCase) Compare(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error) {
+	return m.compareFunc(ctx, trackIDA, trackIDB)
+}
+
+func TestCompareHandler_Compare(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		compare        func(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:  "正常系: モードごとのスコア内訳",
+			query: "a=https://open.spotify.com/track/aaa&b=spotify:track:bbb",
+			compare: func(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error) {
+				if trackIDA != "aaa" || trackIDB != "bbb" {
+					return nil, domain.ErrTrackNotFound
+				}
+				return &domain.TrackComparison{
+					A:                 domain.ComparedTrack{Track: domain.Track{ID: "aaa"}, Features: &domain.TrackFeatures{BPM: 120}},
+					B:                 domain.ComparedTrack{Track: domain.Track{ID: "bbb"}, Features: &domain.TrackFeatures{BPM: 125}},
+					GenreFilterBonus:  1.0,
+					PassesGenreFilter: true,
+					MatchReasons:      []string{"similar_bpm"},
+					Scores: []domain.ModeScore{{
+						Mode: domain.RecommendModeBalanced,
+						Components: []domain.SimilarityComponent{
+							{Name: domain.ComponentBPM, Similarity: 0.5, Weight: 1.5},
+							{Name: domain.ComponentDuration, Similarity: 0.4, Weight: 0.5},
+						},
+						BaseSimilarity: 0.475, GenreBonus: 1, ArtistBonus: 1, SameArtistBonus: 1, SeriesBonus: 1, FinalScore: 0.475,
+					}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: bが空",
+			query:          "a=https://open.spotify.com/track/aaa",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name:  "異常系: 曲が見つからない",
+			query: "a=https://open.spotify.com/track/aaa&b=https://open.spotify.com/track/bbb",
+			compare: func(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error) {
+				return nil, domain.ErrTrackNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "TRACK_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCompareHandler(&mockCompareUseCase{compareFunc: tt.compare})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/track/compare?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.Compare(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			scores, _ := result["scores"].(map[string]interface{})
+			balanced, ok := scores["balanced"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected balanced score, got %v", scores)
+			}
+			components, _ := balanced["components"].([]interface{})
+			if len(components) != 2 {
+				t.Fatalf("expected 2 components, got %v", components)
+			}
+			bpm, _ := components[0].(map[string]interface{})
+			// 1.5 * 0.5 / (1.5 + 0.5)
+			if bpm["name"] != "bpm" || bpm["contribution"] != 0.375 {
+				t.Errorf("unexpected bpm component: %v", bpm)
+			}
+			b, _ := result["b"].(map[string]interface{})
+			if res, _ := b["resolution"].(map[string]interface{}); res["id"] != "bbb" {
+				t.Errorf("unexpected b resolution: %v", b["resolution"])
+			}
+		})
+	}
+}
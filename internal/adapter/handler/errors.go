@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// kindStatus maps each error kind to its HTTP status.
+// Unclassified (internal) errors keep the endpoint's legacy 503.
+var kindStatus = map[domain.ErrorKind]int{
+	domain.KindNotFound:     http.StatusNotFound,
+	domain.KindInvalidInput: http.StatusBadRequest,
+	domain.KindRateLimited:  http.StatusTooManyRequests,
+	domain.KindUnavailable:  http.StatusServiceUnavailable,
+	domain.KindTimeout:      http.StatusGatewayTimeout,
+	domain.KindPartial:      http.StatusGatewayTimeout,
+}
+
+// kindCode is the code used when an error of the kind has none.
+var kindCode = map[domain.ErrorKind]string{
+	domain.KindNotFound:     "NOT_FOUND",
+	domain.KindInvalidInput: "INVALID_PARAM",
+	domain.KindRateLimited:  domain.ErrUpstreamRateLimited.Code,
+	domain.KindUnavailable:  domain.ErrUpstreamUnavailable.Code,
+	domain.KindTimeout:      domain.ErrTimeout.Code,
+	domain.KindPartial:      domain.ErrPartial.Code,
+}
+
+// writeError writes err as an error response in the format and language the request asks for.
+// Typed domain errors get the status, code and Retry-After of their kind;
+// unclassified errors are written as 503 with fallbackCode.
+func writeError(w http.ResponseWriter, r *http.Request, feature string, err error, fallbackCode string) {
+	e := domain.AsError(err)
+	lang := requestLang(r)
+	status, code, message := describeError(lang, e, fallbackCode)
+
+	if _, ok := kindStatus[e.Kind]; !ok {
+		logger.ErrorContext(r.Context(), feature, "API エラー: "+err.Error())
+		writeErrorBody(w, r, status, code, kindTitles[lang][domain.KindInternal], message)
+		return
+	}
+
+	if status >= http.StatusInternalServerError {
+		logger.ErrorContext(r.Context(), feature, fmt.Sprintf("%s: %v", code, err))
+	} else {
+		logger.WarningContext(r.Context(), feature, fmt.Sprintf("%s: %v", code, err))
+	}
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(e.RetryAfter)))
+	}
+	writeErrorBody(w, r, status, code, kindTitles[lang][e.Kind], message)
+}
+
+// describeError returns the HTTP status, code and localised message of e.
+// Unclassified errors are 503 with fallbackCode.
+func describeError(lang string, e *domain.Error, fallbackCode string) (int, string, string) {
+	status, ok := kindStatus[e.Kind]
+	if !ok {
+		message, _ := localize(lang, fallbackCode, nil)
+		return http.StatusServiceUnavailable, fallbackCode, message
+	}
+
+	code := e.Code
+	if code == "" {
+		code = kindCode[e.Kind]
+	}
+	message, ok := localize(lang, code, e.Params)
+	if !ok && e.Kind == domain.KindInvalidInput && e.Message != "" {
+		// Validation errors without a catalogue entry describe themselves
+		message = e.Message
+	} else if !ok {
+		message, _ = localize(lang, kindCode[e.Kind], nil)
+	}
+	return status, code, message
+}
+
+// retryAfterSeconds rounds d up to whole seconds, as Retry-After requires.
+func retryAfterSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantCode       string
+		wantMessage    string
+		wantRetryAfter string
+	}{
+		{
+			name:        "正常系: not found",
+			err:         fmt.Errorf("fetch: %w", domain.ErrArtistNotFound),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "ARTIST_NOT_FOUND",
+			wantMessage: "アーティストが見つかりませんでした",
+		},
+		{
+			name:        "正常系: カタログにない入力エラーは自身のメッセージを使う",
+			err:         domain.NewInvalidInputError("UNKNOWN_PARAM", "URLが入力されていません"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "UNKNOWN_PARAM",
+			wantMessage: "URLが入力されていません",
+		},
+		{
+			name:           "正常系: レート制限は Retry-After を秒で切り上げる",
+			err:            &domain.Error{Kind: domain.KindRateLimited, Code: "UPSTREAM_RATE_LIMITED", Message: "spotify: status 429", RetryAfter: 1500 * time.Millisecond},
+			wantStatus:     http.StatusTooManyRequests,
+			wantCode:       "UPSTREAM_RATE_LIMITED",
+			wantRetryAfter: "2",
+		},
+		{
+			name:       "正常系: コードのない not found は種別の既定コード",
+			err:        domain.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "NOT_FOUND",
+		},
+		{
+			name:       "正常系: context のタイムアウト",
+			err:        fmt.Errorf("collect: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   "TIMEOUT",
+		},
+		{
+			name:        "異常系: 未分類エラーはエンドポイントの既定値",
+			err:         errors.New("boom"),
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCode:    "SOMETHING_API_ERROR",
+			wantMessage: "APIで問題が発生しているようです",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			writeError(rec, req, "Test", tt.err, "SOMETHING_API_ERROR")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("expected Retry-After %q, got %q", tt.wantRetryAfter, got)
+			}
+
+			var resp errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Status != tt.wantStatus || resp.Code != tt.wantCode {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantCode, resp.Status, resp.Code)
+			}
+			if resp.Message == "" {
+				t.Error("expected a message")
+			}
+			if tt.wantMessage != "" && resp.Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, resp.Message)
+			}
+		})
+	}
+}
+
+func TestWriteError_ProblemJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		err            error
+		wantStatus     int
+		wantCode       string
+		wantTitle      string
+		wantDetail     string
+	}{
+		{
+			name:           "正常系: 英語",
+			acceptLanguage: "en-US,en;q=0.9,ja;q=0.8",
+			err:            domain.ErrTrackNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantCode:       "TRACK_NOT_FOUND",
+			wantTitle:      "Not found",
+			wantDetail:     "Track not found",
+		},
+		{
+			name:           "正常系: パラメータ付きメッセージ",
+			acceptLanguage: "en",
+			err: &domain.Error{
+				Kind:   domain.KindInvalidInput,
+				Code:   domain.ErrCodeDifferentSpotifyURL,
+				Params: map[string]string{"resource": "Track"},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "DIFFERENT_SPOTIFY_URL",
+			wantTitle:  "Invalid request",
+			wantDetail: "Please enter a Spotify Track URL",
+		},
+		{
+			name:           "正常系: 未対応の言語は日本語",
+			acceptLanguage: "fr",
+			err:            errors.New("boom"),
+			wantStatus:     http.StatusServiceUnavailable,
+			wantCode:       "SOMETHING_API_ERROR",
+			wantTitle:      "外部APIで問題が発生しました",
+			wantDetail:     "APIで問題が発生しているようです",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/v1/track/fetch", nil)
+			req.Header.Set("Accept", "application/problem+json, application/json;q=0.5")
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "req-1"))
+
+			writeError(rec, req, "Test", tt.err, "SOMETHING_API_ERROR")
+
+			if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("expected problem+json, got %s", got)
+			}
+			var resp problemResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			want := problemResponse{
+				Type:     "urn:tracktaste:error:" + tt.wantCode,
+				Title:    tt.wantTitle,
+				Status:   tt.wantStatus,
+				Detail:   tt.wantDetail,
+				Instance: "req-1",
+				Code:     tt.wantCode,
+			}
+			if resp != want || rec.Code != tt.wantStatus {
+				t.Errorf("expected %+v, got %d %+v", want, rec.Code, resp)
+			}
+		})
+	}
+}
+
+func TestNegotiateLang(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "ja"},
+		{"en", "en"},
+		{"en-GB", "en"},
+		{"ja-JP,en;q=0.5", "ja"},
+		{"fr,en;q=0.8,ja;q=0.9", "ja"},
+		{"en;q=0, ja", "ja"},
+		{"de", "ja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := negotiateLang(tt.header); got != tt.want {
+				t.Errorf("negotiateLang(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,53 +1,174 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 )
 
-type extractError struct {
-	Code    string
-	Message string
+// spotifyURIPattern matches Spotify URIs such as spotify:track:4uLU6hMCjMI75M1A2tKUQC.
+var spotifyURIPattern = regexp.MustCompile(`^spotify:(track|artist|album|playlist):([A-Za-z0-9]+)$`)
+
+// shortLinkHosts are the hosts of Spotify share short links.
+var shortLinkHosts = []string{"spotify.link", "spotify.app.link"}
+
+// isSpotifyLink reports whether rawURL is a Spotify URL, URI or short link.
+func isSpotifyLink(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "spotify:") || isSpotifyHost(linkHost(rawURL)) || isShortLink(rawURL)
 }
 
-func (e *extractError) Error() string {
-	return e.Message
+// isShortLink reports whether rawURL is a Spotify share short link (https://spotify.link/...).
+func isShortLink(rawURL string) bool {
+	host := linkHost(rawURL)
+	for _, h := range shortLinkHosts {
+		if host == h {
+			return true
+		}
+	}
+	return false
 }
 
-func extractSpotifyID(rawURL string, resourceType string) (string, error) {
-	if rawURL == "" {
-		return "", &extractError{Code: "EMPTY_PARAM", Message: "URLが入力されていません"}
+// isSpotifyHost reports whether host is spotify.com or one of its subdomains.
+func isSpotifyHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "spotify.com" || strings.HasSuffix(host, ".spotify.com")
+}
+
+// linkHost returns the lowercase host of rawURL, which may omit the scheme, or "" if it does not parse.
+func linkHost(rawURL string) string {
+	raw := rawURL
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
 	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
 
-	if !strings.Contains(rawURL, "spotify.com") {
-		return "", &extractError{Code: "NOT_SPOTIFY_URL", Message: "SpotifyのURLを入力してください"}
+// LinkExpander は Spotify の短縮リンク（spotify.link）をリダイレクトをたどって展開します
+// HTTP クライアントを差し替えられるため、テストではネットワークに出ずに確認できます
+type LinkExpander struct {
+	httpc *http.Client
+}
+
+// NewLinkExpander は LinkExpander を作成します。httpc が nil の場合は 5 秒でタイムアウトするクライアントを使います
+func NewLinkExpander(httpc *http.Client) *LinkExpander {
+	if httpc == nil {
+		httpc = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &LinkExpander{httpc: httpc}
+}
+
+// Expand は短縮リンクを open.spotify.com の URL に展開します。短縮リンク以外はそのまま返します
+func (e *LinkExpander) Expand(ctx context.Context, rawURL string) (string, error) {
+	if !isShortLink(rawURL) {
+		return rawURL, nil
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
 	}
 
-	resourceTypes := []string{"track", "artist", "album"}
-	for _, rt := range resourceTypes {
-		if rt != resourceType && strings.Contains(rawURL, "/"+rt+"/") {
-			return "", &extractError{
-				Code:    "DIFFERENT_SPOTIFY_URL",
-				Message: fmt.Sprintf("%sのURLを入力してください", getResourceTypeName(resourceType)),
-			}
+	// Stop at the first open.spotify.com location instead of loading the web player
+	client := *e.httpc
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if isSpotifyHost(req.URL.Hostname()) {
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after %d redirects", len(via))
 		}
+		return nil
 	}
 
-	patterns := []string{
-		fmt.Sprintf(`open\.spotify\.com/(?:intl-[a-z]{2}/)?%s/([A-Za-z0-9]+)`, resourceType),
-		fmt.Sprintf(`api\.spotify\.com/v1/%ss/([a-zA-Z0-9]+)`, resourceType),
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", domain.NewInvalidInputError(domain.ErrCodeInvalidURL, "invalid short link")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", &domain.Error{Kind: domain.KindUnavailable, Message: "failed to expand Spotify short link", Err: err}
 	}
+	defer resp.Body.Close()
 
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(rawURL)
-		if len(matches) > 1 {
-			return matches[1], nil
+	expanded := resp.Request.URL
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		if loc, err := resp.Location(); err == nil {
+			expanded = loc
 		}
 	}
+	if !isSpotifyHost(expanded.Hostname()) {
+		return "", domain.NewInvalidInputError(domain.ErrCodeInvalidURL, "short link does not point to Spotify")
+	}
+	return expanded.String(), nil
+}
+
+// spotifyID は短縮リンクを展開してから Spotify の ID を取り出します
+// トラック・アーティスト・アルバム・レコメンドの各ハンドラーで共通です
+func spotifyID(ctx context.Context, links *LinkExpander, rawURL string, resourceType string) (string, error) {
+	if links == nil {
+		links = defaultLinkExpander
+	}
+	expanded, err := links.Expand(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	return extractSpotifyID(expanded, resourceType)
+}
+
+// defaultLinkExpander is used by handlers created without WithLinkExpander.
+var defaultLinkExpander = NewLinkExpander(nil)
 
-	return "", &extractError{Code: "INVALID_URL", Message: "無効なURL形式です"}
+// spotifyPathPatterns match the path of a Spotify resource URL on each host,
+// capturing the resource type and the ID.
+var spotifyPathPatterns = map[string]*regexp.Regexp{
+	"open.spotify.com": regexp.MustCompile(`^/(?:intl-[a-z]{2}/)?(?:embed/)?(track|artist|album|playlist)/([A-Za-z0-9]+)/?$`),
+	"api.spotify.com":  regexp.MustCompile(`^/v1/(track|artist|album|playlist)s/([A-Za-z0-9]+)/?$`),
+}
+
+func extractSpotifyID(rawURL string, resourceType string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", domain.NewInvalidInputError(domain.ErrCodeEmptyParam, "empty URL")
+	}
+
+	// spotify:track:ID is handled as https://open.spotify.com/track/ID
+	if m := spotifyURIPattern.FindStringSubmatch(rawURL); m != nil {
+		rawURL = "https://open.spotify.com/" + m[1] + "/" + m[2]
+	}
+
+	host := linkHost(rawURL)
+	if !isSpotifyHost(host) {
+		return "", domain.NewInvalidInputError(domain.ErrCodeNotSpotifyURL, "not a Spotify URL")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	pattern := spotifyPathPatterns[host]
+	if err != nil || pattern == nil {
+		return "", domain.NewInvalidInputError(domain.ErrCodeInvalidURL, "invalid URL format")
+	}
+	m := pattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", domain.NewInvalidInputError(domain.ErrCodeInvalidURL, "invalid URL format")
+	}
+	if m[1] != resourceType {
+		return "", &domain.Error{
+			Kind:    domain.KindInvalidInput,
+			Code:    domain.ErrCodeDifferentSpotifyURL,
+			Message: fmt.Sprintf("not a Spotify %s URL", resourceType),
+			Params:  map[string]string{"resource": getResourceTypeName(resourceType)},
+		}
+	}
+	return m[2], nil
 }
 
 func getResourceTypeName(resourceType string) string {
@@ -58,6 +179,8 @@ func getResourceTypeName(resourceType string) string {
 		return "Artist"
 	case "album":
 		return "Album"
+	case "playlist":
+		return "Playlist"
 	default:
 		return resourceType
 	}
@@ -74,3 +197,7 @@ func extractSpotifyArtistID(rawURL string) (string, error) {
 func extractSpotifyAlbumID(rawURL string) (string, error) {
 	return extractSpotifyID(rawURL, "album")
 }
+
+func extractSpotifyPlaylistID(rawURL string) (string, error) {
+	return extractSpotifyID(rawURL, "playlist")
+}
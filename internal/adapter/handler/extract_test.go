@@ -1,7 +1,14 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
 )
 
 func TestExtractSpotifyTrackID(t *testing.T) {
@@ -37,6 +44,30 @@ func TestExtractSpotifyTrackID(t *testing.T) {
 			wantID:  "4uLU6hMCjMI75M1A2tKUQC",
 			wantErr: false,
 		},
+		{
+			name:    "Spotify URI",
+			url:     "spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+			wantID:  "4uLU6hMCjMI75M1A2tKUQC",
+			wantErr: false,
+		},
+		{
+			name:    "埋め込みURL",
+			url:     "https://open.spotify.com/embed/track/4uLU6hMCjMI75M1A2tKUQC?utm_source=generator",
+			wantID:  "4uLU6hMCjMI75M1A2tKUQC",
+			wantErr: false,
+		},
+		{
+			name:    "Web API のURL",
+			url:     "https://api.spotify.com/v1/tracks/4uLU6hMCjMI75M1A2tKUQC",
+			wantID:  "4uLU6hMCjMI75M1A2tKUQC",
+			wantErr: false,
+		},
+		{
+			name:    "スキームなし",
+			url:     "open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+			wantID:  "4uLU6hMCjMI75M1A2tKUQC",
+			wantErr: false,
+		},
 		// 異常系
 		{
 			name:     "空文字",
@@ -50,6 +81,18 @@ func TestExtractSpotifyTrackID(t *testing.T) {
 			wantErr:  true,
 			wantCode: "NOT_SPOTIFY_URL",
 		},
+		{
+			name:     "クエリにSpotifyのURLを含む別のホスト",
+			url:      "https://evil.example/?u=open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+			wantErr:  true,
+			wantCode: "NOT_SPOTIFY_URL",
+		},
+		{
+			name:     "パスの途中にトラックのパス",
+			url:      "https://open.spotify.com/user/x/track/4uLU6hMCjMI75M1A2tKUQC",
+			wantErr:  true,
+			wantCode: "INVALID_URL",
+		},
 		{
 			name:     "artistのURL",
 			url:      "https://open.spotify.com/artist/4uLU6hMCjMI75M1A2tKUQC",
@@ -62,6 +105,12 @@ func TestExtractSpotifyTrackID(t *testing.T) {
 			wantErr:  true,
 			wantCode: "DIFFERENT_SPOTIFY_URL",
 		},
+		{
+			name:     "albumのURI",
+			url:      "spotify:album:4uLU6hMCjMI75M1A2tKUQC",
+			wantErr:  true,
+			wantCode: "DIFFERENT_SPOTIFY_URL",
+		},
 		{
 			name:     "不正な形式",
 			url:      "https://open.spotify.com/invalid/format",
@@ -79,12 +128,12 @@ func TestExtractSpotifyTrackID(t *testing.T) {
 					t.Errorf("extractSpotifyTrackID() error = nil, wantErr %v", tt.wantErr)
 					return
 				}
-				if e, ok := err.(*extractError); ok {
+				if e, ok := err.(*domain.Error); ok && e.Kind == domain.KindInvalidInput {
 					if e.Code != tt.wantCode {
 						t.Errorf("extractSpotifyTrackID() error code = %v, want %v", e.Code, tt.wantCode)
 					}
 				} else {
-					t.Errorf("extractSpotifyTrackID() error type = %T, want *extractError", err)
+					t.Errorf("extractSpotifyTrackID() error type = %T, want invalid input *domain.Error", err)
 				}
 				return
 			}
@@ -146,7 +195,7 @@ func TestExtractSpotifyArtistID(t *testing.T) {
 					t.Errorf("extractSpotifyArtistID() error = nil, wantErr %v", tt.wantErr)
 					return
 				}
-				if e, ok := err.(*extractError); ok {
+				if e, ok := err.(*domain.Error); ok && e.Kind == domain.KindInvalidInput {
 					if e.Code != tt.wantCode {
 						t.Errorf("extractSpotifyArtistID() error code = %v, want %v", e.Code, tt.wantCode)
 					}
@@ -211,7 +260,7 @@ func TestExtractSpotifyAlbumID(t *testing.T) {
 					t.Errorf("extractSpotifyAlbumID() error = nil, wantErr %v", tt.wantErr)
 					return
 				}
-				if e, ok := err.(*extractError); ok {
+				if e, ok := err.(*domain.Error); ok && e.Kind == domain.KindInvalidInput {
 					if e.Code != tt.wantCode {
 						t.Errorf("extractSpotifyAlbumID() error code = %v, want %v", e.Code, tt.wantCode)
 					}
@@ -230,3 +279,211 @@ func TestExtractSpotifyAlbumID(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSpotifyPlaylistID(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		wantID   string
+		wantCode string
+	}{
+		{name: "正常系: 標準URL", url: "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc", wantID: "37i9dQZF1DXcBWIGoYBM5M"},
+		{name: "正常系: Spotify URI", url: "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", wantID: "37i9dQZF1DXcBWIGoYBM5M"},
+		{name: "正常系: intl-ja付きURL", url: "https://open.spotify.com/intl-ja/playlist/37i9dQZF1DXcBWIGoYBM5M", wantID: "37i9dQZF1DXcBWIGoYBM5M"},
+		{name: "異常系: trackのURL", url: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", wantCode: "DIFFERENT_SPOTIFY_URL"},
+		{name: "異常系: Spotify以外のURL", url: "https://example.com/playlist/abc", wantCode: "NOT_SPOTIFY_URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, err := extractSpotifyPlaylistID(tt.url)
+			if tt.wantCode != "" {
+				if e := domain.AsError(err); e == nil || e.Code != tt.wantCode {
+					t.Errorf("extractSpotifyPlaylistID() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractSpotifyPlaylistID() unexpected error = %v", err)
+			}
+			if gotID != tt.wantID {
+				t.Errorf("extractSpotifyPlaylistID() = %v, want %v", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+// Playlist URLs are told apart from track URLs
+func TestExtractSpotifyTrackID_PlaylistURL(t *testing.T) {
+	_, err := extractSpotifyTrackID("https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M")
+	if e := domain.AsError(err); e == nil || e.Code != "DIFFERENT_SPOTIFY_URL" || e.Params["resource"] != "Track" {
+		t.Errorf("expected DIFFERENT_SPOTIFY_URL for Track, got %v", err)
+	}
+}
+
+// rewriteTransport sends every request to the test server, keeping the path and Host header.
+type rewriteTransport struct {
+	target string
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(rt.target)
+	req = req.Clone(req.Context())
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+	req.URL.Scheme = u.Scheme
+	req.URL.Host = u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// failingTransport fails every request, as if offline.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("network unreachable")
+}
+
+func TestSpotifyID_ShortLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "spotify.link" && r.URL.Path == "/track":
+			http.Redirect(w, r, "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc123", http.StatusTemporaryRedirect)
+		case r.Host == "spotify.link" && r.URL.Path == "/chain":
+			http.Redirect(w, r, "https://spotify.app.link/artist", http.StatusFound)
+		case r.Host == "spotify.app.link" && r.URL.Path == "/artist":
+			http.Redirect(w, r, "https://open.spotify.com/artist/0OdUWJ0sBjDrqHygGUXeCF", http.StatusFound)
+		case r.URL.Path == "/elsewhere":
+			http.Redirect(w, r, "https://example.com/", http.StatusFound)
+		case r.URL.Path == "/lookalike":
+			http.Redirect(w, r, "https://evilspotify.com/open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", http.StatusFound)
+		case r.Host == "evilspotify.com":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	links := NewLinkExpander(&http.Client{Transport: rewriteTransport{target: server.URL}})
+
+	tests := []struct {
+		name         string
+		links        *LinkExpander
+		url          string
+		resourceType string
+		wantID       string
+		wantKind     domain.ErrorKind
+		wantCode     string
+	}{
+		{
+			name:         "正常系: 短縮リンク",
+			links:        links,
+			url:          "https://spotify.link/track",
+			resourceType: "track",
+			wantID:       "4uLU6hMCjMI75M1A2tKUQC",
+		},
+		{
+			name:         "正常系: スキームなしの短縮リンク",
+			links:        links,
+			url:          "spotify.link/track",
+			resourceType: "track",
+			wantID:       "4uLU6hMCjMI75M1A2tKUQC",
+		},
+		{
+			name:         "正常系: 複数回リダイレクトする短縮リンク",
+			links:        links,
+			url:          "https://spotify.link/chain",
+			resourceType: "artist",
+			wantID:       "0OdUWJ0sBjDrqHygGUXeCF",
+		},
+		{
+			name:         "正常系: 短縮リンク以外は展開しない",
+			links:        NewLinkExpander(&http.Client{Transport: failingTransport{}}),
+			url:          "https://open.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy",
+			resourceType: "album",
+			wantID:       "4aawyAB9vmqN3uQ7FjRGTy",
+		},
+		{
+			name:         "異常系: 別の種類を指す短縮リンク",
+			links:        links,
+			url:          "https://spotify.link/track",
+			resourceType: "album",
+			wantKind:     domain.KindInvalidInput,
+			wantCode:     domain.ErrCodeDifferentSpotifyURL,
+		},
+		{
+			name:         "異常系: Spotify以外にリダイレクト",
+			links:        links,
+			url:          "https://spotify.link/elsewhere",
+			resourceType: "track",
+			wantKind:     domain.KindInvalidInput,
+			wantCode:     domain.ErrCodeInvalidURL,
+		},
+		{
+			name:         "異常系: spotify.com で終わる別のホストにリダイレクト",
+			links:        links,
+			url:          "https://spotify.link/lookalike",
+			resourceType: "track",
+			wantKind:     domain.KindInvalidInput,
+			wantCode:     domain.ErrCodeInvalidURL,
+		},
+		{
+			name:         "異常系: 存在しない短縮リンク",
+			links:        links,
+			url:          "https://spotify.link/missing",
+			resourceType: "track",
+			wantKind:     domain.KindInvalidInput,
+			wantCode:     domain.ErrCodeInvalidURL,
+		},
+		{
+			name:         "異常系: 展開に失敗",
+			links:        NewLinkExpander(&http.Client{Transport: failingTransport{}}),
+			url:          "https://spotify.link/track",
+			resourceType: "track",
+			wantKind:     domain.KindUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, err := spotifyID(context.Background(), tt.links, tt.url, tt.resourceType)
+
+			if tt.wantKind != "" {
+				var e *domain.Error
+				if !errors.As(err, &e) || e.Kind != tt.wantKind || (tt.wantCode != "" && e.Code != tt.wantCode) {
+					t.Errorf("spotifyID() error = %v, want kind %s code %s", err, tt.wantKind, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("spotifyID() unexpected error = %v", err)
+			}
+			if gotID != tt.wantID {
+				t.Errorf("spotifyID() = %v, want %v", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestIsSpotifyLink(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{name: "正常系: open.spotify.com", url: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", want: true},
+		{name: "正常系: スキームなし", url: "open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", want: true},
+		{name: "正常系: URI", url: "spotify:track:4uLU6hMCjMI75M1A2tKUQC", want: true},
+		{name: "正常系: 短縮リンク", url: "https://spotify.link/abc", want: true},
+		{name: "異常系: spotify.com で終わる別のホスト", url: "https://evilspotify.com/track/4uLU6hMCjMI75M1A2tKUQC", want: false},
+		{name: "異常系: パスに spotify.com を含む", url: "https://www.deezer.com/track/1?from=open.spotify.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSpotifyLink(tt.url); got != tt.want {
+				t.Errorf("isSpotifyLink(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	usecasev2 "github.com/t1nyb0x/tracktaste/internal/usecase/v2"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+const (
+	// featuresResolveConcurrency bounds concurrent URL resolutions of a batch request.
+	featuresResolveConcurrency = 10
+	// maxFeaturesBatchBody is the largest batch request body accepted (bytes).
+	maxFeaturesBatchBody = 1 << 20
+)
+
+// FeaturesUseCase returns the merged features of Spotify tracks.
+type FeaturesUseCase interface {
+	FetchFeatures(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error)
+	FetchFeaturesBatch(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error)
+}
+
+// FeaturesHandler handles track feature requests.
+type FeaturesHandler struct {
+	featuresUC FeaturesUseCase
+	resolver   TrackResolver // Optional: without it only Spotify URLs are accepted
+	links      *LinkExpander
+}
+
+// NewFeaturesHandler creates a new FeaturesHandler.
+func NewFeaturesHandler(featuresUC FeaturesUseCase) *FeaturesHandler {
+	return &FeaturesHandler{featuresUC: featuresUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *FeaturesHandler) WithLinkExpander(links *LinkExpander) *FeaturesHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *FeaturesHandler) WithResolver(r TrackResolver) *FeaturesHandler {
+	h.resolver = r
+	return h
+}
+
+type trackFeaturesResponse struct {
+	Track      trackSummaryResult `json:"track"`
+	Features   featuresResult     `json:"features"`
+	Genres     []string           `json:"genres"`
+	Sources    map[string]string  `json:"sources"`
+	Resolution *resolutionResult  `json:"resolution,omitempty"`
+}
+
+type featuresResult struct {
+	BPM             float64  `json:"bpm"`
+	DurationSeconds int      `json:"duration_seconds"`
+	Gain            float64  `json:"gain"`
+	Tags            []string `json:"tags"`
+	ArtistMBID      string   `json:"artist_mbid"`
+}
+
+type featuresBatchRequest struct {
+	URLs []string `json:"urls"`
+}
+
+func (r *featuresBatchRequest) batchURLs() []string { return r.URLs }
+
+// batchRequest is a request body with a list of track URLs.
+type batchRequest interface {
+	batchURLs() []string
+}
+
+type featuresBatchResponse struct {
+	Items []featuresBatchItem `json:"items"`
+}
+
+// featuresBatchItem is the result of one URL of a batch; exactly one of Result and Error is set.
+type featuresBatchItem struct {
+	URL    string                 `json:"url"`
+	Result *trackFeaturesResponse `json:"result,omitempty"`
+	Error  *itemError             `json:"error,omitempty"`
+}
+
+type itemError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FetchFeatures handles GET /v2/track/features.
+func (h *FeaturesHandler) FetchFeatures(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "TrackFeatures", "リクエスト開始")
+
+	rawURL := r.URL.Query().Get("url")
+	trackID, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, rawURL)
+	if err != nil {
+		writeError(w, r, "TrackFeatures", err, "INVALID_PARAM")
+		return
+	}
+
+	set, err := h.featuresUC.FetchFeatures(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, "TrackFeatures", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	resp := convertFeatureSet(set)
+	resp.Resolution = resolution
+	logger.InfoContext(r.Context(), "TrackFeatures", "リクエスト完了")
+	success(w, resp)
+}
+
+// FetchFeaturesBatch handles POST /v2/track/features/batch.
+// URLs that cannot be resolved or looked up are reported per item; the request itself succeeds.
+func (h *FeaturesHandler) FetchFeaturesBatch(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "TrackFeatures", "バッチリクエスト開始")
+
+	var req featuresBatchRequest
+	if !decodeBatchRequest(w, r, "TrackFeatures", &req) {
+		return
+	}
+
+	lang := requestLang(r)
+	batch := resolveBatch(r.Context(), h.links, h.resolver, lang, req.URLs)
+	if len(batch.ids) > 0 {
+		sets, err := h.featuresUC.FetchFeaturesBatch(r.Context(), batch.ids)
+		if err != nil {
+			writeError(w, r, "TrackFeatures", err, "SOMETHING_API_ERROR")
+			return
+		}
+		batch.fill(lang, sets)
+	}
+
+	logger.InfoContext(r.Context(), "TrackFeatures", "バッチリクエスト完了")
+	success(w, featuresBatchResponse{Items: batch.items})
+}
+
+// decodeBatchRequest reads a batch request body into req, writing the error response
+// and returning false if the body is invalid or its URLs are empty or too many.
+func decodeBatchRequest(w http.ResponseWriter, r *http.Request, feature string, req batchRequest) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFeaturesBatchBody)).Decode(req); err != nil {
+		writeError(w, r, feature, domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "invalid request body"), "INVALID_PARAM")
+		return false
+	}
+	if len(req.batchURLs()) == 0 {
+		writeError(w, r, feature, domain.NewInvalidInputError(domain.ErrCodeEmptyParam, "urls is empty"), "INVALID_PARAM")
+		return false
+	}
+	if len(req.batchURLs()) > usecasev2.MaxFeaturesBatchSize {
+		writeError(w, r, feature, domain.NewTooManyTracksError(usecasev2.MaxFeaturesBatchSize), "INVALID_PARAM")
+		return false
+	}
+	return true
+}
+
+// resolvedBatch is a batch request whose URLs have been resolved to Spotify tracks.
+type resolvedBatch struct {
+	items       []featuresBatchItem // Error is set for URLs that could not be resolved
+	resolutions []*resolutionResult
+	ids         []string // Track IDs of the resolved URLs, to be looked up
+	positions   []int    // Index in items of each of ids
+}
+
+// resolveBatch resolves urls concurrently.
+func resolveBatch(ctx context.Context, links *LinkExpander, resolver TrackResolver, lang string, urls []string) *resolvedBatch {
+	b := &resolvedBatch{
+		items:       make([]featuresBatchItem, len(urls)),
+		resolutions: make([]*resolutionResult, len(urls)),
+	}
+	trackIDs := make([]string, len(urls))
+
+	sem := make(chan struct{}, featuresResolveConcurrency)
+	g := safego.NewGroup("TrackFeatures")
+	for i, rawURL := range urls {
+		b.items[i].URL = rawURL
+		g.Go("resolve", func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			trackID, resolution, err := resolveTrackURL(ctx, links, resolver, rawURL)
+			if err != nil {
+				b.items[i].Error = newItemError(lang, err)
+				return
+			}
+			trackIDs[i] = trackID
+			b.resolutions[i] = resolution
+		})
+	}
+	g.Wait()
+
+	// Look up only the resolved URLs, remembering where each one came from
+	for i, id := range trackIDs {
+		if b.items[i].Error == nil {
+			b.ids = append(b.ids, id)
+			b.positions = append(b.positions, i)
+		}
+	}
+	return b
+}
+
+// fill sets the items of the feature sets looked up for ids, in the same order.
+func (b *resolvedBatch) fill(lang string, sets []domain.TrackFeatureSet) {
+	for j, set := range sets {
+		i := b.positions[j]
+		if set.Err != nil {
+			b.items[i].Error = newItemError(lang, set.Err)
+			continue
+		}
+		resp := convertFeatureSet(&set)
+		resp.Resolution = b.resolutions[i]
+		b.items[i].Result = &resp
+	}
+}
+
+// newItemError converts err into the code and localised message of a batch item.
+func newItemError(lang string, err error) *itemError {
+	_, code, message := describeError(lang, domain.AsError(err), "SOMETHING_API_ERROR")
+	return &itemError{Code: code, Message: message}
+}
+
+// convertFeatureSet converts a feature set into its response form.
+func convertFeatureSet(set *domain.TrackFeatureSet) trackFeaturesResponse {
+	resp := trackFeaturesResponse{
+		Genres:  set.Genres,
+		Sources: make(map[string]string, len(set.Sources)),
+	}
+	if resp.Genres == nil {
+		resp.Genres = []string{}
+	}
+	if set.Track != nil {
+		resp.Track = trackSummaryResult{
+			ID:     set.Track.ID,
+			Name:   set.Track.Name,
+			Artist: primaryArtistName(set.Track),
+			ISRC:   trackISRC(set.Track),
+		}
+	}
+	if set.Features != nil {
+		resp.Features = featuresResult{
+			BPM:             set.Features.BPM,
+			DurationSeconds: set.Features.DurationSeconds,
+			Gain:            set.Features.Gain,
+			Tags:            set.Features.Tags,
+			ArtistMBID:      set.Features.ArtistMBID,
+		}
+	}
+	if resp.Features.Tags == nil {
+		resp.Features.Tags = []string{}
+	}
+	for field, source := range set.Sources {
+		resp.Sources[field] = string(source)
+	}
+	return resp
+}
+
+// primaryArtistName returns the name of the track's first artist.
+func primaryArtistName(t *domain.Track) string {
+	if len(t.Artists) == 0 {
+		return ""
+	}
+	return t.Artists[0].Name
+}
+
+// trackISRC returns the ISRC of the track, or "" if it has none.
+func trackISRC(t *domain.Track) string {
+	if t.ISRC == nil {
+		return ""
+	}
+	return *t.ISRC
+}
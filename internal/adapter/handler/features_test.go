@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockFeaturesUseCase for features handler tests
+type mockFeaturesUseCase struct {
+	fetchFeaturesFunc      func(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error)
+	fetchFeaturesBatchFunc func(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error)
+}
+
+func (m *mockFeaturesUseCase) FetchFeatures(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error) {
+	return m.fetchFeaturesFunc(ctx, trackID)
+}
+
+func (m *mockFeaturesUseCase) FetchFeaturesBatch(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error) {
+	return m.fetchFeaturesBatchFunc(ctx, trackIDs)
+}
+
+func testFeatureSet(trackID string) domain.TrackFeatureSet {
+	isrc := "JPU901800200"
+	return domain.TrackFeatureSet{
+		Track:    &domain.Track{ID: trackID, Name: "Lemon", ISRC: &isrc, Artists: []domain.Artist{{Name: "米津玄師"}}},
+		Features: &domain.TrackFeatures{TrackID: trackID, ISRC: isrc, BPM: 87, DurationSeconds: 255, Gain: -7.5},
+		Genres:   []string{"j-pop"},
+		Sources: map[string]domain.FeatureSource{
+			"bpm":              domain.FeatureSourceDeezer,
+			"duration_seconds": domain.FeatureSourceDeezer,
+			"gain":             domain.FeatureSourceDeezer,
+			"genres":           domain.FeatureSourceSpotifyGenres,
+		},
+	}
+}
+
+func TestFeaturesHandler_FetchFeatures(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		fetchFeatures  func(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "正常系: 特徴量と取得元",
+			url:  "https://open.spotify.com/track/abc123",
+			fetchFeatures: func(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error) {
+				set := testFeatureSet(trackID)
+				return &set, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 空のURL",
+			url:            "",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name: "異常系: 曲が見つからない",
+			url:  "https://open.spotify.com/track/abc123",
+			fetchFeatures: func(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error) {
+				return nil, domain.ErrTrackNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "TRACK_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewFeaturesHandler(&mockFeaturesUseCase{fetchFeaturesFunc: tt.fetchFeatures})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/track/features?url="+tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			h.FetchFeatures(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			features, _ := result["features"].(map[string]interface{})
+			if features["bpm"] != 87.0 || features["duration_seconds"] != 255.0 {
+				t.Errorf("unexpected features: %v", features)
+			}
+			sources, _ := result["sources"].(map[string]interface{})
+			if sources["bpm"] != "deezer" || sources["genres"] != "spotify_genres" {
+				t.Errorf("unexpected sources: %v", sources)
+			}
+			if _, ok := sources["tags"]; ok {
+				t.Errorf("tags should have no source: %v", sources)
+			}
+			track, _ := result["track"].(map[string]interface{})
+			if track["artist"] != "米津玄師" || track["isrc"] != "JPU901800200" {
+				t.Errorf("unexpected track: %v", track)
+			}
+			genres, _ := result["genres"].([]interface{})
+			if len(genres) != 1 || genres[0] != "j-pop" {
+				t.Errorf("unexpected genres: %v", genres)
+			}
+		})
+	}
+}
+
+func TestFeaturesHandler_FetchFeaturesBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		fetchBatch     func(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error)
+		expectedStatus int
+		expectedCode   string
+		expectedItems  []string // Per item: "ok" or the error code
+	}{
+		{
+			name: "正常系: 解決できないURLと見つからない曲は項目ごとのエラー",
+			body: `{"urls": ["https://open.spotify.com/track/aaa", "https://example.com/track/1", "https://open.spotify.com/track/bbb"]}`,
+			fetchBatch: func(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error) {
+				if len(trackIDs) != 2 || trackIDs[0] != "aaa" || trackIDs[1] != "bbb" {
+					return nil, fmt.Errorf("unexpected ids: %v", trackIDs)
+				}
+				return []domain.TrackFeatureSet{testFeatureSet("aaa"), {Err: domain.ErrTrackNotFound}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedItems:  []string{"ok", "NOT_SPOTIFY_URL", "TRACK_NOT_FOUND"},
+		},
+		{
+			name:           "異常系: 不正なJSON",
+			body:           `{"urls": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PARAM",
+		},
+		{
+			name:           "異常系: URLが空",
+			body:           `{"urls": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name:           "異常系: 101曲",
+			body:           `{"urls": [` + strings.TrimSuffix(strings.Repeat(`"https://open.spotify.com/track/aaa",`, 101), ",") + `]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "TOO_MANY_TRACKS",
+		},
+		{
+			name: "異常系: ユースケースのエラー",
+			body: `{"urls": ["https://open.spotify.com/track/aaa"]}`,
+			fetchBatch: func(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error) {
+				return nil, domain.ErrUpstreamUnavailable
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   "UPSTREAM_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewFeaturesHandler(&mockFeaturesUseCase{fetchFeaturesBatchFunc: tt.fetchBatch})
+
+			req := httptest.NewRequest(http.MethodPost, "/v2/track/features/batch", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			h.FetchFeaturesBatch(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			items, _ := result["items"].([]interface{})
+			if len(items) != len(tt.expectedItems) {
+				t.Fatalf("expected %d items, got %d", len(tt.expectedItems), len(items))
+			}
+			for i, want := range tt.expectedItems {
+				item, _ := items[i].(map[string]interface{})
+				if want == "ok" {
+					if item["result"] == nil || item["error"] != nil {
+						t.Errorf("item %d: expected result, got %v", i, item)
+					}
+					continue
+				}
+				itemErr, _ := item["error"].(map[string]interface{})
+				if itemErr["code"] != want || itemErr["message"] == "" {
+					t.Errorf("item %d: expected error %s, got %v", i, want, item)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	usecasev2 "github.com/t1nyb0x/tracktaste/internal/usecase/v2"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// GenerateUseCase builds a playlist from the recommendations of seed tracks.
+type GenerateUseCase interface {
+	GeneratePlaylist(ctx context.Context, seedIDs []string, mode domain.RecommendMode, c domain.PlaylistConstraints) (*domain.GeneratedPlaylist, error)
+}
+
+// GenerateHandler handles playlist generation requests.
+type GenerateHandler struct {
+	generateUC GenerateUseCase
+	resolver   TrackResolver // Optional: without it only Spotify URLs are accepted
+	links      *LinkExpander
+}
+
+// NewGenerateHandler creates a new GenerateHandler.
+func NewGenerateHandler(generateUC GenerateUseCase) *GenerateHandler {
+	return &GenerateHandler{generateUC: generateUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *GenerateHandler) WithLinkExpander(links *LinkExpander) *GenerateHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *GenerateHandler) WithResolver(r TrackResolver) *GenerateHandler {
+	h.resolver = r
+	return h
+}
+
+// generateRequest is the body of POST /v2/playlist/generate.
+// Constraints left out take the values of usecasev2.DefaultPlaylistConstraints.
+type generateRequest struct {
+	Seeds         []string `json:"seeds"`
+	Mode          string   `json:"mode"`
+	DurationMs    *int     `json:"duration_ms"`
+	ToleranceMs   *int     `json:"tolerance_ms"`
+	MaxPerArtist  *int     `json:"max_per_artist"`
+	MinGenreShare *float64 `json:"min_genre_share"`
+	MinBPM        float64  `json:"min_bpm"`
+	MaxBPM        float64  `json:"max_bpm"`
+}
+
+func (r *generateRequest) batchURLs() []string { return r.Seeds }
+
+// constraints returns the requested constraints over the defaults.
+func (r *generateRequest) constraints() domain.PlaylistConstraints {
+	c := usecasev2.DefaultPlaylistConstraints()
+	if r.DurationMs != nil {
+		c.TargetDurationMs = *r.DurationMs
+	}
+	if r.ToleranceMs != nil {
+		c.DurationToleranceMs = *r.ToleranceMs
+	}
+	if r.MaxPerArtist != nil {
+		c.MaxTracksPerArtist = *r.MaxPerArtist
+	}
+	if r.MinGenreShare != nil {
+		c.MinGenreShare = *r.MinGenreShare
+	}
+	c.MinBPM, c.MaxBPM = r.MinBPM, r.MaxBPM
+	return c
+}
+
+type generateResponse struct {
+	Seeds           []trackSummaryResult     `json:"seeds"`
+	GenreGroup      string                   `json:"genre_group"`
+	Mode            string                   `json:"mode"`
+	Constraints     constraintsResult        `json:"constraints"`
+	Satisfied       bool                     `json:"satisfied"`
+	Unmet           []itemError              `json:"unmet"`
+	Tracks          []recommendedTrackResult `json:"tracks"`
+	TotalDurationMs int                      `json:"total_duration_ms"`
+	GenreShare      float64                  `json:"genre_share"`
+	Candidates      int                      `json:"candidates"`
+	Excluded        map[string]int           `json:"excluded"`
+	DegradedSources []string                 `json:"degraded_sources,omitempty"`
+	Partial         bool                     `json:"partial"`
+	PartialStages   []string                 `json:"partial_stages,omitempty"`
+	SkippedSeeds    []string                 `json:"skipped_seeds,omitempty"`
+	Resolutions     []*resolutionResult      `json:"resolutions,omitempty"` // Per seed, for seeds given as non-Spotify URLs
+}
+
+type constraintsResult struct {
+	DurationMs    int     `json:"duration_ms"`
+	ToleranceMs   int     `json:"tolerance_ms"`
+	MaxPerArtist  int     `json:"max_per_artist"`
+	MinGenreShare float64 `json:"min_genre_share"`
+	MinBPM        float64 `json:"min_bpm,omitempty"`
+	MaxBPM        float64 `json:"max_bpm,omitempty"`
+}
+
+// GeneratePlaylist handles POST /v2/playlist/generate.
+// A playlist that misses its constraints is still returned, with satisfied false
+// and the reasons in unmet.
+func (h *GenerateHandler) GeneratePlaylist(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "Generate", "リクエスト開始")
+
+	var req generateRequest
+	if !decodeBatchRequest(w, r, "Generate", &req) {
+		return
+	}
+	if len(req.Seeds) > usecasev2.MaxGenerateSeeds {
+		writeError(w, r, "Generate", domain.NewTooManyTracksError(usecasev2.MaxGenerateSeeds), "INVALID_PARAM")
+		return
+	}
+
+	seedIDs := make([]string, len(req.Seeds))
+	resolutions := make([]*resolutionResult, len(req.Seeds))
+	resolved := false
+	for i, url := range req.Seeds {
+		id, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, url)
+		if err != nil {
+			writeError(w, r, "Generate", err, "INVALID_PARAM")
+			return
+		}
+		seedIDs[i], resolutions[i] = id, resolution
+		resolved = resolved || resolution != nil
+	}
+
+	c := req.constraints()
+	playlist, err := h.generateUC.GeneratePlaylist(r.Context(), seedIDs, domain.ParseRecommendMode(req.Mode), c)
+	if err != nil {
+		writeError(w, r, "Generate", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	resp := convertGeneratedPlaylist(requestLang(r), playlist)
+	if resolved {
+		resp.Resolutions = resolutions
+	}
+	logger.InfoContext(r.Context(), "Generate", "リクエスト完了")
+	success(w, resp)
+}
+
+// convertGeneratedPlaylist converts a generated playlist, with its unmet constraints in lang.
+func convertGeneratedPlaylist(lang string, p *domain.GeneratedPlaylist) generateResponse {
+	resp := generateResponse{
+		Seeds:      make([]trackSummaryResult, len(p.Seeds)),
+		GenreGroup: p.GenreGroup,
+		Mode:       string(p.Mode),
+		Constraints: constraintsResult{
+			DurationMs:    p.Constraints.TargetDurationMs,
+			ToleranceMs:   p.Constraints.DurationToleranceMs,
+			MaxPerArtist:  p.Constraints.MaxTracksPerArtist,
+			MinGenreShare: p.Constraints.MinGenreShare,
+			MinBPM:        p.Constraints.MinBPM,
+			MaxBPM:        p.Constraints.MaxBPM,
+		},
+		Satisfied:       p.Satisfied(),
+		Unmet:           make([]itemError, len(p.Unmet)),
+		Tracks:          make([]recommendedTrackResult, len(p.Tracks)),
+		TotalDurationMs: p.TotalDurationMs,
+		GenreShare:      p.GenreShare,
+		Candidates:      p.Candidates,
+		Excluded:        p.Excluded,
+		DegradedSources: p.DegradedSources,
+		Partial:         p.Partial,
+		SkippedSeeds:    p.SkippedSeeds,
+	}
+	if resp.Excluded == nil {
+		resp.Excluded = map[string]int{}
+	}
+	for i := range p.Seeds {
+		seed := &p.Seeds[i]
+		resp.Seeds[i] = trackSummaryResult{ID: seed.ID, Name: seed.Name, Artist: primaryArtistName(seed), ISRC: trackISRC(seed)}
+	}
+	for i, err := range p.Unmet {
+		resp.Unmet[i] = *newItemError(lang, err)
+	}
+	for i := range p.Tracks {
+		resp.Tracks[i] = convertRecommendedTrack(&p.Tracks[i])
+	}
+	for _, stage := range p.PartialStages {
+		resp.PartialStages = append(resp.PartialStages, string(stage))
+	}
+	return resp
+}
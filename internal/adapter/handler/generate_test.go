@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockGenerateUseCase returns playlist, or err, and records what it was asked for
+type mockGenerateUseCase struct {
+	playlist *domain.GeneratedPlaylist
+	err      error
+
+	gotSeeds []string
+	gotMode  domain.RecommendMode
+	gotC     domain.PlaylistConstraints
+}
+
+func (m *mockGenerateUseCase) GeneratePlaylist(ctx context.Context, seedIDs []string, mode domain.RecommendMode, c domain.PlaylistConstraints) (*domain.GeneratedPlaylist, error) {
+	m.gotSeeds, m.gotMode, m.gotC = seedIDs, mode, c
+	if m.err != nil {
+		return nil, m.err
+	}
+	p := *m.playlist
+	p.Mode, p.Constraints = mode, c
+	return &p, nil
+}
+
+func TestGenerateHandler_GeneratePlaylist(t *testing.T) {
+	satisfied := &domain.GeneratedPlaylist{
+		Seeds:      []domain.Track{{ID: "seed1", Name: "Seed"}},
+		GenreGroup: "otaku",
+		Tracks: []domain.RecommendedTrack{
+			{Track: domain.Track{ID: "t1", Name: "Track 1", DurationMs: 240000}, FinalScore: 0.9},
+			{Track: domain.Track{ID: "t2", Name: "Track 2", DurationMs: 200000}, FinalScore: 0.8},
+		},
+		TotalDurationMs: 440000,
+		GenreShare:      1,
+		Candidates:      10,
+		Excluded:        map[string]int{domain.ExcludedExplicit: 2},
+	}
+	unmet := *satisfied
+	c := domain.PlaylistConstraints{TargetDurationMs: 3600000, DurationToleranceMs: 180000, MaxTracksPerArtist: 2}
+	unmet.Unmet = []*domain.Error{domain.NewDurationNotMetError(c, 440000), domain.NewLimitedByArtistCapError(2)}
+
+	tests := []struct {
+		name           string
+		body           string
+		uc             *mockGenerateUseCase
+		expectedStatus int
+		expectedCode   string
+		wantSatisfied  bool
+		wantUnmet      []string
+		check          func(t *testing.T, uc *mockGenerateUseCase)
+	}{
+		{
+			name:           "正常系: 省略した制約はデフォルト",
+			body:           `{"seeds": ["https://open.spotify.com/track/seed1"], "duration_ms": 1800000, "min_bpm": 120}`,
+			uc:             &mockGenerateUseCase{playlist: satisfied},
+			expectedStatus: http.StatusOK,
+			wantSatisfied:  true,
+			check: func(t *testing.T, uc *mockGenerateUseCase) {
+				want := domain.PlaylistConstraints{TargetDurationMs: 1800000, DurationToleranceMs: 180000, MaxTracksPerArtist: 2, MinGenreShare: 0.5, MinBPM: 120}
+				if uc.gotC != want {
+					t.Errorf("expected constraints %+v, got %+v", want, uc.gotC)
+				}
+				if len(uc.gotSeeds) != 1 || uc.gotSeeds[0] != "seed1" || uc.gotMode != domain.RecommendModeBalanced {
+					t.Errorf("unexpected seeds %v / mode %s", uc.gotSeeds, uc.gotMode)
+				}
+			},
+		},
+		{
+			name:           "正常系: 満たせない制約の説明",
+			body:           `{"seeds": ["spotify:track:seed1"], "mode": "similar", "max_per_artist": 2, "min_genre_share": 0}`,
+			uc:             &mockGenerateUseCase{playlist: &unmet},
+			expectedStatus: http.StatusOK,
+			wantUnmet:      []string{"DURATION_NOT_MET", "LIMITED_BY_ARTIST_CAP"},
+			check: func(t *testing.T, uc *mockGenerateUseCase) {
+				if uc.gotC.MinGenreShare != 0 || uc.gotMode != domain.RecommendModeSimilar {
+					t.Errorf("unexpected constraints %+v / mode %s", uc.gotC, uc.gotMode)
+				}
+			},
+		},
+		{
+			name:           "異常系: シードが空",
+			body:           `{"seeds": []}`,
+			uc:             &mockGenerateUseCase{playlist: satisfied},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name:           "異常系: シードが多すぎる",
+			body:           `{"seeds": ["spotify:track:1", "spotify:track:2", "spotify:track:3", "spotify:track:4", "spotify:track:5", "spotify:track:6"]}`,
+			uc:             &mockGenerateUseCase{playlist: satisfied},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "TOO_MANY_TRACKS",
+		},
+		{
+			name:           "異常系: 対応していないURL",
+			body:           `{"seeds": ["https://example.com/track/1"]}`,
+			uc:             &mockGenerateUseCase{playlist: satisfied},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "NOT_SPOTIFY_URL",
+		},
+		{
+			name:           "異常系: 制約が不正",
+			body:           `{"seeds": ["spotify:track:seed1"], "duration_ms": -1}`,
+			uc:             &mockGenerateUseCase{err: domain.NewInvalidInputError("INVALID_PARAM", "duration_ms is out of range")},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PARAM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewGenerateHandler(tt.uc)
+
+			req := httptest.NewRequest(http.MethodPost, "/v2/playlist/generate", strings.NewReader(tt.body))
+			req.Header.Set("Accept-Language", "en")
+			rec := httptest.NewRecorder()
+
+			h.GeneratePlaylist(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			if result["satisfied"] != tt.wantSatisfied {
+				t.Errorf("expected satisfied %v, got %v", tt.wantSatisfied, result["satisfied"])
+			}
+			if tracks, _ := result["tracks"].([]interface{}); len(tracks) != 2 {
+				t.Errorf("expected 2 tracks, got %v", result["tracks"])
+			}
+			if result["total_duration_ms"] != float64(440000) || result["genre_group"] != "otaku" {
+				t.Errorf("unexpected summary: %v", result)
+			}
+			if excluded, _ := result["excluded"].(map[string]interface{}); excluded["explicit"] != float64(2) {
+				t.Errorf("unexpected excluded counts: %v", result["excluded"])
+			}
+
+			unmet, _ := result["unmet"].([]interface{})
+			if len(unmet) != len(tt.wantUnmet) {
+				t.Fatalf("expected unmet %v, got %v", tt.wantUnmet, unmet)
+			}
+			for i, code := range tt.wantUnmet {
+				item, _ := unmet[i].(map[string]interface{})
+				if item["code"] != code || item["message"] == "" {
+					t.Errorf("unmet %d: expected %s, got %v", i, code, item)
+				}
+			}
+			if len(unmet) > 0 {
+				first, _ := unmet[0].(map[string]interface{})
+				if msg, _ := first["message"].(string); !strings.Contains(msg, "7:20") || !strings.Contains(msg, "60:00") {
+					t.Errorf("expected the durations in the message, got %q", msg)
+				}
+			}
+			tt.check(t, tt.uc)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"runtime"
 	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/health"
 )
 
 // Version はビルド時に設定されるバージョン情報
@@ -17,6 +19,24 @@ var (
 type HealthHandler struct {
 	startTime       time.Time
 	enabledServices EnabledServices
+	breakers        map[string]CircuitBreaker
+	prober          Prober
+}
+
+// Prober は依存サービスを能動的に監視した最新の結果を返します
+type Prober interface {
+	// Statuses はサービスごとの最新の監視結果を返します
+	Statuses() []health.Status
+	// Ready は失敗中の必須サービスがないかを返します
+	Ready() bool
+	// Failing は失敗中（一度も成功していない、または連続で失敗している）の必須サービスを返します
+	Failing() []string
+}
+
+// CircuitBreaker は外部APIごとのサーキットブレーカーの状態を返します
+type CircuitBreaker interface {
+	// Status は "closed"、"open"、"half_open" のいずれかを返します
+	Status() string
 }
 
 // EnabledServices は有効化されているサービスの設定
@@ -39,6 +59,8 @@ type HealthResponse struct {
 	Uptime    string       `json:"uptime"`
 	Runtime   RuntimeInfo  `json:"runtime"`
 	Services  ServicesInfo `json:"services"`
+	// CircuitBreakers は外部APIごとのサーキットブレーカーの状態
+	CircuitBreakers map[string]string `json:"circuit_breakers,omitempty"`
 }
 
 // RuntimeInfo はGoランタイムの情報
@@ -69,6 +91,19 @@ func NewHealthHandler(services EnabledServices) *HealthHandler {
 	}
 }
 
+// WithCircuitBreakers はレスポンスに含めるサーキットブレーカーを設定します
+// キーはサービス名（services と同じ名前）です
+func (h *HealthHandler) WithCircuitBreakers(breakers map[string]CircuitBreaker) *HealthHandler {
+	h.breakers = breakers
+	return h
+}
+
+// WithProber は /readyz と /healthz/details が参照する監視結果を設定します
+func (h *HealthHandler) WithProber(p Prober) *HealthHandler {
+	h.prober = p
+	return h
+}
+
 // Check はヘルスチェックを実行します
 func (h *HealthHandler) Check(w http.ResponseWriter, _ *http.Request) {
 	uptime := time.Since(h.startTime).Round(time.Second)
@@ -97,9 +132,140 @@ func (h *HealthHandler) Check(w http.ResponseWriter, _ *http.Request) {
 		},
 	}
 
+	// 開いているブレーカーがあれば縮退中として報告する
+	var open bool
+	response.CircuitBreakers, open = h.breakerStates()
+	if open {
+		response.Status = "degraded"
+	}
+
+	success(w, response)
+}
+
+// breakerStates はサーキットブレーカーの状態と、閉じていないものがあるかを返します
+func (h *HealthHandler) breakerStates() (map[string]string, bool) {
+	if len(h.breakers) == 0 {
+		return nil, false
+	}
+	states := make(map[string]string, len(h.breakers))
+	open := false
+	for name, b := range h.breakers {
+		state := b.Status()
+		states[name] = state
+		if state != "closed" {
+			open = true
+		}
+	}
+	return states, open
+}
+
+// ReadyResponse は readiness チェックのレスポンス
+type ReadyResponse struct {
+	Status  string   `json:"status"`            // "ready" または "not_ready"
+	Failing []string `json:"failing,omitempty"` // 連続で失敗中（または未確認）の必須サービス
+}
+
+// DetailsResponse は詳細ヘルスチェックのレスポンス
+type DetailsResponse struct {
+	// Status は "healthy"、"degraded"（任意サービスの失敗・ブレーカー作動中）、
+	// "unhealthy"（必須サービスの失敗）のいずれか
+	Status          string            `json:"status"`
+	Ready           bool              `json:"ready"`
+	Version         string            `json:"version"`
+	Uptime          string            `json:"uptime"`
+	Services        []ServiceDetail   `json:"services"`
+	CircuitBreakers map[string]string `json:"circuit_breakers,omitempty"`
+}
+
+// ServiceDetail はサービスごとの監視結果
+type ServiceDetail struct {
+	Name          string     `json:"name"`
+	Critical      bool       `json:"critical"`
+	Status        string     `json:"status"` // "up"、"down"、"unknown"（未確認）
+	LatencyMs     int64      `json:"latency_ms"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+}
+
+// Ready は readiness チェックを実行します
+// 必須サービスの監視が連続で失敗している（または一度も成功していない）間は 503 を返し、
+// Kubernetes がこのレプリカをルーティングから外せるようにします
+func (h *HealthHandler) Ready(w http.ResponseWriter, _ *http.Request) {
+	response := ReadyResponse{Status: "ready"}
+	if h.prober != nil && !h.prober.Ready() {
+		response.Status = "not_ready"
+		response.Failing = h.prober.Failing()
+		writeJSON(w, http.StatusServiceUnavailable, successResponse{Status: http.StatusServiceUnavailable, Result: response})
+		return
+	}
+	success(w, response)
+}
+
+// Details はサービスごとの監視結果（レイテンシ・最後のエラー・最後の成功）を返します
+// 必須サービスが失敗していれば 503 を返します
+func (h *HealthHandler) Details(w http.ResponseWriter, _ *http.Request) {
+	response := DetailsResponse{
+		Status:   "healthy",
+		Ready:    true,
+		Version:  Version,
+		Uptime:   time.Since(h.startTime).Round(time.Second).String(),
+		Services: []ServiceDetail{},
+	}
+
+	var open bool
+	response.CircuitBreakers, open = h.breakerStates()
+	if open {
+		response.Status = "degraded"
+	}
+
+	if h.prober != nil {
+		for _, s := range h.prober.Statuses() {
+			detail := serviceDetail(s)
+			response.Services = append(response.Services, detail)
+			if detail.Status != "up" {
+				response.Status = "degraded"
+			}
+		}
+		response.Ready = h.prober.Ready()
+	}
+
+	if !response.Ready {
+		response.Status = "unhealthy"
+		writeJSON(w, http.StatusServiceUnavailable, successResponse{Status: http.StatusServiceUnavailable, Result: response})
+		return
+	}
 	success(w, response)
 }
 
+func serviceDetail(s health.Status) ServiceDetail {
+	detail := ServiceDetail{
+		Name:          s.Name,
+		Critical:      s.Critical,
+		Status:        "unknown",
+		LatencyMs:     s.Latency.Milliseconds(),
+		LastError:     s.LastError,
+		LastErrorAt:   timeOrNil(s.LastErrorAt),
+		LastSuccessAt: timeOrNil(s.LastSuccess),
+		LastCheckedAt: timeOrNil(s.LastChecked),
+	}
+	if s.Checked {
+		detail.Status = "down"
+		if s.Healthy {
+			detail.Status = "up"
+		}
+	}
+	return detail
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func serviceStatus(enabled bool) string {
 	if enabled {
 		return "enabled"
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/health"
 )
 
 func TestHealthHandler_Check(t *testing.T) {
@@ -290,3 +295,223 @@ func TestNewHealthHandler(t *testing.T) {
 		t.Error("startTime should be set")
 	}
 }
+
+type stubBreaker string
+
+func (b stubBreaker) Status() string { return string(b) }
+
+func TestHealthHandler_CircuitBreakers(t *testing.T) {
+	tests := []struct {
+		name       string
+		breakers   map[string]CircuitBreaker
+		wantStatus string
+	}{
+		{
+			name:       "all closed",
+			breakers:   map[string]CircuitBreaker{"lastfm": stubBreaker("closed"), "youtube_music": stubBreaker("closed")},
+			wantStatus: "healthy",
+		},
+		{
+			name:       "one open",
+			breakers:   map[string]CircuitBreaker{"lastfm": stubBreaker("closed"), "youtube_music": stubBreaker("open")},
+			wantStatus: "degraded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(EnabledServices{}).WithCircuitBreakers(tt.breakers)
+
+			req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			w := httptest.NewRecorder()
+			h.Check(w, req)
+
+			var resp successResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			result := resp.Result.(map[string]interface{})
+
+			if result["status"] != tt.wantStatus {
+				t.Errorf("status = %v, want %s", result["status"], tt.wantStatus)
+			}
+			states, ok := result["circuit_breakers"].(map[string]interface{})
+			if !ok {
+				t.Fatal("circuit_breakers is not a map")
+			}
+			for name, b := range tt.breakers {
+				if states[name] != b.Status() {
+					t.Errorf("circuit_breakers[%s] = %v, want %s", name, states[name], b.Status())
+				}
+			}
+		})
+	}
+}
+
+// probedProber returns a prober that has run one round of checks returning the given errors.
+func probedProber(t *testing.T, critical map[string]bool, errs map[string]error) *health.Prober {
+	t.Helper()
+	var checks []health.Check
+	for _, name := range []string{"spotify", "kkbox", "redis"} {
+		err := errs[name]
+		checks = append(checks, health.Check{Name: name, Critical: critical[name], Ping: func(context.Context) error { return err }})
+	}
+	p := health.NewProber(checks, time.Hour, time.Second)
+	p.ProbeAll(context.Background())
+	return p
+}
+
+// flakyProber returns a prober whose critical check succeeded once and then failed once.
+func flakyProber() *health.Prober {
+	var probes int
+	p := health.NewProber([]health.Check{{Name: "spotify", Critical: true, Ping: func(context.Context) error {
+		probes++
+		if probes > 1 {
+			return errors.New("spotify token: status 503")
+		}
+		return nil
+	}}}, time.Hour, time.Second)
+	p.ProbeAll(context.Background())
+	p.ProbeAll(context.Background())
+	return p
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	critical := map[string]bool{"spotify": true, "kkbox": true}
+
+	tests := []struct {
+		name        string
+		prober      Prober
+		wantCode    int
+		wantStatus  string
+		wantFailing []interface{}
+	}{
+		{
+			name:       "正常系: Proberなし",
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+		},
+		{
+			name:       "正常系: Redisの失敗ではreadyのまま",
+			prober:     probedProber(t, critical, map[string]error{"redis": errors.New("connection refused")}),
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+		},
+		{
+			name:       "正常系: 成功後の一度だけの失敗ではreadyのまま",
+			prober:     flakyProber(),
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+		},
+		{
+			name:        "異常系: Spotifyの認証情報が無効",
+			prober:      probedProber(t, critical, map[string]error{"spotify": errors.New("spotify token: status 400")}),
+			wantCode:    http.StatusServiceUnavailable,
+			wantStatus:  "not_ready",
+			wantFailing: []interface{}{"spotify"},
+		},
+		{
+			name:        "異常系: 初回の監視前",
+			prober:      health.NewProber([]health.Check{{Name: "kkbox", Critical: true, Ping: func(context.Context) error { return nil }}}, 0, 0),
+			wantCode:    http.StatusServiceUnavailable,
+			wantStatus:  "not_ready",
+			wantFailing: []interface{}{"kkbox"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(EnabledServices{})
+			if tt.prober != nil {
+				h.WithProber(tt.prober)
+			}
+
+			w := httptest.NewRecorder()
+			h.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", w.Code, tt.wantCode)
+			}
+			var resp successResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			result := resp.Result.(map[string]interface{})
+			if result["status"] != tt.wantStatus {
+				t.Errorf("status = %v, want %s", result["status"], tt.wantStatus)
+			}
+			if tt.wantFailing != nil && !reflect.DeepEqual(result["failing"], tt.wantFailing) {
+				t.Errorf("failing = %v, want %v", result["failing"], tt.wantFailing)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Details(t *testing.T) {
+	critical := map[string]bool{"spotify": true, "kkbox": true}
+
+	tests := []struct {
+		name       string
+		errs       map[string]error
+		wantCode   int
+		wantStatus string
+		wantState  map[string]string
+	}{
+		{
+			name:       "正常系: 全サービス正常",
+			wantCode:   http.StatusOK,
+			wantStatus: "healthy",
+			wantState:  map[string]string{"spotify": "up", "kkbox": "up", "redis": "up"},
+		},
+		{
+			name:       "正常系: Redisが停止",
+			errs:       map[string]error{"redis": errors.New("connection refused")},
+			wantCode:   http.StatusOK,
+			wantStatus: "degraded",
+			wantState:  map[string]string{"spotify": "up", "kkbox": "up", "redis": "down"},
+		},
+		{
+			name:       "異常系: KKBOXが停止",
+			errs:       map[string]error{"kkbox": errors.New("kkbox token: status 503")},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "unhealthy",
+			wantState:  map[string]string{"spotify": "up", "kkbox": "down", "redis": "up"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(EnabledServices{}).WithProber(probedProber(t, critical, tt.errs))
+
+			w := httptest.NewRecorder()
+			h.Details(w, httptest.NewRequest(http.MethodGet, "/healthz/details", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", w.Code, tt.wantCode)
+			}
+			var resp struct {
+				Result DetailsResponse `json:"result"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Result.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", resp.Result.Status, tt.wantStatus)
+			}
+			for _, s := range resp.Result.Services {
+				if s.Status != tt.wantState[s.Name] {
+					t.Errorf("%s: status = %s, want %s", s.Name, s.Status, tt.wantState[s.Name])
+				}
+				if s.LastCheckedAt == nil {
+					t.Errorf("%s: last_checked_at not set", s.Name)
+				}
+				if s.Status == "down" && (s.LastError == "" || s.LastErrorAt == nil) {
+					t.Errorf("%s: last_error not set", s.Name)
+				}
+				if s.Status == "up" && s.LastSuccessAt == nil {
+					t.Errorf("%s: last_success_at not set", s.Name)
+				}
+			}
+		})
+	}
+}
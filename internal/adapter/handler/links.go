@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// LinksUseCase finds a Spotify track on other platforms.
+type LinksUseCase interface {
+	FetchLinks(ctx context.Context, trackID string) (*domain.TrackLinks, error)
+}
+
+// LinksHandler handles cross-platform link requests.
+type LinksHandler struct {
+	linksUC  LinksUseCase
+	resolver TrackResolver // Optional: without it only Spotify URLs are accepted
+	links    *LinkExpander
+}
+
+// NewLinksHandler creates a new LinksHandler.
+func NewLinksHandler(linksUC LinksUseCase) *LinksHandler {
+	return &LinksHandler{linksUC: linksUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *LinksHandler) WithLinkExpander(links *LinkExpander) *LinksHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *LinksHandler) WithResolver(r TrackResolver) *LinksHandler {
+	h.resolver = r
+	return h
+}
+
+type linksResponse struct {
+	Track      trackSummaryResult            `json:"track"`
+	Links      map[string]platformLinkResult `json:"links"`
+	Resolution *resolutionResult             `json:"resolution,omitempty"`
+}
+
+type trackSummaryResult struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Artist string `json:"artist"`
+	ISRC   string `json:"isrc,omitempty"`
+}
+
+type platformLinkResult struct {
+	ID         string  `json:"id"`
+	URL        string  `json:"url"`
+	Confidence float64 `json:"confidence"`
+	Method     string  `json:"method"`
+}
+
+// FetchLinks handles GET /v2/track/links.
+func (h *LinksHandler) FetchLinks(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "TrackLinks", "リクエスト開始")
+
+	rawURL := r.URL.Query().Get("url")
+	trackID, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, rawURL)
+	if err != nil {
+		writeError(w, r, "TrackLinks", err, "INVALID_PARAM")
+		return
+	}
+
+	result, err := h.linksUC.FetchLinks(r.Context(), trackID)
+	if err != nil {
+		writeError(w, r, "TrackLinks", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	resp := linksResponse{
+		Track: trackSummaryResult{
+			ID:     result.TrackID,
+			Name:   result.Title,
+			Artist: result.Artist,
+			ISRC:   result.ISRC,
+		},
+		Links:      make(map[string]platformLinkResult, len(result.Links)),
+		Resolution: resolution,
+	}
+	for platform, link := range result.Links {
+		resp.Links[platform] = platformLinkResult{
+			ID:         link.ID,
+			URL:        link.URL,
+			Confidence: link.Confidence,
+			Method:     string(link.Method),
+		}
+	}
+	logger.InfoContext(r.Context(), "TrackLinks", "リクエスト完了")
+	success(w, resp)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockLinksUseCase for links handler tests
+type mockLinksUseCase struct {
+	fetchLinksFunc func(ctx context.Context, trackID string) (*domain.TrackLinks, error)
+}
+
+func (m *mockLinksUseCase) FetchLinks(ctx context.Context, trackID string) (*domain.TrackLinks, error) {
+	return m.fetchLinksFunc(ctx, trackID)
+}
+
+func TestLinksHandler_FetchLinks(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		fetchLinks     func(ctx context.Context, trackID string) (*domain.TrackLinks, error)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "正常系: 各サービスのリンク",
+			url:  "https://open.spotify.com/track/abc123",
+			fetchLinks: func(ctx context.Context, trackID string) (*domain.TrackLinks, error) {
+				return &domain.TrackLinks{
+					TrackID: trackID,
+					Title:   "Lemon",
+					Artist:  "米津玄師",
+					ISRC:    "JPU901800200",
+					Links: map[string]domain.PlatformLink{
+						domain.PlatformSpotify: {ID: trackID, URL: "https://open.spotify.com/track/" + trackID, Confidence: 1, Method: domain.LinkMethodSource},
+						domain.PlatformDeezer:  {ID: "3135556", URL: "https://www.deezer.com/track/3135556", Confidence: 1, Method: domain.LinkMethodISRC},
+					},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 空のURL",
+			url:            "",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name: "異常系: 曲が見つからない",
+			url:  "https://open.spotify.com/track/abc123",
+			fetchLinks: func(ctx context.Context, trackID string) (*domain.TrackLinks, error) {
+				return nil, domain.ErrTrackNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "TRACK_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewLinksHandler(&mockLinksUseCase{fetchLinksFunc: tt.fetchLinks})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/track/links?url="+tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			h.FetchLinks(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			links, ok := result["links"].(map[string]interface{})
+			if !ok {
+				t.Fatal("expected links to be object")
+			}
+			deezer, _ := links["deezer"].(map[string]interface{})
+			if deezer["url"] != "https://www.deezer.com/track/3135556" || deezer["method"] != "isrc" || deezer["confidence"] != 1.0 {
+				t.Errorf("unexpected deezer link: %v", deezer)
+			}
+			track, _ := result["track"].(map[string]interface{})
+			if track["isrc"] != "JPU901800200" {
+				t.Errorf("unexpected track: %v", track)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// Supported response languages.
+const (
+	langJA = "ja"
+	langEN = "en"
+)
+
+// defaultLang is used when Accept-Language names no supported language.
+// Japanese keeps the messages existing clients have always received.
+const defaultLang = langJA
+
+// messageCatalogue holds the user-facing message of each error code per language.
+// "{name}" placeholders are filled from domain.Error.Params.
+var messageCatalogue = map[string]map[string]string{
+	langJA: {
+		"EMPTY_PARAM":             "URLが入力されていません",
+		"NOT_SPOTIFY_URL":         "SpotifyのURLを入力してください",
+		"DIFFERENT_SPOTIFY_URL":   "{resource}のURLを入力してください",
+		"INVALID_URL":             "無効なURL形式です",
+		"UNSUPPORTED_URL":         "対応していないURLです。Spotify / KKBOX / Deezer / YouTube Music / Apple Music / MusicBrainz の曲URLを入力してください",
+		"INVALID_PARAM":           "パラメータが不正です",
+		"EMPTY_QUERY":             "検索クエリが入力されていません",
+		"INVALID_REGION":          "対応していない地域です: {region}（対応: {supported}）",
+		"ISRC_NOT_FOUND":          "ISRCが見つかりませんでした",
+		"INVALID_ISRC":            "ISRCの形式が正しくありません（例: JPU901800200）",
+		"INVALID_UPC":             "UPCの形式が正しくありません（12桁または13桁の数字）",
+		"TOO_MANY_TRACKS":         "一度に指定できる曲は{max}曲までです",
+		"INVALID_ARC":             "対応していない曲線です: {arc}（対応: {supported}、または 0〜1 のエネルギーをカンマ区切りで2つ以上）",
+		"DURATION_NOT_MET":        "合計時間 {total} が目標の {target}（±{tolerance}）に収まりませんでした",
+		"LIMITED_BY_ARTIST_CAP":   "アーティストごとの曲数の上限（{max}曲）がなければ目標の長さに届きます",
+		"LIMITED_BY_GENRE_SHARE":  "ジャンルグループ {group} の割合（{share}以上）がなければ目標の長さに届きます",
+		"LIMITED_BY_BPM_RANGE":    "BPMの範囲（{range}）がなければ目標の長さに届きます",
+		"NO_SINGLE_CAUSE":         "どれか1つの条件を外しても目標の長さに届きません。条件の組み合わせか、曲の選び方によるものです",
+		"TRACK_NOT_FOUND":         "曲が見つかりませんでした",
+		"KKBOX_TRACK_NOT_FOUND":   "KKBOXで曲が見つかりませんでした",
+		"ARTIST_NOT_FOUND":        "アーティストが見つかりませんでした",
+		"ALBUM_NOT_FOUND":         "アルバムが見つかりませんでした",
+		"PLAYLIST_NOT_FOUND":      "プレイリストが見つかりませんでした（公開されていない可能性があります）",
+		"NOT_FOUND":               "見つかりませんでした",
+		"UPSTREAM_RATE_LIMITED":   "外部APIのレート制限に達しました。しばらくしてから再度お試しください",
+		"UPSTREAM_UNAVAILABLE":    "外部APIが一時的に利用できません。しばらくしてから再度お試しください",
+		"EXTERNAL_API_ERROR":      "APIで問題が発生しているようです",
+		"SOMETHING_API_ERROR":     "APIで問題が発生しているようです",
+		"SOMETHING_SPOTIFY_ERROR": "Spotify APIで問題が発生しているようです",
+		"TIMEOUT":                 "処理がタイムアウトしました",
+		"PARTIAL_RESULT":          "処理が時間内に完了しませんでした",
+		"UNAUTHORIZED":            "認証に失敗しました",
+		"CONFIG_INVALID":          "設定が不正なため再読み込みできませんでした",
+	},
+	langEN: {
+		"EMPTY_PARAM":             "No URL was given",
+		"NOT_SPOTIFY_URL":         "Please enter a Spotify URL",
+		"DIFFERENT_SPOTIFY_URL":   "Please enter a Spotify {resource} URL",
+		"INVALID_URL":             "The URL is not in a valid format",
+		"UNSUPPORTED_URL":         "Unsupported URL. Please enter a track URL from Spotify, KKBOX, Deezer, YouTube Music, Apple Music or MusicBrainz",
+		"INVALID_PARAM":           "Invalid parameter",
+		"EMPTY_QUERY":             "No search query was given",
+		"INVALID_REGION":          "Unsupported region: {region} (supported: {supported})",
+		"ISRC_NOT_FOUND":          "The track has no ISRC",
+		"INVALID_ISRC":            "The ISRC is not in a valid format (e.g. JPU901800200)",
+		"INVALID_UPC":             "The UPC is not in a valid format (12 or 13 digits)",
+		"TOO_MANY_TRACKS":         "Up to {max} tracks can be given at once",
+		"INVALID_ARC":             "Unsupported arc: {arc} (supported: {supported}, or two or more comma-separated energies between 0 and 1)",
+		"DURATION_NOT_MET":        "The total duration {total} is not within {tolerance} of the target {target}",
+		"LIMITED_BY_ARTIST_CAP":   "The target duration is reachable without the limit of {max} tracks per artist",
+		"LIMITED_BY_GENRE_SHARE":  "The target duration is reachable without {share} of tracks in the {group} genre group",
+		"LIMITED_BY_BPM_RANGE":    "The target duration is reachable without the BPM range {range}",
+		"NO_SINGLE_CAUSE":         "No single constraint keeps the playlist from the target duration; their combination or the greedy selection does",
+		"TRACK_NOT_FOUND":         "Track not found",
+		"KKBOX_TRACK_NOT_FOUND":   "Track not found on KKBOX",
+		"ARTIST_NOT_FOUND":        "Artist not found",
+		"ALBUM_NOT_FOUND":         "Album not found",
+		"PLAYLIST_NOT_FOUND":      "Playlist not found (it may not be public)",
+		"NOT_FOUND":               "Not found",
+		"UPSTREAM_RATE_LIMITED":   "An external API rate limit was reached. Please try again later",
+		"UPSTREAM_UNAVAILABLE":    "An external API is temporarily unavailable. Please try again later",
+		"EXTERNAL_API_ERROR":      "An external API is having problems",
+		"SOMETHING_API_ERROR":     "An external API is having problems",
+		"SOMETHING_SPOTIFY_ERROR": "The Spotify API is having problems",
+		"TIMEOUT":                 "The request timed out",
+		"PARTIAL_RESULT":          "The request could not be completed in time",
+		"UNAUTHORIZED":            "Authentication failed",
+		"CONFIG_INVALID":          "The configuration is invalid and was not reloaded",
+	},
+}
+
+// kindTitles holds the short problem title of each error kind per language.
+var kindTitles = map[string]map[domain.ErrorKind]string{
+	langJA: {
+		domain.KindNotFound:     "見つかりません",
+		domain.KindInvalidInput: "リクエストが不正です",
+		domain.KindRateLimited:  "レート制限中です",
+		domain.KindUnavailable:  "外部APIが利用できません",
+		domain.KindTimeout:      "タイムアウトしました",
+		domain.KindPartial:      "処理が完了しませんでした",
+		domain.KindInternal:     "外部APIで問題が発生しました",
+	},
+	langEN: {
+		domain.KindNotFound:     "Not found",
+		domain.KindInvalidInput: "Invalid request",
+		domain.KindRateLimited:  "Rate limited",
+		domain.KindUnavailable:  "Upstream unavailable",
+		domain.KindTimeout:      "Timed out",
+		domain.KindPartial:      "Incomplete",
+		domain.KindInternal:     "Upstream error",
+	},
+}
+
+// localize returns the message of code in lang, filling in params.
+func localize(lang, code string, params map[string]string) (string, bool) {
+	msg, ok := messageCatalogue[lang][code]
+	if !ok {
+		return "", false
+	}
+	for k, v := range params {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", v)
+	}
+	return msg, true
+}
+
+// requestLang picks the response language from the request's Accept-Language header.
+func requestLang(r *http.Request) string {
+	if r == nil {
+		return defaultLang
+	}
+	return negotiateLang(r.Header.Get("Accept-Language"))
+}
+
+// negotiateLang returns the supported language with the highest quality in an
+// Accept-Language header, e.g. "en-US,en;q=0.9,ja;q=0.8" -> "en".
+func negotiateLang(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := messageCatalogue[primary]; ok && q > 0 {
+			candidates = append(candidates, candidate{lang: primary, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return defaultLang
+	}
+	// Stable, so equal weights keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// PlaylistUseCase analyses the features of a playlist or a list of tracks.
+type PlaylistUseCase interface {
+	AnalyzePlaylist(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error)
+	AnalyzeTracks(ctx context.Context, trackIDs []string) (*domain.PlaylistAnalysis, error)
+}
+
+// PlaylistHandler handles playlist analysis requests.
+type PlaylistHandler struct {
+	playlistUC PlaylistUseCase
+	resolver   TrackResolver // Optional: without it only Spotify track URLs are accepted
+	links      *LinkExpander
+}
+
+// NewPlaylistHandler creates a new PlaylistHandler.
+func NewPlaylistHandler(playlistUC PlaylistUseCase) *PlaylistHandler {
+	return &PlaylistHandler{playlistUC: playlistUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *PlaylistHandler) WithLinkExpander(links *LinkExpander) *PlaylistHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *PlaylistHandler) WithResolver(r TrackResolver) *PlaylistHandler {
+	h.resolver = r
+	return h
+}
+
+type playlistAnalysisResponse struct {
+	Playlist     *playlistResult         `json:"playlist,omitempty"`
+	Tracks       []featuresBatchItem     `json:"tracks"`
+	BPMHistogram []histogramBinResult    `json:"bpm_histogram"`
+	BPM          *spreadResult           `json:"bpm"`
+	Gain         *spreadResult           `json:"gain"`
+	TopTags      []tagCountResult        `json:"top_tags"`
+	GenreGroups  []genreGroupCountResult `json:"genre_groups"`
+	Clusters     []clusterResult         `json:"clusters"`
+	Outliers     []outlierResult         `json:"outliers"`
+	Unanalyzed   []string                `json:"unanalyzed"`
+}
+
+type playlistResult struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Owner          string `json:"owner"`
+	URL            string `json:"url"`
+	TotalTracks    int    `json:"total_tracks"`
+	AnalyzedTracks int    `json:"analyzed_tracks"` // At most MaxPlaylistTracks of total_tracks
+}
+
+type histogramBinResult struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+type spreadResult struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+}
+
+type tagCountResult struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type genreGroupCountResult struct {
+	Group string `json:"group"`
+	Count int    `json:"count"`
+}
+
+type clusterResult struct {
+	TrackIDs       []string `json:"track_ids"`
+	MeanSimilarity float64  `json:"mean_similarity"`
+	CommonTags     []string `json:"common_tags"`
+}
+
+type outlierResult struct {
+	TrackID        string   `json:"track_id"`
+	MeanSimilarity float64  `json:"mean_similarity"`
+	Reasons        []string `json:"reasons"`
+}
+
+// AnalyzePlaylist handles GET /v2/playlist/analyze.
+func (h *PlaylistHandler) AnalyzePlaylist(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "PlaylistAnalysis", "リクエスト開始")
+
+	playlistID, err := spotifyID(r.Context(), h.links, r.URL.Query().Get("url"), "playlist")
+	if err != nil {
+		writeError(w, r, "PlaylistAnalysis", err, "INVALID_PARAM")
+		return
+	}
+
+	analysis, err := h.playlistUC.AnalyzePlaylist(r.Context(), playlistID)
+	if err != nil {
+		writeError(w, r, "PlaylistAnalysis", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	lang := requestLang(r)
+	items := make([]featuresBatchItem, len(analysis.Tracks))
+	for i, set := range analysis.Tracks {
+		if set.Track != nil {
+			items[i].URL = set.Track.URL
+		}
+		if set.Err != nil {
+			items[i].Error = newItemError(lang, set.Err)
+			continue
+		}
+		resp := convertFeatureSet(&set)
+		items[i].Result = &resp
+	}
+
+	resp := convertPlaylistAnalysis(analysis, items)
+	logger.InfoContext(r.Context(), "PlaylistAnalysis", "リクエスト完了")
+	success(w, resp)
+}
+
+// AnalyzeTracks handles POST /v2/playlist/analyze with a list of track URLs.
+// URLs that cannot be resolved or looked up are reported per track and left out of the report.
+func (h *PlaylistHandler) AnalyzeTracks(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "PlaylistAnalysis", "リクエスト開始")
+
+	var req featuresBatchRequest
+	if !decodeBatchRequest(w, r, "PlaylistAnalysis", &req) {
+		return
+	}
+
+	lang := requestLang(r)
+	batch := resolveBatch(r.Context(), h.links, h.resolver, lang, req.URLs)
+	analysis, err := h.playlistUC.AnalyzeTracks(r.Context(), batch.ids)
+	if err != nil {
+		writeError(w, r, "PlaylistAnalysis", err, "SOMETHING_API_ERROR")
+		return
+	}
+	batch.fill(lang, analysis.Tracks)
+
+	resp := convertPlaylistAnalysis(analysis, batch.items)
+	logger.InfoContext(r.Context(), "PlaylistAnalysis", "リクエスト完了")
+	success(w, resp)
+}
+
+// convertPlaylistAnalysis converts an analysis into its response form, with items as its tracks.
+func convertPlaylistAnalysis(a *domain.PlaylistAnalysis, items []featuresBatchItem) playlistAnalysisResponse {
+	resp := playlistAnalysisResponse{
+		Tracks:       items,
+		BPMHistogram: make([]histogramBinResult, len(a.BPMHistogram)),
+		BPM:          convertSpread(a.BPM),
+		Gain:         convertSpread(a.Gain),
+		TopTags:      make([]tagCountResult, len(a.TopTags)),
+		GenreGroups:  make([]genreGroupCountResult, len(a.GenreGroups)),
+		Clusters:     make([]clusterResult, len(a.Clusters)),
+		Outliers:     make([]outlierResult, len(a.Outliers)),
+		Unanalyzed:   a.Unanalyzed,
+	}
+	if resp.Unanalyzed == nil {
+		resp.Unanalyzed = []string{}
+	}
+	if p := a.Playlist; p != nil {
+		resp.Playlist = &playlistResult{
+			ID:             p.ID,
+			Name:           p.Name,
+			Owner:          p.Owner,
+			URL:            p.URL,
+			TotalTracks:    p.TotalTracks,
+			AnalyzedTracks: len(p.Tracks),
+		}
+	}
+
+	for i, bin := range a.BPMHistogram {
+		resp.BPMHistogram[i] = histogramBinResult{Min: bin.Min, Max: bin.Max, Count: bin.Count}
+	}
+	for i, tag := range a.TopTags {
+		resp.TopTags[i] = tagCountResult{Tag: tag.Tag, Count: tag.Count}
+	}
+	for i, group := range a.GenreGroups {
+		resp.GenreGroups[i] = genreGroupCountResult{Group: group.Group, Count: group.Count}
+	}
+	for i, c := range a.Clusters {
+		resp.Clusters[i] = clusterResult{TrackIDs: c.TrackIDs, MeanSimilarity: c.MeanSimilarity, CommonTags: c.CommonTags}
+		if resp.Clusters[i].CommonTags == nil {
+			resp.Clusters[i].CommonTags = []string{}
+		}
+	}
+	for i, o := range a.Outliers {
+		resp.Outliers[i] = outlierResult{TrackID: o.TrackID, MeanSimilarity: o.MeanSimilarity, Reasons: o.Reasons}
+	}
+	return resp
+}
+
+// convertSpread converts a spread, keeping nil for features no track has.
+func convertSpread(s *domain.Spread) *spreadResult {
+	if s == nil {
+		return nil
+	}
+	return &spreadResult{Min: s.Min, Max: s.Max, Mean: s.Mean, StdDev: s.StdDev}
+}
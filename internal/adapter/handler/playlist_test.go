@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockPlaylistUseCase for playlist handler tests
+type mockPlaylistUseCase struct {
+	analyzePlaylistFunc func(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error)
+	analyzeTracksFunc   func(ctx context.Context, trackIDs []string) (*domain.PlaylistAnalysis, error)
+}
+
+func (m *mockPlaylistUseCase) AnalyzePlaylist(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error) {
+	return m.analyzePlaylistFunc(ctx, playlistID)
+}
+
+func (m *mockPlaylistUseCase) AnalyzeTracks(ctx context.Context, trackIDs []string) (*domain.PlaylistAnalysis, error) {
+	return m.analyzeTracksFunc(ctx, trackIDs)
+}
+
+func TestPlaylistHandler_AnalyzePlaylist(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		analyze        func(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "正常系: プレイリストの分析",
+			url:  "https://open.spotify.com/playlist/pl1",
+			analyze: func(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error) {
+				set := testFeatureSet("t1")
+				set.Track.URL = "https://open.spotify.com/track/t1"
+				return &domain.PlaylistAnalysis{
+					Playlist:     &domain.Playlist{ID: playlistID, Name: "Mix", TotalTracks: 250, Tracks: make([]domain.Track, 2)},
+					Tracks:       []domain.TrackFeatureSet{set, {Err: domain.ErrTrackNotFound}},
+					BPMHistogram: []domain.HistogramBin{{Min: 80, Max: 90, Count: 1}},
+					BPM:          &domain.Spread{Min: 87, Max: 87, Mean: 87},
+					GenreGroups:  []domain.GenreGroupCount{{Group: "jpop", Count: 1}},
+					Clusters:     []domain.TrackCluster{{TrackIDs: []string{"t1", "t2"}, MeanSimilarity: 0.9}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: トラックのURL",
+			url:            "https://open.spotify.com/track/t1",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "DIFFERENT_SPOTIFY_URL",
+		},
+		{
+			name: "異常系: 非公開のプレイリスト",
+			url:  "spotify:playlist:private",
+			analyze: func(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error) {
+				return nil, domain.ErrPlaylistNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "PLAYLIST_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPlaylistHandler(&mockPlaylistUseCase{analyzePlaylistFunc: tt.analyze})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/playlist/analyze?url="+tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			h.AnalyzePlaylist(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			playlist, _ := result["playlist"].(map[string]interface{})
+			if playlist["id"] != "pl1" || playlist["total_tracks"] != float64(250) || playlist["analyzed_tracks"] != float64(2) {
+				t.Errorf("unexpected playlist: %v", playlist)
+			}
+			tracks, _ := result["tracks"].([]interface{})
+			if len(tracks) != 2 {
+				t.Fatalf("expected 2 tracks, got %v", tracks)
+			}
+			first, _ := tracks[0].(map[string]interface{})
+			if first["url"] != "https://open.spotify.com/track/t1" || first["result"] == nil {
+				t.Errorf("unexpected first track: %v", first)
+			}
+			second, _ := tracks[1].(map[string]interface{})
+			if errBody, _ := second["error"].(map[string]interface{}); errBody["code"] != "TRACK_NOT_FOUND" {
+				t.Errorf("unexpected second track: %v", second)
+			}
+			if result["gain"] != nil {
+				t.Errorf("expected null gain, got %v", result["gain"])
+			}
+			if outliers, ok := result["outliers"].([]interface{}); !ok || len(outliers) != 0 {
+				t.Errorf("expected empty outliers, got %v", result["outliers"])
+			}
+			clusters, _ := result["clusters"].([]interface{})
+			if c, _ := clusters[0].(map[string]interface{}); c["common_tags"] == nil {
+				t.Errorf("expected common_tags to be an empty list, got %v", c)
+			}
+		})
+	}
+}
+
+func TestPlaylistHandler_AnalyzeTracks(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+		wantErrors     []string // Per track, "" for a result
+	}{
+		{
+			name:           "正常系: 解決できないURLは曲ごとのエラー",
+			body:           `{"urls": ["https://open.spotify.com/track/t1", "https://example.com/x", "spotify:track:t2"]}`,
+			expectedStatus: http.StatusOK,
+			wantErrors:     []string{"", "NOT_SPOTIFY_URL", ""},
+		},
+		{
+			name:           "異常系: URLが空",
+			body:           `{"urls": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+		{
+			name:           "異常系: 不正なJSON",
+			body:           `{"urls": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PARAM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []string
+			h := NewPlaylistHandler(&mockPlaylistUseCase{
+				analyzeTracksFunc: func(ctx context.Context, trackIDs []string) (*domain.PlaylistAnalysis, error) {
+					gotIDs = trackIDs
+					sets := make([]domain.TrackFeatureSet, len(trackIDs))
+					for i, id := range trackIDs {
+						sets[i] = testFeatureSet(id)
+					}
+					return &domain.PlaylistAnalysis{Tracks: sets}, nil
+				},
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/v2/playlist/analyze", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			h.AnalyzeTracks(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			if len(gotIDs) != 2 || gotIDs[0] != "t1" || gotIDs[1] != "t2" {
+				t.Errorf("expected only the resolved tracks to be analysed, got %v", gotIDs)
+			}
+			result, _ := resp["result"].(map[string]interface{})
+			if _, ok := result["playlist"]; ok {
+				t.Errorf("expected no playlist, got %v", result["playlist"])
+			}
+			tracks, _ := result["tracks"].([]interface{})
+			if len(tracks) != len(tt.wantErrors) {
+				t.Fatalf("expected %d tracks, got %v", len(tt.wantErrors), tracks)
+			}
+			for i, want := range tt.wantErrors {
+				item, _ := tracks[i].(map[string]interface{})
+				errBody, _ := item["error"].(map[string]interface{})
+				if want == "" && (errBody != nil || item["result"] == nil) {
+					t.Errorf("track %d: expected a result, got %v", i, item)
+				}
+				if want != "" && errBody["code"] != want {
+					t.Errorf("track %d: expected error %s, got %v", i, want, item)
+				}
+			}
+		})
+	}
+}
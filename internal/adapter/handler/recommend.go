@@ -15,29 +15,50 @@ type RecommendUseCase interface {
 	GetRecommendations(ctx context.Context, trackID string, mode domain.RecommendMode, limit int) (*domain.RecommendResult, error)
 }
 
+// RecommendSequencer orders the items of a recommendation after its seed track.
+type RecommendSequencer interface {
+	SequenceRecommendations(result *domain.RecommendResult, arc domain.SequenceArc) *domain.Sequence
+}
+
 // RecommendHandler handles recommendation requests.
 type RecommendHandler struct {
 	recommendUC RecommendUseCase
+	resolver    TrackResolver      // Optional: without it only Spotify URLs are accepted
+	sequencer   RecommendSequencer // Optional: without it the arc parameter is ignored
+	links       *LinkExpander
 }
 
 // NewRecommendHandler creates a new RecommendHandler.
 func NewRecommendHandler(recommendUC RecommendUseCase) *RecommendHandler {
-	return &RecommendHandler{recommendUC: recommendUC}
+	return &RecommendHandler{recommendUC: recommendUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *RecommendHandler) WithLinkExpander(links *LinkExpander) *RecommendHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *RecommendHandler) WithResolver(r TrackResolver) *RecommendHandler {
+	h.resolver = r
+	return h
+}
+
+// WithSequencer orders the items for smooth transitions when the arc parameter is given.
+func (h *RecommendHandler) WithSequencer(s RecommendSequencer) *RecommendHandler {
+	h.sequencer = s
+	return h
 }
 
 // FetchRecommendations handles GET /v2/track/recommend.
 func (h *RecommendHandler) FetchRecommendations(w http.ResponseWriter, r *http.Request) {
-	logger.Info("Recommend", "リクエスト開始")
+	logger.InfoContext(r.Context(), "Recommend", "リクエスト開始")
 
 	rawURL := r.URL.Query().Get("url")
-	trackID, err := extractSpotifyTrackID(rawURL)
+	trackID, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, rawURL)
 	if err != nil {
-		if e, ok := err.(*extractError); ok {
-			logger.Warning("Recommend", e.Message)
-			badRequest(w, e.Message, e.Code)
-			return
-		}
-		badRequest(w, "パラメータが不正です", "INVALID_PARAM")
+		writeError(w, r, "Recommend", err, "INVALID_PARAM")
 		return
 	}
 
@@ -53,30 +74,61 @@ func (h *RecommendHandler) FetchRecommendations(w http.ResponseWriter, r *http.R
 		}
 	}
 
+	// Parse arc parameter; items stay in score order without it
+	var arc *domain.SequenceArc
+	if arcStr := r.URL.Query().Get("arc"); arcStr != "" && h.sequencer != nil {
+		parsed, err := domain.ParseSequenceArc(arcStr)
+		if err != nil {
+			writeError(w, r, "Recommend", err, "INVALID_PARAM")
+			return
+		}
+		arc = &parsed
+	}
+
 	result, err := h.recommendUC.GetRecommendations(r.Context(), trackID, mode, limit)
 	if err != nil {
-		switch err {
-		case domain.ErrISRCNotFound:
-			badRequest(w, "ISRCが見つかりませんでした", "ISRC_NOT_FOUND")
-		case domain.ErrTrackNotFound:
-			notFound(w, "曲が見つかりませんでした", "TRACK_NOT_FOUND")
-		default:
-			logger.Error("Recommend", "API エラー: "+err.Error())
-			serviceUnavailable(w, "APIで問題が発生しているようです", "SOMETHING_API_ERROR")
-		}
+		writeError(w, r, "Recommend", err, "SOMETHING_API_ERROR")
 		return
 	}
 
 	resp := convertRecommendResult(result)
-	logger.Info("Recommend", "リクエスト完了")
+	resp.Resolution = resolution
+	if arc != nil {
+		seq := h.sequencer.SequenceRecommendations(result, *arc)
+		// The seed stays first, so the items follow at Index-1
+		items := make([]recommendedTrackResult, 0, len(resp.Items))
+		for _, t := range seq.Tracks[1:] {
+			items = append(items, resp.Items[t.Index-1])
+		}
+		resp.Items = items
+		resp.Sequence = &recommendSequenceResult{
+			Arc:         convertArc(seq.Arc),
+			Transitions: convertTransitions(seq.Transitions),
+			TotalCost:   seq.TotalCost,
+		}
+	}
+	logger.InfoContext(r.Context(), "Recommend", "リクエスト完了")
 	success(w, resp)
 }
 
 // recommendResponse is the API response structure.
 type recommendResponse struct {
-	SeedTrack seedTrackResult          `json:"seed_track"`
-	Items     []recommendedTrackResult `json:"items"`
-	Mode      string                   `json:"mode"`
+	SeedTrack       seedTrackResult          `json:"seed_track"`
+	Items           []recommendedTrackResult `json:"items"`
+	Mode            string                   `json:"mode"`
+	DegradedSources []string                 `json:"degraded_sources,omitempty"`
+	Partial         bool                     `json:"partial"`
+	PartialStages   []string                 `json:"partial_stages,omitempty"`
+	Resolution      *resolutionResult        `json:"resolution,omitempty"`
+	Sequence        *recommendSequenceResult `json:"sequence,omitempty"`
+}
+
+// recommendSequenceResult describes the order of items requested with the arc parameter.
+// Its first transition leads from the seed track to the first item.
+type recommendSequenceResult struct {
+	Arc         arcResult          `json:"arc"`
+	Transitions []transitionResult `json:"transitions"`
+	TotalCost   float64            `json:"total_cost"`
 }
 
 type seedTrackResult struct {
@@ -165,63 +217,76 @@ func convertRecommendResult(result *domain.RecommendResult) recommendResponse {
 
 	// Convert recommended tracks
 	items := make([]recommendedTrackResult, len(result.Items))
-	for i, rt := range result.Items {
-		artists := make([]recommendArtistResult, len(rt.Track.Artists))
-		for j, a := range rt.Track.Artists {
-			artists[j] = recommendArtistResult{ID: a.ID, Name: a.Name, URL: a.URL}
-		}
+	for i := range result.Items {
+		items[i] = convertRecommendedTrack(&result.Items[i])
+	}
 
-		images := make([]imageResult, len(rt.Track.Album.Images))
-		for j, img := range rt.Track.Album.Images {
-			images[j] = imageResult{URL: img.URL, Height: img.Height, Width: img.Width}
-		}
+	var partialStages []string
+	for _, stage := range result.PartialStages {
+		partialStages = append(partialStages, string(stage))
+	}
 
-		album := recommendAlbumResult{
-			ID:          rt.Track.Album.ID,
-			Name:        rt.Track.Album.Name,
-			URL:         rt.Track.Album.URL,
-			Images:      images,
-			ReleaseDate: rt.Track.Album.ReleaseDate,
-		}
+	return recommendResponse{
+		SeedTrack:       seedTrack,
+		Items:           items,
+		Mode:            string(result.Mode),
+		DegradedSources: result.DegradedSources,
+		Partial:         result.Partial,
+		PartialStages:   partialStages,
+	}
+}
 
-		var features *audioFeaturesResult
-		// Support both old AudioFeatures and new TrackFeatures
+// convertRecommendedTrack converts a recommended track into its response form.
+func convertRecommendedTrack(rt *domain.RecommendedTrack) recommendedTrackResult {
+	artists := make([]recommendArtistResult, len(rt.Track.Artists))
+	for i, a := range rt.Track.Artists {
+		artists[i] = recommendArtistResult{ID: a.ID, Name: a.Name, URL: a.URL}
+	}
+
+	images := make([]imageResult, len(rt.Track.Album.Images))
+	for i, img := range rt.Track.Album.Images {
+		images[i] = imageResult{URL: img.URL, Height: img.Height, Width: img.Width}
+	}
+
+	album := recommendAlbumResult{
+		ID:          rt.Track.Album.ID,
+		Name:        rt.Track.Album.Name,
+		URL:         rt.Track.Album.URL,
+		Images:      images,
+		ReleaseDate: rt.Track.Album.ReleaseDate,
+	}
+
+	var features *audioFeaturesResult
+	// Support both old AudioFeatures and new TrackFeatures
+	//nolint:staticcheck // backward compatibility
+	if rt.AudioFeatures != nil {
 		//nolint:staticcheck // backward compatibility
-		if rt.AudioFeatures != nil {
-			//nolint:staticcheck // backward compatibility
-			features = &audioFeaturesResult{
-				Tempo:        rt.AudioFeatures.Tempo,
-				Energy:       rt.AudioFeatures.Energy,
-				Danceability: rt.AudioFeatures.Danceability,
-				Valence:      rt.AudioFeatures.Valence,
-				Acousticness: rt.AudioFeatures.Acousticness,
-			}
-		} else if rt.Features != nil {
-			features = &audioFeaturesResult{
-				BPM:             rt.Features.BPM,
-				DurationSeconds: rt.Features.DurationSeconds,
-				Gain:            rt.Features.Gain,
-				Tags:            rt.Features.Tags,
-			}
+		features = &audioFeaturesResult{
+			Tempo:        rt.AudioFeatures.Tempo,
+			Energy:       rt.AudioFeatures.Energy,
+			Danceability: rt.AudioFeatures.Danceability,
+			Valence:      rt.AudioFeatures.Valence,
+			Acousticness: rt.AudioFeatures.Acousticness,
 		}
-
-		items[i] = recommendedTrackResult{
-			ID:              rt.Track.ID,
-			Name:            rt.Track.Name,
-			Artists:         artists,
-			Album:           album,
-			URL:             rt.Track.URL,
-			SimilarityScore: rt.SimilarityScore,
-			GenreBonus:      rt.GenreBonus,
-			FinalScore:      rt.FinalScore,
-			MatchReasons:    rt.MatchReasons,
-			AudioFeatures:   features,
+	} else if rt.Features != nil {
+		features = &audioFeaturesResult{
+			BPM:             rt.Features.BPM,
+			DurationSeconds: rt.Features.DurationSeconds,
+			Gain:            rt.Features.Gain,
+			Tags:            rt.Features.Tags,
 		}
 	}
 
-	return recommendResponse{
-		SeedTrack: seedTrack,
-		Items:     items,
-		Mode:      string(result.Mode),
+	return recommendedTrackResult{
+		ID:              rt.Track.ID,
+		Name:            rt.Track.Name,
+		Artists:         artists,
+		Album:           album,
+		URL:             rt.Track.URL,
+		SimilarityScore: rt.SimilarityScore,
+		GenreBonus:      rt.GenreBonus,
+		FinalScore:      rt.FinalScore,
+		MatchReasons:    rt.MatchReasons,
+		AudioFeatures:   features,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/t1nyb0x/tracktaste/internal/domain"
@@ -61,6 +62,14 @@ func (m *mockRecommendSpotifyAPI) SearchByISRC(ctx context.Context, isrc string)
 	return nil, nil
 }
 
+func (m *mockRecommendSpotifyAPI) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	return nil, nil
+}
+
+func (m *mockRecommendSpotifyAPI) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	return nil, nil
+}
+
 func (m *mockRecommendSpotifyAPI) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	if m.getAudioFeaturesFunc != nil {
 		return m.getAudioFeaturesFunc(ctx, trackID)
@@ -231,3 +240,126 @@ func TestRecommendHandler_FetchRecommendations(t *testing.T) {
 		})
 	}
 }
+
+// recommendUseCaseFunc adapts a function to RecommendUseCase
+type recommendUseCaseFunc func(ctx context.Context, trackID string, mode domain.RecommendMode, limit int) (*domain.RecommendResult, error)
+
+func (f recommendUseCaseFunc) GetRecommendations(ctx context.Context, trackID string, mode domain.RecommendMode, limit int) (*domain.RecommendResult, error) {
+	return f(ctx, trackID, mode, limit)
+}
+
+// mockRecommendSequencer puts the items in reverse order after the seed
+type mockRecommendSequencer struct{}
+
+func (mockRecommendSequencer) SequenceRecommendations(result *domain.RecommendResult, arc domain.SequenceArc) *domain.Sequence {
+	seq := &domain.Sequence{Arc: arc, Tracks: []domain.SequencedTrack{{Index: 0, Track: result.SeedTrack}}, TotalCost: 1.5}
+	for i := len(result.Items); i > 0; i-- {
+		prev := seq.Tracks[len(seq.Tracks)-1].Track.ID
+		seq.Tracks = append(seq.Tracks, domain.SequencedTrack{Index: i, Track: result.Items[i-1].Track})
+		seq.Transitions = append(seq.Transitions, domain.Transition{FromTrackID: prev, ToTrackID: result.Items[i-1].Track.ID, Cost: 0.5})
+	}
+	return seq
+}
+
+func TestRecommendHandler_FetchRecommendations_Arc(t *testing.T) {
+	uc := recommendUseCaseFunc(func(ctx context.Context, trackID string, mode domain.RecommendMode, limit int) (*domain.RecommendResult, error) {
+		return &domain.RecommendResult{
+			SeedTrack: domain.Track{ID: trackID},
+			Items: []domain.RecommendedTrack{
+				{Track: domain.Track{ID: "first"}}, {Track: domain.Track{ID: "second"}}, {Track: domain.Track{ID: "third"}},
+			},
+			Mode: mode,
+		}, nil
+	})
+
+	tests := []struct {
+		name         string
+		query        string
+		sequencer    RecommendSequencer
+		wantStatus   int
+		wantCode     string
+		wantItems    []string
+		wantSequence bool
+	}{
+		{
+			name:         "正常系: arcを指定すると曲順を並べ替える",
+			query:        "&arc=peak",
+			sequencer:    mockRecommendSequencer{},
+			wantStatus:   http.StatusOK,
+			wantItems:    []string{"third", "second", "first"},
+			wantSequence: true,
+		},
+		{
+			name:       "正常系: arcなしはスコア順",
+			sequencer:  mockRecommendSequencer{},
+			wantStatus: http.StatusOK,
+			wantItems:  []string{"first", "second", "third"},
+		},
+		{
+			name:       "正常系: シーケンサーなしではarcを無視",
+			query:      "&arc=peak",
+			wantStatus: http.StatusOK,
+			wantItems:  []string{"first", "second", "third"},
+		},
+		{
+			name:       "異常系: 不明なarc",
+			query:      "&arc=party",
+			sequencer:  mockRecommendSequencer{},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_ARC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewRecommendHandler(uc)
+			if tt.sequencer != nil {
+				h.WithSequencer(tt.sequencer)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/track/recommend?url=https://open.spotify.com/track/abc123"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.FetchRecommendations(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Status code = %v, want %v: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if tt.wantCode != "" {
+				if resp["code"] != tt.wantCode {
+					t.Errorf("code = %v, want %v", resp["code"], tt.wantCode)
+				}
+				return
+			}
+
+			result, _ := resp["result"].(map[string]interface{})
+			items, _ := result["items"].([]interface{})
+			var ids []string
+			for _, item := range items {
+				ids = append(ids, item.(map[string]interface{})["id"].(string))
+			}
+			if !reflect.DeepEqual(ids, tt.wantItems) {
+				t.Errorf("items = %v, want %v", ids, tt.wantItems)
+			}
+
+			seq, ok := result["sequence"].(map[string]interface{})
+			if ok != tt.wantSequence {
+				t.Fatalf("sequence = %v, want present %v", result["sequence"], tt.wantSequence)
+			}
+			if ok {
+				transitions, _ := seq["transitions"].([]interface{})
+				if first, _ := transitions[0].(map[string]interface{}); len(transitions) != 3 || first["from"] != "abc123" {
+					t.Errorf("expected 3 transitions from the seed, got %v", transitions)
+				}
+				if arc, _ := seq["arc"].(map[string]interface{}); arc["name"] != "peak" {
+					t.Errorf("arc = %v, want peak", seq["arc"])
+				}
+			}
+		})
+	}
+}
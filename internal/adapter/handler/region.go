@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// Region はリクエストの region パラメータ（例: ?region=TW）をコンテキストに設定するミドルウェアです
+// 指定がない場合は defaultRegion を使います。KKBOX の territory と Spotify の market に反映されます
+// 対応していない地域の場合は 400 INVALID_REGION を返します
+func Region(defaultRegion string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			region := defaultRegion
+			if raw := r.URL.Query().Get("region"); raw != "" {
+				parsed, err := domain.ParseRegion(raw)
+				if err != nil {
+					writeError(w, r, "Region", err, "INVALID_PARAM")
+					return
+				}
+				region = parsed
+			}
+			if region == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(domain.WithRegion(r.Context(), region)))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestRegion(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultRegion string
+		query         string
+		wantStatus    int
+		wantRegion    string
+		wantCode      string
+	}{
+		{name: "正常系: 指定なしはデフォルト", defaultRegion: "JP", wantStatus: http.StatusOK, wantRegion: "JP"},
+		{name: "正常系: 地域を指定", defaultRegion: "JP", query: "?region=TW", wantStatus: http.StatusOK, wantRegion: "TW"},
+		{name: "正常系: 小文字も受け付ける", defaultRegion: "JP", query: "?region=my", wantStatus: http.StatusOK, wantRegion: "MY"},
+		{name: "正常系: デフォルトなし", wantStatus: http.StatusOK, wantRegion: ""},
+		{name: "異常系: 非対応の地域", defaultRegion: "JP", query: "?region=US", wantStatus: http.StatusBadRequest, wantCode: "INVALID_REGION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRegion string
+			called := false
+			h := Region(tt.defaultRegion)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				gotRegion = domain.RegionFrom(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/v1/track/fetch"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				if called {
+					t.Error("next handler should not be called")
+				}
+				var resp errorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %s, want %s", resp.Code, tt.wantCode)
+				}
+				return
+			}
+			if gotRegion != tt.wantRegion {
+				t.Errorf("region = %q, want %q", gotRegion, tt.wantRegion)
+			}
+		})
+	}
+}
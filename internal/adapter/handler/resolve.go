@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/t1nyb0x/tracktaste/internal/usecase/resolver"
+)
+
+// TrackResolver は他サービスの曲URLをSpotifyの曲に解決します
+// *resolver.Resolver がこのインターフェースを満たします
+type TrackResolver interface {
+	Resolve(ctx context.Context, rawURL string) (*resolver.Resolution, error)
+}
+
+// resolutionResult はURLをどのように解決したかを表します
+type resolutionResult struct {
+	Platform string `json:"platform"`       // URLのサービス（spotify, kkbox, deezer, youtube_music, apple_music, musicbrainz）
+	ID       string `json:"id"`             // そのサービスでの曲ID
+	Method   string `json:"method"`         // direct（SpotifyのURL）, isrc, search（曲名とアーティスト名）
+	ISRC     string `json:"isrc,omitempty"` // 照合に使ったISRC
+}
+
+// resolveTrackURL はURLからSpotifyの曲IDを取得します
+// SpotifyのURL・URI・短縮リンクはそのまま使い、それ以外のURLは resolver で解決します（resolver が nil の場合はSpotifyのみ）
+func resolveTrackURL(ctx context.Context, links *LinkExpander, res TrackResolver, rawURL string) (string, *resolutionResult, error) {
+	if res == nil || rawURL == "" || isSpotifyLink(rawURL) {
+		trackID, err := spotifyID(ctx, links, rawURL, "track")
+		if err != nil {
+			return "", nil, err
+		}
+		return trackID, &resolutionResult{Platform: "spotify", ID: trackID, Method: string(resolver.MethodDirect)}, nil
+	}
+
+	resolution, err := res.Resolve(ctx, rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	return resolution.TrackID, &resolutionResult{
+		Platform: resolution.Platform,
+		ID:       resolution.SourceID,
+		Method:   string(resolution.Method),
+		ISRC:     resolution.ISRC,
+	}, nil
+}
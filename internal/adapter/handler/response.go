@@ -2,22 +2,46 @@ package handler
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix prefixes the code to form a problem's type URI.
+const problemTypePrefix = "urn:tracktaste:error:"
+
 type successResponse struct {
 	Status int         `json:"status"`
 	Result interface{} `json:"result"`
 }
 
+// errorResponse is the legacy error envelope.
 type errorResponse struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
 	Code    string `json:"code"`
 }
 
+// problemResponse is an RFC 7807 problem details object.
+type problemResponse struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance,omitempty"` // Request ID
+	Code     string `json:"code"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	writeJSONAs(w, "application/json", status, data)
+}
+
+func writeJSONAs(w http.ResponseWriter, contentType string, status int, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
@@ -26,14 +50,35 @@ func success(w http.ResponseWriter, result interface{}) {
 	writeJSON(w, http.StatusOK, successResponse{Status: http.StatusOK, Result: result})
 }
 
-func badRequest(w http.ResponseWriter, message, code string) {
-	writeJSON(w, http.StatusBadRequest, errorResponse{Status: http.StatusBadRequest, Message: message, Code: code})
-}
-
-func notFound(w http.ResponseWriter, message, code string) {
-	writeJSON(w, http.StatusNotFound, errorResponse{Status: http.StatusNotFound, Message: message, Code: code})
+// writeErrorBody writes an error as problem+json when the request accepts it,
+// and as the legacy envelope otherwise.
+func writeErrorBody(w http.ResponseWriter, r *http.Request, status int, code, title, detail string) {
+	if !acceptsProblem(r) {
+		writeJSON(w, status, errorResponse{Status: status, Message: detail, Code: code})
+		return
+	}
+	writeJSONAs(w, problemContentType, status, problemResponse{
+		Type:     problemTypePrefix + code,
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: middleware.GetReqID(r.Context()),
+		Code:     code,
+	})
 }
 
-func serviceUnavailable(w http.ResponseWriter, message, code string) {
-	writeJSON(w, http.StatusServiceUnavailable, errorResponse{Status: http.StatusServiceUnavailable, Message: message, Code: code})
+// acceptsProblem reports whether the Accept header asks for application/problem+json.
+func acceptsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && mediaType == problemContentType && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
 }
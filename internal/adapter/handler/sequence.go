@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// SequenceUseCase orders tracks for smooth BPM and loudness transitions.
+type SequenceUseCase interface {
+	SequenceTracks(ctx context.Context, trackIDs []string, opts domain.SequenceOptions) ([]domain.TrackFeatureSet, *domain.Sequence, error)
+}
+
+// SequenceHandler handles playlist sequencing requests.
+type SequenceHandler struct {
+	sequenceUC SequenceUseCase
+	resolver   TrackResolver // Optional: without it only Spotify URLs are accepted
+	links      *LinkExpander
+}
+
+// NewSequenceHandler creates a new SequenceHandler.
+func NewSequenceHandler(sequenceUC SequenceUseCase) *SequenceHandler {
+	return &SequenceHandler{sequenceUC: sequenceUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander replaces the expander used for Spotify short links.
+func (h *SequenceHandler) WithLinkExpander(links *LinkExpander) *SequenceHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver accepts track URLs from the platforms the resolver supports.
+func (h *SequenceHandler) WithResolver(r TrackResolver) *SequenceHandler {
+	h.resolver = r
+	return h
+}
+
+type sequenceRequest struct {
+	featuresBatchRequest
+	Arc   string `json:"arc"`
+	First string `json:"first"` // Optional: one of urls, to start with
+}
+
+type sequenceResponse struct {
+	Arc         arcResult              `json:"arc"`
+	Tracks      []sequencedTrackResult `json:"tracks"`
+	Transitions []transitionResult     `json:"transitions"`
+	TotalCost   float64                `json:"total_cost"`
+	// Skipped lists the URLs that could not be resolved or looked up
+	Skipped []featuresBatchItem `json:"skipped"`
+}
+
+type arcResult struct {
+	Name   string    `json:"name"`
+	Points []float64 `json:"points"`
+}
+
+type sequencedTrackResult struct {
+	URL          string             `json:"url"`
+	Track        trackSummaryResult `json:"track"`
+	Features     featuresResult     `json:"features"`
+	Energy       *float64           `json:"energy"`
+	TargetEnergy *float64           `json:"target_energy,omitempty"`
+	ArcCost      float64            `json:"arc_cost"`
+	Resolution   *resolutionResult  `json:"resolution,omitempty"`
+}
+
+type transitionResult struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	BPMCost  float64 `json:"bpm_cost"`
+	GainCost float64 `json:"gain_cost"`
+	ArcCost  float64 `json:"arc_cost"`
+	Cost     float64 `json:"cost"`
+	HalfTime bool    `json:"half_time"`
+}
+
+// SequenceTracks handles POST /v2/playlist/sequence.
+func (h *SequenceHandler) SequenceTracks(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "Sequence", "リクエスト開始")
+
+	var req sequenceRequest
+	if !decodeBatchRequest(w, r, "Sequence", &req) {
+		return
+	}
+	arc, err := domain.ParseSequenceArc(req.Arc)
+	if err != nil {
+		writeError(w, r, "Sequence", err, "INVALID_PARAM")
+		return
+	}
+
+	lang := requestLang(r)
+	batch := resolveBatch(r.Context(), h.links, h.resolver, lang, req.URLs)
+
+	opts := domain.SequenceOptions{Arc: arc}
+	if req.First != "" {
+		firstID, _, err := resolveTrackURL(r.Context(), h.links, h.resolver, req.First)
+		if err != nil {
+			writeError(w, r, "Sequence", err, "INVALID_PARAM")
+			return
+		}
+		if !containsString(batch.ids, firstID) {
+			writeError(w, r, "Sequence", domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "first is not one of urls"), "INVALID_PARAM")
+			return
+		}
+		opts.FirstTrackID = firstID
+	}
+
+	sets, seq, err := h.sequenceUC.SequenceTracks(r.Context(), batch.ids, opts)
+	if err != nil {
+		writeError(w, r, "Sequence", err, "SOMETHING_API_ERROR")
+		return
+	}
+	batch.fill(lang, sets)
+
+	resp := sequenceResponse{
+		Arc:         convertArc(seq.Arc),
+		Tracks:      make([]sequencedTrackResult, len(seq.Tracks)),
+		Transitions: convertTransitions(seq.Transitions),
+		TotalCost:   seq.TotalCost,
+		Skipped:     []featuresBatchItem{},
+	}
+	for i, t := range seq.Tracks {
+		pos := batch.positions[t.Index]
+		set := convertFeatureSet(&domain.TrackFeatureSet{Track: &t.Track, Features: t.Features})
+		resp.Tracks[i] = sequencedTrackResult{
+			URL:          batch.items[pos].URL,
+			Track:        set.Track,
+			Features:     set.Features,
+			Energy:       t.Energy,
+			TargetEnergy: t.TargetEnergy,
+			ArcCost:      t.ArcCost,
+			Resolution:   batch.resolutions[pos],
+		}
+	}
+	for _, item := range batch.items {
+		if item.Error != nil {
+			resp.Skipped = append(resp.Skipped, item)
+		}
+	}
+
+	logger.InfoContext(r.Context(), "Sequence", "リクエスト完了")
+	success(w, resp)
+}
+
+// convertArc converts an arc, with an empty list of points for the smooth arc.
+func convertArc(arc domain.SequenceArc) arcResult {
+	resp := arcResult{Name: arc.Name, Points: arc.Points}
+	if resp.Points == nil {
+		resp.Points = []float64{}
+	}
+	return resp
+}
+
+// convertTransitions converts the transitions of a sequence.
+func convertTransitions(transitions []domain.Transition) []transitionResult {
+	resp := make([]transitionResult, len(transitions))
+	for i, t := range transitions {
+		resp[i] = transitionResult{
+			From:     t.FromTrackID,
+			To:       t.ToTrackID,
+			BPMCost:  t.BPMCost,
+			GainCost: t.GainCost,
+			ArcCost:  t.ArcCost,
+			Cost:     t.Cost,
+			HalfTime: t.HalfTime,
+		}
+	}
+	return resp
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockSequenceUseCase for sequence handler tests; it reverses the tracks it can look up
+type mockSequenceUseCase struct {
+	gotIDs  []string
+	gotOpts domain.SequenceOptions
+}
+
+func (m *mockSequenceUseCase) SequenceTracks(ctx context.Context, trackIDs []string, opts domain.SequenceOptions) ([]domain.TrackFeatureSet, *domain.Sequence, error) {
+	m.gotIDs, m.gotOpts = trackIDs, opts
+	sets := make([]domain.TrackFeatureSet, len(trackIDs))
+	seq := &domain.Sequence{Arc: opts.Arc, TotalCost: 2}
+	for i := len(trackIDs) - 1; i >= 0; i-- {
+		if trackIDs[i] == "missing" {
+			sets[i].Err = domain.ErrTrackNotFound
+			continue
+		}
+		sets[i] = testFeatureSet(trackIDs[i])
+		energy := 0.5
+		seq.Tracks = append(seq.Tracks, domain.SequencedTrack{Index: i, Track: *sets[i].Track, Features: sets[i].Features, Energy: &energy})
+	}
+	for i := 1; i < len(seq.Tracks); i++ {
+		seq.Transitions = append(seq.Transitions, domain.Transition{FromTrackID: seq.Tracks[i-1].Track.ID, ToTrackID: seq.Tracks[i].Track.ID, Cost: 1})
+	}
+	return sets, seq, nil
+}
+
+func TestSequenceHandler_SequenceTracks(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+		wantOrder      []string
+		wantSkipped    int
+		wantFirst      string
+	}{
+		{
+			name:           "正常系: 並べ替えた順と遷移コスト",
+			body:           `{"urls": ["https://open.spotify.com/track/t1", "https://example.com/x", "spotify:track:t2", "spotify:track:missing"], "arc": "peak", "first": "spotify:track:t2"}`,
+			expectedStatus: http.StatusOK,
+			wantOrder:      []string{"t2", "t1"},
+			wantSkipped:    2,
+			wantFirst:      "t2",
+		},
+		{
+			name:           "異常系: 不明なarc",
+			body:           `{"urls": ["spotify:track:t1"], "arc": "party"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_ARC",
+		},
+		{
+			name:           "異常系: firstがurlsにない",
+			body:           `{"urls": ["spotify:track:t1"], "first": "spotify:track:t9"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_PARAM",
+		},
+		{
+			name:           "異常系: URLが空",
+			body:           `{"urls": [], "arc": "peak"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "EMPTY_PARAM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &mockSequenceUseCase{}
+			h := NewSequenceHandler(uc)
+
+			req := httptest.NewRequest(http.MethodPost, "/v2/playlist/sequence", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			h.SequenceTracks(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			if uc.gotOpts.FirstTrackID != tt.wantFirst || uc.gotOpts.Arc.Name != "peak" {
+				t.Errorf("unexpected options: %+v", uc.gotOpts)
+			}
+			result, _ := resp["result"].(map[string]interface{})
+			tracks, _ := result["tracks"].([]interface{})
+			if len(tracks) != len(tt.wantOrder) {
+				t.Fatalf("expected %d tracks, got %v", len(tt.wantOrder), tracks)
+			}
+			for i, want := range tt.wantOrder {
+				track, _ := tracks[i].(map[string]interface{})
+				summary, _ := track["track"].(map[string]interface{})
+				if summary["id"] != want || track["energy"] != 0.5 {
+					t.Errorf("track %d: expected %s, got %v", i, want, track)
+				}
+			}
+			// The URL of a sequenced track is the one it was given as
+			if first, _ := tracks[0].(map[string]interface{}); first["url"] != "spotify:track:t2" {
+				t.Errorf("unexpected url of the first track: %v", first["url"])
+			}
+			if transitions, _ := result["transitions"].([]interface{}); len(transitions) != len(tt.wantOrder)-1 {
+				t.Errorf("expected %d transitions, got %v", len(tt.wantOrder)-1, transitions)
+			}
+			if skipped, _ := result["skipped"].([]interface{}); len(skipped) != tt.wantSkipped {
+				t.Errorf("expected %d skipped URLs, got %v", tt.wantSkipped, skipped)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	usecasev1 "github.com/t1nyb0x/tracktaste/internal/usecase/v1"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
@@ -11,10 +13,31 @@ import (
 type TrackHandler struct {
 	trackUC   *usecasev1.TrackUseCase
 	similarUC *usecasev1.SimilarTracksUseCase
+	isrcUC    *usecasev1.ISRCUseCase
+	resolver  TrackResolver // Optional: without it only Spotify URLs are accepted
+	links     *LinkExpander
 }
 
 func NewTrackHandler(trackUC *usecasev1.TrackUseCase, similarUC *usecasev1.SimilarTracksUseCase) *TrackHandler {
-	return &TrackHandler{trackUC: trackUC, similarUC: similarUC}
+	return &TrackHandler{trackUC: trackUC, similarUC: similarUC, links: defaultLinkExpander}
+}
+
+// WithLinkExpander は短縮リンクの展開に使う LinkExpander を差し替えます
+func (h *TrackHandler) WithLinkExpander(links *LinkExpander) *TrackHandler {
+	h.links = links
+	return h
+}
+
+// WithResolver は他サービスの曲URLを受け付けるようにします
+func (h *TrackHandler) WithResolver(r TrackResolver) *TrackHandler {
+	h.resolver = r
+	return h
+}
+
+// WithISRCUseCase は ISRC での曲検索 (FetchByISRC) に使うユースケースを設定します
+func (h *TrackHandler) WithISRCUseCase(isrcUC *usecasev1.ISRCUseCase) *TrackHandler {
+	h.isrcUC = isrcUC
+	return h
 }
 
 type trackResult struct {
@@ -29,6 +52,7 @@ type trackResult struct {
 	TrackNumber int                 `json:"track_number"`
 	DurationMs  int                 `json:"duration_ms"`
 	Explicit    bool                `json:"explicit"`
+	Resolution  *resolutionResult   `json:"resolution,omitempty"`
 }
 
 type trackAlbumResult struct {
@@ -53,43 +77,37 @@ type imageResult struct {
 }
 
 func (h *TrackHandler) FetchByURL(w http.ResponseWriter, r *http.Request) {
-	logger.Info("TrackFetch", "リクエスト開始")
+	logger.InfoContext(r.Context(), "TrackFetch", "リクエスト開始")
 
 	rawURL := r.URL.Query().Get("url")
-	trackID, err := extractSpotifyTrackID(rawURL)
+	trackID, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, rawURL)
 	if err != nil {
-		if e, ok := err.(*extractError); ok {
-			logger.Warning("TrackFetch", e.Message)
-			badRequest(w, e.Message, e.Code)
-			return
-		}
-		badRequest(w, "パラメータが不正です", "INVALID_PARAM")
+		writeError(w, r, "TrackFetch", err, "INVALID_PARAM")
 		return
 	}
 
 	track, err := h.trackUC.FetchByID(r.Context(), trackID)
 	if err != nil {
-		logger.Error("TrackFetch", "Spotify API エラー: "+err.Error())
-		serviceUnavailable(w, "Spotify APIで問題が発生しているようです", "SOMETHING_SPOTIFY_ERROR")
+		writeError(w, r, "TrackFetch", err, "SOMETHING_SPOTIFY_ERROR")
 		return
 	}
 
 	result := convertTrackToResult(track)
-	logger.Info("TrackFetch", "リクエスト完了")
+	result.Resolution = resolution
+	logger.InfoContext(r.Context(), "TrackFetch", "リクエスト完了")
 	success(w, result)
 }
 
 func (h *TrackHandler) Search(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		badRequest(w, "検索クエリが入力されていません", "EMPTY_QUERY")
+		writeError(w, r, "TrackSearch", domain.ErrEmptyQuery, "INVALID_PARAM")
 		return
 	}
 
 	tracks, err := h.trackUC.Search(r.Context(), query)
 	if err != nil {
-		logger.Error("TrackSearch", "Spotify API エラー: "+err.Error())
-		serviceUnavailable(w, "Spotify APIで問題が発生しているようです", "SOMETHING_SPOTIFY_ERROR")
+		writeError(w, r, "TrackSearch", err, "SOMETHING_SPOTIFY_ERROR")
 		return
 	}
 
@@ -104,42 +122,66 @@ type trackSearchResponse struct {
 	Items []trackResult `json:"items"`
 }
 
+// FetchByISRC は ISRC に一致する Spotify の曲と、KKBOX・Deezer・MusicBrainz で一致した曲を返します
+func (h *TrackHandler) FetchByISRC(w http.ResponseWriter, r *http.Request) {
+	logger.InfoContext(r.Context(), "TrackISRC", "リクエスト開始")
+
+	lookup, err := h.isrcUC.FetchByISRC(r.Context(), chi.URLParam(r, "isrc"))
+	if err != nil {
+		writeError(w, r, "TrackISRC", err, "SOMETHING_API_ERROR")
+		return
+	}
+
+	resp := trackISRCResponse{
+		ISRC:    lookup.ISRC,
+		Matches: make(map[string]platformLinkResult, len(lookup.Matches)),
+	}
+	if lookup.Track != nil {
+		track := convertTrackToResult(lookup.Track)
+		resp.Track = &track
+	}
+	for platform, link := range lookup.Matches {
+		resp.Matches[platform] = platformLinkResult{
+			ID:         link.ID,
+			URL:        link.URL,
+			Confidence: link.Confidence,
+			Method:     string(link.Method),
+		}
+	}
+	logger.InfoContext(r.Context(), "TrackISRC", "リクエスト完了")
+	success(w, resp)
+}
+
+type trackISRCResponse struct {
+	ISRC    string                        `json:"isrc"`
+	Track   *trackResult                  `json:"track"` // Spotify にない場合は null
+	Matches map[string]platformLinkResult `json:"matches"`
+}
+
 func (h *TrackHandler) FetchSimilar(w http.ResponseWriter, r *http.Request) {
-	logger.Info("TrackSimilar", "リクエスト開始")
+	logger.InfoContext(r.Context(), "TrackSimilar", "リクエスト開始")
 
 	rawURL := r.URL.Query().Get("url")
-	trackID, err := extractSpotifyTrackID(rawURL)
+	trackID, resolution, err := resolveTrackURL(r.Context(), h.links, h.resolver, rawURL)
 	if err != nil {
-		if e, ok := err.(*extractError); ok {
-			logger.Warning("TrackSimilar", e.Message)
-			badRequest(w, e.Message, e.Code)
-			return
-		}
-		badRequest(w, "パラメータが不正です", "INVALID_PARAM")
+		writeError(w, r, "TrackSimilar", err, "INVALID_PARAM")
 		return
 	}
 
 	result, err := h.similarUC.FetchSimilar(r.Context(), trackID)
 	if err != nil {
-		switch err {
-		case domain.ErrISRCNotFound:
-			badRequest(w, "ISRCが見つかりませんでした", "ISRC_NOT_FOUND")
-		case domain.ErrTrackNotFound:
-			notFound(w, "KKBOXで曲が見つかりませんでした", "KKBOX_TRACK_NOT_FOUND")
-		default:
-			logger.Error("TrackSimilar", "API エラー: "+err.Error())
-			serviceUnavailable(w, "APIで問題が発生しているようです", "SOMETHING_API_ERROR")
-		}
+		writeError(w, r, "TrackSimilar", err, "SOMETHING_API_ERROR")
 		return
 	}
 
-	resp := similarTracksResponse{Items: convertSimilarTracks(result.Items)}
-	logger.Info("TrackSimilar", "リクエスト完了")
+	resp := similarTracksResponse{Items: convertSimilarTracks(result.Items), Resolution: resolution}
+	logger.InfoContext(r.Context(), "TrackSimilar", "リクエスト完了")
 	success(w, resp)
 }
 
 type similarTracksResponse struct {
-	Items []similarTrackResult `json:"items"`
+	Items      []similarTrackResult `json:"items"`
+	Resolution *resolutionResult    `json:"resolution,omitempty"`
 }
 
 type similarTrackResult struct {
@@ -9,8 +9,12 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/testutil"
+	"github.com/t1nyb0x/tracktaste/internal/usecase/resolver"
 	usecasev1 "github.com/t1nyb0x/tracktaste/internal/usecase/v1"
 )
 
@@ -19,6 +23,8 @@ type mockSpotifyAPI struct {
 	GetTrackByIDFunc          func(ctx context.Context, id string) (*domain.Track, error)
 	SearchTracksFunc          func(ctx context.Context, query string) ([]domain.Track, error)
 	SearchByISRCFunc          func(ctx context.Context, isrc string) (*domain.Track, error)
+	SearchAlbumByUPCFunc      func(ctx context.Context, upc string) (*domain.Album, error)
+	GetPlaylistFunc           func(ctx context.Context, id string, limit int) (*domain.Playlist, error)
 	GetArtistByIDFunc         func(ctx context.Context, id string) (*domain.Artist, error)
 	GetAlbumByIDFunc          func(ctx context.Context, id string) (*domain.Album, error)
 	GetAudioFeaturesFunc      func(ctx context.Context, trackID string) (*domain.AudioFeatures, error)
@@ -63,6 +69,20 @@ func (m *mockSpotifyAPI) SearchByISRC(ctx context.Context, isrc string) (*domain
 	return nil, nil
 }
 
+func (m *mockSpotifyAPI) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	if m.SearchAlbumByUPCFunc != nil {
+		return m.SearchAlbumByUPCFunc(ctx, upc)
+	}
+	return nil, nil
+}
+
+func (m *mockSpotifyAPI) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	if m.GetPlaylistFunc != nil {
+		return m.GetPlaylistFunc(ctx, id, limit)
+	}
+	return nil, nil
+}
+
 func (m *mockSpotifyAPI) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	if m.GetAudioFeaturesFunc != nil {
 		return m.GetAudioFeaturesFunc(ctx, trackID)
@@ -255,6 +275,105 @@ func TestTrackHandler_FetchByURL(t *testing.T) {
 	}
 }
 
+// mockTrackResolver for handler tests
+type mockTrackResolver struct {
+	resolveFunc func(ctx context.Context, rawURL string) (*resolver.Resolution, error)
+}
+
+func (m *mockTrackResolver) Resolve(ctx context.Context, rawURL string) (*resolver.Resolution, error) {
+	return m.resolveFunc(ctx, rawURL)
+}
+
+func TestTrackHandler_FetchByURL_Resolver(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		resolveFunc    func(ctx context.Context, rawURL string) (*resolver.Resolution, error)
+		expectedStatus int
+		expectedCode   string
+		wantResolution map[string]interface{}
+	}{
+		{
+			name: "正常系: Deezer のURLをISRCで解決",
+			url:  "https://www.deezer.com/track/3135556",
+			resolveFunc: func(ctx context.Context, rawURL string) (*resolver.Resolution, error) {
+				return &resolver.Resolution{TrackID: "test-track-id", Platform: "deezer", SourceID: "3135556", Method: resolver.MethodISRC, ISRC: "JPU901800200"}, nil
+			},
+			expectedStatus: http.StatusOK,
+			wantResolution: map[string]interface{}{"platform": "deezer", "id": "3135556", "method": "isrc", "isrc": "JPU901800200"},
+		},
+		{
+			name: "正常系: Spotify のURLは resolver を使わない",
+			url:  "https://open.spotify.com/track/abc123",
+			resolveFunc: func(ctx context.Context, rawURL string) (*resolver.Resolution, error) {
+				t.Error("Resolve() should not be called for Spotify URLs")
+				return nil, nil
+			},
+			expectedStatus: http.StatusOK,
+			wantResolution: map[string]interface{}{"platform": "spotify", "id": "abc123", "method": "direct"},
+		},
+		{
+			name: "異常系: 非対応のURL",
+			url:  "https://example.com/track/abc123",
+			resolveFunc: func(ctx context.Context, rawURL string) (*resolver.Resolution, error) {
+				return nil, resolver.ErrUnsupportedURL
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "UNSUPPORTED_URL",
+		},
+		{
+			name: "異常系: Spotifyに見つからない",
+			url:  "https://music.apple.com/jp/song/lemon/1440881047",
+			resolveFunc: func(ctx context.Context, rawURL string) (*resolver.Resolution, error) {
+				return nil, domain.ErrTrackNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &mockSpotifyAPI{GetTrackByIDFunc: func(ctx context.Context, id string) (*domain.Track, error) {
+				return createTestTrack(), nil
+			}}
+			trackUC := usecasev1.NewTrackUseCase(mockAPI)
+			similarUC := usecasev1.NewSimilarTracksUseCase(mockAPI, &mockKKBOXAPI{})
+			handler := NewTrackHandler(trackUC, similarUC).WithResolver(&mockTrackResolver{resolveFunc: tt.resolveFunc})
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/track/fetch?url="+url.QueryEscape(tt.url), nil)
+			rec := httptest.NewRecorder()
+
+			handler.FetchByURL(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+			}
+			if tt.wantResolution != nil {
+				result, _ := resp["result"].(map[string]interface{})
+				resolution, ok := result["resolution"].(map[string]interface{})
+				if !ok {
+					t.Fatal("expected resolution to be object")
+				}
+				for k, v := range tt.wantResolution {
+					if resolution[k] != v {
+						t.Errorf("resolution[%s] = %v, want %v", k, resolution[k], v)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestTrackHandler_Search(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -347,6 +466,103 @@ func TestTrackHandler_Search(t *testing.T) {
 	}
 }
 
+func TestTrackHandler_FetchByISRC(t *testing.T) {
+	tests := []struct {
+		name           string
+		isrc           string
+		spotifyTrack   *domain.Track
+		expectedStatus int
+		expectedCode   string
+		expectTrack    bool
+	}{
+		{
+			name:           "正常系: Spotifyと他サービスで一致",
+			isrc:           "JPU901800200",
+			spotifyTrack:   createTestTrack(),
+			expectedStatus: http.StatusOK,
+			expectTrack:    true,
+		},
+		{
+			name:           "正常系: Spotifyになければtrackはnull",
+			isrc:           "JPU901800200",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 不正なISRC",
+			isrc:           "abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_ISRC",
+		},
+		{
+			name:           "異常系: どのサービスにもない",
+			isrc:           "USRC17607839",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "TRACK_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotifyAPI := &mockSpotifyAPI{
+				SearchByISRCFunc: func(ctx context.Context, isrc string) (*domain.Track, error) {
+					return tt.spotifyTrack, nil
+				},
+			}
+			kkboxAPI := &mockKKBOXAPI{
+				SearchByISRCFunc: func(ctx context.Context, isrc string) (*external.KKBOXTrackInfo, error) {
+					if isrc != "JPU901800200" {
+						return nil, nil
+					}
+					return &external.KKBOXTrackInfo{ID: "kk1", ISRC: isrc, URL: "https://www.kkbox.com/jp/ja/song/kk1"}, nil
+				},
+			}
+			isrcUC := usecasev1.NewISRCUseCase(spotifyAPI, kkboxAPI, &testutil.MockDeezerAPI{}, &testutil.MockMusicBrainzAPI{})
+			h := NewTrackHandler(usecasev1.NewTrackUseCase(spotifyAPI), usecasev1.NewSimilarTracksUseCase(spotifyAPI, kkboxAPI)).
+				WithISRCUseCase(isrcUC)
+
+			r := chi.NewRouter()
+			r.Get("/v1/track/isrc/{isrc}", h.FetchByISRC)
+			req := httptest.NewRequest(http.MethodGet, "/v1/track/isrc/"+tt.isrc, nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if tt.expectedCode != "" {
+				if code, ok := resp["code"].(string); !ok || code != tt.expectedCode {
+					t.Errorf("expected code %s, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+
+			result, ok := resp["result"].(map[string]interface{})
+			if !ok {
+				t.Fatal("expected result to be object")
+			}
+			if result["isrc"] != "JPU901800200" {
+				t.Errorf("expected isrc JPU901800200, got %v", result["isrc"])
+			}
+			if (result["track"] != nil) != tt.expectTrack {
+				t.Errorf("expected track present = %v, got %v", tt.expectTrack, result["track"])
+			}
+			matches, ok := result["matches"].(map[string]interface{})
+			if !ok {
+				t.Fatal("expected matches to be object")
+			}
+			kkbox, ok := matches["kkbox"].(map[string]interface{})
+			if !ok || kkbox["id"] != "kk1" || kkbox["method"] != "isrc" {
+				t.Errorf("expected kkbox ISRC match kk1, got %v", matches["kkbox"])
+			}
+		})
+	}
+}
+
 func TestTrackHandler_FetchSimilar(t *testing.T) {
 	tests := []struct {
 		name           string
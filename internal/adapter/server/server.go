@@ -1,17 +1,45 @@
 package server
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/t1nyb0x/tracktaste/internal/adapter/gateway/transport"
 	"github.com/t1nyb0x/tracktaste/internal/adapter/handler"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
+)
+
+const (
+	defaultRequestTimeout   = 15 * time.Second
+	defaultRecommendTimeout = 30 * time.Second
+	// writeTimeoutMargin lets a handler finish writing after its route timeout fires
+	writeTimeoutMargin = 5 * time.Second
 )
 
 type Config struct {
-	Addr string
+	Addr        string
+	RetryBudget int // Total upstream retries allowed per request; 0 disables the limit
+
+	// Route timeouts become the request context deadline, from which handlers
+	// budget their work. Zero means the default.
+	RequestTimeout   time.Duration // /v1 routes (default 15s)
+	RecommendTimeout time.Duration // /v2 routes (default 30s)
+
+	// DefaultRegion is the catalog region of /v1 and /v2 requests without a
+	// region parameter. Empty means each gateway's own default.
+	DefaultRegion string
 }
 
 type Handlers struct {
@@ -19,31 +47,173 @@ type Handlers struct {
 	Artist    *handler.ArtistHandler
 	Album     *handler.AlbumHandler
 	Recommend *handler.RecommendHandler
+	Links     *handler.LinksHandler
+	Features  *handler.FeaturesHandler
+	Compare   *handler.CompareHandler
+	Playlist  *handler.PlaylistHandler
+	Sequence  *handler.SequenceHandler
+	Generate  *handler.GenerateHandler
 	Health    *handler.HealthHandler
+	Admin     *handler.AdminHandler // Optional
 }
 
 func New(cfg Config, h Handlers) *http.Server {
+	requestTimeout := durationOr(cfg.RequestTimeout, defaultRequestTimeout)
+	recommendTimeout := durationOr(cfg.RecommendTimeout, defaultRecommendTimeout)
+
 	r := chi.NewRouter()
-	r.Use(middleware.RequestID, middleware.Recoverer, middleware.Timeout(15*time.Second), middleware.Logger)
-	r.Get("/healthz", h.Health.Check)
+	r.Use(middleware.RequestID, traceRequests, logContext, httpMetrics, schedulerFlow, retryBudget(cfg.RetryBudget), middleware.Recoverer, accessLog)
+	r.Get("/metrics", metrics.Default.Handler().ServeHTTP)
+	r.With(middleware.Timeout(requestTimeout)).Get("/healthz", h.Health.Check)
+	r.With(middleware.Timeout(requestTimeout)).Get("/healthz/details", h.Health.Details)
+	r.With(middleware.Timeout(requestTimeout)).Get("/readyz", h.Health.Ready)
+
+	if h.Admin != nil {
+		r.With(middleware.Timeout(requestTimeout)).Post("/admin/config/reload", h.Admin.ReloadConfig)
+	}
 
 	r.Route("/v1", func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout), handler.Region(cfg.DefaultRegion))
 		r.Get("/track/fetch", h.Track.FetchByURL)
 		r.Get("/track/search", h.Track.Search)
 		r.Get("/track/similar", h.Track.FetchSimilar)
+		r.Get("/track/isrc/{isrc}", h.Track.FetchByISRC)
 		r.Get("/artist/fetch", h.Artist.FetchByURL)
 		r.Get("/album/fetch", h.Album.FetchByURL)
+		r.Get("/album/upc/{upc}", h.Album.FetchByUPC)
 	})
 
 	r.Route("/v2", func(r chi.Router) {
+		r.Use(middleware.Timeout(recommendTimeout), handler.Region(cfg.DefaultRegion))
 		r.Get("/track/recommend", h.Recommend.FetchRecommendations)
+		r.Get("/track/links", h.Links.FetchLinks)
+		r.Get("/track/features", h.Features.FetchFeatures)
+		r.Post("/track/features/batch", h.Features.FetchFeaturesBatch)
+		r.Get("/track/compare", h.Compare.Compare)
+		r.Get("/playlist/analyze", h.Playlist.AnalyzePlaylist)
+		r.Post("/playlist/analyze", h.Playlist.AnalyzeTracks)
+		r.Post("/playlist/sequence", h.Sequence.SequenceTracks)
+		r.Post("/playlist/generate", h.Generate.GeneratePlaylist)
 	})
 
 	return &http.Server{
 		Handler:      r,
 		Addr:         cfg.Addr,
 		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 35 * time.Second,
+		WriteTimeout: max(requestTimeout, recommendTimeout) + writeTimeoutMargin,
 		IdleTimeout:  60 * time.Second,
 	}
 }
+
+// traceRequests starts a server span per request, continuing the caller's
+// trace when a traceparent header is present. The span is named after the
+// route pattern once routing is done, and its trace ID is added to log records.
+func traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("tracktaste.request_id", middleware.GetReqID(r.Context())),
+			))
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = logger.WithAttrs(ctx, slog.String("trace_id", sc.TraceID().String()))
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			if route := chi.RouteContext(r.Context()).RoutePattern(); route != "" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(attribute.String("http.route", route))
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			var err error
+			if status >= 500 {
+				err = fmt.Errorf("status %d", status)
+			}
+			tracing.End(span, err)
+		}()
+		next.ServeHTTP(ww, r.WithContext(ctx))
+	})
+}
+
+// logContext attaches the request ID to every log record written with the request context.
+func logContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := logger.WithRequestID(r.Context(), middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessLog logs one line per request through the application logger.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		defer func() {
+			logger.InfoContext(r.Context(), "HTTP", fmt.Sprintf("%s %s %d %dB in %v",
+				r.Method, r.URL.RequestURI(), ww.Status(), ww.BytesWritten(), time.Since(start)))
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// httpMetrics records the count and latency of each request by route pattern,
+// so path parameters do not create a series per value.
+func httpMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		defer func() {
+			route := chi.RouteContext(r.Context()).RoutePattern()
+			if route == "" {
+				route = "unmatched"
+			}
+			status := ww.Status()
+			if status == 0 {
+				// Nothing written: net/http sends 200
+				status = http.StatusOK
+			}
+			metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(status))
+			metrics.HTTPDuration.Observe(metrics.Since(start), route, r.Method)
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// schedulerFlow tags the request context with the request ID so that upstream
+// schedulers share capacity fairly between concurrent requests.
+func schedulerFlow(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := scheduler.WithFlow(r.Context(), middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// retryBudget caps the upstream retries one request may trigger, so a
+// struggling upstream cannot multiply the load of every request.
+func retryBudget(retries int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if retries <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := transport.WithRetryBudget(r.Context(), retries)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// durationOr returns d, or def when d is not positive.
+func durationOr(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
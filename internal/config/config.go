@@ -1,31 +1,310 @@
+// Package config holds the application configuration.
+//
+// Load builds it in three layers: the defaults of Default, then an optional
+// YAML file, then environment variables. The result is validated before use.
 package config
 
+import "time"
+
 type HTTP struct {
-	Addr string
+	Addr             string        `yaml:"addr"`
+	RequestTimeout   time.Duration `yaml:"request_timeout"`   // /v1 routes
+	RecommendTimeout time.Duration `yaml:"recommend_timeout"` // /v2 routes
 }
 
 type KKBOX struct {
-	APIKey string
-	Secret string
+	APIKey    string `yaml:"client_id"`
+	Secret    string `yaml:"client_secret"`
+	Territory string `yaml:"territory"` // Catalog territory, e.g. "JP"; also the default request region
 }
 
 type Spotify struct {
-	APIKey string
-	Secret string
+	APIKey string `yaml:"client_id"`
+	Secret string `yaml:"client_secret"`
 }
 
 type LastFM struct {
-	APIKey string
+	APIKey string `yaml:"api_key"` // Empty disables Last.fm
 }
 
 type YTMusic struct {
-	SidecarURL string
+	SidecarURL string `yaml:"sidecar_url"` // Empty disables YouTube Music
+}
+
+// Redis configures the L2 cache and distributed rate limits.
+type Redis struct {
+	Addr     string `yaml:"addr"` // host:port
+	Password string `yaml:"password"`
+}
+
+// RateLimit is the token-bucket limit of one upstream.
+type RateLimit struct {
+	Rate        float64 `yaml:"rate"` // Requests per second
+	Burst       int     `yaml:"burst"`
+	Distributed bool    `yaml:"distributed"` // Share the limit across instances via Redis
+}
+
+// RateLimits holds the limits of every upstream.
+type RateLimits struct {
+	Spotify     RateLimit `yaml:"spotify"`
+	KKBOX       RateLimit `yaml:"kkbox"`
+	Deezer      RateLimit `yaml:"deezer"`
+	MusicBrainz RateLimit `yaml:"musicbrainz"`
+	LastFM      RateLimit `yaml:"lastfm"`
+	YTMusic     RateLimit `yaml:"ytmusic"`
+	AppleMusic  RateLimit `yaml:"applemusic"`
+}
+
+// DefaultRateLimits returns limits that stay within each upstream's published policy.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Spotify:     RateLimit{Rate: 10, Burst: 20},
+		KKBOX:       RateLimit{Rate: 10, Burst: 20},
+		Deezer:      RateLimit{Rate: 8, Burst: 10},                   // 50 requests per 5 seconds: 8×5 + 10
+		MusicBrainz: RateLimit{Rate: 1, Burst: 1, Distributed: true}, // 1 request per second per client IP
+		LastFM:      RateLimit{Rate: 5, Burst: 5},                    // 5 requests per second
+		YTMusic:     RateLimit{Rate: 10, Burst: 10},                  // Local sidecar
+		AppleMusic:  RateLimit{Rate: 0.3, Burst: 5},                  // iTunes Search API: about 20 requests per minute
+	}
+}
+
+// RetryPolicy configures retries of transient upstream failures.
+type RetryPolicy struct {
+	MaxRetries    int           `yaml:"max_retries"`
+	BaseDelay     time.Duration `yaml:"base_delay"`
+	MaxDelay      time.Duration `yaml:"max_delay"`
+	MaxRetryAfter time.Duration `yaml:"max_retry_after"` // A longer Retry-After is not waited for
+}
+
+// RetryPolicies holds the retry policies of every upstream and the
+// total retry budget of one incoming request.
+type RetryPolicies struct {
+	Spotify       RetryPolicy `yaml:"spotify"`
+	KKBOX         RetryPolicy `yaml:"kkbox"`
+	Deezer        RetryPolicy `yaml:"deezer"`
+	MusicBrainz   RetryPolicy `yaml:"musicbrainz"`
+	LastFM        RetryPolicy `yaml:"lastfm"`
+	YTMusic       RetryPolicy `yaml:"ytmusic"`
+	AppleMusic    RetryPolicy `yaml:"applemusic"`
+	RequestBudget int         `yaml:"request_budget"` // 0 disables the limit
+}
+
+// DefaultRetryPolicies returns the default retry settings.
+func DefaultRetryPolicies() RetryPolicies {
+	standard := RetryPolicy{
+		MaxRetries:    2,
+		BaseDelay:     200 * time.Millisecond,
+		MaxDelay:      2 * time.Second,
+		MaxRetryAfter: 5 * time.Second,
+	}
+	return RetryPolicies{
+		Spotify: standard,
+		KKBOX:   standard,
+		Deezer:  standard,
+		// MusicBrainz answers 503 when rate limited; back off for a full slot
+		MusicBrainz: RetryPolicy{
+			MaxRetries:    1,
+			BaseDelay:     time.Second,
+			MaxDelay:      2 * time.Second,
+			MaxRetryAfter: 5 * time.Second,
+		},
+		LastFM:        standard,
+		YTMusic:       RetryPolicy{MaxRetries: 1, BaseDelay: 200 * time.Millisecond, MaxDelay: time.Second},
+		AppleMusic:    standard,
+		RequestBudget: 20,
+	}
+}
+
+// CircuitBreaker configures the circuit breaker of each upstream.
+type CircuitBreaker struct {
+	FailureThreshold int           `yaml:"failure_threshold"`  // Consecutive failures that open the breaker
+	OpenTimeout      time.Duration `yaml:"open_timeout"`       // Time to stay open before probing
+	HalfOpenRequests int           `yaml:"half_open_requests"` // Probe requests allowed while half-open
+}
+
+// DefaultCircuitBreaker returns the default circuit breaker settings.
+func DefaultCircuitBreaker() CircuitBreaker {
+	return CircuitBreaker{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// Health configures the dependency probes behind /readyz and /healthz/details.
+type Health struct {
+	ProbeInterval    time.Duration `yaml:"probe_interval"`
+	ProbeTimeout     time.Duration `yaml:"probe_timeout"`
+	FailureThreshold int           `yaml:"failure_threshold"` // Consecutive failed probes before a required service makes the replica not ready
+}
+
+// Log configures the logger. Format is legacy, text or json;
+// Level is debug, info, warn or error.
+type Log struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+}
+
+// Tracing configures OpenTelemetry export. An empty Endpoint disables it.
+type Tracing struct {
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// FeatureWeights are the similarity weights of one recommendation mode.
+type FeatureWeights struct {
+	BPM           float64 `yaml:"bpm"`
+	Duration      float64 `yaml:"duration"`
+	Gain          float64 `yaml:"gain"`
+	TagSimilarity float64 `yaml:"tag_similarity"`
+}
+
+// ModeWeights holds the weights of each recommendation mode.
+type ModeWeights struct {
+	Balanced FeatureWeights `yaml:"balanced"`
+	Similar  FeatureWeights `yaml:"similar"`
+	Related  FeatureWeights `yaml:"related"`
+}
+
+// CandidateLimits is the number of candidates requested from each source.
+type CandidateLimits struct {
+	KKBOX       int `yaml:"kkbox"`
+	LastFM      int `yaml:"lastfm"`
+	MusicBrainz int `yaml:"musicbrainz"`
+	YTMusic     int `yaml:"ytmusic"`
+}
+
+// StageBudget is the share of the request time each pipeline stage may use.
+type StageBudget struct {
+	Collect float64 `yaml:"collect"`
+	Enrich  float64 `yaml:"enrich"`
+	Filter  float64 `yaml:"filter"`
+	Score   float64 `yaml:"score"`
+}
+
+// Sources switches candidate sources of the recommendation pipeline on and off.
+// A source whose upstream is not configured stays off regardless.
+type Sources struct {
+	KKBOX        bool `yaml:"kkbox"`
+	LastFM       bool `yaml:"lastfm"`
+	MusicBrainz  bool `yaml:"musicbrainz"`
+	YouTubeMusic bool `yaml:"youtube_music"`
+}
+
+// Recommend tunes the /v2 recommendation pipeline.
+type Recommend struct {
+	Timeout            time.Duration   `yaml:"timeout"` // For callers without a request deadline
+	MaxResults         int             `yaml:"max_results"`
+	MaxCandidates      int             `yaml:"max_candidates"` // Kept after the genre filter
+	CandidateLimits    CandidateLimits `yaml:"candidate_limits"`
+	SpotifyConcurrency int             `yaml:"spotify_concurrency"`
+	Weights            ModeWeights     `yaml:"weights"`
+	StageBudget        StageBudget     `yaml:"stage_budget"`
+	Sources            Sources         `yaml:"sources"`
+	// Additional Spotify genres per genre group (otaku, jpop, rock, kpop, idol)
+	GenreGroups map[string][]string `yaml:"genre_groups"`
+}
+
+// Similar tunes the /v1 KKBOX-based similar track lookup.
+type Similar struct {
+	Timeout        time.Duration `yaml:"timeout"`
+	RequestTimeout time.Duration `yaml:"request_timeout"` // Per Spotify ISRC search
+	Concurrency    int           `yaml:"concurrency"`
+	MaxResults     int           `yaml:"max_results"`
+}
+
+// Cache configures the feature store and the cross-platform link cache.
+type Cache struct {
+	DeezerTTL         time.Duration `yaml:"deezer_ttl"`
+	MusicBrainzTTL    time.Duration `yaml:"musicbrainz_ttl"`
+	SpotifyGenresTTL  time.Duration `yaml:"spotify_genres_ttl"`
+	NegativeTTL       time.Duration `yaml:"negative_ttl"`        // How long a "not found" result is trusted
+	FeatureEntryTTL   time.Duration `yaml:"feature_entry_ttl"`   // Redis expiry of an entry not written to
+	MaxFeatureEntries int           `yaml:"max_feature_entries"` // In-memory (L1) entries
+	LinksTTL          time.Duration `yaml:"links_ttl"`           // How long /v2/track/links results are reused
+	MaxLinkEntries    int           `yaml:"max_link_entries"`    // In-memory (L1) entries of the link cache
+}
+
+// Admin configures the administrative endpoints. An empty Token disables them.
+type Admin struct {
+	Token string `yaml:"token"` // Bearer token
 }
 
 type Config struct {
-	HTTP    HTTP
-	KKBOX   KKBOX
-	Spotify Spotify
-	LastFM  LastFM
-	YTMusic YTMusic
+	HTTP           HTTP           `yaml:"http"`
+	KKBOX          KKBOX          `yaml:"kkbox"`
+	Spotify        Spotify        `yaml:"spotify"`
+	LastFM         LastFM         `yaml:"lastfm"`
+	YTMusic        YTMusic        `yaml:"ytmusic"`
+	Redis          Redis          `yaml:"redis"`
+	RateLimits     RateLimits     `yaml:"rate_limits"`
+	Retries        RetryPolicies  `yaml:"retries"`
+	CircuitBreaker CircuitBreaker `yaml:"circuit_breaker"`
+	Health         Health         `yaml:"health"`
+	Log            Log            `yaml:"log"`
+	Tracing        Tracing        `yaml:"tracing"`
+	Recommend      Recommend      `yaml:"recommend"`
+	Similar        Similar        `yaml:"similar"`
+	Cache          Cache          `yaml:"cache"`
+	Admin          Admin          `yaml:"admin"`
+}
+
+// Default returns the configuration used where neither the file nor the
+// environment says otherwise. Credentials have no default.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:             ":8080",
+			RequestTimeout:   15 * time.Second,
+			RecommendTimeout: 30 * time.Second,
+		},
+		KKBOX:          KKBOX{Territory: "JP"},
+		Redis:          Redis{Addr: "localhost:6379"},
+		RateLimits:     DefaultRateLimits(),
+		Retries:        DefaultRetryPolicies(),
+		CircuitBreaker: DefaultCircuitBreaker(),
+		Health: Health{
+			ProbeInterval:    30 * time.Second,
+			ProbeTimeout:     5 * time.Second,
+			FailureThreshold: 3,
+		},
+		Log:     Log{Format: "legacy", Level: "info"},
+		Tracing: Tracing{ServiceName: "tracktaste", SampleRatio: 1},
+		Recommend: Recommend{
+			Timeout:       30 * time.Second,
+			MaxResults:    30,
+			MaxCandidates: 50,
+			CandidateLimits: CandidateLimits{
+				KKBOX:       30,
+				LastFM:      30,
+				MusicBrainz: 20,
+				YTMusic:     25,
+			},
+			SpotifyConcurrency: 15,
+			Weights: ModeWeights{
+				Balanced: FeatureWeights{BPM: 1.5, Duration: 0.5, Gain: 1.2, TagSimilarity: 2.0},
+				Similar:  FeatureWeights{BPM: 2.0, Duration: 0.8, Gain: 1.5, TagSimilarity: 1.0},
+				Related:  FeatureWeights{BPM: 0.5, Duration: 0.3, Gain: 0.5, TagSimilarity: 3.0},
+			},
+			StageBudget: StageBudget{Collect: 0.40, Enrich: 0.45, Filter: 0.05, Score: 0.05},
+			Sources:     Sources{KKBOX: true, LastFM: true, MusicBrainz: true, YouTubeMusic: true},
+		},
+		Similar: Similar{
+			Timeout:        30 * time.Second,
+			RequestTimeout: 5 * time.Second,
+			Concurrency:    5,
+			MaxResults:     30,
+		},
+		Cache: Cache{
+			DeezerTTL:         30 * 24 * time.Hour,
+			MusicBrainzTTL:    7 * 24 * time.Hour,
+			SpotifyGenresTTL:  7 * 24 * time.Hour,
+			NegativeTTL:       24 * time.Hour,
+			FeatureEntryTTL:   30 * 24 * time.Hour,
+			MaxFeatureEntries: 20000,
+			LinksTTL:          7 * 24 * time.Hour,
+			MaxLinkEntries:    5000,
+		},
+	}
 }
@@ -161,3 +161,68 @@ func TestConfig_ZeroValue(t *testing.T) {
 		t.Errorf("expected empty Spotify.Secret, got '%s'", cfg.Spotify.Secret)
 	}
 }
+
+func TestDefaultRateLimits(t *testing.T) {
+	limits := DefaultRateLimits()
+
+	tests := []struct {
+		name  string
+		limit RateLimit
+	}{
+		{name: "Spotify", limit: limits.Spotify},
+		{name: "KKBOX", limit: limits.KKBOX},
+		{name: "Deezer", limit: limits.Deezer},
+		{name: "MusicBrainz", limit: limits.MusicBrainz},
+		{name: "LastFM", limit: limits.LastFM},
+		{name: "YTMusic", limit: limits.YTMusic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.limit.Rate <= 0 || tt.limit.Burst <= 0 {
+				t.Errorf("expected positive limit, got %+v", tt.limit)
+			}
+		})
+	}
+
+	if limits.MusicBrainz.Rate > 1 {
+		t.Errorf("MusicBrainz allows at most 1 req/s, got %v", limits.MusicBrainz.Rate)
+	}
+	if !limits.MusicBrainz.Distributed {
+		t.Error("MusicBrainz limit should be shared across instances by default")
+	}
+}
+
+func TestDefaultRetryPolicies(t *testing.T) {
+	policies := DefaultRetryPolicies()
+
+	if policies.RequestBudget <= 0 {
+		t.Errorf("expected positive request budget, got %d", policies.RequestBudget)
+	}
+	for name, p := range map[string]RetryPolicy{
+		"Spotify":     policies.Spotify,
+		"KKBOX":       policies.KKBOX,
+		"Deezer":      policies.Deezer,
+		"MusicBrainz": policies.MusicBrainz,
+		"LastFM":      policies.LastFM,
+		"YTMusic":     policies.YTMusic,
+	} {
+		if p.MaxRetries <= 0 || p.BaseDelay <= 0 || p.MaxDelay < p.BaseDelay {
+			t.Errorf("%s: unexpected policy %+v", name, p)
+		}
+	}
+}
+
+func TestDefaultCircuitBreaker(t *testing.T) {
+	cb := DefaultCircuitBreaker()
+
+	if cb.FailureThreshold <= 0 {
+		t.Errorf("expected positive failure threshold, got %d", cb.FailureThreshold)
+	}
+	if cb.OpenTimeout <= 0 {
+		t.Errorf("expected positive open timeout, got %v", cb.OpenTimeout)
+	}
+	if cb.HalfOpenRequests <= 0 {
+		t.Errorf("expected positive half-open requests, got %d", cb.HalfOpenRequests)
+	}
+}
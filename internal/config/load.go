@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load returns the configuration: Default, overridden by the YAML file at path
+// (skipped when path is empty), overridden by environment variables.
+// The result is validated; every problem found is reported in one error.
+func Load(path string) (*Config, error) {
+	return load(path, os.LookupEnv)
+}
+
+// LoadWithEnv is Load with the environment variables looked up by lookupEnv
+// instead of the process environment.
+func LoadWithEnv(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	return load(path, lookupEnv)
+}
+
+func load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(&cfg, lookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadFile decodes a YAML file over cfg. Unknown keys are rejected so that
+// a typo does not silently leave the default in place.
+func loadFile(path string, cfg *Config) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q (use .yaml or .yml)", path, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides cfg with the environment variables that are set.
+func applyEnv(cfg *Config, lookupEnv func(string) (string, bool)) error {
+	e := &envReader{lookup: lookupEnv}
+
+	e.str("HTTP_ADDR", &cfg.HTTP.Addr)
+	e.duration("REQUEST_TIMEOUT", &cfg.HTTP.RequestTimeout)
+	e.duration("RECOMMEND_TIMEOUT", &cfg.HTTP.RecommendTimeout)
+
+	e.str("SPOTIFY_CLIENT_ID", &cfg.Spotify.APIKey)
+	e.str("SPOTIFY_CLIENT_SECRET", &cfg.Spotify.Secret)
+	e.str("KKBOX_ID", &cfg.KKBOX.APIKey)
+	e.str("KKBOX_SECRET", &cfg.KKBOX.Secret)
+	e.str("KKBOX_TERRITORY", &cfg.KKBOX.Territory)
+	e.str("LASTFM_API_KEY", &cfg.LastFM.APIKey)
+	e.str("YTMUSIC_SIDECAR_URL", &cfg.YTMusic.SidecarURL)
+	e.str("REDIS_URL", &cfg.Redis.Addr)
+	e.str("REDIS_PASSWORD", &cfg.Redis.Password)
+
+	for _, u := range []struct {
+		prefix string
+		limit  *RateLimit
+		retry  *RetryPolicy
+	}{
+		{"SPOTIFY", &cfg.RateLimits.Spotify, &cfg.Retries.Spotify},
+		{"KKBOX", &cfg.RateLimits.KKBOX, &cfg.Retries.KKBOX},
+		{"DEEZER", &cfg.RateLimits.Deezer, &cfg.Retries.Deezer},
+		{"MUSICBRAINZ", &cfg.RateLimits.MusicBrainz, &cfg.Retries.MusicBrainz},
+		{"LASTFM", &cfg.RateLimits.LastFM, &cfg.Retries.LastFM},
+		{"YTMUSIC", &cfg.RateLimits.YTMusic, &cfg.Retries.YTMusic},
+		{"APPLEMUSIC", &cfg.RateLimits.AppleMusic, &cfg.Retries.AppleMusic},
+	} {
+		e.float(u.prefix+"_RATE_LIMIT", &u.limit.Rate)
+		e.int(u.prefix+"_RATE_BURST", &u.limit.Burst)
+		e.bool(u.prefix+"_RATE_DISTRIBUTED", &u.limit.Distributed)
+		e.int(u.prefix+"_RETRY_MAX", &u.retry.MaxRetries)
+		e.duration(u.prefix+"_RETRY_BASE_DELAY", &u.retry.BaseDelay)
+		e.duration(u.prefix+"_RETRY_MAX_DELAY", &u.retry.MaxDelay)
+	}
+	e.int("RETRY_BUDGET", &cfg.Retries.RequestBudget)
+
+	e.int("CIRCUIT_BREAKER_FAILURE_THRESHOLD", &cfg.CircuitBreaker.FailureThreshold)
+	e.duration("CIRCUIT_BREAKER_OPEN_TIMEOUT", &cfg.CircuitBreaker.OpenTimeout)
+	e.int("CIRCUIT_BREAKER_HALF_OPEN_REQUESTS", &cfg.CircuitBreaker.HalfOpenRequests)
+
+	e.duration("HEALTH_PROBE_INTERVAL", &cfg.Health.ProbeInterval)
+	e.duration("HEALTH_PROBE_TIMEOUT", &cfg.Health.ProbeTimeout)
+	e.int("HEALTH_FAILURE_THRESHOLD", &cfg.Health.FailureThreshold)
+
+	e.str("LOG_FORMAT", &cfg.Log.Format)
+	e.str("LOG_LEVEL", &cfg.Log.Level)
+
+	e.str("OTEL_EXPORTER_OTLP_ENDPOINT", &cfg.Tracing.Endpoint)
+	e.str("OTEL_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	e.float("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+
+	e.str("ADMIN_TOKEN", &cfg.Admin.Token)
+
+	return errors.Join(e.errs...)
+}
+
+// envReader parses environment variables into config fields, collecting parse errors.
+// Unset and empty variables leave the field unchanged.
+type envReader struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+func (e *envReader) get(key string) (string, bool) {
+	v, ok := e.lookup(key)
+	v = strings.TrimSpace(v)
+	return v, ok && v != ""
+}
+
+func (e *envReader) fail(key, v, want string) {
+	e.errs = append(e.errs, fmt.Errorf("env %s: %q is not %s", key, v, want))
+}
+
+func (e *envReader) str(key string, dst *string) {
+	if v, ok := e.get(key); ok {
+		*dst = v
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	if v, ok := e.get(key); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.fail(key, v, "an integer")
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	if v, ok := e.get(key); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			e.fail(key, v, "a number")
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	if v, ok := e.get(key); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			e.fail(key, v, "a boolean")
+			return
+		}
+		*dst = b
+	}
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	if v, ok := e.get(key); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			e.fail(key, v, `a duration such as "30s"`)
+			return
+		}
+		*dst = d
+	}
+}
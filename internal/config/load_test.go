@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envMap is a lookupEnv backed by a map.
+func envMap(m map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+// credentials are the environment variables every valid configuration needs.
+func credentials() map[string]string {
+	return map[string]string{
+		"SPOTIFY_CLIENT_ID":     "spotify_id",
+		"SPOTIFY_CLIENT_SECRET": "spotify_secret",
+		"KKBOX_ID":              "kkbox_id",
+		"KKBOX_SECRET":          "kkbox_secret",
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefault_IsValidWithCredentials(t *testing.T) {
+	cfg := Default()
+	cfg.Spotify = Spotify{APIKey: "id", Secret: "secret"}
+	cfg.KKBOX.APIKey = "id"
+	cfg.KKBOX.Secret = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected defaults to be valid, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := `
+http:
+  addr: ":9090"
+kkbox:
+  territory: TW
+recommend:
+  max_results: 20
+  weights:
+    related:
+      tag_similarity: 4
+cache:
+  deezer_ttl: 48h
+`
+
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "正常系: ファイルなしはデフォルト",
+			check: func(t *testing.T, cfg *Config) {
+				want := Default()
+				if cfg.HTTP != want.HTTP || cfg.Recommend.MaxResults != want.Recommend.MaxResults || cfg.KKBOX.Territory != "JP" {
+					t.Errorf("expected defaults, got %+v", cfg)
+				}
+				if cfg.Spotify.APIKey != "spotify_id" {
+					t.Errorf("expected credentials from env, got %q", cfg.Spotify.APIKey)
+				}
+			},
+		},
+		{
+			name: "正常系: ファイルがデフォルトを上書き",
+			file: file,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTTP.Addr != ":9090" || cfg.KKBOX.Territory != "TW" || cfg.Recommend.MaxResults != 20 {
+					t.Errorf("file values not applied: %+v", cfg)
+				}
+				if cfg.Cache.DeezerTTL != 48*time.Hour {
+					t.Errorf("expected deezer_ttl 48h, got %v", cfg.Cache.DeezerTTL)
+				}
+				if cfg.Recommend.Weights.Related.TagSimilarity != 4 || cfg.Recommend.Weights.Related.BPM != 0.5 {
+					t.Errorf("expected partial weight override, got %+v", cfg.Recommend.Weights.Related)
+				}
+				if cfg.HTTP.RequestTimeout != 15*time.Second {
+					t.Errorf("expected unset keys to keep defaults, got %v", cfg.HTTP.RequestTimeout)
+				}
+			},
+		},
+		{
+			name: "正常系: 環境変数がファイルを上書き",
+			file: file,
+			env:  map[string]string{"HTTP_ADDR": ":7070", "KKBOX_TERRITORY": "HK", "DEEZER_RATE_BURST": "5", "REQUEST_TIMEOUT": "3s"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTTP.Addr != ":7070" || cfg.KKBOX.Territory != "HK" {
+					t.Errorf("env not applied: %+v %+v", cfg.HTTP, cfg.KKBOX)
+				}
+				if cfg.RateLimits.Deezer.Burst != 5 || cfg.HTTP.RequestTimeout != 3*time.Second {
+					t.Errorf("env not applied: %+v %v", cfg.RateLimits.Deezer, cfg.HTTP.RequestTimeout)
+				}
+				if cfg.Recommend.MaxResults != 20 {
+					t.Errorf("expected file value to remain, got %d", cfg.Recommend.MaxResults)
+				}
+			},
+		},
+		{
+			name: "正常系: 空の環境変数は無視",
+			env:  map[string]string{"HTTP_ADDR": "", "LOG_LEVEL": "  "},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTTP.Addr != ":8080" || cfg.Log.Level != "info" {
+					t.Errorf("expected defaults, got %+v %+v", cfg.HTTP, cfg.Log)
+				}
+			},
+		},
+		{
+			name: "正常系: 空のファイル",
+			file: "# nothing here\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTTP.Addr != ":8080" {
+					t.Errorf("expected default addr, got %q", cfg.HTTP.Addr)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = writeFile(t, "config.yaml", tt.file)
+			}
+			env := credentials()
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			cfg, err := load(path, envMap(env))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		file     string
+		env      map[string]string
+		want     []string
+	}{
+		{
+			name:     "異常系: 未知のキー",
+			fileName: "config.yaml",
+			file:     "http:\n  adr: \":9090\"\n",
+			want:     []string{"field adr not found"},
+		},
+		{
+			name:     "異常系: 対応していない形式",
+			fileName: "config.json",
+			file:     "{}",
+			want:     []string{`unsupported format ".json"`},
+		},
+		{
+			name:     "異常系: ファイルの型不一致",
+			fileName: "config.yaml",
+			file:     "recommend:\n  max_results: many\n",
+			want:     []string{"config.yaml"},
+		},
+		{
+			name: "異常系: 環境変数の形式エラーをまとめて報告",
+			env:  map[string]string{"REQUEST_TIMEOUT": "15", "SPOTIFY_RATE_BURST": "x"},
+			want: []string{`env REQUEST_TIMEOUT: "15" is not a duration`, `env SPOTIFY_RATE_BURST: "x" is not an integer`},
+		},
+		{
+			name: "異常系: 検証エラーをまとめて報告",
+			env: map[string]string{
+				"KKBOX_TERRITORY":      "jp",
+				"TRACING_SAMPLE_RATIO": "1.5",
+				"HEALTH_PROBE_TIMEOUT": "1m",
+				"LOG_FORMAT":           "xml",
+			},
+			want: []string{"kkbox.territory", "tracing.sample_ratio", "health.probe_timeout", "log.format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = writeFile(t, tt.fileName, tt.file)
+			}
+			env := credentials()
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			_, err := load(path, envMap(env))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("expected error to contain %q, got %v", w, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := load(filepath.Join(t.TempDir(), "missing.yaml"), envMap(credentials()))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.Spotify = Spotify{APIKey: "id", Secret: "secret"}
+		cfg.KKBOX.APIKey = "id"
+		cfg.KKBOX.Secret = "secret"
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{name: "異常系: Spotify 認証情報なし", modify: func(c *Config) { c.Spotify.Secret = "" }, want: "spotify.client_secret: is required (or set SPOTIFY_CLIENT_SECRET)"},
+		{name: "異常系: KKBOX 認証情報なし", modify: func(c *Config) { c.KKBOX.APIKey = "" }, want: "kkbox.client_id: is required (or set KKBOX_ID)"},
+		{name: "異常系: 非対応の地域", modify: func(c *Config) { c.KKBOX.Territory = "US" }, want: "kkbox.territory: must be one of JP, TW, HK, SG, MY"},
+		{name: "異常系: サイドカー URL", modify: func(c *Config) { c.YTMusic.SidecarURL = "localhost:8000" }, want: "ytmusic.sidecar_url"},
+		{name: "異常系: レート 0", modify: func(c *Config) { c.RateLimits.LastFM.Rate = 0 }, want: "rate_limits.lastfm.rate"},
+		{name: "異常系: max_delay < base_delay", modify: func(c *Config) { c.Retries.Deezer.MaxDelay = time.Millisecond }, want: "retries.deezer.max_delay"},
+		{name: "異常系: タイムアウト 0", modify: func(c *Config) { c.HTTP.RecommendTimeout = 0 }, want: "http.recommend_timeout"},
+		{name: "異常系: 候補数 0", modify: func(c *Config) { c.Recommend.CandidateLimits.YTMusic = 0 }, want: "recommend.candidate_limits.ytmusic"},
+		{name: "異常系: 並列数 0", modify: func(c *Config) { c.Similar.Concurrency = 0 }, want: "similar.concurrency"},
+		{name: "異常系: 重みがすべて 0", modify: func(c *Config) { c.Recommend.Weights.Similar = FeatureWeights{} }, want: "recommend.weights.similar: at least one weight must be positive"},
+		{name: "異常系: 負の重み", modify: func(c *Config) { c.Recommend.Weights.Balanced.Gain = -1 }, want: "recommend.weights.balanced: weights must not be negative"},
+		{name: "異常系: ステージ配分の合計が 1 超", modify: func(c *Config) { c.Recommend.StageBudget.Collect = 0.9 }, want: "recommend.stage_budget"},
+		{name: "異常系: 未知のジャンルグループ", modify: func(c *Config) { c.Recommend.GenreGroups = map[string][]string{"jazz": {"bebop"}} }, want: "recommend.genre_groups.jazz: unknown genre group"},
+		{name: "異常系: キャッシュ TTL 0", modify: func(c *Config) { c.Cache.NegativeTTL = 0 }, want: "cache.negative_ttl"},
+		{name: "異常系: L1 エントリ数 0", modify: func(c *Config) { c.Cache.MaxFeatureEntries = 0 }, want: "cache.max_feature_entries"},
+		{name: "異常系: リンクキャッシュ TTL 0", modify: func(c *Config) { c.Cache.LinksTTL = 0 }, want: "cache.links_ttl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if len(verr.Problems) != 1 {
+				t.Errorf("expected exactly one problem, got %v", verr.Problems)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error to contain %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
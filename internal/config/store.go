@@ -0,0 +1,233 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// Store holds the live configuration and swaps it atomically on reload.
+//
+// Readers call Current once per unit of work (e.g. per request) and keep the
+// snapshot they got; a reload never changes a *Config already handed out.
+// Components that cache settings subscribe to the part they use and are
+// notified after a reload that changed it.
+type Store struct {
+	load    func() (*Config, error)
+	current atomic.Pointer[Config]
+
+	mu   sync.Mutex // Serializes reloads and subscriptions
+	subs []subscription
+}
+
+type subscription struct {
+	name  string
+	part  func(*Config) any
+	apply func(*Config)
+}
+
+// NewStore creates a store holding cfg. load produces the configuration to
+// reload; it is typically Load with the same file path.
+func NewStore(cfg *Config, load func() (*Config, error)) *Store {
+	s := &Store{load: load}
+	s.current.Store(cfg)
+	return s
+}
+
+// Current returns the live configuration. The caller must not modify it.
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// Subscribe registers apply to be called with the new configuration after a
+// reload that changes the value returned by part (compared with reflect.DeepEqual).
+// name identifies the subscriber in logs.
+func (s *Store) Subscribe(name string, part func(*Config) any, apply func(*Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, subscription{name: name, part: part, apply: apply})
+}
+
+// Reload loads and validates the configuration and, if it is valid, swaps in its
+// hot settings and notifies the subscribers whose part changed. An invalid configuration is
+// rejected and the live one is kept. It returns what changed.
+func (s *Store) Reload() ([]Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := s.load()
+	if err != nil {
+		logger.Error("Config", fmt.Sprintf("Reload rejected, keeping the current configuration: %v", err))
+		return nil, err
+	}
+	// Restart-only settings keep their running values, so Current reports what the
+	// process uses and later reloads keep warning about them until a restart
+	prev := s.current.Load()
+	changes := Diff(prev, next)
+	if len(changes) == 0 {
+		logger.Info("Config", "Reloaded: no changes")
+		return nil, nil
+	}
+
+	next = effective(prev, next)
+	s.current.Store(next)
+	for _, c := range changes {
+		if c.Restart {
+			logger.Warning("Config", fmt.Sprintf("Reloaded: %s (takes effect after restart)", c))
+		} else {
+			logger.Info("Config", fmt.Sprintf("Reloaded: %s", c))
+		}
+	}
+	for _, sub := range s.subs {
+		if !reflect.DeepEqual(sub.part(prev), sub.part(next)) {
+			logger.Debug("Config", fmt.Sprintf("Applying new settings to %s", sub.name))
+			sub.apply(next)
+		}
+	}
+	return changes, nil
+}
+
+// Change is one setting that differs between two configurations.
+type Change struct {
+	Key     string `json:"key"` // As in the YAML file, e.g. "recommend.weights.related.bpm"
+	Old     string `json:"old"` // Secrets are masked
+	New     string `json:"new"`
+	Restart bool   `json:"restart_required"` // Only takes effect after a restart
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+// hotKeys are the key prefixes applied without a restart.
+// Everything else is read once at startup.
+var hotKeys = []string{
+	"rate_limits.",
+	"log.",
+	"recommend.",
+	"similar.",
+	"cache.deezer_ttl",
+	"cache.musicbrainz_ttl",
+	"cache.spotify_genres_ttl",
+	"cache.negative_ttl",
+	"admin.",
+}
+
+// secretKeys are masked in diffs.
+var secretKeys = []string{"client_secret", "password", "token", "api_key"}
+
+func needsRestart(key string) bool {
+	// Switching between a local and a shared limiter rebuilds the scheduler
+	if strings.HasPrefix(key, "rate_limits.") && strings.HasSuffix(key, ".distributed") {
+		return true
+	}
+	for _, p := range hotKeys {
+		if strings.HasPrefix(key, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// effective returns running with the settings applied without a restart taken from next.
+func effective(running, next *Config) *Config {
+	cfg := *running
+	copyHot("", reflect.ValueOf(&cfg).Elem(), reflect.ValueOf(*next))
+	return &cfg
+}
+
+// copyHot sets every leaf setting of dst that needsRestart does not cover to its value in src.
+func copyHot(prefix string, dst, src reflect.Value) {
+	if dst.Kind() != reflect.Struct {
+		if !needsRestart(prefix) {
+			dst.Set(src)
+		}
+		return
+	}
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		copyHot(join(prefix, name), dst.Field(i), src.Field(i))
+	}
+}
+
+// Diff returns the settings that differ between old and new, sorted by key.
+func Diff(old, new *Config) []Change {
+	before := make(map[string]string)
+	after := make(map[string]string)
+	flatten("", reflect.ValueOf(*old), before)
+	flatten("", reflect.ValueOf(*new), after)
+
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		keys = append(keys, k)
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, k := range keys {
+		if before[k] == after[k] {
+			continue
+		}
+		changes = append(changes, Change{
+			Key:     k,
+			Old:     mask(k, before[k]),
+			New:     mask(k, after[k]),
+			Restart: needsRestart(k),
+		})
+	}
+	return changes
+}
+
+// flatten writes every leaf setting of v to out, keyed by its dotted YAML path.
+func flatten(prefix string, v reflect.Value, out map[string]string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			flatten(join(prefix, name), v.Field(i), out)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			flatten(join(prefix, fmt.Sprint(k.Interface())), v.MapIndex(k), out)
+		}
+	default:
+		out[prefix] = fmt.Sprint(v.Interface())
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func mask(key, value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, s := range secretKeys {
+		if strings.HasSuffix(key, s) {
+			return "***"
+		}
+	}
+	return value
+}
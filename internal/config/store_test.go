@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// validConfig returns the defaults with credentials set.
+func validConfig() *Config {
+	cfg := Default()
+	cfg.Spotify = Spotify{APIKey: "id", Secret: "secret"}
+	cfg.KKBOX.APIKey = "id"
+	cfg.KKBOX.Secret = "secret"
+	return &cfg
+}
+
+func TestStore_Reload(t *testing.T) {
+	initial := validConfig()
+	next := validConfig()
+	next.Recommend.MaxResults = 20
+	next.RateLimits.Deezer.Rate = 5
+
+	store := NewStore(initial, func() (*Config, error) { return next, nil })
+
+	var recommendCalls, rateCalls, similarCalls int
+	var applied *Config
+	store.Subscribe("recommend", func(c *Config) any { return c.Recommend }, func(c *Config) {
+		recommendCalls++
+		applied = c
+	})
+	store.Subscribe("rate limits", func(c *Config) any { return c.RateLimits }, func(*Config) { rateCalls++ })
+	store.Subscribe("similar", func(c *Config) any { return c.Similar }, func(*Config) { similarCalls++ })
+
+	snapshot := store.Current()
+	changes, err := store.Reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Change{
+		{Key: "rate_limits.deezer.rate", Old: "8", New: "5"},
+		{Key: "recommend.max_results", Old: "30", New: "20"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+	if !reflect.DeepEqual(store.Current(), next) {
+		t.Error("new configuration should be live")
+	}
+	if snapshot.Recommend.MaxResults != 30 {
+		t.Error("a snapshot taken before the reload must not change")
+	}
+	if recommendCalls != 1 || rateCalls != 1 || similarCalls != 0 {
+		t.Errorf("subscriber calls = %d/%d/%d, want 1/1/0", recommendCalls, rateCalls, similarCalls)
+	}
+	if applied != store.Current() {
+		t.Error("subscriber should receive the new configuration")
+	}
+
+	// Reloading the same configuration changes nothing
+	changes, err = store.Reload()
+	if err != nil || len(changes) != 0 || recommendCalls != 1 {
+		t.Errorf("second reload = %v, %v (calls %d)", changes, err, recommendCalls)
+	}
+}
+
+func TestStore_Reload_RestartOnly(t *testing.T) {
+	initial := validConfig()
+	next := validConfig()
+	next.HTTP.Addr = ":9090"
+	next.RateLimits.MusicBrainz.Distributed = false
+	next.RateLimits.MusicBrainz.Rate = 2
+
+	store := NewStore(initial, func() (*Config, error) { return next, nil })
+
+	for i := 0; i < 2; i++ {
+		changes, err := store.Reload()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []Change{
+			{Key: "http.addr", Old: ":8080", New: ":9090", Restart: true},
+			{Key: "rate_limits.musicbrainz.distributed", Old: "true", New: "false", Restart: true},
+		}
+		if i == 0 {
+			want = append(want, Change{Key: "rate_limits.musicbrainz.rate", Old: "1", New: "2"})
+		}
+		if !reflect.DeepEqual(changes, want) {
+			t.Errorf("reload %d: changes = %+v, want %+v", i+1, changes, want)
+		}
+	}
+
+	cfg := store.Current()
+	if cfg.HTTP.Addr != ":8080" || !cfg.RateLimits.MusicBrainz.Distributed {
+		t.Errorf("restart-only settings should keep their running values, got %s/%v", cfg.HTTP.Addr, cfg.RateLimits.MusicBrainz.Distributed)
+	}
+	if cfg.RateLimits.MusicBrainz.Rate != 2 {
+		t.Errorf("hot setting should be applied, got rate %v", cfg.RateLimits.MusicBrainz.Rate)
+	}
+	if initial.HTTP.Addr != ":8080" || initial.RateLimits.MusicBrainz.Rate != 1 {
+		t.Error("the initial configuration must not change")
+	}
+}
+
+func TestStore_Reload_Invalid(t *testing.T) {
+	initial := validConfig()
+	loadErr := errors.New("invalid configuration")
+	store := NewStore(initial, func() (*Config, error) { return nil, loadErr })
+
+	calls := 0
+	store.Subscribe("recommend", func(c *Config) any { return c.Recommend }, func(*Config) { calls++ })
+
+	if _, err := store.Reload(); !errors.Is(err, loadErr) {
+		t.Fatalf("expected load error, got %v", err)
+	}
+	if store.Current() != initial {
+		t.Error("current configuration should be kept")
+	}
+	if calls != 0 {
+		t.Error("subscribers should not be notified")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   []Change
+	}{
+		{
+			name:   "正常系: 変更なし",
+			modify: func(*Config) {},
+			want:   nil,
+		},
+		{
+			name:   "正常系: 時間は文字列で表示",
+			modify: func(c *Config) { c.Cache.NegativeTTL = time.Hour },
+			want:   []Change{{Key: "cache.negative_ttl", Old: "24h0m0s", New: "1h0m0s"}},
+		},
+		{
+			name:   "正常系: 秘密情報はマスク",
+			modify: func(c *Config) { c.Spotify.Secret = "rotated"; c.Admin.Token = "t0ken" },
+			want: []Change{
+				{Key: "admin.token", Old: `""`, New: "***"},
+				{Key: "spotify.client_secret", Old: "***", New: "***", Restart: true},
+			},
+		},
+		{
+			name:   "正常系: 再起動が必要な項目",
+			modify: func(c *Config) { c.HTTP.Addr = ":9090"; c.RateLimits.MusicBrainz.Distributed = false },
+			want: []Change{
+				{Key: "http.addr", Old: ":8080", New: ":9090", Restart: true},
+				{Key: "rate_limits.musicbrainz.distributed", Old: "true", New: "false", Restart: true},
+			},
+		},
+		{
+			name:   "正常系: マップの追加",
+			modify: func(c *Config) { c.Recommend.GenreGroups = map[string][]string{"otaku": {"vtuber"}} },
+			want:   []Change{{Key: "recommend.genre_groups.otaku", Old: `""`, New: "[vtuber]"}},
+		},
+		{
+			name:   "正常系: 重みと情報源",
+			modify: func(c *Config) { c.Recommend.Weights.Related.BPM = 1; c.Recommend.Sources.LastFM = false },
+			want: []Change{
+				{Key: "recommend.sources.lastfm", Old: "true", New: "false"},
+				{Key: "recommend.weights.related.bpm", Old: "0.5", New: "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := validConfig()
+			next := validConfig()
+			tt.modify(next)
+			if got := Diff(old, next); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
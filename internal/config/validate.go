@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// ValidationError lists every invalid setting found by Validate.
+type ValidationError struct {
+	Problems []string // "<key>: <problem>", keys as in the YAML file
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validator collects problems.
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(key, format string, args ...interface{}) {
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(key, value, env string) {
+	if value == "" {
+		v.add(key, "is required (or set %s)", env)
+	}
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.add(key, "must be a positive duration, got %v", d)
+	}
+}
+
+func (v *validator) atLeast(key string, n, min int) {
+	if n < min {
+		v.add(key, "must be at least %d, got %d", min, n)
+	}
+}
+
+func (v *validator) httpURL(key, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.add(key, "must be an http(s) URL, got %q", raw)
+	}
+}
+
+// Validate checks every setting and reports all problems at once.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.HTTP.Addr == "" {
+		v.add("http.addr", "is required")
+	}
+	v.positive("http.request_timeout", c.HTTP.RequestTimeout)
+	v.positive("http.recommend_timeout", c.HTTP.RecommendTimeout)
+
+	v.required("spotify.client_id", c.Spotify.APIKey, "SPOTIFY_CLIENT_ID")
+	v.required("spotify.client_secret", c.Spotify.Secret, "SPOTIFY_CLIENT_SECRET")
+	v.required("kkbox.client_id", c.KKBOX.APIKey, "KKBOX_ID")
+	v.required("kkbox.client_secret", c.KKBOX.Secret, "KKBOX_SECRET")
+	if !domain.IsSupportedRegion(c.KKBOX.Territory) {
+		v.add("kkbox.territory", "must be one of %s, got %q", strings.Join(domain.SupportedRegions, ", "), c.KKBOX.Territory)
+	}
+	if c.YTMusic.SidecarURL != "" {
+		v.httpURL("ytmusic.sidecar_url", c.YTMusic.SidecarURL)
+	}
+	if c.Redis.Addr == "" {
+		v.add("redis.addr", "is required")
+	}
+
+	for name, l := range map[string]RateLimit{
+		"spotify": c.RateLimits.Spotify, "kkbox": c.RateLimits.KKBOX, "deezer": c.RateLimits.Deezer,
+		"musicbrainz": c.RateLimits.MusicBrainz, "lastfm": c.RateLimits.LastFM, "ytmusic": c.RateLimits.YTMusic,
+		"applemusic": c.RateLimits.AppleMusic,
+	} {
+		if l.Rate <= 0 {
+			v.add("rate_limits."+name+".rate", "must be positive, got %v", l.Rate)
+		}
+		v.atLeast("rate_limits."+name+".burst", l.Burst, 1)
+	}
+	for name, p := range map[string]RetryPolicy{
+		"spotify": c.Retries.Spotify, "kkbox": c.Retries.KKBOX, "deezer": c.Retries.Deezer,
+		"musicbrainz": c.Retries.MusicBrainz, "lastfm": c.Retries.LastFM, "ytmusic": c.Retries.YTMusic,
+		"applemusic": c.Retries.AppleMusic,
+	} {
+		key := "retries." + name
+		v.atLeast(key+".max_retries", p.MaxRetries, 0)
+		if p.BaseDelay < 0 || p.MaxDelay < 0 || p.MaxRetryAfter < 0 {
+			v.add(key, "delays must not be negative")
+		}
+		if p.MaxDelay > 0 && p.MaxDelay < p.BaseDelay {
+			v.add(key+".max_delay", "must not be shorter than base_delay (%v < %v)", p.MaxDelay, p.BaseDelay)
+		}
+	}
+	v.atLeast("retries.request_budget", c.Retries.RequestBudget, 0)
+
+	v.atLeast("circuit_breaker.failure_threshold", c.CircuitBreaker.FailureThreshold, 1)
+	v.positive("circuit_breaker.open_timeout", c.CircuitBreaker.OpenTimeout)
+	v.atLeast("circuit_breaker.half_open_requests", c.CircuitBreaker.HalfOpenRequests, 1)
+
+	v.positive("health.probe_interval", c.Health.ProbeInterval)
+	v.positive("health.probe_timeout", c.Health.ProbeTimeout)
+	if c.Health.ProbeTimeout > c.Health.ProbeInterval {
+		v.add("health.probe_timeout", "must not exceed probe_interval (%v > %v)", c.Health.ProbeTimeout, c.Health.ProbeInterval)
+	}
+	v.atLeast("health.failure_threshold", c.Health.FailureThreshold, 1)
+
+	if _, err := logger.ParseFormat(c.Log.Format); err != nil {
+		v.add("log.format", "must be legacy, text or json, got %q", c.Log.Format)
+	}
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		v.add("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	if c.Tracing.Endpoint != "" {
+		v.httpURL("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio <= 0 || c.Tracing.SampleRatio > 1 {
+		v.add("tracing.sample_ratio", "must be in (0, 1], got %v", c.Tracing.SampleRatio)
+	}
+
+	c.validateRecommend(v)
+
+	v.positive("similar.timeout", c.Similar.Timeout)
+	v.positive("similar.request_timeout", c.Similar.RequestTimeout)
+	v.atLeast("similar.concurrency", c.Similar.Concurrency, 1)
+	v.atLeast("similar.max_results", c.Similar.MaxResults, 1)
+
+	v.positive("cache.deezer_ttl", c.Cache.DeezerTTL)
+	v.positive("cache.musicbrainz_ttl", c.Cache.MusicBrainzTTL)
+	v.positive("cache.spotify_genres_ttl", c.Cache.SpotifyGenresTTL)
+	v.positive("cache.negative_ttl", c.Cache.NegativeTTL)
+	v.positive("cache.feature_entry_ttl", c.Cache.FeatureEntryTTL)
+	v.atLeast("cache.max_feature_entries", c.Cache.MaxFeatureEntries, 1)
+	v.positive("cache.links_ttl", c.Cache.LinksTTL)
+	v.atLeast("cache.max_link_entries", c.Cache.MaxLinkEntries, 1)
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	// Map iteration order varies; keep the report stable
+	sort.Strings(v.problems)
+	return &ValidationError{Problems: v.problems}
+}
+
+func (c *Config) validateRecommend(v *validator) {
+	r := c.Recommend
+	v.positive("recommend.timeout", r.Timeout)
+	v.atLeast("recommend.max_results", r.MaxResults, 1)
+	v.atLeast("recommend.max_candidates", r.MaxCandidates, 1)
+	v.atLeast("recommend.candidate_limits.kkbox", r.CandidateLimits.KKBOX, 1)
+	v.atLeast("recommend.candidate_limits.lastfm", r.CandidateLimits.LastFM, 1)
+	v.atLeast("recommend.candidate_limits.musicbrainz", r.CandidateLimits.MusicBrainz, 1)
+	v.atLeast("recommend.candidate_limits.ytmusic", r.CandidateLimits.YTMusic, 1)
+	v.atLeast("recommend.spotify_concurrency", r.SpotifyConcurrency, 1)
+
+	for mode, w := range map[string]FeatureWeights{
+		"balanced": r.Weights.Balanced, "similar": r.Weights.Similar, "related": r.Weights.Related,
+	} {
+		key := "recommend.weights." + mode
+		if w.BPM < 0 || w.Duration < 0 || w.Gain < 0 || w.TagSimilarity < 0 {
+			v.add(key, "weights must not be negative")
+		} else if w.BPM+w.Duration+w.Gain+w.TagSimilarity == 0 {
+			v.add(key, "at least one weight must be positive")
+		}
+	}
+
+	for group, genres := range r.GenreGroups {
+		key := "recommend.genre_groups." + group
+		if !isGenreGroup(group) {
+			v.add(key, "unknown genre group (use otaku, jpop, rock, kpop or idol)")
+		}
+		for _, g := range genres {
+			if strings.TrimSpace(g) == "" {
+				v.add(key, "genres must not be empty")
+				break
+			}
+		}
+	}
+
+	b := r.StageBudget
+	if b.Collect < 0 || b.Enrich < 0 || b.Filter < 0 || b.Score < 0 {
+		v.add("recommend.stage_budget", "shares must not be negative")
+	} else if sum := b.Collect + b.Enrich + b.Filter + b.Score; sum > 1 {
+		v.add("recommend.stage_budget", "shares must add up to at most 1, got %.2f", sum)
+	}
+}
+
+// isGenreGroup reports whether name is a genre group the genre matcher knows.
+func isGenreGroup(name string) bool {
+	switch name {
+	case "otaku", "jpop", "rock", "kpop", "idol":
+		return true
+	}
+	return false
+}
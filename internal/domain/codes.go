@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// isrcPattern is an ISRC without hyphens: country, registrant, year and designation
+	isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+	// upcPattern is a UPC-A (12 digits) or EAN-13 (13 digits)
+	upcPattern = regexp.MustCompile(`^[0-9]{12,13}$`)
+)
+
+// ParseISRC normalizes an ISRC from a request (e.g. "jp-u90-18-00200" -> "JPU901800200")
+// and returns ErrInvalidISRC if it is not a valid code.
+func ParseISRC(s string) (string, error) {
+	isrc := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", ""))
+	if !isrcPattern.MatchString(isrc) {
+		return "", ErrInvalidISRC
+	}
+	return isrc, nil
+}
+
+// ParseUPC normalizes a UPC or EAN from a request and returns ErrInvalidUPC
+// if it is not a valid code. Leading zeros are kept, as Spotify matches them exactly.
+func ParseUPC(s string) (string, error) {
+	upc := strings.ReplaceAll(strings.TrimSpace(s), "-", "")
+	if !upcPattern.MatchString(upc) {
+		return "", ErrInvalidUPC
+	}
+	return upc, nil
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseISRC(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: 大文字", input: "JPU901800200", want: "JPU901800200"},
+		{name: "正常系: 小文字とハイフンを正規化", input: " jp-u90-18-00200 ", want: "JPU901800200"},
+		{name: "異常系: 桁数不足", input: "JPU9018002", wantErr: true},
+		{name: "異常系: 国コードが数字", input: "12U901800200", wantErr: true},
+		{name: "異常系: 空", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseISRC(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidISRC) {
+					t.Fatalf("expected %v, got %v", ErrInvalidISRC, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseISRC(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUPC(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: UPC-A", input: "075678671173", want: "075678671173"},
+		{name: "正常系: EAN-13", input: "4988031265318", want: "4988031265318"},
+		{name: "正常系: ハイフンを除去", input: "4-988031-265318", want: "4988031265318"},
+		{name: "異常系: 英字を含む", input: "49880312653AB", wantErr: true},
+		{name: "異常系: 桁数超過", input: "49880312653180", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUPC(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidUPC) {
+					t.Fatalf("expected %v, got %v", ErrInvalidUPC, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUPC(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,39 +1,165 @@
 package domain
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrorKind classifies an error by how the caller should react to it.
+// Handlers map each kind to an HTTP status.
+type ErrorKind string
+
+const (
+	// KindInternal is an unexpected failure. Unclassified errors have this kind.
+	KindInternal ErrorKind = "internal"
+	// KindNotFound means the requested resource does not exist.
+	KindNotFound ErrorKind = "not_found"
+	// KindInvalidInput means the request cannot be served as given.
+	KindInvalidInput ErrorKind = "invalid_input"
+	// KindRateLimited means an upstream API rejected the call for its rate limit.
+	KindRateLimited ErrorKind = "rate_limited"
+	// KindUnavailable means an upstream API is failing or temporarily skipped.
+	KindUnavailable ErrorKind = "unavailable"
+	// KindTimeout means the operation ran out of time.
+	KindTimeout ErrorKind = "timeout"
+	// KindPartial means the operation was cut short and its partial result is not usable.
+	KindPartial ErrorKind = "partial"
+)
+
+// Error is a typed domain error.
+//
+// errors.Is matches two *Error values with the same Kind and Code; a target
+// without a Code matches every error of its kind, so
+// errors.Is(err, ErrNotFound) holds for ErrTrackNotFound as well.
+type Error struct {
+	Kind       ErrorKind
+	Code       string            // Stable machine-readable code, e.g. "TRACK_NOT_FOUND"
+	Message    string            // Human-readable description
+	Params     map[string]string // Values for the localised message of Code, e.g. {"resource": "Track"}
+	RetryAfter time.Duration     // When to retry (rate limited / unavailable), if known
+	Err        error             // Underlying cause, if any
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error of the same kind and code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Kind == e.Kind && (t.Code == "" || t.Code == e.Code)
+}
 
 // Domain errors represent business logic errors.
 var (
 	// ErrTrackNotFound indicates that a track was not found.
-	ErrTrackNotFound = errors.New("track not found")
+	ErrTrackNotFound = &Error{Kind: KindNotFound, Code: "TRACK_NOT_FOUND", Message: "track not found"}
+
+	// ErrKKBOXTrackNotFound indicates that a track is missing from the KKBOX catalog.
+	// It matches ErrTrackNotFound.
+	ErrKKBOXTrackNotFound = &Error{Kind: KindNotFound, Code: "KKBOX_TRACK_NOT_FOUND", Err: ErrTrackNotFound}
 
 	// ErrArtistNotFound indicates that an artist was not found.
-	ErrArtistNotFound = errors.New("artist not found")
+	ErrArtistNotFound = &Error{Kind: KindNotFound, Code: "ARTIST_NOT_FOUND", Message: "artist not found"}
 
 	// ErrAlbumNotFound indicates that an album was not found.
-	ErrAlbumNotFound = errors.New("album not found")
+	ErrAlbumNotFound = &Error{Kind: KindNotFound, Code: "ALBUM_NOT_FOUND", Message: "album not found"}
+
+	// ErrPlaylistNotFound indicates that a playlist was not found or is not public.
+	ErrPlaylistNotFound = &Error{Kind: KindNotFound, Code: "PLAYLIST_NOT_FOUND", Message: "playlist not found"}
 
 	// ErrISRCNotFound indicates that ISRC was not found for a track.
-	ErrISRCNotFound = errors.New("ISRC not found")
+	// The track cannot be used as a seed, so it is treated as invalid input.
+	ErrISRCNotFound = &Error{Kind: KindInvalidInput, Code: "ISRC_NOT_FOUND", Message: "ISRC not found"}
+
+	// ErrInvalidISRC indicates that the provided ISRC is not a valid code.
+	ErrInvalidISRC = &Error{Kind: KindInvalidInput, Code: "INVALID_ISRC", Message: "invalid ISRC"}
+
+	// ErrInvalidUPC indicates that the provided UPC is not a valid code.
+	ErrInvalidUPC = &Error{Kind: KindInvalidInput, Code: "INVALID_UPC", Message: "invalid UPC"}
 
 	// ErrInvalidURL indicates that the provided URL is invalid.
-	ErrInvalidURL = errors.New("invalid URL")
+	ErrInvalidURL = &Error{Kind: KindInvalidInput, Code: ErrCodeInvalidURL, Message: "invalid URL"}
 
 	// ErrEmptyQuery indicates that the search query is empty.
-	ErrEmptyQuery = errors.New("empty query")
+	ErrEmptyQuery = &Error{Kind: KindInvalidInput, Code: "EMPTY_QUERY", Message: "empty query"}
 
 	// ErrExternalAPIError indicates an error from external API.
-	ErrExternalAPIError = errors.New("external API error")
+	ErrExternalAPIError = &Error{Kind: KindUnavailable, Code: "EXTERNAL_API_ERROR", Message: "external API error"}
 
 	// ErrTimeout indicates that the operation timed out.
-	ErrTimeout = errors.New("operation timed out")
+	ErrTimeout = &Error{Kind: KindTimeout, Code: "TIMEOUT", Message: "operation timed out"}
+
+	// ErrUpstreamRateLimited indicates that an upstream service rejected the call for its rate limit.
+	ErrUpstreamRateLimited = &Error{Kind: KindRateLimited, Code: "UPSTREAM_RATE_LIMITED", Message: "upstream rate limited"}
+
+	// ErrUpstreamUnavailable indicates that an upstream service is temporarily
+	// unavailable and was skipped without being called.
+	ErrUpstreamUnavailable = &Error{Kind: KindUnavailable, Code: "UPSTREAM_UNAVAILABLE", Message: "upstream unavailable"}
+
+	// ErrPartial indicates that an operation was cut short without a usable result.
+	ErrPartial = &Error{Kind: KindPartial, Code: "PARTIAL_RESULT", Message: "partial result"}
 
 	// ErrNotFound is a generic not found error.
-	ErrNotFound = errors.New("not found")
+	// It matches every error of KindNotFound.
+	ErrNotFound = &Error{Kind: KindNotFound, Message: "not found"}
 )
 
+// KindOf returns the kind of err.
+// Context deadline errors are timeouts; anything unclassified is internal.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &e) && e.Kind != "":
+		return e.Kind
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	default:
+		var extractErr *ExtractError
+		if errors.As(err, &extractErr) {
+			return KindInvalidInput
+		}
+		return KindInternal
+	}
+}
+
+// AsError returns err as a typed *Error, classifying it with KindOf when it is not one.
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) && e.Kind != "" {
+		return e
+	}
+	var extractErr *ExtractError
+	if errors.As(err, &extractErr) {
+		return &Error{Kind: KindInvalidInput, Code: extractErr.Code, Message: extractErr.Message}
+	}
+	return &Error{Kind: KindOf(err), Err: err}
+}
+
 // ExtractError represents an error during URL extraction.
 // It contains a machine-readable Code and a human-readable Message.
+//
+// Deprecated: Use *Error with KindInvalidInput and one of the ErrCode* codes.
 type ExtractError struct {
 	Code    string
 	Message string
@@ -44,10 +170,29 @@ func (e *ExtractError) Error() string {
 	return e.Message
 }
 
-// Error codes for URL extraction errors.
+// Error codes of invalid input errors, e.g. from URL extraction.
 const (
 	ErrCodeEmptyParam          = "EMPTY_PARAM"
 	ErrCodeNotSpotifyURL       = "NOT_SPOTIFY_URL"
 	ErrCodeDifferentSpotifyURL = "DIFFERENT_SPOTIFY_URL"
 	ErrCodeInvalidURL          = "INVALID_URL"
+	ErrCodeInvalidRegion       = "INVALID_REGION"
+	ErrCodeUnsupportedURL      = "UNSUPPORTED_URL"
+	ErrCodeInvalidParam        = "INVALID_PARAM"
 )
+
+// NewInvalidInputError creates an invalid input error with the given code and message.
+func NewInvalidInputError(code, message string) *Error {
+	return &Error{Kind: KindInvalidInput, Code: code, Message: message}
+}
+
+// NewTooManyTracksError creates the error of a request with more than max tracks.
+func NewTooManyTracksError(max int) *Error {
+	limit := strconv.Itoa(max)
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "TOO_MANY_TRACKS",
+		Message: "too many tracks (max " + limit + ")",
+		Params:  map[string]string{"max": limit},
+	}
+}
@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
 func TestDomainErrors(t *testing.T) {
@@ -83,10 +86,40 @@ func TestDomainErrors_Is(t *testing.T) {
 		},
 		{
 			name:     "ラップされたエラー",
-			err:      ErrISRCNotFound,
+			err:      fmt.Errorf("fetch: %w", ErrISRCNotFound),
 			target:   ErrISRCNotFound,
 			expected: true,
 		},
+		{
+			name:     "ErrNotFoundは全ての not found に一致",
+			err:      ErrAlbumNotFound,
+			target:   ErrNotFound,
+			expected: true,
+		},
+		{
+			name:     "ErrTrackNotFoundはErrNotFound全般には一致しない",
+			err:      ErrNotFound,
+			target:   ErrTrackNotFound,
+			expected: false,
+		},
+		{
+			name:     "ErrKKBOXTrackNotFoundはErrTrackNotFoundに一致",
+			err:      ErrKKBOXTrackNotFound,
+			target:   ErrTrackNotFound,
+			expected: true,
+		},
+		{
+			name:     "同じ種別・コードの別インスタンスに一致",
+			err:      &Error{Kind: KindRateLimited, Code: "UPSTREAM_RATE_LIMITED", Message: "spotify: status 429"},
+			target:   ErrUpstreamRateLimited,
+			expected: true,
+		},
+		{
+			name:     "種別が異なれば不一致",
+			err:      ErrUpstreamUnavailable,
+			target:   ErrUpstreamRateLimited,
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -219,3 +252,46 @@ func TestExtractError_TypeAssertion(t *testing.T) {
 		t.Errorf("expected message 'パラメータが空です', got '%s'", extractErr.Message)
 	}
 }
+
+func TestError_Wrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := &Error{Kind: KindUnavailable, Code: "UPSTREAM_UNAVAILABLE", Message: "spotify", RetryAfter: 3 * time.Second, Err: cause}
+
+	if err.Error() != "spotify: connection refused" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected errors.Is to find the cause")
+	}
+
+	var target *Error
+	if !errors.As(fmt.Errorf("collect: %w", err), &target) {
+		t.Fatal("expected errors.As to find *Error")
+	}
+	if target.RetryAfter != 3*time.Second {
+		t.Errorf("expected RetryAfter 3s, got %v", target.RetryAfter)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorKind
+	}{
+		{name: "nil", err: nil, expected: ""},
+		{name: "not found", err: ErrTrackNotFound, expected: KindNotFound},
+		{name: "ラップされたエラー", err: fmt.Errorf("x: %w", ErrISRCNotFound), expected: KindInvalidInput},
+		{name: "deadline", err: fmt.Errorf("x: %w", context.DeadlineExceeded), expected: KindTimeout},
+		{name: "ExtractError", err: &ExtractError{Code: ErrCodeEmptyParam, Message: "empty"}, expected: KindInvalidInput},
+		{name: "未分類", err: errors.New("boom"), expected: KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// LinkMethod is how a track was matched on another platform.
+type LinkMethod string
+
+const (
+	// LinkMethodSource means the link is the track itself (the Spotify track).
+	LinkMethodSource LinkMethod = "source"
+	// LinkMethodISRC means the platform returned a track with the same ISRC.
+	LinkMethodISRC LinkMethod = "isrc"
+	// LinkMethodFuzzyTitle means the track was found by searching title and artist.
+	LinkMethodFuzzyTitle LinkMethod = "fuzzy_title"
+)
+
+// Platform names used as keys of TrackLinks.Links.
+const (
+	PlatformSpotify      = "spotify"
+	PlatformKKBOX        = "kkbox"
+	PlatformDeezer       = "deezer"
+	PlatformMusicBrainz  = "musicbrainz"
+	PlatformYouTubeMusic = "youtube_music"
+)
+
+// PlatformLink is the same track on another platform.
+type PlatformLink struct {
+	ID         string     `json:"id"`
+	URL        string     `json:"url"`
+	Confidence float64    `json:"confidence"` // 0-1; 1 for ISRC matches
+	Method     LinkMethod `json:"method"`
+}
+
+// TrackLinks maps a Spotify track to the same track on other platforms.
+type TrackLinks struct {
+	TrackID    string                  `json:"track_id"`              // Spotify track ID, as requested
+	RelinkedID string                  `json:"relinked_id,omitempty"` // Spotify track ID played in the region instead, if relinked
+	Region     string                  `json:"region,omitempty"`
+	ISRC       string                  `json:"isrc,omitempty"`
+	Title      string                  `json:"title"`
+	Artist     string                  `json:"artist"`
+	Links      map[string]PlatformLink `json:"links"`      // Keyed by platform, e.g. "deezer"
+	FetchedAt  time.Time               `json:"fetched_at"` // When the platforms were last asked
+}
+
+// ISRCLookup is a recording found by ISRC on Spotify and the other catalogs.
+type ISRCLookup struct {
+	ISRC    string                  `json:"isrc"`
+	Track   *Track                  `json:"track,omitempty"` // Spotify track; nil when Spotify has none with the ISRC
+	Matches map[string]PlatformLink `json:"matches"`         // Other platforms with the ISRC, keyed by platform
+}
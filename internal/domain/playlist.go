@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Playlist represents a Spotify playlist with (up to a limit of) its tracks.
+type Playlist struct {
+	ID          string
+	Name        string
+	Owner       string
+	URL         string
+	TotalTracks int     // Tracks in the playlist, which may be more than len(Tracks)
+	Tracks      []Track // Local files and podcast episodes are skipped
+}
+
+// PlaylistAnalysis is the feature report of a playlist or a list of tracks.
+type PlaylistAnalysis struct {
+	Playlist *Playlist         // Nil when a list of tracks was analysed
+	Tracks   []TrackFeatureSet // In input order; tracks that failed have Err set
+
+	BPMHistogram []HistogramBin
+	BPM          *Spread // Nil when no track has a BPM
+	Gain         *Spread // Nil when no track has a gain
+
+	TopTags     []TagCount        // MusicBrainz tags, most common first
+	GenreGroups []GenreGroupCount // Most common first
+
+	Clusters []TrackCluster    // Groups of mutually similar tracks, largest first
+	Outliers []PlaylistOutlier // Least similar first
+
+	// Unanalyzed lists the IDs of tracks without any features; they are left out
+	// of the clusters and outliers.
+	Unanalyzed []string
+}
+
+// HistogramBin counts the values in [Min, Max).
+type HistogramBin struct {
+	Min   float64
+	Max   float64
+	Count int
+}
+
+// Spread summarises the distribution of a feature.
+type Spread struct {
+	Min    float64
+	Max    float64
+	Mean   float64
+	StdDev float64
+}
+
+// TagCount is the number of tracks with a tag.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// GenreGroupCount is the number of tracks in a genre group.
+type GenreGroupCount struct {
+	Group string
+	Count int
+}
+
+// TrackCluster is a group of tracks that are similar to each other.
+type TrackCluster struct {
+	TrackIDs       []string
+	MeanSimilarity float64  // Mean pairwise similarity within the cluster
+	CommonTags     []string // Tags shared by at least half of the tracks
+}
+
+// PlaylistOutlier is a track that does not fit the rest of the playlist.
+type PlaylistOutlier struct {
+	TrackID        string
+	MeanSimilarity float64 // Mean similarity to the other tracks
+	Reasons        []string
+}
+
+// Outlier reasons reported in PlaylistOutlier.Reasons.
+const (
+	OutlierLowSimilarity   = "low_similarity"
+	OutlierGenreMismatch   = "genre_mismatch"
+	OutlierUnusualBPM      = "unusual_bpm"
+	OutlierUnusualLoudness = "unusual_loudness"
+	OutlierUnusualDuration = "unusual_duration"
+	OutlierNoSharedTags    = "no_shared_tags"
+)
+
+// PlaylistConstraints are the constraints of a generated playlist.
+// Explicit tracks are always left out.
+type PlaylistConstraints struct {
+	TargetDurationMs    int
+	DurationToleranceMs int     // How far the total duration may be from the target
+	MaxTracksPerArtist  int     // Counted for every artist of a track
+	MinGenreShare       float64 // Share of tracks in the seeds' genre group, between 0 and 1
+	MinBPM              float64 // Optional: 0 for no lower bound
+	MaxBPM              float64 // Optional: 0 for no upper bound
+}
+
+// HasBPMRange reports whether the constraints bound the BPM.
+func (c PlaylistConstraints) HasBPMRange() bool {
+	return c.MinBPM > 0 || c.MaxBPM > 0
+}
+
+// GeneratedPlaylist is a playlist selected from the recommendations of its seeds.
+type GeneratedPlaylist struct {
+	Seeds       []Track
+	SeedGenres  []string
+	GenreGroup  string // Genre group of the seeds; MinGenreShare is not applied for "other"
+	Mode        RecommendMode
+	Constraints PlaylistConstraints
+
+	Tracks          []RecommendedTrack // Best score first
+	TotalDurationMs int
+	GenreShare      float64 // Share of Tracks in GenreGroup
+
+	Candidates int            // Distinct scored candidates of all seeds
+	Excluded   map[string]int // Candidates no playlist may contain, by Excluded* reason
+
+	// Unmet explains why the playlist misses its constraints: DURATION_NOT_MET,
+	// followed by the constraints that kept it from reaching the target (LIMITED_BY_*),
+	// or NO_SINGLE_CAUSE when the candidates are long enough but no one constraint is to blame.
+	// Empty when all constraints are met.
+	Unmet []*Error
+
+	DegradedSources []string
+	// Partial is true when a seed was skipped (SkippedSeeds) or a pipeline stage
+	// ran out of time (PartialStages).
+	Partial       bool
+	PartialStages []PipelineStage
+	SkippedSeeds  []string // IDs of the seeds whose recommendations failed
+}
+
+// Satisfied reports whether the playlist meets all of its constraints.
+func (p *GeneratedPlaylist) Satisfied() bool {
+	return len(p.Unmet) == 0
+}
+
+// Reasons for leaving a candidate out of a generated playlist, counted in GeneratedPlaylist.Excluded.
+const (
+	ExcludedExplicit      = "explicit"
+	ExcludedNoDuration    = "no_duration"
+	ExcludedUnknownBPM    = "unknown_bpm" // Only when the BPM is bounded
+	ExcludedBPMOutOfRange = "bpm_out_of_range"
+)
+
+// NewDurationNotMetError creates the error of a playlist whose total duration is off its target.
+func NewDurationNotMetError(c PlaylistConstraints, totalMs int) *Error {
+	target, tolerance, total := formatMinutes(c.TargetDurationMs), formatMinutes(c.DurationToleranceMs), formatMinutes(totalMs)
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "DURATION_NOT_MET",
+		Message: "total duration " + total + " is not within " + tolerance + " of " + target,
+		Params:  map[string]string{"target": target, "tolerance": tolerance, "total": total},
+	}
+}
+
+// NewLimitedByArtistCapError creates the error of a playlist kept short by the per-artist limit.
+func NewLimitedByArtistCapError(max int) *Error {
+	limit := strconv.Itoa(max)
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "LIMITED_BY_ARTIST_CAP",
+		Message: "the target duration is reachable without the limit of " + limit + " tracks per artist",
+		Params:  map[string]string{"max": limit},
+	}
+}
+
+// NewLimitedByGenreShareError creates the error of a playlist kept short by the genre share.
+func NewLimitedByGenreShareError(group string, minShare float64) *Error {
+	share := strconv.Itoa(int(math.Round(minShare*100))) + "%"
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "LIMITED_BY_GENRE_SHARE",
+		Message: "the target duration is reachable without " + share + " of tracks in " + group,
+		Params:  map[string]string{"group": group, "share": share},
+	}
+}
+
+// NewLimitedByBPMRangeError creates the error of a playlist kept short by its BPM bounds.
+func NewLimitedByBPMRangeError(c PlaylistConstraints) *Error {
+	var bpm string
+	switch {
+	case c.MinBPM > 0 && c.MaxBPM > 0:
+		bpm = formatBPM(c.MinBPM) + "-" + formatBPM(c.MaxBPM)
+	case c.MinBPM > 0:
+		bpm = ">=" + formatBPM(c.MinBPM)
+	default:
+		bpm = "<=" + formatBPM(c.MaxBPM)
+	}
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "LIMITED_BY_BPM_RANGE",
+		Message: "the target duration is reachable without the BPM range " + bpm,
+		Params:  map[string]string{"range": bpm},
+	}
+}
+
+// NewNoSingleCauseError creates the error of a playlist kept short although its candidates
+// are long enough and dropping any one constraint would not help.
+func NewNoSingleCauseError() *Error {
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "NO_SINGLE_CAUSE",
+		Message: "no single constraint keeps the playlist from the target duration",
+	}
+}
+
+// formatMinutes formats a duration as minutes and seconds, e.g. "62:05".
+func formatMinutes(ms int) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func formatBPM(bpm float64) string {
+	return strconv.FormatFloat(bpm, 'f', -1, 64)
+}
@@ -28,6 +28,28 @@ func ParseRecommendMode(s string) RecommendMode {
 	}
 }
 
+// Candidate source names reported in RecommendResult.DegradedSources.
+const (
+	SourceKKBOX        = "kkbox"
+	SourceLastFM       = "lastfm"
+	SourceMusicBrainz  = "musicbrainz"
+	SourceYouTubeMusic = "youtube_music"
+)
+
+// PipelineStage names a stage of the recommendation pipeline.
+type PipelineStage string
+
+const (
+	// StageCollect gathers candidate tracks from the candidate sources.
+	StageCollect PipelineStage = "collect"
+	// StageEnrich resolves candidates on Spotify and attaches their features.
+	StageEnrich PipelineStage = "enrich"
+	// StageFilter drops candidates with unrelated genres.
+	StageFilter PipelineStage = "filter"
+	// StageScore scores and ranks the candidates.
+	StageScore PipelineStage = "score"
+)
+
 // RecommendedTrack represents a recommended track with similarity information.
 type RecommendedTrack struct {
 	Track           Track          `json:"track"`
@@ -47,6 +69,63 @@ type RecommendResult struct {
 	SeedGenres   []string           `json:"seed_genres,omitempty"`
 	Items        []RecommendedTrack `json:"items"`
 	Mode         RecommendMode      `json:"mode"`
+	// DegradedSources lists candidate sources that failed or were skipped
+	// (e.g. by an open circuit breaker), so the result may be less complete.
+	DegradedSources []string `json:"degraded_sources,omitempty"`
+	// Partial is true when a pipeline stage ran out of time and passed on
+	// only what it had; PartialStages lists those stages.
+	Partial       bool            `json:"partial"`
+	PartialStages []PipelineStage `json:"partial_stages,omitempty"`
 	// Deprecated: Use SeedFeatures instead
 	SeedAudioFeatures *AudioFeatures `json:"seed_audio_features,omitempty"`
 }
+
+// Similarity component names reported in SimilarityComponent.Name.
+const (
+	ComponentBPM      = "bpm"
+	ComponentDuration = "duration"
+	ComponentGain     = "gain"
+	ComponentTags     = "tags"
+)
+
+// SimilarityComponent is the similarity of one feature between two tracks
+// and its weight in the base similarity.
+type SimilarityComponent struct {
+	Name       string
+	Similarity float64 // 0.0 to 1.0
+	Weight     float64
+}
+
+// ComparedTrack is one side of a TrackComparison.
+type ComparedTrack struct {
+	Track    Track
+	Features *TrackFeatures // Tags include the Spotify genres
+	Genres   []string       // Spotify genres of the primary artist
+}
+
+// ModeScore is the score of a comparison under one recommendation mode's weights.
+// FinalScore = BaseSimilarity * GenreBonus * ArtistBonus * SameArtistBonus * SeriesBonus.
+type ModeScore struct {
+	Mode            RecommendMode
+	Components      []SimilarityComponent
+	BaseSimilarity  float64
+	GenreBonus      float64
+	ArtistBonus     float64
+	SameArtistBonus float64
+	SeriesBonus     float64
+	FinalScore      float64
+}
+
+// TrackComparison explains how the recommender scores track B as a candidate for seed track A.
+type TrackComparison struct {
+	A ComparedTrack
+	B ComparedTrack
+	// GenreFilterBonus is the genre bonus of A's Spotify genres against B's tags;
+	// candidates below 1.0 are dropped before scoring unless A has no genres.
+	GenreFilterBonus  float64
+	PassesGenreFilter bool
+	SameArtist        bool
+	Series            string // Franchise both tracks belong to, empty if none
+	MatchReasons      []string
+	Scores            []ModeScore // Balanced, similar and related, in that order
+}
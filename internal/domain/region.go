@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// SupportedRegions are the territories served by both catalogs: the KKBOX
+// territory and the Spotify market of upstream lookups.
+var SupportedRegions = []string{"JP", "TW", "HK", "SG", "MY"}
+
+// IsSupportedRegion reports whether region is one of SupportedRegions.
+// The code must be uppercase.
+func IsSupportedRegion(region string) bool {
+	for _, r := range SupportedRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRegion normalizes a region code from a request (e.g. "tw" -> "TW")
+// and returns an INVALID_REGION error if it is not supported.
+func ParseRegion(s string) (string, error) {
+	region := strings.ToUpper(strings.TrimSpace(s))
+	if !IsSupportedRegion(region) {
+		supported := strings.Join(SupportedRegions, ", ")
+		return "", &Error{
+			Kind:    KindInvalidInput,
+			Code:    ErrCodeInvalidRegion,
+			Message: "unsupported region " + s + " (supported: " + supported + ")",
+			Params:  map[string]string{"region": s, "supported": supported},
+		}
+	}
+	return region, nil
+}
+
+type regionKey struct{}
+
+// WithRegion returns a context whose catalog lookups use region
+// as the KKBOX territory and Spotify market.
+func WithRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
+// RegionFrom returns the region stored in ctx, or "" when none was requested.
+func RegionFrom(ctx context.Context) string {
+	if region, ok := ctx.Value(regionKey{}).(string); ok {
+		return region
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "正常系: 大文字", input: "TW", want: "TW"},
+		{name: "正常系: 小文字と空白を正規化", input: " hk ", want: "HK"},
+		{name: "異常系: 非対応の地域", input: "US", wantErr: true},
+		{name: "異常系: 不正な形式", input: "japan", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRegion(tt.input)
+			if tt.wantErr {
+				var e *Error
+				if !errors.As(err, &e) || e.Kind != KindInvalidInput || e.Code != ErrCodeInvalidRegion {
+					t.Fatalf("expected INVALID_REGION error, got %v", err)
+				}
+				if e.Params["region"] != tt.input {
+					t.Errorf("expected region param %q, got %q", tt.input, e.Params["region"])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRegion(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionFrom(t *testing.T) {
+	if got := RegionFrom(context.Background()); got != "" {
+		t.Errorf("expected no region, got %q", got)
+	}
+	if got := RegionFrom(WithRegion(context.Background(), "SG")); got != "SG" {
+		t.Errorf("expected SG, got %q", got)
+	}
+}
+
+func TestTrack_Playable(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name       string
+		isPlayable *bool
+		want       bool
+	}{
+		{name: "正常系: 地域指定なし", isPlayable: nil, want: true},
+		{name: "正常系: 再生可能", isPlayable: &yes, want: true},
+		{name: "正常系: 再生不可", isPlayable: &no, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track := Track{IsPlayable: tt.isPlayable}
+			if got := track.Playable(); got != tt.want {
+				t.Errorf("Playable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
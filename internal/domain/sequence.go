@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SequenceArc is the energy curve a sequence follows from its first to its last track.
+// Points are target energies between 0 (the calmest track of the set) and 1 (the most
+// energetic one), spread evenly over the sequence and interpolated in between.
+type SequenceArc struct {
+	Name   string
+	Points []float64 // Nil for no arc: only the transitions are smoothed
+}
+
+// Named arcs accepted by ParseSequenceArc.
+const (
+	SequenceArcSmooth   = "smooth"
+	SequenceArcWarmUp   = "warm_up"
+	SequenceArcPeak     = "peak"
+	SequenceArcCoolDown = "cool_down"
+	SequenceArcCustom   = "custom" // Points given by the caller
+)
+
+// sequenceArcs are the points of the named arcs.
+var sequenceArcs = map[string][]float64{
+	SequenceArcSmooth:   nil,
+	SequenceArcWarmUp:   {0, 1},
+	SequenceArcPeak:     {0, 1, 1, 0}, // Warm-up, peak, cool-down
+	SequenceArcCoolDown: {1, 0},
+}
+
+// SupportedSequenceArcs are the names of the arcs in ParseSequenceArc's error message.
+var SupportedSequenceArcs = []string{SequenceArcSmooth, SequenceArcWarmUp, SequenceArcPeak, SequenceArcCoolDown}
+
+// ParseSequenceArc parses an arc name, or comma-separated energies between 0 and 1
+// (e.g. "0.2,1,0.4") for a custom arc. An empty string is the smooth arc.
+// Returns an INVALID_ARC error for anything else.
+func ParseSequenceArc(s string) (SequenceArc, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "" {
+		return SequenceArc{Name: SequenceArcSmooth}, nil
+	}
+	if points, ok := sequenceArcs[name]; ok {
+		return SequenceArc{Name: name, Points: points}, nil
+	}
+
+	parts := strings.Split(name, ",")
+	if len(parts) < 2 {
+		return SequenceArc{}, invalidArcError(s)
+	}
+	points := make([]float64, len(parts))
+	for i, part := range parts {
+		p, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(p) || p < 0 || p > 1 {
+			return SequenceArc{}, invalidArcError(s)
+		}
+		points[i] = p
+	}
+	return SequenceArc{Name: SequenceArcCustom, Points: points}, nil
+}
+
+func invalidArcError(s string) *Error {
+	supported := strings.Join(SupportedSequenceArcs, ", ")
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    "INVALID_ARC",
+		Message: "unsupported arc " + s + " (supported: " + supported + " or energies between 0 and 1)",
+		Params:  map[string]string{"arc": s, "supported": supported},
+	}
+}
+
+// SequenceOptions tunes a sequence.
+type SequenceOptions struct {
+	Arc          SequenceArc
+	FirstTrackID string // Optional: the track to start with
+}
+
+// SequenceInput is a track to be sequenced.
+type SequenceInput struct {
+	Track    Track
+	Features *TrackFeatures // Nil when unknown
+}
+
+// Sequence is an order of tracks with smooth BPM and loudness transitions.
+type Sequence struct {
+	Arc         SequenceArc
+	Tracks      []SequencedTrack // In play order
+	Transitions []Transition     // Transitions[i] leads from Tracks[i] to Tracks[i+1]
+	// TotalCost is the cost of all transitions plus the arc cost of the first track.
+	TotalCost float64
+}
+
+// SequencedTrack is a track at its position in a sequence.
+type SequencedTrack struct {
+	Index        int // Position in the input
+	Track        Track
+	Features     *TrackFeatures
+	Energy       *float64 // Estimated from BPM and loudness; nil when neither is known
+	TargetEnergy *float64 // Asked for by the arc at this position; nil without an arc
+	ArcCost      float64
+}
+
+// Transition is the move from one track of a sequence to the next.
+type Transition struct {
+	FromTrackID string
+	ToTrackID   string
+	BPMCost     float64
+	GainCost    float64
+	ArcCost     float64 // Arc cost of the track moved to
+	Cost        float64 // Weighted sum of the costs above
+	// HalfTime is true when the BPMs match at half or double tempo (e.g. 87 → 174).
+	HalfTime bool
+}
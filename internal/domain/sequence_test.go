@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSequenceArc(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    SequenceArc
+		wantErr bool
+	}{
+		{name: "正常系: 空はsmooth", input: "", want: SequenceArc{Name: SequenceArcSmooth}},
+		{name: "正常系: 名前付きの曲線", input: " Peak ", want: SequenceArc{Name: SequenceArcPeak, Points: []float64{0, 1, 1, 0}}},
+		{name: "正常系: カスタムの曲線", input: "0.2, 1,0.4", want: SequenceArc{Name: SequenceArcCustom, Points: []float64{0.2, 1, 0.4}}},
+		{name: "異常系: 不明な名前", input: "party", wantErr: true},
+		{name: "異常系: 点が1つ", input: "0.5", wantErr: true},
+		{name: "異常系: 範囲外のエネルギー", input: "0,1.5", wantErr: true},
+		{name: "異常系: NaNのエネルギー", input: "nan,1", wantErr: true},
+		{name: "異常系: 無限大のエネルギー", input: "0,inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSequenceArc(tt.input)
+			if tt.wantErr {
+				var e *Error
+				if !errors.As(err, &e) || e.Kind != KindInvalidInput || e.Code != "INVALID_ARC" {
+					t.Fatalf("expected INVALID_ARC error, got %v", err)
+				}
+				if e.Params["arc"] != tt.input {
+					t.Errorf("expected arc param %q, got %q", tt.input, e.Params["arc"])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSequenceArc(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package domain defines the core business entities for TrackTaste.
+package domain
+
+import "time"
+
+// FeatureSource identifies an upstream that contributes a group of track features.
+type FeatureSource string
+
+const (
+	// FeatureSourceDeezer provides BPM, duration and gain.
+	FeatureSourceDeezer FeatureSource = "deezer"
+	// FeatureSourceMusicBrainz provides tags and the artist MBID.
+	FeatureSourceMusicBrainz FeatureSource = "musicbrainz"
+	// FeatureSourceSpotifyGenres provides the primary artist's Spotify genres.
+	FeatureSourceSpotifyGenres FeatureSource = "spotify_genres"
+)
+
+// FeatureGroupMeta describes when and how a feature group was fetched.
+type FeatureGroupMeta struct {
+	Version   int       `json:"version"`    // Schema version of the group at fetch time
+	FetchedAt time.Time `json:"fetched_at"` // When the upstream was last asked
+	Found     bool      `json:"found"`      // false records a negative lookup (not found upstream)
+}
+
+// DeezerFeatureGroup holds features fetched from Deezer.
+type DeezerFeatureGroup struct {
+	FeatureGroupMeta
+	BPM             float64 `json:"bpm"`
+	DurationSeconds int     `json:"duration_seconds"`
+	Gain            float64 `json:"gain"`
+}
+
+// MusicBrainzFeatureGroup holds features fetched from MusicBrainz.
+type MusicBrainzFeatureGroup struct {
+	FeatureGroupMeta
+	Tags       []string `json:"tags"`
+	ArtistMBID string   `json:"artist_mbid"`
+}
+
+// SpotifyGenresFeatureGroup holds the Spotify genres of the track's primary artist.
+type SpotifyGenresFeatureGroup struct {
+	FeatureGroupMeta
+	Genres []string `json:"genres"`
+}
+
+// StoredFeatures is the persisted form of a track's features, keyed by ISRC.
+// Each group is versioned and timestamped independently so that staleness
+// can be decided per upstream. A nil group has never been fetched.
+type StoredFeatures struct {
+	ISRC          string                     `json:"isrc"`
+	Deezer        *DeezerFeatureGroup        `json:"deezer,omitempty"`
+	MusicBrainz   *MusicBrainzFeatureGroup   `json:"musicbrainz,omitempty"`
+	SpotifyGenres *SpotifyGenresFeatureGroup `json:"spotify_genres,omitempty"`
+}
+
+// Merge copies every non-nil group of other into f, unless the group in f
+// was fetched later, so that a delayed write cannot replace newer data.
+func (f *StoredFeatures) Merge(other *StoredFeatures) {
+	if other == nil {
+		return
+	}
+	if other.Deezer != nil && (f.Deezer == nil || !f.Deezer.newerThan(other.Deezer.FeatureGroupMeta)) {
+		f.Deezer = other.Deezer
+	}
+	if other.MusicBrainz != nil && (f.MusicBrainz == nil || !f.MusicBrainz.newerThan(other.MusicBrainz.FeatureGroupMeta)) {
+		f.MusicBrainz = other.MusicBrainz
+	}
+	if other.SpotifyGenres != nil && (f.SpotifyGenres == nil || !f.SpotifyGenres.newerThan(other.SpotifyGenres.FeatureGroupMeta)) {
+		f.SpotifyGenres = other.SpotifyGenres
+	}
+}
+
+// newerThan reports whether m was fetched after other.
+func (m FeatureGroupMeta) newerThan(other FeatureGroupMeta) bool {
+	return m.FetchedAt.After(other.FetchedAt)
+}
+
+// ToTrackFeatures converts the stored groups into TrackFeatures.
+// Spotify genres are not merged into Tags; callers decide how to combine them.
+func (f *StoredFeatures) ToTrackFeatures(trackID string) *TrackFeatures {
+	features := &TrackFeatures{
+		TrackID: trackID,
+		ISRC:    f.ISRC,
+	}
+	if f.Deezer != nil && f.Deezer.Found {
+		features.BPM = f.Deezer.BPM
+		features.DurationSeconds = f.Deezer.DurationSeconds
+		features.Gain = f.Deezer.Gain
+	}
+	if f.MusicBrainz != nil && f.MusicBrainz.Found {
+		features.Tags = append([]string(nil), f.MusicBrainz.Tags...)
+		features.ArtistMBID = f.MusicBrainz.ArtistMBID
+	}
+	return features
+}
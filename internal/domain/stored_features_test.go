@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoredFeatures_Merge(t *testing.T) {
+	now := time.Now()
+	deezer := func(bpm float64, fetchedAt time.Time) *DeezerFeatureGroup {
+		return &DeezerFeatureGroup{FeatureGroupMeta: FeatureGroupMeta{Version: 1, FetchedAt: fetchedAt, Found: true}, BPM: bpm}
+	}
+
+	tests := []struct {
+		name    string
+		current *DeezerFeatureGroup
+		other   *DeezerFeatureGroup
+		wantBPM float64
+	}{
+		{name: "正常系: 未取得のグループを追加", current: nil, other: deezer(120, now), wantBPM: 120},
+		{name: "正常系: 新しいグループで上書き", current: deezer(120, now.Add(-time.Hour)), other: deezer(130, now), wantBPM: 130},
+		{name: "正常系: 同時刻は後から書いたほうを残す", current: deezer(120, now), other: deezer(130, now), wantBPM: 130},
+		{name: "異常系: 古いグループでは上書きしない", current: deezer(120, now), other: deezer(130, now.Add(-time.Hour)), wantBPM: 120},
+		{name: "異常系: nilのグループは無視", current: deezer(120, now), other: nil, wantBPM: 120},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &StoredFeatures{ISRC: "ISRC1", Deezer: tt.current}
+			f.Merge(&StoredFeatures{ISRC: "ISRC1", Deezer: tt.other})
+			if f.Deezer == nil || f.Deezer.BPM != tt.wantBPM {
+				t.Errorf("expected BPM %v, got %+v", tt.wantBPM, f.Deezer)
+			}
+		})
+	}
+}
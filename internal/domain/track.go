@@ -17,6 +17,15 @@ type Track struct {
 	Explicit    bool     `json:"explicit,omitempty"`
 	Artists     []Artist `json:"artists"`
 	Album       Album    `json:"album"`
+	// IsPlayable reports availability in the market the track was looked up in.
+	// Nil when the lookup named no market.
+	IsPlayable *bool `json:"is_playable,omitempty"`
+}
+
+// Playable reports whether the track can be played in the market it was
+// looked up in. Tracks looked up without a market count as playable.
+func (t *Track) Playable() bool {
+	return t.IsPlayable == nil || *t.IsPlayable
 }
 
 // SimpleTrack represents a simplified track without full album details.
@@ -75,3 +84,13 @@ type YTMusicTrack struct {
 	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
 	IsExplicit      bool   `json:"is_explicit"`
 }
+
+// AppleMusicTrack represents a song from the Apple Music catalog.
+type AppleMusicTrack struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
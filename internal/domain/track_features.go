@@ -35,6 +35,16 @@ func (f *TrackFeatures) GetTagNames() []string {
 	return f.Tags
 }
 
+// TrackFeatureSet is a track's merged features and Spotify genres,
+// with the source that provided each field.
+type TrackFeatureSet struct {
+	Track    *Track
+	Features *TrackFeatures
+	Genres   []string                 // Spotify genres of the primary artist
+	Sources  map[string]FeatureSource // Field name (as in JSON, e.g. "bpm") -> source
+	Err      error                    // Batch lookups only: why the track has no features
+}
+
 // ArtistInfo represents artist information for relation bonus calculation.
 type ArtistInfo struct {
 	SpotifyID string       // Spotify Artist ID
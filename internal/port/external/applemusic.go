@@ -0,0 +1,13 @@
+package external
+
+import (
+	"context"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// AppleMusicAPI defines the interface for Apple Music catalog lookups.
+type AppleMusicAPI interface {
+	// GetSong retrieves a song by its catalog ID in the given storefront (e.g. "jp").
+	GetSong(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error)
+}
@@ -13,10 +13,14 @@ type DeezerAPI interface {
 	// GetTrackByISRC searches for a track by ISRC and returns its features.
 	GetTrackByISRC(ctx context.Context, isrc string) (*domain.DeezerTrack, error)
 
+	// GetTrackByID retrieves a track by its Deezer track ID.
+	GetTrackByID(ctx context.Context, id string) (*domain.DeezerTrack, error)
+
 	// SearchTrack searches for a track by title and artist (fallback when ISRC is not available).
 	SearchTrack(ctx context.Context, title, artist string) (*domain.DeezerTrack, error)
 
 	// GetTracksByISRCBatch retrieves multiple tracks by their ISRCs.
-	// Returns a map of ISRC -> DeezerTrack. ISRCs not found will be omitted from the result.
+	// Returns a map of ISRC -> DeezerTrack. ISRCs not found map to nil;
+	// ISRCs whose lookup failed are omitted from the result.
 	GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error)
 }
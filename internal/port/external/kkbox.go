@@ -6,9 +6,11 @@ import (
 
 // KKBOXTrackInfo represents minimal track info from KKBOX.
 type KKBOXTrackInfo struct {
-	ID   string
-	Name string
-	ISRC string
+	ID     string
+	Name   string
+	ISRC   string
+	URL    string // Web page of the track
+	Artist string // Artist of the album; only set by GetTrackDetail
 }
 
 // KKBOXAPI defines the interface for KKBOX API operations.
@@ -13,14 +13,15 @@ type MusicBrainzAPI interface {
 	// GetRecordingByISRC searches for a recording by ISRC.
 	GetRecordingByISRC(ctx context.Context, isrc string) (*domain.MBRecording, error)
 
-	// GetRecordingWithTags retrieves recording details including tags.
+	// GetRecordingWithTags retrieves recording details including tags and its first ISRC.
 	GetRecordingWithTags(ctx context.Context, mbid string) (*domain.MBRecording, error)
 
 	// GetArtistWithRelations retrieves artist details including tags and relations.
 	GetArtistWithRelations(ctx context.Context, mbid string) (*domain.MBArtist, error)
 
 	// GetRecordingsByISRCBatch retrieves multiple recordings by their ISRCs.
-	// Returns a map of ISRC -> MBRecording. ISRCs not found will be omitted from the result.
+	// Returns a map of ISRC -> MBRecording. ISRCs not found map to nil;
+	// ISRCs whose lookup failed are omitted from the result.
 	GetRecordingsByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error)
 
 	// GetArtistRecordings retrieves recordings by an artist (same artist's other tracks).
@@ -14,6 +14,9 @@ type SpotifyAPI interface {
 	GetAlbumByID(ctx context.Context, id string) (*domain.Album, error)
 	SearchTracks(ctx context.Context, query string) ([]domain.Track, error)
 	SearchByISRC(ctx context.Context, isrc string) (*domain.Track, error)
+	SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error)
+	// GetPlaylist returns a playlist with at most limit of its tracks.
+	GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error)
 
 	// Audio Features API
 	GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error)
@@ -15,4 +15,8 @@ type YouTubeMusicAPI interface {
 	// SearchTracks searches for tracks on YouTube Music.
 	// Useful for finding video IDs when only artist/track name is available.
 	SearchTracks(ctx context.Context, query string, limit int) ([]domain.YTMusicTrack, error)
+
+	// GetTrack retrieves a single track by its YouTube video ID.
+	// Useful for resolving a shared YouTube Music link to its title and artist.
+	GetTrack(ctx context.Context, videoID string) (*domain.YTMusicTrack, error)
 }
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// FeatureStore defines the interface for persisting track features keyed by ISRC.
+type FeatureStore interface {
+	// GetFeatures returns the stored features for an ISRC, or (nil, nil) if none are stored.
+	GetFeatures(ctx context.Context, isrc string) (*domain.StoredFeatures, error)
+
+	// GetFeaturesBatch returns stored features for multiple ISRCs.
+	// ISRCs without stored features are omitted from the result.
+	GetFeaturesBatch(ctx context.Context, isrcs []string) (map[string]*domain.StoredFeatures, error)
+
+	// SaveFeatures merges the non-nil groups of features into the stored entry.
+	// Groups that are nil are left untouched.
+	SaveFeatures(ctx context.Context, features *domain.StoredFeatures) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// LinkStore defines the interface for caching cross-platform track links.
+// Entries are keyed by Spotify track ID and region.
+type LinkStore interface {
+	// GetLinks returns the stored links, or (nil, nil) if none are stored.
+	GetLinks(ctx context.Context, trackID, region string) (*domain.TrackLinks, error)
+
+	// SaveLinks stores links, replacing any previous entry.
+	SaveLinks(ctx context.Context, links *domain.TrackLinks) error
+}
@@ -15,6 +15,8 @@ type MockSpotifyAPI struct {
 	GetAlbumByIDFunc          func(ctx context.Context, id string) (*domain.Album, error)
 	SearchTracksFunc          func(ctx context.Context, query string) ([]domain.Track, error)
 	SearchByISRCFunc          func(ctx context.Context, isrc string) (*domain.Track, error)
+	SearchAlbumByUPCFunc      func(ctx context.Context, upc string) (*domain.Album, error)
+	GetPlaylistFunc           func(ctx context.Context, id string, limit int) (*domain.Playlist, error)
 	GetAudioFeaturesFunc      func(ctx context.Context, trackID string) (*domain.AudioFeatures, error)
 	GetAudioFeaturesBatchFunc func(ctx context.Context, trackIDs []string) ([]domain.AudioFeatures, error)
 	GetRecommendationsFunc    func(ctx context.Context, params external.RecommendationParams) ([]domain.Track, error)
@@ -57,6 +59,20 @@ func (m *MockSpotifyAPI) SearchByISRC(ctx context.Context, isrc string) (*domain
 	return nil, nil
 }
 
+func (m *MockSpotifyAPI) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	if m.SearchAlbumByUPCFunc != nil {
+		return m.SearchAlbumByUPCFunc(ctx, upc)
+	}
+	return nil, nil
+}
+
+func (m *MockSpotifyAPI) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	if m.GetPlaylistFunc != nil {
+		return m.GetPlaylistFunc(ctx, id, limit)
+	}
+	return nil, nil
+}
+
 func (m *MockSpotifyAPI) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	if m.GetAudioFeaturesFunc != nil {
 		return m.GetAudioFeaturesFunc(ctx, trackID)
@@ -160,6 +176,7 @@ func (m *MockTokenRepository) InvalidateToken(ctx context.Context, key string) e
 type MockYouTubeMusicAPI struct {
 	GetSimilarTracksFunc func(ctx context.Context, videoID string, limit int) ([]domain.YTMusicTrack, error)
 	SearchTracksFunc     func(ctx context.Context, query string, limit int) ([]domain.YTMusicTrack, error)
+	GetTrackFunc         func(ctx context.Context, videoID string) (*domain.YTMusicTrack, error)
 }
 
 func (m *MockYouTubeMusicAPI) GetSimilarTracks(ctx context.Context, videoID string, limit int) ([]domain.YTMusicTrack, error) {
@@ -176,6 +193,93 @@ func (m *MockYouTubeMusicAPI) SearchTracks(ctx context.Context, query string, li
 	return nil, nil
 }
 
+func (m *MockYouTubeMusicAPI) GetTrack(ctx context.Context, videoID string) (*domain.YTMusicTrack, error) {
+	if m.GetTrackFunc != nil {
+		return m.GetTrackFunc(ctx, videoID)
+	}
+	return nil, nil
+}
+
+// MockDeezerAPI is a mock implementation of external.DeezerAPI.
+type MockDeezerAPI struct {
+	GetTrackByISRCFunc       func(ctx context.Context, isrc string) (*domain.DeezerTrack, error)
+	GetTrackByIDFunc         func(ctx context.Context, id string) (*domain.DeezerTrack, error)
+	SearchTrackFunc          func(ctx context.Context, title, artist string) (*domain.DeezerTrack, error)
+	GetTracksByISRCBatchFunc func(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error)
+}
+
+func (m *MockDeezerAPI) GetTrackByISRC(ctx context.Context, isrc string) (*domain.DeezerTrack, error) {
+	if m.GetTrackByISRCFunc != nil {
+		return m.GetTrackByISRCFunc(ctx, isrc)
+	}
+	return nil, nil
+}
+
+func (m *MockDeezerAPI) GetTrackByID(ctx context.Context, id string) (*domain.DeezerTrack, error) {
+	if m.GetTrackByIDFunc != nil {
+		return m.GetTrackByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockDeezerAPI) SearchTrack(ctx context.Context, title, artist string) (*domain.DeezerTrack, error) {
+	if m.SearchTrackFunc != nil {
+		return m.SearchTrackFunc(ctx, title, artist)
+	}
+	return nil, nil
+}
+
+func (m *MockDeezerAPI) GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error) {
+	if m.GetTracksByISRCBatchFunc != nil {
+		return m.GetTracksByISRCBatchFunc(ctx, isrcs)
+	}
+	return nil, nil
+}
+
+// MockMusicBrainzAPI is a mock implementation of external.MusicBrainzAPI.
+type MockMusicBrainzAPI struct {
+	GetRecordingByISRCFunc       func(ctx context.Context, isrc string) (*domain.MBRecording, error)
+	GetRecordingWithTagsFunc     func(ctx context.Context, mbid string) (*domain.MBRecording, error)
+	GetArtistWithRelationsFunc   func(ctx context.Context, mbid string) (*domain.MBArtist, error)
+	GetRecordingsByISRCBatchFunc func(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error)
+	GetArtistRecordingsFunc      func(ctx context.Context, artistMBID string, limit int) ([]domain.MBRecording, error)
+}
+
+func (m *MockMusicBrainzAPI) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.MBRecording, error) {
+	if m.GetRecordingByISRCFunc != nil {
+		return m.GetRecordingByISRCFunc(ctx, isrc)
+	}
+	return nil, nil
+}
+
+func (m *MockMusicBrainzAPI) GetRecordingWithTags(ctx context.Context, mbid string) (*domain.MBRecording, error) {
+	if m.GetRecordingWithTagsFunc != nil {
+		return m.GetRecordingWithTagsFunc(ctx, mbid)
+	}
+	return nil, nil
+}
+
+func (m *MockMusicBrainzAPI) GetArtistWithRelations(ctx context.Context, mbid string) (*domain.MBArtist, error) {
+	if m.GetArtistWithRelationsFunc != nil {
+		return m.GetArtistWithRelationsFunc(ctx, mbid)
+	}
+	return nil, nil
+}
+
+func (m *MockMusicBrainzAPI) GetRecordingsByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error) {
+	if m.GetRecordingsByISRCBatchFunc != nil {
+		return m.GetRecordingsByISRCBatchFunc(ctx, isrcs)
+	}
+	return nil, nil
+}
+
+func (m *MockMusicBrainzAPI) GetArtistRecordings(ctx context.Context, artistMBID string, limit int) ([]domain.MBRecording, error) {
+	if m.GetArtistRecordingsFunc != nil {
+		return m.GetArtistRecordingsFunc(ctx, artistMBID, limit)
+	}
+	return nil, nil
+}
+
 // Helper functions for creating test data
 
 // StringPtr returns a pointer to the given string.
@@ -173,6 +173,81 @@ func TestMockSpotifyAPI_SearchByISRC(t *testing.T) {
 	}
 }
 
+func TestMockSpotifyAPI_SearchAlbumByUPC(t *testing.T) {
+	mock := &MockSpotifyAPI{
+		SearchAlbumByUPCFunc: func(ctx context.Context, upc string) (*domain.Album, error) {
+			return &domain.Album{ID: "album1", UPC: &upc}, nil
+		},
+	}
+
+	album, err := mock.SearchAlbumByUPC(context.Background(), "4547366473086")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if album == nil || album.ID != "album1" {
+		t.Error("expected album to be found")
+	}
+
+	emptyMock := &MockSpotifyAPI{}
+	album, err = emptyMock.SearchAlbumByUPC(context.Background(), "UPC")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if album != nil {
+		t.Error("expected nil album")
+	}
+}
+
+func TestMockDeezerAPI_GetTrackByISRC(t *testing.T) {
+	mock := &MockDeezerAPI{
+		GetTrackByISRCFunc: func(ctx context.Context, isrc string) (*domain.DeezerTrack, error) {
+			return &domain.DeezerTrack{ID: 3135556, ISRC: isrc}, nil
+		},
+	}
+
+	track, err := mock.GetTrackByISRC(context.Background(), "JPU901800200")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if track == nil || track.ID != 3135556 {
+		t.Error("expected track to be found")
+	}
+
+	emptyMock := &MockDeezerAPI{}
+	track, err = emptyMock.GetTrackByISRC(context.Background(), "ISRC")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if track != nil {
+		t.Error("expected nil track")
+	}
+}
+
+func TestMockMusicBrainzAPI_GetRecordingByISRC(t *testing.T) {
+	mock := &MockMusicBrainzAPI{
+		GetRecordingByISRCFunc: func(ctx context.Context, isrc string) (*domain.MBRecording, error) {
+			return &domain.MBRecording{MBID: "mbid1", ISRC: isrc}, nil
+		},
+	}
+
+	rec, err := mock.GetRecordingByISRC(context.Background(), "JPU901800200")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if rec == nil || rec.MBID != "mbid1" {
+		t.Error("expected recording to be found")
+	}
+
+	emptyMock := &MockMusicBrainzAPI{}
+	rec, err = emptyMock.GetRecordingByISRC(context.Background(), "ISRC")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if rec != nil {
+		t.Error("expected nil recording")
+	}
+}
+
 func TestMockKKBOXAPI_SearchByISRC(t *testing.T) {
 	mock := &MockKKBOXAPI{
 		SearchByISRCFunc: func(ctx context.Context, isrc string) (*external.KKBOXTrackInfo, error) {
@@ -105,13 +105,40 @@ func init() {
 }
 
 // GenreMatcher calculates genre bonus for recommendations.
-type GenreMatcher struct{}
+type GenreMatcher struct {
+	genreToGroup map[string]GenreGroup // nil means the built-in groups
+}
 
-// NewGenreMatcher creates a new GenreMatcher.
+// NewGenreMatcher creates a new GenreMatcher with the built-in genre groups.
 func NewGenreMatcher() *GenreMatcher {
 	return &GenreMatcher{}
 }
 
+// NewGenreMatcherWithGroups creates a GenreMatcher whose groups also contain
+// the genres in extra. A built-in genre listed in extra moves to that group.
+func NewGenreMatcherWithGroups(extra map[GenreGroup][]string) *GenreMatcher {
+	if len(extra) == 0 {
+		return NewGenreMatcher()
+	}
+	lookup := make(map[string]GenreGroup, len(genreToGroup))
+	for genre, group := range genreToGroup {
+		lookup[genre] = group
+	}
+	for group, genres := range extra {
+		for _, genre := range genres {
+			lookup[strings.ToLower(strings.TrimSpace(genre))] = group
+		}
+	}
+	return &GenreMatcher{genreToGroup: lookup}
+}
+
+func (m *GenreMatcher) lookup() map[string]GenreGroup {
+	if m.genreToGroup == nil {
+		return genreToGroup
+	}
+	return m.genreToGroup
+}
+
 // CalculateBonus calculates the genre bonus based on seed and candidate genres.
 // Returns:
 // - 2.0 for exact genre match (strong boost)
@@ -128,8 +155,8 @@ func (m *GenreMatcher) CalculateBonus(seedGenres, candidateGenres []string) floa
 		return 2.0
 	}
 
-	seedGroup := getGenreGroup(seedGenres)
-	candidateGroup := getGenreGroup(candidateGenres)
+	seedGroup := groupOf(m.lookup(), seedGenres)
+	candidateGroup := groupOf(m.lookup(), candidateGenres)
 
 	// If both are unknown/other, no penalty
 	if seedGroup == GenreGroupOther && candidateGroup == GenreGroupOther {
@@ -156,12 +183,26 @@ func (m *GenreMatcher) IsGenreMatch(seedGenres, candidateGenres []string) bool {
 		return true
 	}
 
-	seedGroup := getGenreGroup(seedGenres)
-	candidateGroup := getGenreGroup(candidateGenres)
+	seedGroup := groupOf(m.lookup(), seedGenres)
+	candidateGroup := groupOf(m.lookup(), candidateGenres)
 
 	return seedGroup == candidateGroup && seedGroup != GenreGroupOther
 }
 
+// Group returns the primary genre group of genres, or GenreGroupOther if none is known.
+func (m *GenreMatcher) Group(genres []string) GenreGroup {
+	return groupOf(m.lookup(), genres)
+}
+
+// IsRelated reports whether two genre groups are the same or related.
+// GenreGroupOther is related to no group, not even itself.
+func (m *GenreMatcher) IsRelated(group1, group2 GenreGroup) bool {
+	if group1 == GenreGroupOther || group2 == GenreGroupOther {
+		return false
+	}
+	return group1 == group2 || isRelatedGroup(group1, group2)
+}
+
 // hasExactMatch checks if any genre appears in both lists.
 func hasExactMatch(genres1, genres2 []string) bool {
 	set := make(map[string]struct{}, len(genres1))
@@ -177,14 +218,20 @@ func hasExactMatch(genres1, genres2 []string) bool {
 	return false
 }
 
-// getGenreGroup determines the primary genre group from a list of genres.
-// Prioritizes more specific groups (otaku > idol > jpop > rock > kpop > other).
+// getGenreGroup determines the primary genre group from a list of genres
+// using the built-in groups.
 func getGenreGroup(genres []string) GenreGroup {
+	return groupOf(genreToGroup, genres)
+}
+
+// groupOf determines the primary genre group from a list of genres.
+// Prioritizes more specific groups (otaku > idol > jpop > rock > kpop > other).
+func groupOf(lookup map[string]GenreGroup, genres []string) GenreGroup {
 	groupCounts := make(map[GenreGroup]int)
 
 	for _, genre := range genres {
 		g := strings.ToLower(genre)
-		if group, ok := genreToGroup[g]; ok {
+		if group, ok := lookup[g]; ok {
 			groupCounts[group]++
 		}
 	}
@@ -138,6 +138,70 @@ func TestGenreMatcher_IsGenreMatch(t *testing.T) {
 	}
 }
 
+func TestNewGenreMatcherWithGroups(t *testing.T) {
+	m := NewGenreMatcherWithGroups(map[GenreGroup][]string{
+		GenreGroupOtaku: {"Vtuber", " utaite "},
+		GenreGroupRock:  {"city pop"}, // moves from jpop
+	})
+
+	tests := []struct {
+		name            string
+		seedGenres      []string
+		candidateGenres []string
+		want            bool
+	}{
+		{name: "added genre joins its group", seedGenres: []string{"anime"}, candidateGenres: []string{"vtuber"}, want: true},
+		{name: "added genre is trimmed", seedGenres: []string{"anime"}, candidateGenres: []string{"utaite"}, want: true},
+		{name: "moved genre leaves its old group", seedGenres: []string{"j-pop"}, candidateGenres: []string{"city pop"}, want: false},
+		{name: "moved genre joins its new group", seedGenres: []string{"j-rock"}, candidateGenres: []string{"city pop"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.IsGenreMatch(tt.seedGenres, tt.candidateGenres); got != tt.want {
+				t.Errorf("IsGenreMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The built-in groups are not modified
+	if got := NewGenreMatcher().IsGenreMatch([]string{"j-pop"}, []string{"city pop"}); !got {
+		t.Error("built-in matcher should be unaffected")
+	}
+}
+
+func TestGenreMatcher_Group(t *testing.T) {
+	m := NewGenreMatcherWithGroups(map[GenreGroup][]string{GenreGroupOtaku: {"vtuber"}})
+
+	if got := m.Group([]string{"VTuber", "j-pop"}); got != GenreGroupOtaku {
+		t.Errorf("Group() = %v, want %v", got, GenreGroupOtaku)
+	}
+	if got := m.Group(nil); got != GenreGroupOther {
+		t.Errorf("Group(nil) = %v, want %v", got, GenreGroupOther)
+	}
+}
+
+func TestGenreMatcher_IsRelated(t *testing.T) {
+	tests := []struct {
+		name   string
+		group1 GenreGroup
+		group2 GenreGroup
+		want   bool
+	}{
+		{name: "same group", group1: GenreGroupJPop, group2: GenreGroupJPop, want: true},
+		{name: "related groups", group1: GenreGroupOtaku, group2: GenreGroupRock, want: true},
+		{name: "unrelated groups", group1: GenreGroupOtaku, group2: GenreGroupKPop, want: false},
+		{name: "other is unrelated to itself", group1: GenreGroupOther, group2: GenreGroupOther, want: false},
+	}
+	m := NewGenreMatcher()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.IsRelated(tt.group1, tt.group2); got != tt.want {
+				t.Errorf("IsRelated(%v, %v) = %v, want %v", tt.group1, tt.group2, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetGenreGroup(t *testing.T) {
 	tests := []struct {
 		genres []string
@@ -0,0 +1,190 @@
+package resolver
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+)
+
+var (
+	numericID = regexp.MustCompile(`^[0-9]+$`)
+	videoID   = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	mbid      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	country   = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// kkboxPlatform resolves KKBOX song links such as
+// https://www.kkbox.com/jp/ja/song/OsK2nxkg2sXvqxdxwD.
+type kkboxPlatform struct{ api external.KKBOXAPI }
+
+// NewKKBOX creates the KKBOX platform.
+func NewKKBOX(api external.KKBOXAPI) Platform { return kkboxPlatform{api: api} }
+
+func (kkboxPlatform) Name() string { return "kkbox" }
+
+func (kkboxPlatform) Parse(u *url.URL) (Link, bool) {
+	if host(u) != "kkbox.com" {
+		return Link{}, false
+	}
+	parts := segments(u)
+	for i, s := range parts {
+		if s == "song" && i+1 < len(parts) {
+			link := Link{ID: parts[i+1]}
+			if i > 0 && country.MatchString(parts[0]) {
+				link.Country = parts[0]
+			}
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+func (p kkboxPlatform) Lookup(ctx context.Context, link Link) (*Track, error) {
+	// KKBOX song IDs belong to the territory of the link
+	if region := strings.ToUpper(link.Country); domain.IsSupportedRegion(region) {
+		ctx = domain.WithRegion(ctx, region)
+	}
+	info, err := p.api.GetTrackDetail(ctx, link.ID)
+	if err != nil || info == nil {
+		return nil, err
+	}
+	return &Track{ID: info.ID, ISRC: info.ISRC, Title: info.Name, Artist: info.Artist}, nil
+}
+
+// deezerPlatform resolves Deezer track links such as https://www.deezer.com/en/track/3135556.
+type deezerPlatform struct{ api external.DeezerAPI }
+
+// NewDeezer creates the Deezer platform.
+func NewDeezer(api external.DeezerAPI) Platform { return deezerPlatform{api: api} }
+
+func (deezerPlatform) Name() string { return "deezer" }
+
+func (deezerPlatform) Parse(u *url.URL) (Link, bool) {
+	if host(u) != "deezer.com" {
+		return Link{}, false
+	}
+	parts := segments(u)
+	for i, s := range parts {
+		if s == "track" && i+1 < len(parts) && numericID.MatchString(parts[i+1]) {
+			return Link{ID: parts[i+1]}, true
+		}
+	}
+	return Link{}, false
+}
+
+func (p deezerPlatform) Lookup(ctx context.Context, link Link) (*Track, error) {
+	t, err := p.api.GetTrackByID(ctx, link.ID)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &Track{ID: link.ID, ISRC: t.ISRC, Title: t.Title, Artist: t.ArtistName}, nil
+}
+
+// youTubeMusicPlatform resolves YouTube Music and YouTube video links such as
+// https://music.youtube.com/watch?v=SX_ViT4Ra7k and https://youtu.be/SX_ViT4Ra7k.
+type youTubeMusicPlatform struct{ api external.YouTubeMusicAPI }
+
+// NewYouTubeMusic creates the YouTube Music platform.
+func NewYouTubeMusic(api external.YouTubeMusicAPI) Platform { return youTubeMusicPlatform{api: api} }
+
+func (youTubeMusicPlatform) Name() string { return "youtube_music" }
+
+func (youTubeMusicPlatform) Parse(u *url.URL) (Link, bool) {
+	var id string
+	switch host(u) {
+	case "music.youtube.com", "youtube.com", "m.youtube.com":
+		if u.Path == "/watch" {
+			id = u.Query().Get("v")
+		}
+	case "youtu.be":
+		if parts := segments(u); len(parts) == 1 {
+			id = parts[0]
+		}
+	}
+	if !videoID.MatchString(id) {
+		return Link{}, false
+	}
+	return Link{ID: id}, true
+}
+
+func (p youTubeMusicPlatform) Lookup(ctx context.Context, link Link) (*Track, error) {
+	t, err := p.api.GetTrack(ctx, link.ID)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	// Auto-generated artist channels are named "<artist> - Topic"
+	artist := strings.TrimSuffix(t.Artist, " - Topic")
+	return &Track{ID: t.VideoID, Title: t.Title, Artist: artist}, nil
+}
+
+// appleMusicPlatform resolves Apple Music song links such as
+// https://music.apple.com/jp/album/lemon/1440881040?i=1440881047 and
+// https://music.apple.com/jp/song/lemon/1440881047.
+type appleMusicPlatform struct{ api external.AppleMusicAPI }
+
+// NewAppleMusic creates the Apple Music platform.
+func NewAppleMusic(api external.AppleMusicAPI) Platform { return appleMusicPlatform{api: api} }
+
+func (appleMusicPlatform) Name() string { return "apple_music" }
+
+func (appleMusicPlatform) Parse(u *url.URL) (Link, bool) {
+	if host(u) != "music.apple.com" {
+		return Link{}, false
+	}
+	parts := segments(u)
+	if len(parts) < 3 || !country.MatchString(parts[0]) {
+		return Link{}, false
+	}
+	link := Link{Country: parts[0]}
+	switch parts[1] {
+	case "album":
+		// A song within an album is selected by the i parameter
+		link.ID = u.Query().Get("i")
+	case "song":
+		link.ID = parts[len(parts)-1]
+	}
+	if !numericID.MatchString(link.ID) {
+		return Link{}, false
+	}
+	return link, true
+}
+
+func (p appleMusicPlatform) Lookup(ctx context.Context, link Link) (*Track, error) {
+	t, err := p.api.GetSong(ctx, link.Country, link.ID)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &Track{ID: t.ID, Title: t.Title, Artist: t.Artist}, nil
+}
+
+// musicBrainzPlatform resolves MusicBrainz recording links such as
+// https://musicbrainz.org/recording/b1a9c0e9-d987-4042-ae91-78d6a3267d69.
+type musicBrainzPlatform struct{ api external.MusicBrainzAPI }
+
+// NewMusicBrainz creates the MusicBrainz platform.
+func NewMusicBrainz(api external.MusicBrainzAPI) Platform { return musicBrainzPlatform{api: api} }
+
+func (musicBrainzPlatform) Name() string { return "musicbrainz" }
+
+func (musicBrainzPlatform) Parse(u *url.URL) (Link, bool) {
+	if host(u) != "musicbrainz.org" {
+		return Link{}, false
+	}
+	parts := segments(u)
+	if len(parts) < 2 || parts[0] != "recording" || !mbid.MatchString(parts[1]) {
+		return Link{}, false
+	}
+	return Link{ID: strings.ToLower(parts[1])}, true
+}
+
+func (p musicBrainzPlatform) Lookup(ctx context.Context, link Link) (*Track, error) {
+	rec, err := p.api.GetRecordingWithTags(ctx, link.ID)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return &Track{ID: rec.MBID, ISRC: rec.ISRC, Title: rec.Title, Artist: rec.ArtistName}, nil
+}
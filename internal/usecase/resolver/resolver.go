@@ -0,0 +1,201 @@
+// Package resolver maps track links from other music services to Spotify tracks.
+//
+// Each supported service is a Platform: it recognizes its own track URLs and
+// looks the track up through its gateway. The resolver then finds the Spotify
+// track, by ISRC when the platform knows it and by title and artist otherwise.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// Method is how a link was mapped to a Spotify track.
+type Method string
+
+const (
+	// MethodDirect means the link was a Spotify link.
+	MethodDirect Method = "direct"
+	// MethodISRC means the Spotify track was found by the ISRC of the linked track.
+	MethodISRC Method = "isrc"
+	// MethodSearch means the Spotify track was found by title and artist.
+	MethodSearch Method = "search"
+)
+
+// Link is a track link recognized by a Platform.
+type Link struct {
+	ID      string // Track ID on the platform
+	Country string // Lowercase catalog country or storefront named in the link (e.g. "jp"), if any
+}
+
+// Track identifies a linked track well enough to find it on Spotify.
+type Track struct {
+	ID     string
+	ISRC   string
+	Title  string
+	Artist string
+}
+
+// Platform parses the track links of one service and looks the tracks up.
+type Platform interface {
+	// Name identifies the platform in responses, e.g. "kkbox".
+	Name() string
+	// Parse reports whether u is a track link of this platform.
+	Parse(u *url.URL) (Link, bool)
+	// Lookup retrieves the linked track.
+	Lookup(ctx context.Context, link Link) (*Track, error)
+}
+
+// Resolution describes how a link was resolved to a Spotify track.
+type Resolution struct {
+	TrackID  string // Spotify track ID
+	Platform string // Platform of the link
+	SourceID string // Track ID on that platform
+	Method   Method
+	ISRC     string // ISRC used for matching, if any
+}
+
+// ErrUnsupportedURL indicates that no platform recognizes the link.
+var ErrUnsupportedURL = &domain.Error{
+	Kind:    domain.KindInvalidInput,
+	Code:    domain.ErrCodeUnsupportedURL,
+	Message: "unsupported URL",
+}
+
+// Resolver maps links from the registered platforms to Spotify tracks.
+type Resolver struct {
+	spotifyAPI external.SpotifyAPI
+	platforms  []Platform
+}
+
+// New creates a resolver for the given platforms, tried in order.
+func New(spotifyAPI external.SpotifyAPI, platforms ...Platform) *Resolver {
+	return &Resolver{spotifyAPI: spotifyAPI, platforms: platforms}
+}
+
+// Register adds a platform after the existing ones.
+func (r *Resolver) Register(p Platform) *Resolver {
+	r.platforms = append(r.platforms, p)
+	return r
+}
+
+// Resolve finds the Spotify track a link from a registered platform points to.
+func (r *Resolver) Resolve(ctx context.Context, rawURL string) (*Resolution, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, domain.NewInvalidInputError(domain.ErrCodeEmptyParam, "empty URL")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, domain.ErrInvalidURL
+	}
+
+	for _, p := range r.platforms {
+		link, ok := p.Parse(u)
+		if !ok {
+			continue
+		}
+		logger.DebugContext(ctx, "Resolver", fmt.Sprintf("%s の曲として解決します: %s", p.Name(), link.ID))
+
+		track, err := p.Lookup(ctx, link)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && track == nil) {
+			return nil, domain.ErrTrackNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		spotifyTrack, method, err := r.findOnSpotify(ctx, track)
+		if err != nil {
+			return nil, err
+		}
+		res := &Resolution{
+			TrackID:  spotifyTrack.ID,
+			Platform: p.Name(),
+			SourceID: link.ID,
+			Method:   method,
+		}
+		if method == MethodISRC {
+			res.ISRC = track.ISRC
+		}
+		return res, nil
+	}
+	return nil, ErrUnsupportedURL
+}
+
+// findOnSpotify finds track on Spotify by ISRC, falling back to title and artist.
+// Tracks that are not playable in the requested region are skipped, and so are
+// search results that match the title and artist with less than
+// usecase.MinMatchConfidence; the best remaining result wins.
+func (r *Resolver) findOnSpotify(ctx context.Context, track *Track) (*domain.Track, Method, error) {
+	if track.ISRC != "" {
+		found, err := r.spotifyAPI.SearchByISRC(ctx, track.ISRC)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, "", err
+		}
+		if found != nil && found.Playable() {
+			return found, MethodISRC, nil
+		}
+	}
+
+	// Quotes and colons in the title would break out of the field filters,
+	// so both are sanitized the same way the recommendation search does.
+	if title := usecase.SanitizeSearchQuery(track.Title); title != "" {
+		query := "track:" + title
+		if artist := usecase.SanitizeSearchQuery(track.Artist); artist != "" {
+			query += " artist:" + artist
+		}
+		results, err := r.spotifyAPI.SearchTracks(ctx, query)
+		if err != nil {
+			return nil, "", err
+		}
+		var best *domain.Track
+		var bestConfidence float64
+		for i := range results {
+			if !results[i].Playable() {
+				continue
+			}
+			artist := ""
+			if len(results[i].Artists) > 0 {
+				artist = results[i].Artists[0].Name
+			}
+			confidence := usecase.MatchConfidence(track.Title, track.Artist, results[i].Name, artist)
+			if confidence < usecase.MinMatchConfidence || (best != nil && confidence <= bestConfidence) {
+				continue
+			}
+			best, bestConfidence = &results[i], confidence
+		}
+		if best != nil {
+			return best, MethodSearch, nil
+		}
+	}
+
+	return nil, "", domain.ErrTrackNotFound
+}
+
+// host returns the lowercase host of u without a leading "www.".
+func host(u *url.URL) string {
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// segments returns the non-empty path segments of u.
+func segments(u *url.URL) []string {
+	var parts []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,430 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/testutil"
+)
+
+type mockDeezerAPI struct {
+	getTrackByIDFunc func(ctx context.Context, id string) (*domain.DeezerTrack, error)
+}
+
+func (m *mockDeezerAPI) GetTrackByISRC(ctx context.Context, isrc string) (*domain.DeezerTrack, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockDeezerAPI) GetTrackByID(ctx context.Context, id string) (*domain.DeezerTrack, error) {
+	if m.getTrackByIDFunc != nil {
+		return m.getTrackByIDFunc(ctx, id)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockDeezerAPI) SearchTrack(ctx context.Context, title, artist string) (*domain.DeezerTrack, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockDeezerAPI) GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error) {
+	return nil, nil
+}
+
+type mockAppleMusicAPI struct {
+	getSongFunc func(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error)
+}
+
+func (m *mockAppleMusicAPI) GetSong(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+	if m.getSongFunc != nil {
+		return m.getSongFunc(ctx, storefront, id)
+	}
+	return nil, domain.ErrTrackNotFound
+}
+
+type mockMusicBrainzAPI struct {
+	getRecordingWithTagsFunc func(ctx context.Context, mbid string) (*domain.MBRecording, error)
+}
+
+func (m *mockMusicBrainzAPI) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.MBRecording, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockMusicBrainzAPI) GetRecordingWithTags(ctx context.Context, mbid string) (*domain.MBRecording, error) {
+	if m.getRecordingWithTagsFunc != nil {
+		return m.getRecordingWithTagsFunc(ctx, mbid)
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockMusicBrainzAPI) GetArtistWithRelations(ctx context.Context, mbid string) (*domain.MBArtist, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockMusicBrainzAPI) GetRecordingsByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error) {
+	return nil, nil
+}
+
+func (m *mockMusicBrainzAPI) GetArtistRecordings(ctx context.Context, artistMBID string, limit int) ([]domain.MBRecording, error) {
+	return nil, nil
+}
+
+func TestPlatform_Parse(t *testing.T) {
+	platforms := []Platform{
+		NewKKBOX(nil),
+		NewDeezer(nil),
+		NewYouTubeMusic(nil),
+		NewAppleMusic(nil),
+		NewMusicBrainz(nil),
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		wantPlatform string
+		wantLink     Link
+	}{
+		{
+			name:         "正常系: KKBOX",
+			url:          "https://www.kkbox.com/jp/ja/song/OsK2nxkg2sXvqxdxwD",
+			wantPlatform: "kkbox",
+			wantLink:     Link{ID: "OsK2nxkg2sXvqxdxwD", Country: "jp"},
+		},
+		{
+			name:         "正常系: Deezer（言語付き）",
+			url:          "https://www.deezer.com/en/track/3135556",
+			wantPlatform: "deezer",
+			wantLink:     Link{ID: "3135556"},
+		},
+		{
+			name:         "正常系: YouTube Music",
+			url:          "https://music.youtube.com/watch?v=SX_ViT4Ra7k&si=abc",
+			wantPlatform: "youtube_music",
+			wantLink:     Link{ID: "SX_ViT4Ra7k"},
+		},
+		{
+			name:         "正常系: youtu.be",
+			url:          "https://youtu.be/SX_ViT4Ra7k",
+			wantPlatform: "youtube_music",
+			wantLink:     Link{ID: "SX_ViT4Ra7k"},
+		},
+		{
+			name:         "正常系: Apple Music（アルバム内の曲）",
+			url:          "https://music.apple.com/jp/album/lemon/1440881040?i=1440881047",
+			wantPlatform: "apple_music",
+			wantLink:     Link{ID: "1440881047", Country: "jp"},
+		},
+		{
+			name:         "正常系: Apple Music（曲）",
+			url:          "https://music.apple.com/us/song/lemon/1440881047",
+			wantPlatform: "apple_music",
+			wantLink:     Link{ID: "1440881047", Country: "us"},
+		},
+		{
+			name:         "正常系: MusicBrainz",
+			url:          "https://musicbrainz.org/recording/B1A9C0E9-D987-4042-AE91-78D6A3267D69",
+			wantPlatform: "musicbrainz",
+			wantLink:     Link{ID: "b1a9c0e9-d987-4042-ae91-78d6a3267d69"},
+		},
+		{
+			name: "異常系: Apple Music のアルバム",
+			url:  "https://music.apple.com/jp/album/lemon/1440881040",
+		},
+		{
+			name: "異常系: Deezer のアルバム",
+			url:  "https://www.deezer.com/album/302127",
+		},
+		{
+			name: "異常系: YouTube のチャンネル",
+			url:  "https://www.youtube.com/channel/UCxxxxxxxxxxxxxxxxxxxxxx",
+		},
+		{
+			name: "異常系: 非対応のサービス",
+			url:  "https://example.com/track/123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+			var gotPlatform string
+			var gotLink Link
+			for _, p := range platforms {
+				if link, ok := p.Parse(u); ok {
+					gotPlatform, gotLink = p.Name(), link
+					break
+				}
+			}
+			if gotPlatform != tt.wantPlatform {
+				t.Fatalf("platform = %q, want %q", gotPlatform, tt.wantPlatform)
+			}
+			if gotLink != tt.wantLink {
+				t.Errorf("link = %+v, want %+v", gotLink, tt.wantLink)
+			}
+		})
+	}
+}
+
+// spotifyTrack is a Spotify track by artist.
+func spotifyTrack(id, name, artist string) domain.Track {
+	track := testutil.CreateTestTrack(id, name)
+	track.Artists = []domain.Artist{*testutil.CreateTestArtist("artist_"+id, artist)}
+	return *track
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	playable := false
+
+	tests := []struct {
+		name         string
+		url          string
+		setupSpotify func(*testutil.MockSpotifyAPI)
+		platforms    func() []Platform
+		want         *Resolution
+		wantErr      error
+		wantCode     string
+	}{
+		{
+			name: "正常系: Deezer をISRCで解決",
+			url:  "https://www.deezer.com/track/3135556",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchByISRCFunc = func(ctx context.Context, isrc string) (*domain.Track, error) {
+					if isrc != "JPU901800200" {
+						t.Errorf("SearchByISRC() isrc = %q", isrc)
+					}
+					return testutil.CreateTestTrack("sp1", "Lemon"), nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewDeezer(&mockDeezerAPI{
+					getTrackByIDFunc: func(ctx context.Context, id string) (*domain.DeezerTrack, error) {
+						return &domain.DeezerTrack{ID: 3135556, Title: "Lemon", ISRC: "JPU901800200", ArtistName: "米津玄師"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp1", Platform: "deezer", SourceID: "3135556", Method: MethodISRC, ISRC: "JPU901800200"},
+		},
+		{
+			name: "正常系: Apple Music を曲名とアーティスト名で解決",
+			url:  "music.apple.com/jp/song/lemon/1440881047",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					if query != "track:Lemon artist:米津玄師" {
+						t.Errorf("SearchTracks() query = %q", query)
+					}
+					return []domain.Track{spotifyTrack("sp2", "Lemon", "米津玄師")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewAppleMusic(&mockAppleMusicAPI{
+					getSongFunc: func(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+						if storefront != "jp" {
+							t.Errorf("GetSong() storefront = %q", storefront)
+						}
+						return &domain.AppleMusicTrack{ID: id, Title: "Lemon", Artist: "米津玄師"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp2", Platform: "apple_music", SourceID: "1440881047", Method: MethodSearch},
+		},
+		{
+			name: "正常系: YouTube Music の Topic チャンネル名を除いて検索",
+			url:  "https://music.youtube.com/watch?v=SX_ViT4Ra7k",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					if query != "track:Lemon artist:米津玄師" {
+						t.Errorf("SearchTracks() query = %q", query)
+					}
+					return []domain.Track{spotifyTrack("sp3", "Lemon", "米津玄師")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewYouTubeMusic(&testutil.MockYouTubeMusicAPI{
+					GetTrackFunc: func(ctx context.Context, videoID string) (*domain.YTMusicTrack, error) {
+						return &domain.YTMusicTrack{VideoID: videoID, Title: "Lemon", Artist: "米津玄師 - Topic"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp3", Platform: "youtube_music", SourceID: "SX_ViT4Ra7k", Method: MethodSearch},
+		},
+		{
+			name: "正常系: KKBOX はリンクの地域で曲を取得",
+			url:  "https://www.kkbox.com/tw/tc/song/OsK2nxkg2sXvqxdxwD",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchByISRCFunc = func(ctx context.Context, isrc string) (*domain.Track, error) {
+					return testutil.CreateTestTrack("sp4", "Lemon"), nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewKKBOX(&testutil.MockKKBOXAPI{
+					GetTrackDetailFunc: func(ctx context.Context, trackID string) (*external.KKBOXTrackInfo, error) {
+						if got := domain.RegionFrom(ctx); got != "TW" {
+							t.Errorf("region = %q, want TW", got)
+						}
+						return &external.KKBOXTrackInfo{ID: trackID, Name: "Lemon", ISRC: "JPU901800200"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp4", Platform: "kkbox", SourceID: "OsK2nxkg2sXvqxdxwD", Method: MethodISRC, ISRC: "JPU901800200"},
+		},
+		{
+			name: "正常系: ISRCの曲が再生できない場合は検索にフォールバック",
+			url:  "https://musicbrainz.org/recording/b1a9c0e9-d987-4042-ae91-78d6a3267d69",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchByISRCFunc = func(ctx context.Context, isrc string) (*domain.Track, error) {
+					track := testutil.CreateTestTrack("unplayable", "Lemon")
+					track.IsPlayable = &playable
+					return track, nil
+				}
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					unplayable := spotifyTrack("unplayable", "Lemon", "米津玄師")
+					unplayable.IsPlayable = &playable
+					return []domain.Track{unplayable, spotifyTrack("sp5", "Lemon", "米津玄師")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewMusicBrainz(&mockMusicBrainzAPI{
+					getRecordingWithTagsFunc: func(ctx context.Context, mbid string) (*domain.MBRecording, error) {
+						return &domain.MBRecording{MBID: mbid, Title: "Lemon", ISRC: "JPU901800200", ArtistName: "米津玄師"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp5", Platform: "musicbrainz", SourceID: "b1a9c0e9-d987-4042-ae91-78d6a3267d69", Method: MethodSearch},
+		},
+		{
+			name: "正常系: KKBOX のISRCがない曲はアルバムのアーティスト名で検索",
+			url:  "https://www.kkbox.com/jp/ja/song/OsK2nxkg2sXvqxdxwD",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					if query != "track:Lemon artist:米津玄師" {
+						t.Errorf("SearchTracks() query = %q", query)
+					}
+					return []domain.Track{spotifyTrack("cover", "Lemon", "Cover Band"), spotifyTrack("sp6", "Lemon", "米津玄師")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewKKBOX(&testutil.MockKKBOXAPI{
+					GetTrackDetailFunc: func(ctx context.Context, trackID string) (*external.KKBOXTrackInfo, error) {
+						return &external.KKBOXTrackInfo{ID: trackID, Name: "Lemon", Artist: "米津玄師"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp6", Platform: "kkbox", SourceID: "OsK2nxkg2sXvqxdxwD", Method: MethodSearch},
+		},
+		{
+			name: "正常系: 曲名の記号を除いて検索",
+			url:  "music.apple.com/jp/song/re-re/1440881047",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					if query != "track:Re Re artist:ASIAN KUNG-FU GENERATION" {
+						t.Errorf("SearchTracks() query = %q", query)
+					}
+					return []domain.Track{spotifyTrack("sp7", "Re:Re:", "ASIAN KUNG-FU GENERATION")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewAppleMusic(&mockAppleMusicAPI{
+					getSongFunc: func(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+						return &domain.AppleMusicTrack{ID: id, Title: `"Re:Re:"`, Artist: "ASIAN KUNG-FU GENERATION"}, nil
+					},
+				})}
+			},
+			want: &Resolution{TrackID: "sp7", Platform: "apple_music", SourceID: "1440881047", Method: MethodSearch},
+		},
+		{
+			name:         "異常系: リンク先の曲が存在しない",
+			url:          "https://www.deezer.com/track/1",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {},
+			platforms: func() []Platform {
+				return []Platform{NewDeezer(&mockDeezerAPI{})}
+			},
+			wantErr: domain.ErrTrackNotFound,
+		},
+		{
+			name: "異常系: Spotifyに見つからない",
+			url:  "https://music.apple.com/jp/song/lemon/1440881047",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					return nil, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewAppleMusic(&mockAppleMusicAPI{
+					getSongFunc: func(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+						return &domain.AppleMusicTrack{ID: id, Title: "Lemon", Artist: "米津玄師"}, nil
+					},
+				})}
+			},
+			wantErr: domain.ErrTrackNotFound,
+		},
+		{
+			name: "異常系: 検索結果が別の曲",
+			url:  "https://music.apple.com/jp/song/lemon/1440881047",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				m.SearchTracksFunc = func(ctx context.Context, query string) ([]domain.Track, error) {
+					return []domain.Track{spotifyTrack("other", "Flamingo", "米津玄師"), spotifyTrack("cover", "Lemon", "Cover Band")}, nil
+				}
+			},
+			platforms: func() []Platform {
+				return []Platform{NewAppleMusic(&mockAppleMusicAPI{
+					getSongFunc: func(ctx context.Context, storefront, id string) (*domain.AppleMusicTrack, error) {
+						return &domain.AppleMusicTrack{ID: id, Title: "Lemon", Artist: "米津玄師"}, nil
+					},
+				})}
+			},
+			wantErr: domain.ErrTrackNotFound,
+		},
+		{
+			name:         "異常系: 非対応のURL",
+			url:          "https://example.com/track/123",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {},
+			platforms: func() []Platform {
+				return []Platform{NewDeezer(&mockDeezerAPI{})}
+			},
+			wantCode: domain.ErrCodeUnsupportedURL,
+		},
+		{
+			name:         "異常系: 空のURL",
+			url:          "",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {},
+			platforms:    func() []Platform { return nil },
+			wantCode:     domain.ErrCodeEmptyParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotifyAPI := &testutil.MockSpotifyAPI{}
+			tt.setupSpotify(spotifyAPI)
+			r := New(spotifyAPI, tt.platforms()...)
+
+			got, err := r.Resolve(context.Background(), tt.url)
+
+			if tt.wantErr != nil || tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("Resolve() error = nil, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				var de *domain.Error
+				if tt.wantCode != "" && (!errors.As(err, &de) || de.Code != tt.wantCode) {
+					t.Errorf("Resolve() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() unexpected error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// MinMatchConfidence is the MatchConfidence below which a search result is
+// more likely another track. A perfect title match with a different artist
+// scores below it.
+const MinMatchConfidence = 0.7
+
+// MatchConfidence scores how likely a search result is the track, from title
+// similarity (70%) and an artist match (30%). Fuzzy matches are scaled to stay
+// below 1, which is reserved for ISRC matches.
+func MatchConfidence(title, artist, candidateTitle, candidateArtist string) float64 {
+	score := 0.7 * titleSimilarity(title, candidateTitle)
+	if artist != "" && candidateArtist != "" && FuzzyMatchArtist(artist, candidateArtist) {
+		score += 0.3
+	}
+	return math.Round(score*0.95*100) / 100
+}
+
+// titleSimilarity compares two titles without subtitles such as (feat. ...) or [Remix],
+// as 1 minus the edit distance relative to the longer title.
+func titleSimilarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(SimplifyTrackName(a)))
+	rb := []rune(strings.ToLower(SimplifyTrackName(b)))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// specialCharsRegex matches characters that may cause search issues.
+var specialCharsRegex = regexp.MustCompile(`[～〜「」『』【】（）()[\]<>《》、。・"'：:；;！!？?＆&＃#＄$％%＠@＊*＋+＝=｜|＼\\／/]`)
+
+// SanitizeSearchQuery removes special characters that may cause search issues.
+func SanitizeSearchQuery(s string) string {
+	// Remove special characters
+	s = specialCharsRegex.ReplaceAllString(s, " ")
+	// Collapse multiple spaces
+	s = regexp.MustCompile(`\s+`).ReplaceAllString(s, " ")
+	// Trim
+	return strings.TrimSpace(s)
+}
+
+// subtitlePatterns match subtitles such as (feat. ...) or [Remix] at the end of a title.
+var subtitlePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\s*[\(（【\[].+$`),               // Remove everything after opening bracket
+	regexp.MustCompile(`\s*[-－ー]\s*.+$`),               // Remove everything after dash (common in Japanese titles)
+	regexp.MustCompile(`(?i)\s*(feat\.?|ft\.?).+$`),    // Remove feat. and everything after
+	regexp.MustCompile(`(?i)\s*(remix|ver\.|version)`), // Remove remix/version indicators
+}
+
+// SimplifyTrackName removes common suffixes like (feat. ...), [Remix], etc.
+func SimplifyTrackName(name string) string {
+	result := name
+	for _, pattern := range subtitlePatterns {
+		simplified := pattern.ReplaceAllString(result, "")
+		if simplified != "" && len(simplified) >= 3 {
+			result = simplified
+		}
+	}
+	return SanitizeSearchQuery(result)
+}
+
+// FuzzyMatchArtist checks if two artist names are similar.
+func FuzzyMatchArtist(a, b string) bool {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+
+	if a == b {
+		return true
+	}
+
+	// Check if one contains the other
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return true
+	}
+
+	// Remove common suffixes/prefixes for comparison
+	normalize := func(s string) string {
+		s = strings.ReplaceAll(s, "the ", "")
+		s = strings.ReplaceAll(s, " the", "")
+		s = strings.ReplaceAll(s, "&", "and")
+		s = strings.ReplaceAll(s, "＆", "and")
+		return strings.TrimSpace(s)
+	}
+
+	return normalize(a) == normalize(b)
+}
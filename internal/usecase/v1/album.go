@@ -22,3 +22,19 @@ func (uc *AlbumUseCase) FetchByID(ctx context.Context, albumID string) (*domain.
 	}
 	return uc.spotifyAPI.GetAlbumByID(ctx, albumID)
 }
+
+// FetchByUPC returns the album with the given UPC or EAN.
+func (uc *AlbumUseCase) FetchByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	upc, err := domain.ParseUPC(upc)
+	if err != nil {
+		return nil, err
+	}
+	album, err := uc.spotifyAPI.SearchAlbumByUPC(ctx, upc)
+	if err != nil {
+		return nil, err
+	}
+	if album == nil {
+		return nil, domain.ErrAlbumNotFound
+	}
+	return album, nil
+}
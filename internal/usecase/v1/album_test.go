@@ -80,3 +80,83 @@ func TestAlbumUseCase_FetchByID(t *testing.T) {
 		})
 	}
 }
+
+func TestAlbumUseCase_FetchByUPC(t *testing.T) {
+	tests := []struct {
+		name      string
+		upc       string
+		setupMock func(*testutil.MockSpotifyAPI)
+		wantUPC   string
+		wantErr   error
+	}{
+		{
+			name: "正常系: 有効なUPC",
+			upc:  "4547366473086",
+			setupMock: func(m *testutil.MockSpotifyAPI) {
+				m.SearchAlbumByUPCFunc = func(ctx context.Context, upc string) (*domain.Album, error) {
+					return testutil.CreateTestAlbum("album123", "Test Album"), nil
+				}
+			},
+			wantUPC: "4547366473086",
+		},
+		{
+			name: "正常系: ハイフン付きUPCを正規化",
+			upc:  "4-547366-473086",
+			setupMock: func(m *testutil.MockSpotifyAPI) {
+				m.SearchAlbumByUPCFunc = func(ctx context.Context, upc string) (*domain.Album, error) {
+					return testutil.CreateTestAlbum("album123", "Test Album"), nil
+				}
+			},
+			wantUPC: "4547366473086",
+		},
+		{
+			name:      "異常系: 不正なUPC",
+			upc:       "abc",
+			setupMock: func(m *testutil.MockSpotifyAPI) {},
+			wantErr:   domain.ErrInvalidUPC,
+		},
+		{
+			name: "異常系: 該当なし",
+			upc:  "4547366473086",
+			setupMock: func(m *testutil.MockSpotifyAPI) {
+				m.SearchAlbumByUPCFunc = func(ctx context.Context, upc string) (*domain.Album, error) {
+					return nil, nil
+				}
+			},
+			wantErr: domain.ErrAlbumNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &testutil.MockSpotifyAPI{}
+			tt.setupMock(mockAPI)
+			var gotUPC string
+			if search := mockAPI.SearchAlbumByUPCFunc; search != nil {
+				mockAPI.SearchAlbumByUPCFunc = func(ctx context.Context, upc string) (*domain.Album, error) {
+					gotUPC = upc
+					return search(ctx, upc)
+				}
+			}
+
+			uc := NewAlbumUseCase(mockAPI)
+			got, err := uc.FetchByUPC(context.Background(), tt.upc)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FetchByUPC() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchByUPC() unexpected error = %v", err)
+			}
+			if got == nil || got.ID != "album123" {
+				t.Errorf("FetchByUPC() = %v, want album123", got)
+			}
+			if gotUPC != tt.wantUPC {
+				t.Errorf("searched UPC = %q, want %q", gotUPC, tt.wantUPC)
+			}
+		})
+	}
+}
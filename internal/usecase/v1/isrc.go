@@ -0,0 +1,108 @@
+// Package v1 contains V1 business logic for TrackTaste.
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+// ISRCUseCase looks a recording up by ISRC on Spotify, KKBOX, Deezer and MusicBrainz.
+type ISRCUseCase struct {
+	spotifyAPI     external.SpotifyAPI
+	kkboxAPI       external.KKBOXAPI
+	deezerAPI      external.DeezerAPI
+	musicBrainzAPI external.MusicBrainzAPI
+}
+
+func NewISRCUseCase(
+	spotifyAPI external.SpotifyAPI,
+	kkboxAPI external.KKBOXAPI,
+	deezerAPI external.DeezerAPI,
+	musicBrainzAPI external.MusicBrainzAPI,
+) *ISRCUseCase {
+	return &ISRCUseCase{
+		spotifyAPI:     spotifyAPI,
+		kkboxAPI:       kkboxAPI,
+		deezerAPI:      deezerAPI,
+		musicBrainzAPI: musicBrainzAPI,
+	}
+}
+
+// FetchByISRC returns the Spotify track with the ISRC and its matches on the other platforms.
+// A Spotify failure fails the lookup; a failing platform is left out of the matches.
+// It returns ErrTrackNotFound when no platform has the ISRC.
+func (uc *ISRCUseCase) FetchByISRC(ctx context.Context, isrc string) (*domain.ISRCLookup, error) {
+	isrc, err := domain.ParseISRC(isrc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ISRCLookup{ISRC: isrc, Matches: make(map[string]domain.PlatformLink)}
+	var (
+		mu         sync.Mutex
+		spotifyErr error
+	)
+	match := func(platform string, find func() (*domain.PlatformLink, error)) func() {
+		return func() {
+			link, err := find()
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "TrackISRC", fmt.Sprintf("%s の検索に失敗: %v", platform, err))
+				return
+			}
+			if link != nil {
+				mu.Lock()
+				result.Matches[platform] = *link
+				mu.Unlock()
+			}
+		}
+	}
+
+	g := safego.NewGroup("TrackISRC")
+	g.Go(domain.PlatformSpotify, func() {
+		track, err := uc.spotifyAPI.SearchByISRC(ctx, isrc)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			spotifyErr = err
+			return
+		}
+		result.Track = track
+	})
+	g.Go(domain.PlatformKKBOX, match(domain.PlatformKKBOX, func() (*domain.PlatformLink, error) {
+		info, err := uc.kkboxAPI.SearchByISRC(ctx, isrc)
+		if err != nil || info == nil || info.ID == "" {
+			return nil, err
+		}
+		return &domain.PlatformLink{ID: info.ID, URL: info.URL, Confidence: 1, Method: domain.LinkMethodISRC}, nil
+	}))
+	g.Go(domain.PlatformDeezer, match(domain.PlatformDeezer, func() (*domain.PlatformLink, error) {
+		track, err := uc.deezerAPI.GetTrackByISRC(ctx, isrc)
+		if err != nil || track == nil {
+			return nil, err
+		}
+		id := fmt.Sprintf("%d", track.ID)
+		return &domain.PlatformLink{ID: id, URL: "https://www.deezer.com/track/" + id, Confidence: 1, Method: domain.LinkMethodISRC}, nil
+	}))
+	g.Go(domain.PlatformMusicBrainz, match(domain.PlatformMusicBrainz, func() (*domain.PlatformLink, error) {
+		rec, err := uc.musicBrainzAPI.GetRecordingByISRC(ctx, isrc)
+		if err != nil || rec == nil || rec.MBID == "" {
+			return nil, err
+		}
+		return &domain.PlatformLink{ID: rec.MBID, URL: "https://musicbrainz.org/recording/" + rec.MBID, Confidence: 1, Method: domain.LinkMethodISRC}, nil
+	}))
+	g.Wait()
+
+	if spotifyErr != nil {
+		return nil, spotifyErr
+	}
+	if result.Track == nil && len(result.Matches) == 0 {
+		return nil, domain.ErrTrackNotFound
+	}
+	logger.InfoContext(ctx, "TrackISRC", fmt.Sprintf("%d 件のサービスで一致", len(result.Matches)))
+	return result, nil
+}
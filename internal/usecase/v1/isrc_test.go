@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/testutil"
+)
+
+func TestISRCUseCase_FetchByISRC(t *testing.T) {
+	const isrc = "JPU901800200"
+
+	tests := []struct {
+		name        string
+		isrc        string
+		spotifyErr  error
+		spotifyMiss bool
+		deezerErr   error
+		wantTrack   bool
+		wantMatches []string
+		wantErr     error
+	}{
+		{
+			name:        "正常系: 全サービスで一致",
+			isrc:        isrc,
+			wantTrack:   true,
+			wantMatches: []string{domain.PlatformKKBOX, domain.PlatformDeezer, domain.PlatformMusicBrainz},
+		},
+		{
+			name:        "正常系: 小文字とハイフンを正規化",
+			isrc:        "jp-u90-18-00200",
+			wantTrack:   true,
+			wantMatches: []string{domain.PlatformKKBOX, domain.PlatformDeezer, domain.PlatformMusicBrainz},
+		},
+		{
+			name:        "正常系: Spotifyになくても他サービスの一致を返す",
+			isrc:        isrc,
+			spotifyMiss: true,
+			wantMatches: []string{domain.PlatformKKBOX, domain.PlatformDeezer, domain.PlatformMusicBrainz},
+		},
+		{
+			name:        "正常系: Deezerの障害は一致から除外",
+			isrc:        isrc,
+			deezerErr:   errors.New("deezer down"),
+			wantTrack:   true,
+			wantMatches: []string{domain.PlatformKKBOX, domain.PlatformMusicBrainz},
+		},
+		{
+			name:    "異常系: 不正なISRC",
+			isrc:    "not-an-isrc",
+			wantErr: domain.ErrInvalidISRC,
+		},
+		{
+			name:    "異常系: どのサービスにもない",
+			isrc:    "USRC17607839",
+			wantErr: domain.ErrTrackNotFound,
+		},
+		{
+			name:       "異常系: Spotifyの障害",
+			isrc:       isrc,
+			spotifyErr: domain.ErrUpstreamUnavailable,
+			wantErr:    domain.ErrUpstreamUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotify := &testutil.MockSpotifyAPI{
+				SearchByISRCFunc: func(ctx context.Context, code string) (*domain.Track, error) {
+					if tt.spotifyErr != nil {
+						return nil, tt.spotifyErr
+					}
+					if tt.spotifyMiss || code != isrc {
+						return nil, nil
+					}
+					return testutil.CreateTestTrack("track123", "Lemon"), nil
+				},
+			}
+			kkbox := &testutil.MockKKBOXAPI{
+				SearchByISRCFunc: func(ctx context.Context, code string) (*external.KKBOXTrackInfo, error) {
+					if code != isrc {
+						return nil, nil
+					}
+					return &external.KKBOXTrackInfo{ID: "kk1", ISRC: code, URL: "https://www.kkbox.com/jp/ja/song/kk1"}, nil
+				},
+			}
+			deezer := &testutil.MockDeezerAPI{
+				GetTrackByISRCFunc: func(ctx context.Context, code string) (*domain.DeezerTrack, error) {
+					if tt.deezerErr != nil {
+						return nil, tt.deezerErr
+					}
+					if code != isrc {
+						return nil, domain.ErrNotFound
+					}
+					return &domain.DeezerTrack{ID: 3135556, ISRC: code}, nil
+				},
+			}
+			mb := &testutil.MockMusicBrainzAPI{
+				GetRecordingByISRCFunc: func(ctx context.Context, code string) (*domain.MBRecording, error) {
+					if code != isrc {
+						return nil, domain.ErrNotFound
+					}
+					return &domain.MBRecording{MBID: "mbid1", ISRC: code}, nil
+				},
+			}
+
+			uc := NewISRCUseCase(spotify, kkbox, deezer, mb)
+			got, err := uc.FetchByISRC(context.Background(), tt.isrc)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FetchByISRC() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchByISRC() unexpected error = %v", err)
+			}
+			if got.ISRC != isrc {
+				t.Errorf("ISRC = %q, want %q", got.ISRC, isrc)
+			}
+			if (got.Track != nil) != tt.wantTrack {
+				t.Errorf("Track = %v, want present = %v", got.Track, tt.wantTrack)
+			}
+			if len(got.Matches) != len(tt.wantMatches) {
+				t.Errorf("Matches = %v, want %v", got.Matches, tt.wantMatches)
+			}
+			for _, platform := range tt.wantMatches {
+				link, ok := got.Matches[platform]
+				if !ok {
+					t.Errorf("Matches[%s] missing", platform)
+					continue
+				}
+				if link.Method != domain.LinkMethodISRC || link.Confidence != 1 {
+					t.Errorf("Matches[%s] = %+v, want ISRC match", platform, link)
+				}
+			}
+			if link, ok := got.Matches[domain.PlatformDeezer]; ok && link.URL != "https://www.deezer.com/track/3135556" {
+				t.Errorf("Matches[deezer].URL = %q, want https://www.deezer.com/track/3135556", link.URL)
+			}
+		})
+	}
+}
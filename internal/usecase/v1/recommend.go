@@ -11,6 +11,7 @@ import (
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
 	"github.com/t1nyb0x/tracktaste/internal/usecase"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
 )
 
 const (
@@ -46,6 +47,7 @@ func (uc *RecommendUseCase) GetRecommendations(
 	mode domain.RecommendMode,
 	limit int,
 ) (*domain.RecommendResult, error) {
+	ctx = logger.WithSeedTrack(ctx, trackID)
 	ctx, cancel := context.WithTimeout(ctx, recommendTimeout)
 	defer cancel()
 
@@ -57,17 +59,17 @@ func (uc *RecommendUseCase) GetRecommendations(
 	}
 
 	// Step 1: Get seed track info
-	logger.Info("Recommend", "シードトラック情報を取得")
+	logger.InfoContext(ctx, "Recommend", "シードトラック情報を取得")
 	track, err := uc.spotifyAPI.GetTrackByID(ctx, trackID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Step 2: Get audio features for seed track
-	logger.Info("Recommend", "Audio Features を取得")
+	logger.InfoContext(ctx, "Recommend", "Audio Features を取得")
 	seedFeatures, err := uc.spotifyAPI.GetAudioFeatures(ctx, trackID)
 	if err != nil {
-		logger.Warning("Recommend", "Audio Features 取得失敗: "+err.Error())
+		logger.WarningContext(ctx, "Recommend", "Audio Features 取得失敗: "+err.Error())
 		// Continue without audio features
 	}
 
@@ -76,14 +78,14 @@ func (uc *RecommendUseCase) GetRecommendations(
 	if len(track.Artists) > 0 {
 		seedGenres, err = uc.spotifyAPI.GetArtistGenres(ctx, track.Artists[0].ID)
 		if err != nil {
-			logger.Warning("Recommend", "アーティストジャンル取得失敗: "+err.Error())
+			logger.WarningContext(ctx, "Recommend", "アーティストジャンル取得失敗: "+err.Error())
 		}
 	}
 
 	// Step 4: Collect candidate tracks (parallel)
-	logger.Info("Recommend", "候補トラックを収集")
+	logger.InfoContext(ctx, "Recommend", "候補トラックを収集")
 	candidates := uc.collectCandidates(ctx, track, seedFeatures)
-	logger.Info("Recommend", "候補トラック数: "+string(rune('0'+len(candidates)/10))+string(rune('0'+len(candidates)%10)))
+	logger.InfoContext(ctx, "Recommend", "候補トラック数: "+string(rune('0'+len(candidates)/10))+string(rune('0'+len(candidates)%10)))
 
 	if len(candidates) == 0 {
 		return &domain.RecommendResult{
@@ -96,14 +98,14 @@ func (uc *RecommendUseCase) GetRecommendations(
 	}
 
 	// Step 5: Get audio features for candidates (batch)
-	logger.Info("Recommend", "候補の Audio Features をバッチ取得")
+	logger.InfoContext(ctx, "Recommend", "候補の Audio Features をバッチ取得")
 	candidateIDs := make([]string, len(candidates))
 	for i, c := range candidates {
 		candidateIDs[i] = c.ID
 	}
 	candidateFeatures, err := uc.spotifyAPI.GetAudioFeaturesBatch(ctx, candidateIDs)
 	if err != nil {
-		logger.Warning("Recommend", "候補 Audio Features 取得失敗: "+err.Error())
+		logger.WarningContext(ctx, "Recommend", "候補 Audio Features 取得失敗: "+err.Error())
 	}
 
 	// Create feature map for quick lookup
@@ -113,16 +115,16 @@ func (uc *RecommendUseCase) GetRecommendations(
 	}
 
 	// Step 6: Get artist genres for candidates (batch)
-	logger.Info("Recommend", "候補のアーティストジャンルをバッチ取得")
+	logger.InfoContext(ctx, "Recommend", "候補のアーティストジャンルをバッチ取得")
 	artistIDs := collectUniqueArtistIDs(candidates)
 	artistGenres, err := uc.spotifyAPI.GetArtistGenresBatch(ctx, artistIDs)
 	if err != nil {
-		logger.Warning("Recommend", "アーティストジャンルバッチ取得失敗: "+err.Error())
+		logger.WarningContext(ctx, "Recommend", "アーティストジャンルバッチ取得失敗: "+err.Error())
 		artistGenres = make(map[string][]string)
 	}
 
 	// Step 7: Calculate scores and rank
-	logger.Info("Recommend", "スコア計算とランキング")
+	logger.InfoContext(ctx, "Recommend", "スコア計算とランキング")
 	recommendedTracks := make([]domain.RecommendedTrack, 0, len(candidates))
 
 	for _, candidate := range candidates {
@@ -190,15 +192,12 @@ func (uc *RecommendUseCase) collectCandidates(
 ) []domain.Track {
 	var (
 		mu         sync.Mutex
-		wg         sync.WaitGroup
+		g          = safego.NewGroup("Recommend")
 		candidates []domain.Track
 	)
 
 	// Spotify Recommendations API
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
+	g.Go("Spotify", func() {
 		params := external.RecommendationParams{
 			SeedTracks: []string{seedTrack.ID},
 			Limit:      spotifyCandidateLimit,
@@ -216,21 +215,18 @@ func (uc *RecommendUseCase) collectCandidates(
 
 		tracks, err := uc.spotifyAPI.GetRecommendations(ctx, params)
 		if err != nil {
-			logger.Warning("Recommend", "Spotify Recommendations 取得失敗: "+err.Error())
+			logger.WarningContext(ctx, "Recommend", "Spotify Recommendations 取得失敗: "+err.Error())
 			return
 		}
 
 		mu.Lock()
 		candidates = append(candidates, tracks...)
 		mu.Unlock()
-	}()
+	})
 
 	// KKBOX Recommendations (via ISRC)
 	if seedTrack.ISRC != nil && *seedTrack.ISRC != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-
+		g.Go("KKBOX", func() {
 			// Search for the track in KKBOX
 			kkboxTrack, err := uc.kkboxAPI.SearchByISRC(ctx, *seedTrack.ISRC)
 			if err != nil || kkboxTrack == nil {
@@ -240,7 +236,7 @@ func (uc *RecommendUseCase) collectCandidates(
 			// Get KKBOX recommendations
 			recommended, err := uc.kkboxAPI.GetRecommendedTracks(ctx, kkboxTrack.ID)
 			if err != nil {
-				logger.Warning("Recommend", "KKBOX Recommendations 取得失敗: "+err.Error())
+				logger.WarningContext(ctx, "Recommend", "KKBOX Recommendations 取得失敗: "+err.Error())
 				return
 			}
 
@@ -250,10 +246,10 @@ func (uc *RecommendUseCase) collectCandidates(
 			mu.Lock()
 			candidates = append(candidates, kkboxTracks...)
 			mu.Unlock()
-		}()
+		})
 	}
 
-	wg.Wait()
+	g.Wait()
 
 	// Remove duplicates and filter
 	candidates = removeDuplicateTracks(candidates, seedTrack.ID)
@@ -273,7 +269,7 @@ func (uc *RecommendUseCase) convertKKBOXToSpotify(
 ) []domain.Track {
 	var (
 		mu     sync.Mutex
-		wg     sync.WaitGroup
+		g      = safego.NewGroup("Recommend")
 		tracks []domain.Track
 		sem    = make(chan struct{}, 5) // Limit concurrent requests
 	)
@@ -283,24 +279,23 @@ func (uc *RecommendUseCase) convertKKBOXToSpotify(
 			continue
 		}
 
-		wg.Add(1)
-		go func(isrc string) {
-			defer wg.Done()
+		isrc := kt.ISRC
+		g.Go("Spotify ISRC", func() {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
 			track, err := uc.spotifyAPI.SearchByISRC(ctx, isrc)
-			if err != nil || track == nil {
+			if err != nil || track == nil || !track.Playable() {
 				return
 			}
 
 			mu.Lock()
 			tracks = append(tracks, *track)
 			mu.Unlock()
-		}(kt.ISRC)
+		})
 	}
 
-	wg.Wait()
+	g.Wait()
 	return tracks
 }
 
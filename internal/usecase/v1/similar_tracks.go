@@ -5,34 +5,64 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
 )
 
-const (
-	maxConcurrent    = 5
-	requestTimeout   = 5 * time.Second
-	overallTimeout   = 30 * time.Second
-	maxSimilarTracks = 30
-)
+// SimilarOptions tunes the KKBOX-based similar track lookup.
+type SimilarOptions struct {
+	Timeout        time.Duration // Overall deadline of one lookup
+	RequestTimeout time.Duration // Deadline of each Spotify ISRC search
+	Concurrency    int           // Concurrent Spotify ISRC searches
+	MaxResults     int
+}
+
+// DefaultSimilarOptions returns the default similar track settings.
+func DefaultSimilarOptions() SimilarOptions {
+	return SimilarOptions{
+		Timeout:        30 * time.Second,
+		RequestTimeout: 5 * time.Second,
+		Concurrency:    5,
+		MaxResults:     30,
+	}
+}
 
 type SimilarTracksUseCase struct {
 	spotifyAPI external.SpotifyAPI
 	kkboxAPI   external.KKBOXAPI
+	opts       atomic.Pointer[SimilarOptions]
 }
 
 func NewSimilarTracksUseCase(spotifyAPI external.SpotifyAPI, kkboxAPI external.KKBOXAPI) *SimilarTracksUseCase {
-	return &SimilarTracksUseCase{spotifyAPI: spotifyAPI, kkboxAPI: kkboxAPI}
+	uc := &SimilarTracksUseCase{spotifyAPI: spotifyAPI, kkboxAPI: kkboxAPI}
+	uc.UpdateOptions(DefaultSimilarOptions())
+	return uc
+}
+
+// WithOptions replaces the lookup settings (see DefaultSimilarOptions).
+func (uc *SimilarTracksUseCase) WithOptions(opts SimilarOptions) *SimilarTracksUseCase {
+	uc.UpdateOptions(opts)
+	return uc
+}
+
+// UpdateOptions replaces the lookup settings at runtime.
+// Lookups already running keep the settings they started with.
+func (uc *SimilarTracksUseCase) UpdateOptions(opts SimilarOptions) {
+	uc.opts.Store(&opts)
 }
 
 func (uc *SimilarTracksUseCase) FetchSimilar(ctx context.Context, trackID string) (*domain.SimilarTracksResult, error) {
-	ctx, cancel := context.WithTimeout(ctx, overallTimeout)
+	opts := *uc.opts.Load()
+	ctx = logger.WithSeedTrack(ctx, trackID)
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	logger.Info("SimilarTracks", "Spotifyからトラック情報を取得")
+	logger.InfoContext(ctx, "SimilarTracks", "Spotifyからトラック情報を取得")
 	track, err := uc.spotifyAPI.GetTrackByID(ctx, trackID)
 	if err != nil {
 		return nil, err
@@ -43,16 +73,16 @@ func (uc *SimilarTracksUseCase) FetchSimilar(ctx context.Context, trackID string
 	}
 	isrc := *track.ISRC
 
-	logger.Info("SimilarTracks", "KKBOXで検索")
+	logger.InfoContext(ctx, "SimilarTracks", "KKBOXで検索")
 	kkboxTrack, err := uc.kkboxAPI.SearchByISRC(ctx, isrc)
 	if err != nil {
 		return nil, err
 	}
 	if kkboxTrack == nil {
-		return nil, domain.ErrTrackNotFound
+		return nil, domain.ErrKKBOXTrackNotFound
 	}
 
-	logger.Info("SimilarTracks", "KKBOXからレコメンドトラックを取得")
+	logger.InfoContext(ctx, "SimilarTracks", "KKBOXからレコメンドトラックを取得")
 	recommended, err := uc.kkboxAPI.GetRecommendedTracks(ctx, kkboxTrack.ID)
 	if err != nil {
 		return nil, err
@@ -70,8 +100,8 @@ func (uc *SimilarTracksUseCase) FetchSimilar(ctx context.Context, trackID string
 		}
 	}
 
-	logger.Info("SimilarTracks", "Spotifyで並列検索開始")
-	similarTracks := uc.searchSpotifyParallel(ctx, isrcList)
+	logger.InfoContext(ctx, "SimilarTracks", "Spotifyで並列検索開始")
+	similarTracks := uc.searchSpotifyParallel(ctx, isrcList, opts)
 	similarTracks = removeDuplicates(similarTracks)
 
 	sort.Slice(similarTracks, func(i, j int) bool {
@@ -85,18 +115,18 @@ func (uc *SimilarTracksUseCase) FetchSimilar(ctx context.Context, trackID string
 		return popI > popJ
 	})
 
-	if len(similarTracks) > maxSimilarTracks {
-		similarTracks = similarTracks[:maxSimilarTracks]
+	if len(similarTracks) > opts.MaxResults {
+		similarTracks = similarTracks[:opts.MaxResults]
 	}
 
 	return &domain.SimilarTracksResult{Items: similarTracks}, nil
 }
 
-func (uc *SimilarTracksUseCase) searchSpotifyParallel(ctx context.Context, isrcList []string) []domain.SimilarTrack {
+func (uc *SimilarTracksUseCase) searchSpotifyParallel(ctx context.Context, isrcList []string, opts SimilarOptions) []domain.SimilarTrack {
 	var results []domain.SimilarTrack
 	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, maxConcurrent)
+	g := safego.NewGroup("SimilarTracks")
+	sem := make(chan struct{}, opts.Concurrency)
 
 	for _, isrc := range isrcList {
 		select {
@@ -105,19 +135,21 @@ func (uc *SimilarTracksUseCase) searchSpotifyParallel(ctx context.Context, isrcL
 		default:
 		}
 
-		wg.Add(1)
-		go func(isrc string) {
-			defer wg.Done()
+		g.Go("Spotify ISRC", func() {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+			reqCtx, cancel := context.WithTimeout(ctx, opts.RequestTimeout)
 			defer cancel()
 
 			track, err := uc.spotifyAPI.SearchByISRC(reqCtx, isrc)
 			if err != nil || track == nil {
 				return
 			}
+			// Not available in the requested market
+			if !track.Playable() {
+				return
+			}
 
 			result := domain.SimilarTrack{
 				ID:          track.ID,
@@ -134,10 +166,10 @@ func (uc *SimilarTracksUseCase) searchSpotifyParallel(ctx context.Context, isrcL
 			mu.Lock()
 			results = append(results, result)
 			mu.Unlock()
-		}(isrc)
+		})
 	}
 
-	wg.Wait()
+	g.Wait()
 	return results
 }
 
@@ -55,6 +55,38 @@ func TestSimilarTracksUseCase_FetchSimilar(t *testing.T) {
 			wantErr:       nil,
 			wantItemCount: 2,
 		},
+		{
+			name:    "正常系: 指定地域で再生できない曲を除外",
+			trackID: "track123",
+			setupSpotify: func(m *testutil.MockSpotifyAPI) {
+				isrc := "JPTEST12345"
+				m.GetTrackByIDFunc = func(ctx context.Context, id string) (*domain.Track, error) {
+					track := testutil.CreateTestTrack(id, "Test Track")
+					track.ISRC = &isrc
+					return track, nil
+				}
+				m.SearchByISRCFunc = func(ctx context.Context, isrc string) (*domain.Track, error) {
+					playable := isrc == "ISRC001"
+					track := testutil.CreateTestTrack("similar-"+isrc, "Similar Track")
+					track.ISRC = testutil.StringPtr(isrc)
+					track.IsPlayable = &playable
+					return track, nil
+				}
+			},
+			setupKKBOX: func(m *testutil.MockKKBOXAPI) {
+				m.SearchByISRCFunc = func(ctx context.Context, isrc string) (*external.KKBOXTrackInfo, error) {
+					return &external.KKBOXTrackInfo{ID: "kkbox123", Name: "Test Track", ISRC: isrc}, nil
+				}
+				m.GetRecommendedTracksFunc = func(ctx context.Context, trackID string) ([]external.KKBOXTrackInfo, error) {
+					return []external.KKBOXTrackInfo{
+						{ID: "rec1", Name: "Rec 1", ISRC: "ISRC001"},
+						{ID: "rec2", Name: "Rec 2", ISRC: "ISRC002"},
+					}, nil
+				}
+			},
+			wantErr:       nil,
+			wantItemCount: 1,
+		},
 		{
 			name:    "正常系: レコメンド0件",
 			trackID: "track123",
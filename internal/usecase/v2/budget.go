@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
+)
+
+// StageBudget is the share of the time left before the request deadline that
+// each pipeline stage may use. Shares are cumulative: time a stage does not use
+// is carried over to the next one. Whatever is left after Score is kept in
+// reserve for writing the response.
+type StageBudget struct {
+	Collect float64
+	Enrich  float64
+	Filter  float64
+	Score   float64
+}
+
+// DefaultStageBudget returns the default stage shares.
+// Collect and enrich wait on upstream APIs; filter and score are local computation.
+func DefaultStageBudget() StageBudget {
+	return StageBudget{
+		Collect: 0.40,
+		Enrich:  0.45,
+		Filter:  0.05,
+		Score:   0.05,
+	}
+}
+
+// stagePlan holds the deadline of each stage and records the stages that ran out of time.
+type stagePlan struct {
+	deadlines map[domain.PipelineStage]time.Time // Empty when the request has no deadline
+
+	mu      sync.Mutex
+	partial []domain.PipelineStage
+}
+
+// newStagePlan splits the time left before ctx's deadline between the stages.
+func newStagePlan(ctx context.Context, budget StageBudget, now time.Time) *stagePlan {
+	p := &stagePlan{deadlines: make(map[domain.PipelineStage]time.Time)}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return p
+	}
+
+	remaining := deadline.Sub(now)
+	share := 0.0
+	for _, s := range []struct {
+		stage domain.PipelineStage
+		share float64
+	}{
+		{domain.StageCollect, budget.Collect},
+		{domain.StageEnrich, budget.Enrich},
+		{domain.StageFilter, budget.Filter},
+		{domain.StageScore, budget.Score},
+	} {
+		share += s.share
+		if share > 1 {
+			share = 1
+		}
+		p.deadlines[s.stage] = now.Add(time.Duration(float64(remaining) * share))
+	}
+	return p
+}
+
+// context returns the context a stage runs with, carrying the stage's span.
+func (p *stagePlan) context(ctx context.Context, stage domain.PipelineStage) (context.Context, context.CancelFunc) {
+	ctx, _ = tracing.Start(ctx, "recommend."+string(stage))
+	return p.bound(ctx, stage)
+}
+
+// bound returns ctx limited to the stage's deadline, without starting a span.
+func (p *stagePlan) bound(ctx context.Context, stage domain.PipelineStage) (context.Context, context.CancelFunc) {
+	if deadline, ok := p.deadlines[stage]; ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// finish ends the stage's span and records the stage as cut short
+// if its context ran out before it returned.
+func (p *stagePlan) finish(ctx context.Context, stage domain.PipelineStage) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	if ctx.Err() == nil {
+		return
+	}
+	span.SetAttributes(attribute.Bool("tracktaste.partial", true))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.partial = append(p.partial, stage)
+}
+
+// partialStages returns the stages that were cut short, in pipeline order.
+func (p *stagePlan) partialStages() []domain.PipelineStage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.PipelineStage(nil), p.partial...)
+}
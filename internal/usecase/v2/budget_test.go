@@ -0,0 +1,71 @@
+package v2
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestNewStagePlan(t *testing.T) {
+	now := time.Unix(1000, 0)
+	budget := StageBudget{Collect: 0.4, Enrich: 0.4, Filter: 0.1, Score: 0.1}
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(10*time.Second))
+	defer cancel()
+	plan := newStagePlan(ctx, budget, now)
+
+	want := map[domain.PipelineStage]time.Duration{
+		domain.StageCollect: 4 * time.Second,
+		domain.StageEnrich:  8 * time.Second,
+		domain.StageFilter:  9 * time.Second,
+		domain.StageScore:   10 * time.Second,
+	}
+	for stage, d := range want {
+		if got := plan.deadlines[stage].Sub(now); got != d {
+			t.Errorf("%s deadline = +%v, want +%v", stage, got, d)
+		}
+	}
+}
+
+func TestNewStagePlan_NoDeadline(t *testing.T) {
+	plan := newStagePlan(context.Background(), DefaultStageBudget(), time.Now())
+
+	ctx, cancel := plan.context(context.Background(), domain.StageCollect)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("stage should have no deadline when the request has none")
+	}
+}
+
+func TestStagePlan_Bound(t *testing.T) {
+	now := time.Now()
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(10*time.Second))
+	defer cancel()
+	plan := newStagePlan(ctx, StageBudget{Collect: 0.3, Enrich: 0.5}, now)
+
+	bounded, cancelBound := plan.bound(ctx, domain.StageEnrich)
+	defer cancelBound()
+	deadline, ok := bounded.Deadline()
+	if !ok || deadline.Sub(now) != 8*time.Second {
+		t.Errorf("enrich deadline = +%v (set %v), want +8s", deadline.Sub(now), ok)
+	}
+}
+
+func TestStagePlan_Finish(t *testing.T) {
+	plan := newStagePlan(context.Background(), DefaultStageBudget(), time.Now())
+
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	plan.finish(context.Background(), domain.StageCollect)
+	plan.finish(done, domain.StageEnrich)
+	plan.finish(context.Background(), domain.StageFilter)
+	plan.finish(done, domain.StageScore)
+
+	want := []domain.PipelineStage{domain.StageEnrich, domain.StageScore}
+	if got := plan.partialStages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("partialStages() = %v, want %v", got, want)
+	}
+}
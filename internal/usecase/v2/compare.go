@@ -0,0 +1,125 @@
+package v2
+
+import (
+	"context"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+// compareModes are the modes Compare scores, in result order.
+var compareModes = []domain.RecommendMode{
+	domain.RecommendModeBalanced,
+	domain.RecommendModeSimilar,
+	domain.RecommendModeRelated,
+}
+
+// comparedSide is one track of a comparison with its artist relations.
+type comparedSide struct {
+	domain.ComparedTrack
+	artist *domain.ArtistInfo
+}
+
+// Compare explains how track B scores as a recommendation for seed track A.
+// Both tracks' features are looked up as the seed's are, so B's MusicBrainz tags and
+// artist relations are included even when the recommender has not stored them yet.
+func (uc *RecommendUseCase) Compare(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error) {
+	return uc.snapshot().compare(ctx, trackIDA, trackIDB)
+}
+
+func (uc *RecommendUseCase) compare(ctx context.Context, trackIDA, trackIDB string) (*domain.TrackComparison, error) {
+	if trackIDA == "" || trackIDB == "" {
+		return nil, domain.ErrTrackNotFound
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.opts.Timeout)
+		defer cancel()
+	}
+
+	var a, b *comparedSide
+	var errA, errB error
+	g := safego.NewGroup("CompareV2")
+	g.Go("A", func() { a, errA = uc.compareSide(ctx, trackIDA) })
+	g.Go("B", func() { b, errB = uc.compareSide(ctx, trackIDB) })
+	g.Wait()
+	if errA != nil {
+		return nil, errA
+	}
+	if errB != nil {
+		return nil, errB
+	}
+
+	result := &domain.TrackComparison{A: a.ComparedTrack, B: b.ComparedTrack}
+
+	// Genre filter, as filterByGenre applies it before scoring
+	result.GenreFilterBonus = uc.genreMatcher.CalculateBonus(a.Genres, b.Features.Tags)
+	result.PassesGenreFilter = len(a.Genres) == 0 || result.GenreFilterBonus >= 1.0
+
+	sameArtistBonus := 1.0
+	if newArtistSet(&a.Track).matches(&b.Track) {
+		result.SameArtist = true
+		sameArtistBonus = sameArtistMultiplier
+	}
+	seriesBonus := 1.0
+	if result.Series = matchSeries(a.Track.Name, b.Track.Name); result.Series != "" {
+		seriesBonus = seriesMultiplier
+	}
+
+	for _, mode := range compareModes {
+		calc := NewSimilarityCalculator(uc.opts.weightsFor(mode), uc.genreMatcher)
+		baseSim, genreBonus, artistBonus, _ := calc.CalculateWithBonus(a.Features, b.Features, a.artist, b.artist)
+		result.Scores = append(result.Scores, domain.ModeScore{
+			Mode:            mode,
+			Components:      calc.Components(a.Features, b.Features),
+			BaseSimilarity:  baseSim,
+			GenreBonus:      genreBonus,
+			ArtistBonus:     artistBonus,
+			SameArtistBonus: sameArtistBonus,
+			SeriesBonus:     seriesBonus,
+			FinalScore:      baseSim * genreBonus * artistBonus * sameArtistBonus * seriesBonus,
+		})
+	}
+
+	// Match reasons do not depend on the weights, so the balanced ones stand for all modes
+	balanced := result.Scores[0]
+	reasons := uc.calculator.MatchReasons(a.Features, b.Features)
+	if balanced.GenreBonus > 1.0 && uc.genreMatcher.IsGenreMatch(a.Genres, b.Features.Tags) {
+		reasons = append(reasons, "genre_match")
+	}
+	if balanced.ArtistBonus > 1.0 {
+		reasons = append(reasons, "artist_relation")
+	}
+	if result.SameArtist {
+		reasons = append(reasons, "same_artist")
+	}
+	if result.Series != "" {
+		reasons = append(reasons, "same_series:"+result.Series)
+	}
+	result.MatchReasons = reasons
+
+	return result, nil
+}
+
+// compareSide looks up a track and its features the way the seed of a recommendation is.
+func (uc *RecommendUseCase) compareSide(ctx context.Context, trackID string) (*comparedSide, error) {
+	track, err := uc.spotifyAPI.GetTrackByID(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *domain.StoredFeatures
+	if isrc := trackISRC(track); isrc != "" {
+		stored = loadStoredFeatures(ctx, uc.featureStore, "RecommendV2", []string{isrc})[isrc]
+	}
+	features, artist := uc.getSeedFeatures(ctx, track, stored)
+	genres := uc.getArtistGenres(ctx, track, stored)
+	if len(genres) > 0 {
+		features.Tags = uc.mergeTags(features.Tags, genres)
+	}
+
+	return &comparedSide{
+		ComparedTrack: domain.ComparedTrack{Track: *track, Features: features, Genres: genres},
+		artist:        artist,
+	}, nil
+}
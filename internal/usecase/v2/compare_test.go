@@ -0,0 +1,104 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestRecommendUseCase_Compare(t *testing.T) {
+	isrcA, isrcB, isrcC := "JPA000000001", "JPA000000002", "KRA000000003"
+	spotify := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			"a": {ID: "a", Name: "ラブライブ! Song A", ISRC: &isrcA, Artists: []domain.Artist{{ID: "art1", Name: "Aqours"}}},
+			"b": {ID: "b", Name: "ラブライブ! Song B", ISRC: &isrcB, Artists: []domain.Artist{{ID: "art1", Name: "Aqours"}}},
+			"c": {ID: "c", Name: "Other", ISRC: &isrcC, Artists: []domain.Artist{{ID: "art2", Name: "K Group"}}},
+		},
+		artists: map[string][]string{"art1": {"anime"}, "art2": {"k-pop"}},
+	}
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		isrcA: {BPM: 170, DurationSeconds: 250, Gain: -8},
+		isrcB: {BPM: 160, DurationSeconds: 260, Gain: -7},
+		isrcC: {BPM: 100, DurationSeconds: 200, Gain: -5},
+	}}
+	uc := NewRecommendUseCase(spotify, &mockKKBOXAPI{}, deezer, &mockMusicBrainzAPI{})
+
+	tests := []struct {
+		name           string
+		trackA         string
+		trackB         string
+		wantSameArtist bool
+		wantSeries     string
+		wantPasses     bool
+		wantReasons    []string
+		wantErr        error
+	}{
+		{
+			name:           "正常系: 同じアーティスト・同じシリーズ",
+			trackA:         "a",
+			trackB:         "b",
+			wantSameArtist: true,
+			wantSeries:     "Love Live",
+			wantPasses:     true,
+			wantReasons:    []string{"similar_bpm", "similar_duration", "similar_loudness", "same_tag:anime", "genre_match", "same_artist", "same_series:Love Live"},
+		},
+		{
+			name:        "正常系: 関連しないジャンルはジャンルフィルタで除外される",
+			trackA:      "a",
+			trackB:      "c",
+			wantPasses:  false,
+			wantReasons: []string{"similar_loudness"},
+		},
+		{
+			name:    "異常系: 曲が見つからない",
+			trackA:  "a",
+			trackB:  "missing",
+			wantErr: domain.ErrTrackNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.Compare(context.Background(), tt.trackA, tt.trackB)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.SameArtist != tt.wantSameArtist || got.Series != tt.wantSeries || got.PassesGenreFilter != tt.wantPasses {
+				t.Errorf("same_artist=%v series=%q passes=%v", got.SameArtist, got.Series, got.PassesGenreFilter)
+			}
+			if !reflect.DeepEqual(got.MatchReasons, tt.wantReasons) {
+				t.Errorf("expected reasons %v, got %v", tt.wantReasons, got.MatchReasons)
+			}
+			if len(got.Scores) != len(compareModes) {
+				t.Fatalf("expected %d scores, got %d", len(compareModes), len(got.Scores))
+			}
+			for i, s := range got.Scores {
+				if s.Mode != compareModes[i] {
+					t.Errorf("score %d: expected mode %s, got %s", i, compareModes[i], s.Mode)
+				}
+				if len(s.Components) != 4 {
+					t.Errorf("%s: expected 4 components, got %v", s.Mode, s.Components)
+				}
+				want := s.BaseSimilarity * s.GenreBonus * s.ArtistBonus * s.SameArtistBonus * s.SeriesBonus
+				if math.Abs(s.FinalScore-want) > 1e-9 {
+					t.Errorf("%s: final score %f is not the product of its parts %f", s.Mode, s.FinalScore, want)
+				}
+			}
+			// Similar mode weighs BPM more than related mode does
+			if got.Scores[1].Components[0].Weight <= got.Scores[2].Components[0].Weight {
+				t.Errorf("expected mode weights to differ: %v / %v", got.Scores[1].Components, got.Scores[2].Components)
+			}
+		})
+	}
+}
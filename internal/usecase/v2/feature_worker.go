@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+)
+
+const (
+	featureWorkerQueueSize   = 1000
+	featureWorkerItemTimeout = 30 * time.Second
+)
+
+// FeatureWorker fills in missing MusicBrainz tags for candidate tracks in the background.
+// Candidates are enriched without MusicBrainz during a request (it is limited to 1 req/s),
+// so the worker fetches their recordings afterwards and writes them to the feature store.
+// Later requests then read the tags from the store.
+type FeatureWorker struct {
+	musicBrainzAPI external.MusicBrainzAPI
+	store          repository.FeatureStore
+	policy         StalenessPolicy
+	queue          chan string
+	pending        map[string]struct{}
+	mu             sync.Mutex // Guards pending and policy
+	now            func() time.Time
+}
+
+// NewFeatureWorker creates a new FeatureWorker.
+func NewFeatureWorker(
+	musicBrainzAPI external.MusicBrainzAPI,
+	store repository.FeatureStore,
+	policy StalenessPolicy,
+) *FeatureWorker {
+	return &FeatureWorker{
+		musicBrainzAPI: musicBrainzAPI,
+		store:          store,
+		policy:         policy,
+		queue:          make(chan string, featureWorkerQueueSize),
+		pending:        make(map[string]struct{}),
+		now:            time.Now,
+	}
+}
+
+// SetPolicy replaces the staleness policy at runtime.
+func (w *FeatureWorker) SetPolicy(policy StalenessPolicy) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.policy = policy
+}
+
+// Enqueue schedules ISRCs for a MusicBrainz lookup.
+// ISRCs already queued are ignored, and new ones are dropped when the queue is full.
+func (w *FeatureWorker) Enqueue(isrcs ...string) {
+	for _, isrc := range isrcs {
+		if isrc == "" {
+			continue
+		}
+
+		w.mu.Lock()
+		if _, ok := w.pending[isrc]; ok {
+			w.mu.Unlock()
+			continue
+		}
+		w.pending[isrc] = struct{}{}
+		w.mu.Unlock()
+
+		select {
+		case w.queue <- isrc:
+		default:
+			w.done(isrc)
+			logger.Debug("FeatureWorker", "キューが満杯のためスキップ: "+isrc)
+		}
+	}
+}
+
+// Run processes queued ISRCs until ctx is cancelled.
+func (w *FeatureWorker) Run(ctx context.Context) {
+	logger.InfoContext(ctx, "FeatureWorker", "バックグラウンドワーカー開始")
+	for {
+		select {
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "FeatureWorker", "バックグラウンドワーカー停止")
+			return
+		case isrc := <-w.queue:
+			// A panic on one ISRC must not stop the worker
+			_ = safego.Run("FeatureWorker", isrc, func() error {
+				w.process(ctx, isrc)
+				return nil
+			})
+			w.done(isrc)
+		}
+	}
+}
+
+// process fetches the MusicBrainz recording for an ISRC unless the store already has fresh tags.
+func (w *FeatureWorker) process(ctx context.Context, isrc string) {
+	ctx, cancel := context.WithTimeout(ctx, featureWorkerItemTimeout)
+	defer cancel()
+	ctx = scheduler.WithFlow(scheduler.WithPriority(ctx, scheduler.PriorityBackground), "feature-worker")
+
+	w.mu.Lock()
+	policy := w.policy
+	w.mu.Unlock()
+
+	stored, err := w.store.GetFeatures(ctx, isrc)
+	if err == nil && !policy.IsStale(domain.FeatureSourceMusicBrainz, musicBrainzMeta(stored), w.now()) {
+		return
+	}
+
+	recording, err := w.musicBrainzAPI.GetRecordingByISRC(ctx, isrc)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		// Transient failure: leave the group untouched so a later request re-enqueues it
+		logger.WarningContext(ctx, "FeatureWorker", fmt.Sprintf("MusicBrainz取得エラー (%s): %v", isrc, err))
+		return
+	}
+
+	features := &domain.StoredFeatures{
+		ISRC:        isrc,
+		MusicBrainz: newMusicBrainzGroup(recording, w.now()),
+	}
+	if err := w.store.SaveFeatures(ctx, features); err != nil {
+		logger.WarningContext(ctx, "FeatureWorker", "特徴量ストア書き込みエラー: "+err.Error())
+	}
+}
+
+// done removes an ISRC from the pending set.
+func (w *FeatureWorker) done(isrc string) {
+	w.mu.Lock()
+	delete(w.pending, isrc)
+	w.mu.Unlock()
+}
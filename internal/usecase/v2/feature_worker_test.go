@@ -0,0 +1,142 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+)
+
+// errMusicBrainzAPI always fails with a transient error
+type errMusicBrainzAPI struct {
+	mockMusicBrainzAPI
+}
+
+func (m *errMusicBrainzAPI) GetRecordingByISRC(ctx context.Context, isrc string) (*domain.MBRecording, error) {
+	return nil, errors.New("service unavailable")
+}
+
+func TestFeatureWorker_Process(t *testing.T) {
+	isrc := "JPXX00000100"
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		mb        external.MusicBrainzAPI
+		stored    *domain.StoredFeatures
+		wantSaved bool
+		wantFound bool
+		wantTags  []string
+	}{
+		{
+			name: "正常系: タグを保存",
+			mb: &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{
+				isrc: {MBID: "rec", ArtistMBID: "artist", Tags: []domain.MBTag{{Name: "rock"}}},
+			}},
+			wantSaved: true,
+			wantFound: true,
+			wantTags:  []string{"rock"},
+		},
+		{
+			name:      "正常系: 見つからない場合はネガティブ結果を保存",
+			mb:        &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{}},
+			wantSaved: true,
+			wantFound: false,
+		},
+		{
+			name:      "異常系: 一時的なエラーは保存しない",
+			mb:        &errMusicBrainzAPI{},
+			wantSaved: false,
+		},
+		{
+			name: "正常系: 新しいタグがあればスキップ",
+			mb:   &errMusicBrainzAPI{},
+			stored: &domain.StoredFeatures{
+				ISRC: isrc,
+				MusicBrainz: &domain.MusicBrainzFeatureGroup{
+					FeatureGroupMeta: domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now, Found: true},
+					Tags:             []string{"cached"},
+				},
+			},
+			wantSaved: true,
+			wantFound: true,
+			wantTags:  []string{"cached"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockFeatureStore()
+			if tt.stored != nil {
+				store.features[isrc] = tt.stored
+			}
+
+			worker := NewFeatureWorker(tt.mb, store, DefaultStalenessPolicy())
+			worker.process(context.Background(), isrc)
+
+			saved := store.features[isrc]
+			if (saved != nil && saved.MusicBrainz != nil) != tt.wantSaved {
+				t.Fatalf("saved = %+v, wantSaved %v", saved, tt.wantSaved)
+			}
+			if !tt.wantSaved {
+				return
+			}
+			if saved.MusicBrainz.Found != tt.wantFound {
+				t.Errorf("Found = %v, want %v", saved.MusicBrainz.Found, tt.wantFound)
+			}
+			if len(saved.MusicBrainz.Tags) != len(tt.wantTags) {
+				t.Errorf("Tags = %v, want %v", saved.MusicBrainz.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestFeatureWorker_Enqueue_Dedup(t *testing.T) {
+	worker := NewFeatureWorker(&mockMusicBrainzAPI{}, newMockFeatureStore(), DefaultStalenessPolicy())
+
+	worker.Enqueue("A", "B", "A", "")
+	worker.Enqueue("B")
+
+	if got := len(worker.queue); got != 2 {
+		t.Errorf("queue length = %d, want 2", got)
+	}
+}
+
+func TestFeatureWorker_Run(t *testing.T) {
+	isrc := "JPXX00000200"
+	store := newMockFeatureStore()
+	mb := &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{
+		isrc: {MBID: "rec", Tags: []domain.MBTag{{Name: "pop"}}},
+	}}
+	worker := NewFeatureWorker(mb, store, DefaultStalenessPolicy())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	worker.Enqueue(isrc)
+	deadline := time.After(time.Second)
+	for {
+		if f, _ := store.GetFeatures(ctx, isrc); f != nil && f.MusicBrainz != nil {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("worker did not save features in time")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+}
@@ -0,0 +1,156 @@
+package v2
+
+import (
+	"context"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// Current schema versions of each feature group.
+// Bump a version when the way a group is derived changes; stored groups
+// with an older version are treated as stale and refetched.
+const (
+	deezerFeatureVersion        = 1
+	musicBrainzFeatureVersion   = 1
+	spotifyGenresFeatureVersion = 1
+)
+
+// StalenessPolicy decides when a stored feature group must be refetched.
+type StalenessPolicy struct {
+	DeezerTTL        time.Duration // BPM/duration/gain rarely change
+	MusicBrainzTTL   time.Duration // Community tags evolve over time
+	SpotifyGenresTTL time.Duration // Artist genres are curated by Spotify
+	NegativeTTL      time.Duration // How long a "not found" result is trusted
+}
+
+// DefaultStalenessPolicy returns the default staleness rules.
+func DefaultStalenessPolicy() StalenessPolicy {
+	return StalenessPolicy{
+		DeezerTTL:        30 * 24 * time.Hour,
+		MusicBrainzTTL:   7 * 24 * time.Hour,
+		SpotifyGenresTTL: 7 * 24 * time.Hour,
+		NegativeTTL:      24 * time.Hour,
+	}
+}
+
+// IsStale reports whether a feature group from source must be refetched.
+// A nil meta (never fetched) is always stale.
+func (p StalenessPolicy) IsStale(source domain.FeatureSource, meta *domain.FeatureGroupMeta, now time.Time) bool {
+	if meta == nil {
+		return true
+	}
+
+	var ttl time.Duration
+	var version int
+	switch source {
+	case domain.FeatureSourceDeezer:
+		ttl, version = p.DeezerTTL, deezerFeatureVersion
+	case domain.FeatureSourceMusicBrainz:
+		ttl, version = p.MusicBrainzTTL, musicBrainzFeatureVersion
+	case domain.FeatureSourceSpotifyGenres:
+		ttl, version = p.SpotifyGenresTTL, spotifyGenresFeatureVersion
+	default:
+		return true
+	}
+
+	if meta.Version < version {
+		return true
+	}
+	if !meta.Found {
+		ttl = p.NegativeTTL
+	}
+	return now.Sub(meta.FetchedAt) > ttl
+}
+
+// deezerMeta returns the group meta of a stored Deezer group, or nil.
+func deezerMeta(f *domain.StoredFeatures) *domain.FeatureGroupMeta {
+	if f == nil || f.Deezer == nil {
+		return nil
+	}
+	return &f.Deezer.FeatureGroupMeta
+}
+
+// musicBrainzMeta returns the group meta of a stored MusicBrainz group, or nil.
+func musicBrainzMeta(f *domain.StoredFeatures) *domain.FeatureGroupMeta {
+	if f == nil || f.MusicBrainz == nil {
+		return nil
+	}
+	return &f.MusicBrainz.FeatureGroupMeta
+}
+
+// spotifyGenresMeta returns the group meta of stored Spotify genres, or nil.
+func spotifyGenresMeta(f *domain.StoredFeatures) *domain.FeatureGroupMeta {
+	if f == nil || f.SpotifyGenres == nil {
+		return nil
+	}
+	return &f.SpotifyGenres.FeatureGroupMeta
+}
+
+// newDeezerGroup builds a Deezer feature group from an upstream result (nil = not found).
+func newDeezerGroup(dt *domain.DeezerTrack, now time.Time) *domain.DeezerFeatureGroup {
+	group := &domain.DeezerFeatureGroup{
+		FeatureGroupMeta: domain.FeatureGroupMeta{Version: deezerFeatureVersion, FetchedAt: now},
+	}
+	if dt != nil {
+		group.Found = true
+		group.BPM = dt.BPM
+		group.DurationSeconds = dt.DurationSeconds
+		group.Gain = dt.Gain
+	}
+	return group
+}
+
+// newMusicBrainzGroup builds a MusicBrainz feature group from an upstream result (nil = not found).
+func newMusicBrainzGroup(rec *domain.MBRecording, now time.Time) *domain.MusicBrainzFeatureGroup {
+	group := &domain.MusicBrainzFeatureGroup{
+		FeatureGroupMeta: domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now},
+	}
+	if rec != nil {
+		group.Found = true
+		group.ArtistMBID = rec.ArtistMBID
+		group.Tags = make([]string, len(rec.Tags))
+		for i, tag := range rec.Tags {
+			group.Tags[i] = tag.Name
+		}
+	}
+	return group
+}
+
+// newSpotifyGenresGroup builds a Spotify genres feature group.
+func newSpotifyGenresGroup(genres []string, now time.Time) *domain.SpotifyGenresFeatureGroup {
+	return &domain.SpotifyGenresFeatureGroup{
+		FeatureGroupMeta: domain.FeatureGroupMeta{
+			Version:   spotifyGenresFeatureVersion,
+			FetchedAt: now,
+			Found:     len(genres) > 0,
+		},
+		Genres: genres,
+	}
+}
+
+// loadStoredFeatures reads features for the given ISRCs from store, logging failures under component.
+// Returns an empty map when no store is configured or the read fails.
+func loadStoredFeatures(ctx context.Context, store repository.FeatureStore, component string, isrcs []string) map[string]*domain.StoredFeatures {
+	if store == nil || len(isrcs) == 0 {
+		return map[string]*domain.StoredFeatures{}
+	}
+	stored, err := store.GetFeaturesBatch(ctx, isrcs)
+	if err != nil {
+		logger.WarningContext(ctx, component, "特徴量ストア読み込みエラー: "+err.Error())
+		return map[string]*domain.StoredFeatures{}
+	}
+	return stored
+}
+
+// saveStoredFeatures writes features to store (best effort), logging failures under component.
+func saveStoredFeatures(ctx context.Context, store repository.FeatureStore, component string, features *domain.StoredFeatures) {
+	if store == nil || features == nil {
+		return
+	}
+	if err := store.SaveFeatures(ctx, features); err != nil {
+		logger.WarningContext(ctx, component, "特徴量ストア書き込みエラー: "+err.Error())
+	}
+}
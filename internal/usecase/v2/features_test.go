@@ -0,0 +1,263 @@
+package v2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// mockFeatureStore is an in-memory FeatureStore for testing
+type mockFeatureStore struct {
+	features map[string]*domain.StoredFeatures
+	mu       sync.Mutex
+}
+
+func newMockFeatureStore() *mockFeatureStore {
+	return &mockFeatureStore{features: make(map[string]*domain.StoredFeatures)}
+}
+
+func (m *mockFeatureStore) GetFeatures(ctx context.Context, isrc string) (*domain.StoredFeatures, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.features[isrc]; ok {
+		c := *f
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m *mockFeatureStore) GetFeaturesBatch(ctx context.Context, isrcs []string) (map[string]*domain.StoredFeatures, error) {
+	result := make(map[string]*domain.StoredFeatures)
+	for _, isrc := range isrcs {
+		if f, _ := m.GetFeatures(ctx, isrc); f != nil {
+			result[isrc] = f
+		}
+	}
+	return result, nil
+}
+
+func (m *mockFeatureStore) SaveFeatures(ctx context.Context, features *domain.StoredFeatures) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.features[features.ISRC]
+	if !ok {
+		existing = &domain.StoredFeatures{ISRC: features.ISRC}
+		m.features[features.ISRC] = existing
+	}
+	existing.Merge(features)
+	return nil
+}
+
+func TestStalenessPolicy_IsStale(t *testing.T) {
+	policy := DefaultStalenessPolicy()
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		source domain.FeatureSource
+		meta   *domain.FeatureGroupMeta
+		want   bool
+	}{
+		{
+			name:   "未取得",
+			source: domain.FeatureSourceDeezer,
+			meta:   nil,
+			want:   true,
+		},
+		{
+			name:   "Deezer: TTL内",
+			source: domain.FeatureSourceDeezer,
+			meta:   &domain.FeatureGroupMeta{Version: deezerFeatureVersion, FetchedAt: now.Add(-20 * 24 * time.Hour), Found: true},
+			want:   false,
+		},
+		{
+			name:   "MusicBrainz: TTL切れ",
+			source: domain.FeatureSourceMusicBrainz,
+			meta:   &domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now.Add(-8 * 24 * time.Hour), Found: true},
+			want:   true,
+		},
+		{
+			name:   "Spotifyジャンル: TTL内",
+			source: domain.FeatureSourceSpotifyGenres,
+			meta:   &domain.FeatureGroupMeta{Version: spotifyGenresFeatureVersion, FetchedAt: now.Add(-time.Hour), Found: true},
+			want:   false,
+		},
+		{
+			name:   "古いバージョン",
+			source: domain.FeatureSourceDeezer,
+			meta:   &domain.FeatureGroupMeta{Version: deezerFeatureVersion - 1, FetchedAt: now, Found: true},
+			want:   true,
+		},
+		{
+			name:   "ネガティブ結果: TTL内",
+			source: domain.FeatureSourceMusicBrainz,
+			meta:   &domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now.Add(-time.Hour)},
+			want:   false,
+		},
+		{
+			name:   "ネガティブ結果: TTL切れ",
+			source: domain.FeatureSourceDeezer,
+			meta:   &domain.FeatureGroupMeta{Version: deezerFeatureVersion, FetchedAt: now.Add(-25 * time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "不明なソース",
+			source: domain.FeatureSource("unknown"),
+			meta:   &domain.FeatureGroupMeta{Version: 1, FetchedAt: now, Found: true},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.IsStale(tt.source, tt.meta, now); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendUseCase_GetSeedFeatures_UsesStore(t *testing.T) {
+	isrc := "JPXX00000001"
+	track := &domain.Track{ID: "seed", ISRC: &isrc}
+	now := time.Now()
+
+	store := newMockFeatureStore()
+	store.features[isrc] = &domain.StoredFeatures{
+		ISRC: isrc,
+		Deezer: &domain.DeezerFeatureGroup{
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: deezerFeatureVersion, FetchedAt: now, Found: true},
+			BPM:              140,
+		},
+		MusicBrainz: &domain.MusicBrainzFeatureGroup{
+			// Stale: must be refetched
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now.Add(-30 * 24 * time.Hour), Found: true},
+			Tags:             []string{"old-tag"},
+		},
+	}
+
+	// Deezer has no data, so BPM can only come from the store
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}}
+	mb := &mockMusicBrainzAPI{
+		recordings: map[string]*domain.MBRecording{
+			isrc: {MBID: "rec-1", Tags: []domain.MBTag{{Name: "j-pop", Count: 3}}},
+		},
+	}
+	uc := NewRecommendUseCase(&mockSpotifyAPI{}, &mockKKBOXAPI{}, deezer, mb).WithFeatureStore(store, nil)
+
+	features, _ := uc.getSeedFeatures(context.Background(), track, store.features[isrc])
+
+	if features.BPM != 140 {
+		t.Errorf("BPM = %v, want 140 from store", features.BPM)
+	}
+	if len(features.Tags) != 1 || features.Tags[0] != "j-pop" {
+		t.Errorf("Tags = %v, want refetched [j-pop]", features.Tags)
+	}
+	if saved := store.features[isrc].MusicBrainz; saved == nil || saved.Tags[0] != "j-pop" {
+		t.Errorf("refetched MusicBrainz group not saved: %+v", saved)
+	}
+}
+
+func TestRecommendUseCase_GetSeedFeatures_SavesNegativeResult(t *testing.T) {
+	isrc := "JPXX00000002"
+	track := &domain.Track{ID: "seed", ISRC: &isrc}
+
+	store := newMockFeatureStore()
+	uc := NewRecommendUseCase(
+		&mockSpotifyAPI{}, &mockKKBOXAPI{},
+		&mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}},
+		&mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{}},
+	).WithFeatureStore(store, nil)
+
+	uc.getSeedFeatures(context.Background(), track, nil)
+
+	saved := store.features[isrc]
+	if saved == nil || saved.Deezer == nil || saved.MusicBrainz == nil {
+		t.Fatalf("negative results not saved: %+v", saved)
+	}
+	if saved.Deezer.Found || saved.MusicBrainz.Found {
+		t.Error("negative results should be saved with Found = false")
+	}
+}
+
+func TestRecommendUseCase_GetDeezerFeatures_SavesNegativeResult(t *testing.T) {
+	found := "JPXX00000003"
+	missing := "JPXX00000004"
+
+	store := newMockFeatureStore()
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		found: {ISRC: found, BPM: 128},
+	}}
+	uc := NewRecommendUseCase(&mockSpotifyAPI{}, &mockKKBOXAPI{}, deezer, &mockMusicBrainzAPI{}).WithFeatureStore(store, nil)
+
+	features := uc.getDeezerFeatures(context.Background(), []string{found, missing}, nil)
+	if features[found] == nil || features[found].BPM != 128 {
+		t.Errorf("features[%s] = %+v, want BPM 128", found, features[found])
+	}
+	if _, ok := features[missing]; ok {
+		t.Errorf("features[%s] should be absent", missing)
+	}
+
+	saved := store.features[missing]
+	if saved == nil || saved.Deezer == nil || saved.Deezer.Found {
+		t.Fatalf("negative result not saved: %+v", saved)
+	}
+
+	// The negative entry is fresh, so the next lookup must not hit Deezer again
+	deezer.tracks[missing] = &domain.DeezerTrack{ISRC: missing, BPM: 99}
+	stored, _ := store.GetFeaturesBatch(context.Background(), []string{found, missing})
+	features = uc.getDeezerFeatures(context.Background(), []string{found, missing}, stored)
+	if _, ok := features[missing]; ok {
+		t.Errorf("features[%s] should come from the negative entry, got %+v", missing, features[missing])
+	}
+}
+
+func TestRecommendUseCase_EnrichCandidatesParallel_UsesStoredTags(t *testing.T) {
+	isrc1 := "JPXX00000010"
+	isrc2 := "JPXX00000011"
+	now := time.Now()
+
+	store := newMockFeatureStore()
+	store.features[isrc1] = &domain.StoredFeatures{
+		ISRC: isrc1,
+		MusicBrainz: &domain.MusicBrainzFeatureGroup{
+			FeatureGroupMeta: domain.FeatureGroupMeta{Version: musicBrainzFeatureVersion, FetchedAt: now, Found: true},
+			Tags:             []string{"anime"},
+		},
+	}
+
+	spotify := &mockSpotifyAPI{
+		tracksByISRC: map[string]*domain.Track{
+			isrc1: {ID: "t1", ISRC: &isrc1, Artists: []domain.Artist{{ID: "a1"}}},
+			isrc2: {ID: "t2", ISRC: &isrc2, Artists: []domain.Artist{{ID: "a2"}}},
+		},
+		artists: map[string][]string{"a1": {"j-pop"}, "a2": {"j-rock"}},
+	}
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		isrc1: {ISRC: isrc1, BPM: 120},
+		isrc2: {ISRC: isrc2, BPM: 130},
+	}}
+	worker := NewFeatureWorker(&mockMusicBrainzAPI{}, store, DefaultStalenessPolicy())
+	uc := NewRecommendUseCase(spotify, &mockKKBOXAPI{}, deezer, &mockMusicBrainzAPI{}).WithFeatureStore(store, worker)
+
+	candidates := []domain.Track{{ID: "k1", ISRC: &isrc1}, {ID: "k2", ISRC: &isrc2}}
+	_, features := uc.enrichCandidatesParallel(context.Background(), candidates)
+
+	if got := features["t1"].Tags; len(got) != 2 || got[0] != "anime" || got[1] != "j-pop" {
+		t.Errorf("t1 Tags = %v, want [anime j-pop]", got)
+	}
+	if got := features["t2"].Tags; len(got) != 1 || got[0] != "j-rock" {
+		t.Errorf("t2 Tags = %v, want [j-rock]", got)
+	}
+	if store.features[isrc2].Deezer == nil || store.features[isrc2].Deezer.BPM != 130 {
+		t.Error("fetched Deezer features should be saved")
+	}
+
+	// Only the candidate without stored tags is queued for the worker
+	if len(worker.queue) != 1 || <-worker.queue != isrc2 {
+		t.Error("candidate without MusicBrainz tags should be enqueued")
+	}
+}
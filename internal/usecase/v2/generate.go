@@ -0,0 +1,389 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+const (
+	// MaxGenerateSeeds is the most seed tracks a generated playlist may have.
+	MaxGenerateSeeds = 5
+	// MaxGenerateDurationMs is the longest target duration of a generated playlist (6 hours).
+	MaxGenerateDurationMs = 6 * 60 * 60 * 1000
+)
+
+// DefaultPlaylistConstraints returns the constraints of a one-hour playlist.
+func DefaultPlaylistConstraints() domain.PlaylistConstraints {
+	return domain.PlaylistConstraints{
+		TargetDurationMs:    60 * 60 * 1000,
+		DurationToleranceMs: 3 * 60 * 1000,
+		MaxTracksPerArtist:  2,
+		MinGenreShare:       0.5,
+	}
+}
+
+// validatePlaylistConstraints checks the seeds and constraints of a generated playlist.
+func validatePlaylistConstraints(seedIDs []string, c domain.PlaylistConstraints) error {
+	switch {
+	case len(seedIDs) == 0:
+		return domain.NewInvalidInputError(domain.ErrCodeEmptyParam, "seeds is empty")
+	case len(seedIDs) > MaxGenerateSeeds:
+		return domain.NewTooManyTracksError(MaxGenerateSeeds)
+	case c.TargetDurationMs <= 0 || c.TargetDurationMs > MaxGenerateDurationMs:
+		return domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "duration_ms is out of range")
+	case c.DurationToleranceMs < 0:
+		return domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "tolerance_ms is negative")
+	case c.MaxTracksPerArtist < 1:
+		return domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "max_per_artist must be at least 1")
+	case c.MinGenreShare < 0 || c.MinGenreShare > 1:
+		return domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "min_genre_share must be between 0 and 1")
+	case c.MinBPM < 0 || c.MaxBPM < 0 || (c.MaxBPM > 0 && c.MinBPM > c.MaxBPM):
+		return domain.NewInvalidInputError(domain.ErrCodeInvalidParam, "invalid BPM range")
+	}
+	return nil
+}
+
+// GeneratePlaylist selects a playlist from the recommendations of up to MaxGenerateSeeds
+// seed tracks. Candidates of all seeds are pooled with their best score and selected
+// greedily by score under the constraints (see selectPlaylist), so a selection that
+// meets the target duration may be missed even when one exists.
+// When the target duration is missed, the selection is returned with
+// GeneratedPlaylist.Unmet explaining why.
+// A seed whose recommendations fail is skipped and listed in SkippedSeeds, and the
+// playlist is marked partial; an error is returned only when every seed fails.
+func (uc *RecommendUseCase) GeneratePlaylist(
+	ctx context.Context,
+	seedIDs []string,
+	mode domain.RecommendMode,
+	c domain.PlaylistConstraints,
+) (*domain.GeneratedPlaylist, error) {
+	if err := validatePlaylistConstraints(seedIDs, c); err != nil {
+		return nil, err
+	}
+	return uc.snapshot().generatePlaylist(ctx, seedIDs, mode, c)
+}
+
+func (uc *RecommendUseCase) generatePlaylist(
+	ctx context.Context,
+	seedIDs []string,
+	mode domain.RecommendMode,
+	c domain.PlaylistConstraints,
+) (*domain.GeneratedPlaylist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.opts.Timeout)
+		defer cancel()
+	}
+
+	// Each seed is ranked on its own copy, as rank replaces the calculator
+	results := make([]*domain.RecommendResult, len(seedIDs))
+	errs := make([]error, len(seedIDs))
+	g := safego.NewGroup("GenerateV2")
+	for i, id := range seedIDs {
+		run := *uc
+		g.Go("Seed", func() { results[i], errs[i] = run.rank(ctx, id, mode) })
+	}
+	g.Wait()
+
+	// A failed seed is skipped; the playlist is built from the others
+	playlist := &domain.GeneratedPlaylist{Mode: mode, Constraints: c}
+	ranked := results[:0]
+	for i, r := range results {
+		if errs[i] != nil {
+			logger.WarningContext(ctx, "GenerateV2", fmt.Sprintf("シード %s をスキップ: %v", seedIDs[i], errs[i]))
+			playlist.SkippedSeeds = append(playlist.SkippedSeeds, seedIDs[i])
+			continue
+		}
+		ranked = append(ranked, r)
+	}
+	if len(ranked) == 0 {
+		return nil, errs[0]
+	}
+	results = ranked
+	playlist.Partial = len(playlist.SkippedSeeds) > 0
+
+	seeds := make(map[string]bool, len(results))
+	for _, r := range results {
+		seeds[r.SeedTrack.ID] = true
+	}
+	var candidates []domain.RecommendedTrack
+	best := make(map[string]int)
+	degraded := make(map[string]bool)
+	stages := make(map[domain.PipelineStage]bool)
+	for _, r := range results {
+		playlist.Seeds = append(playlist.Seeds, r.SeedTrack)
+		playlist.SeedGenres = mergeTagLists(playlist.SeedGenres, r.SeedGenres)
+		for _, rt := range r.Items {
+			if seeds[rt.Track.ID] {
+				continue
+			}
+			if i, ok := best[rt.Track.ID]; ok {
+				if rt.FinalScore > candidates[i].FinalScore {
+					candidates[i] = rt
+				}
+				continue
+			}
+			best[rt.Track.ID] = len(candidates)
+			candidates = append(candidates, rt)
+		}
+		for _, source := range r.DegradedSources {
+			if !degraded[source] {
+				degraded[source] = true
+				playlist.DegradedSources = append(playlist.DegradedSources, source)
+			}
+		}
+		for _, stage := range r.PartialStages {
+			if !stages[stage] {
+				stages[stage] = true
+				playlist.PartialStages = append(playlist.PartialStages, stage)
+			}
+		}
+		playlist.Partial = playlist.Partial || r.Partial
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].FinalScore > candidates[j].FinalScore
+	})
+	playlist.Candidates = len(candidates)
+
+	group := uc.genreMatcher.Group(playlist.SeedGenres)
+	playlist.GenreGroup = string(group)
+	inGroup := func(rt *domain.RecommendedTrack) bool {
+		return group != usecase.GenreGroupOther && rt.Features != nil && uc.genreMatcher.Group(rt.Features.Tags) == group
+	}
+
+	var eligible []domain.RecommendedTrack
+	eligible, playlist.Excluded = eligibleCandidates(candidates, c)
+	sel := selectPlaylist(eligible, c, group != usecase.GenreGroupOther, inGroup)
+	playlist.Tracks = sel.tracks()
+	playlist.TotalDurationMs = sel.total
+	if len(playlist.Tracks) > 0 {
+		playlist.GenreShare = float64(sel.matching) / float64(len(playlist.Tracks))
+	}
+
+	if !sel.meetsDuration() {
+		playlist.Unmet = explainDuration(candidates, eligible, c, group, inGroup, sel.total)
+	}
+	return playlist, nil
+}
+
+// explainDuration lists why a selection of totalMs misses the target duration: the
+// duration itself, and every constraint without which the target would be reached.
+// When no constraint alone explains the miss although the eligible candidates are
+// long enough, it says so, as the constraints combined or the greedy selection are to blame.
+func explainDuration(
+	candidates, eligible []domain.RecommendedTrack,
+	c domain.PlaylistConstraints,
+	group usecase.GenreGroup,
+	inGroup func(*domain.RecommendedTrack) bool,
+	totalMs int,
+) []*domain.Error {
+	unmet := []*domain.Error{domain.NewDurationNotMetError(c, totalMs)}
+	applyShare := group != usecase.GenreGroupOther && c.MinGenreShare > 0
+
+	relaxed := c
+	relaxed.MaxTracksPerArtist = len(eligible)
+	if len(eligible) > 0 && selectPlaylist(eligible, relaxed, applyShare, inGroup).meetsDuration() {
+		unmet = append(unmet, domain.NewLimitedByArtistCapError(c.MaxTracksPerArtist))
+	}
+	if applyShare && selectPlaylist(eligible, c, false, inGroup).meetsDuration() {
+		unmet = append(unmet, domain.NewLimitedByGenreShareError(string(group), c.MinGenreShare))
+	}
+	if c.HasBPMRange() {
+		relaxed = c
+		relaxed.MinBPM, relaxed.MaxBPM = 0, 0
+		unbounded, _ := eligibleCandidates(candidates, relaxed)
+		if selectPlaylist(unbounded, c, applyShare, inGroup).meetsDuration() {
+			unmet = append(unmet, domain.NewLimitedByBPMRangeError(c))
+		}
+	}
+	if len(unmet) == 1 && poolDurationMs(eligible) >= c.TargetDurationMs-c.DurationToleranceMs {
+		unmet = append(unmet, domain.NewNoSingleCauseError())
+	}
+	return unmet
+}
+
+// poolDurationMs returns the total duration of tracks.
+func poolDurationMs(tracks []domain.RecommendedTrack) int {
+	total := 0
+	for _, rt := range tracks {
+		total += rt.Track.DurationMs
+	}
+	return total
+}
+
+// eligibleCandidates drops the candidates no playlist may contain, counting them by reason.
+func eligibleCandidates(candidates []domain.RecommendedTrack, c domain.PlaylistConstraints) ([]domain.RecommendedTrack, map[string]int) {
+	eligible := make([]domain.RecommendedTrack, 0, len(candidates))
+	excluded := make(map[string]int)
+	for _, rt := range candidates {
+		switch {
+		case rt.Track.Explicit:
+			excluded[domain.ExcludedExplicit]++
+		case rt.Track.DurationMs <= 0:
+			excluded[domain.ExcludedNoDuration]++
+		case c.HasBPMRange() && (rt.Features == nil || rt.Features.BPM <= 0):
+			excluded[domain.ExcludedUnknownBPM]++
+		case c.HasBPMRange() && ((c.MinBPM > 0 && rt.Features.BPM < c.MinBPM) || (c.MaxBPM > 0 && rt.Features.BPM > c.MaxBPM)):
+			excluded[domain.ExcludedBPMOutOfRange]++
+		default:
+			eligible = append(eligible, rt)
+		}
+	}
+	return eligible, excluded
+}
+
+// playlistSelection is a selection from a pool of eligible candidates, best first.
+type playlistSelection struct {
+	c          domain.PlaylistConstraints
+	applyShare bool
+	pool       []domain.RecommendedTrack
+	inGroup    []bool
+	chosen     []bool
+	artists    map[string]int
+	total      int // Duration of the chosen tracks (ms)
+	matching   int // Chosen tracks in the genre group
+	others     int
+}
+
+// selectPlaylist selects the best-scoring tracks of pool that fit the constraints.
+// Tracks are added greedily by score until the target duration is reached; when the
+// pool runs out first, chosen tracks are swapped for longer ones to close the gap.
+// The per-artist limit and, if applyShare, the genre share hold at every step.
+func selectPlaylist(
+	pool []domain.RecommendedTrack,
+	c domain.PlaylistConstraints,
+	applyShare bool,
+	inGroup func(*domain.RecommendedTrack) bool,
+) *playlistSelection {
+	s := &playlistSelection{
+		c:          c,
+		applyShare: applyShare && c.MinGenreShare > 0,
+		pool:       pool,
+		inGroup:    make([]bool, len(pool)),
+		chosen:     make([]bool, len(pool)),
+		artists:    make(map[string]int),
+	}
+	for i := range pool {
+		s.inGroup[i] = inGroup(&pool[i])
+	}
+
+	s.fill()
+	// Each swap lengthens the selection, so this ends
+	for s.total < c.TargetDurationMs-c.DurationToleranceMs && s.swapLonger() {
+		s.fill()
+	}
+	return s
+}
+
+// fill adds the best tracks that fit until the target is reached or none fits.
+// A track left out for the genre share may fit once more tracks of the group are in,
+// so the pool is passed over until nothing is added.
+func (s *playlistSelection) fill() {
+	for added := true; added && s.total < s.c.TargetDurationMs; {
+		added = false
+		for i := range s.pool {
+			if s.total >= s.c.TargetDurationMs {
+				return
+			}
+			if s.canAdd(i) {
+				s.add(i)
+				added = true
+			}
+		}
+	}
+}
+
+// swapLonger replaces the worst chosen track it can with the best longer track that fits.
+func (s *playlistSelection) swapLonger() bool {
+	for j := len(s.pool) - 1; j >= 0; j-- {
+		if !s.chosen[j] {
+			continue
+		}
+		s.remove(j)
+		for i := range s.pool {
+			if i != j && s.pool[i].Track.DurationMs > s.pool[j].Track.DurationMs && s.canAdd(i) {
+				s.add(i)
+				return true
+			}
+		}
+		s.add(j)
+	}
+	return false
+}
+
+func (s *playlistSelection) canAdd(i int) bool {
+	if s.chosen[i] || s.total+s.pool[i].Track.DurationMs > s.c.TargetDurationMs+s.c.DurationToleranceMs {
+		return false
+	}
+	for _, artist := range artistKeys(&s.pool[i].Track) {
+		if s.artists[artist] >= s.c.MaxTracksPerArtist {
+			return false
+		}
+	}
+	return s.inGroup[i] || s.shareHolds(s.matching, s.others+1)
+}
+
+// shareHolds reports whether matching of matching+others tracks meet the genre share.
+func (s *playlistSelection) shareHolds(matching, others int) bool {
+	if !s.applyShare {
+		return true
+	}
+	return float64(matching) >= s.c.MinGenreShare*float64(matching+others)-1e-9
+}
+
+func (s *playlistSelection) add(i int) {
+	s.update(i, 1)
+}
+
+func (s *playlistSelection) remove(i int) {
+	s.update(i, -1)
+}
+
+func (s *playlistSelection) update(i, delta int) {
+	s.chosen[i] = delta > 0
+	s.total += delta * s.pool[i].Track.DurationMs
+	for _, artist := range artistKeys(&s.pool[i].Track) {
+		s.artists[artist] += delta
+	}
+	if s.inGroup[i] {
+		s.matching += delta
+	} else {
+		s.others += delta
+	}
+}
+
+// meetsDuration reports whether the total duration is within the tolerance of the target.
+func (s *playlistSelection) meetsDuration() bool {
+	diff := s.total - s.c.TargetDurationMs
+	return diff >= -s.c.DurationToleranceMs && diff <= s.c.DurationToleranceMs
+}
+
+// tracks returns the chosen tracks, best first.
+func (s *playlistSelection) tracks() []domain.RecommendedTrack {
+	tracks := make([]domain.RecommendedTrack, 0, s.matching+s.others)
+	for i, rt := range s.pool {
+		if s.chosen[i] {
+			tracks = append(tracks, rt)
+		}
+	}
+	return tracks
+}
+
+// artistKeys identifies the artists of a track, by ID or else by name.
+func artistKeys(t *domain.Track) []string {
+	keys := make([]string, 0, len(t.Artists))
+	for _, a := range t.Artists {
+		if a.ID != "" {
+			keys = append(keys, a.ID)
+		} else {
+			keys = append(keys, a.Name)
+		}
+	}
+	return keys
+}
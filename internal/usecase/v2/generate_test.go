@@ -0,0 +1,301 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+)
+
+// generateCandidate is a candidate of minutes length by artist, in the otaku group if anime.
+func generateCandidate(id, artist string, minutes int, anime bool) domain.RecommendedTrack {
+	tags := []string{"rock"}
+	if anime {
+		tags = []string{"anime"}
+	}
+	return domain.RecommendedTrack{
+		Track: domain.Track{
+			ID:         id,
+			Artists:    []domain.Artist{{ID: artist, Name: artist}},
+			DurationMs: minutes * 60 * 1000,
+		},
+		Features: &domain.TrackFeatures{TrackID: id, BPM: 120, Tags: tags},
+	}
+}
+
+func isAnime(rt *domain.RecommendedTrack) bool {
+	return rt.Features != nil && rt.Features.Tags[0] == "anime"
+}
+
+func selectedIDs(s *playlistSelection) []string {
+	ids := []string{}
+	for _, rt := range s.tracks() {
+		ids = append(ids, rt.Track.ID)
+	}
+	return ids
+}
+
+func TestSelectPlaylist(t *testing.T) {
+	minutes := func(m int) int { return m * 60 * 1000 }
+
+	tests := []struct {
+		name         string
+		pool         []domain.RecommendedTrack
+		constraints  domain.PlaylistConstraints
+		want         []string
+		wantDuration bool
+	}{
+		{
+			name: "正常系: スコア順に目標の長さまで",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a", "x", 4, true),
+				generateCandidate("b", "y", 4, true),
+				generateCandidate("c", "z", 4, true),
+				generateCandidate("d", "w", 4, true),
+			},
+			constraints:  domain.PlaylistConstraints{TargetDurationMs: minutes(12), DurationToleranceMs: minutes(1), MaxTracksPerArtist: 2},
+			want:         []string{"a", "b", "c"},
+			wantDuration: true,
+		},
+		{
+			name: "正常系: アーティストごとの上限",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a", "x", 4, true),
+				generateCandidate("b", "x", 4, true),
+				generateCandidate("c", "z", 4, true),
+			},
+			constraints:  domain.PlaylistConstraints{TargetDurationMs: minutes(8), DurationToleranceMs: minutes(1), MaxTracksPerArtist: 1},
+			want:         []string{"a", "c"},
+			wantDuration: true,
+		},
+		{
+			name: "正常系: ジャンルグループの割合を保つ",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("rock1", "r1", 4, false),
+				generateCandidate("rock2", "r2", 4, false),
+				generateCandidate("anime1", "a1", 4, true),
+				generateCandidate("anime2", "a2", 4, true),
+			},
+			constraints:  domain.PlaylistConstraints{TargetDurationMs: minutes(12), DurationToleranceMs: minutes(1), MaxTracksPerArtist: 2, MinGenreShare: 0.6},
+			want:         []string{"rock1", "anime1", "anime2"},
+			wantDuration: true,
+		},
+		{
+			name: "正常系: 長い曲と入れ替えて目標に届ける",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("short", "x", 3, true),
+				generateCandidate("mid", "y", 4, true),
+				generateCandidate("long", "z", 7, true),
+			},
+			constraints:  domain.PlaylistConstraints{TargetDurationMs: minutes(11), DurationToleranceMs: 0, MaxTracksPerArtist: 2},
+			want:         []string{"mid", "long"},
+			wantDuration: true,
+		},
+		{
+			name: "異常系: 候補が足りない",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a", "x", 4, true),
+			},
+			constraints: domain.PlaylistConstraints{TargetDurationMs: minutes(30), DurationToleranceMs: minutes(1), MaxTracksPerArtist: 2},
+			want:        []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := selectPlaylist(tt.pool, tt.constraints, true, isAnime)
+			if got := selectedIDs(s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if s.meetsDuration() != tt.wantDuration {
+				t.Errorf("expected meetsDuration %v, total %d", tt.wantDuration, s.total)
+			}
+		})
+	}
+}
+
+func TestEligibleCandidates(t *testing.T) {
+	explicit := generateCandidate("explicit", "x", 4, true)
+	explicit.Track.Explicit = true
+	noDuration := generateCandidate("no_duration", "x", 0, true)
+	unknownBPM := generateCandidate("unknown_bpm", "x", 4, true)
+	unknownBPM.Features = nil
+	fast := generateCandidate("fast", "x", 4, true)
+	fast.Features.BPM = 180
+	ok := generateCandidate("ok", "x", 4, true)
+
+	candidates := []domain.RecommendedTrack{explicit, noDuration, unknownBPM, fast, ok}
+	eligible, excluded := eligibleCandidates(candidates, domain.PlaylistConstraints{MinBPM: 100, MaxBPM: 140})
+
+	if len(eligible) != 1 || eligible[0].Track.ID != "ok" {
+		t.Errorf("expected only ok to be eligible, got %v", eligible)
+	}
+	want := map[string]int{
+		domain.ExcludedExplicit:      1,
+		domain.ExcludedNoDuration:    1,
+		domain.ExcludedUnknownBPM:    1,
+		domain.ExcludedBPMOutOfRange: 1,
+	}
+	if !reflect.DeepEqual(excluded, want) {
+		t.Errorf("expected %v, got %v", want, excluded)
+	}
+
+	// Without a BPM range, tracks without features are eligible
+	if eligible, _ := eligibleCandidates(candidates, domain.PlaylistConstraints{}); len(eligible) != 3 {
+		t.Errorf("expected 3 eligible candidates, got %d", len(eligible))
+	}
+}
+
+func TestExplainDuration(t *testing.T) {
+	minutes := func(m int) int { return m * 60 * 1000 }
+
+	tests := []struct {
+		name      string
+		pool      []domain.RecommendedTrack
+		c         domain.PlaylistConstraints
+		want      []string
+		wantTotal string
+	}{
+		{
+			name: "正常系: アーティストごとの上限が原因",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a1", "x", 4, true),
+				generateCandidate("a2", "x", 4, true),
+				generateCandidate("r1", "y", 4, false),
+			},
+			c:         domain.PlaylistConstraints{TargetDurationMs: minutes(12), MaxTracksPerArtist: 1, MinGenreShare: 0.5},
+			want:      []string{"DURATION_NOT_MET", "LIMITED_BY_ARTIST_CAP"},
+			wantTotal: "8:00",
+		},
+		{
+			name: "正常系: 1つの条件では説明できない",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a1", "x", 4, true),
+				generateCandidate("r1", "y", 4, false),
+				generateCandidate("r2", "y", 4, false),
+			},
+			c:         domain.PlaylistConstraints{TargetDurationMs: minutes(12), MaxTracksPerArtist: 1, MinGenreShare: 0.5},
+			want:      []string{"DURATION_NOT_MET", "NO_SINGLE_CAUSE"},
+			wantTotal: "8:00",
+		},
+		{
+			name: "正常系: 候補が足りない",
+			pool: []domain.RecommendedTrack{
+				generateCandidate("a1", "x", 4, true),
+			},
+			c:         domain.PlaylistConstraints{TargetDurationMs: minutes(12), MaxTracksPerArtist: 1, MinGenreShare: 0.5},
+			want:      []string{"DURATION_NOT_MET"},
+			wantTotal: "4:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := selectPlaylist(tt.pool, tt.c, true, isAnime)
+			if s.meetsDuration() {
+				t.Fatalf("expected the duration to be missed, got %v", selectedIDs(s))
+			}
+			unmet := explainDuration(tt.pool, tt.pool, tt.c, "otaku", isAnime, s.total)
+
+			codes := make([]string, len(unmet))
+			for i, err := range unmet {
+				codes[i] = err.Code
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, codes)
+			}
+			if unmet[0].Params["total"] != tt.wantTotal || unmet[0].Params["target"] != "12:00" {
+				t.Errorf("unexpected params: %v", unmet[0].Params)
+			}
+		})
+	}
+}
+
+func TestRecommendUseCase_GeneratePlaylist_Invalid(t *testing.T) {
+	uc := NewRecommendUseCase(&mockSpotifyAPI{}, &mockKKBOXAPI{}, &mockDeezerAPI{}, &mockMusicBrainzAPI{})
+	valid := DefaultPlaylistConstraints()
+
+	tests := []struct {
+		name     string
+		seeds    []string
+		modify   func(c *domain.PlaylistConstraints)
+		wantCode string
+	}{
+		{name: "異常系: シードなし", seeds: nil, wantCode: domain.ErrCodeEmptyParam},
+		{name: "異常系: シードが多すぎる", seeds: []string{"1", "2", "3", "4", "5", "6"}, wantCode: "TOO_MANY_TRACKS"},
+		{name: "異常系: 長さが0", seeds: []string{"1"}, modify: func(c *domain.PlaylistConstraints) { c.TargetDurationMs = 0 }, wantCode: domain.ErrCodeInvalidParam},
+		{name: "異常系: 割合が1を超える", seeds: []string{"1"}, modify: func(c *domain.PlaylistConstraints) { c.MinGenreShare = 1.5 }, wantCode: domain.ErrCodeInvalidParam},
+		{name: "異常系: BPMの範囲が逆", seeds: []string{"1"}, modify: func(c *domain.PlaylistConstraints) { c.MinBPM, c.MaxBPM = 150, 100 }, wantCode: domain.ErrCodeInvalidParam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			if tt.modify != nil {
+				tt.modify(&c)
+			}
+			_, err := uc.GeneratePlaylist(context.Background(), tt.seeds, domain.RecommendModeBalanced, c)
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || domainErr.Code != tt.wantCode {
+				t.Errorf("expected %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestRecommendUseCase_GeneratePlaylist_SkipsFailedSeed(t *testing.T) {
+	isrc := "JPAB10000000"
+	candidateISRC := "JPAB10000001"
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			"seed": {ID: "seed", Name: "Seed", ISRC: &isrc, Artists: []domain.Artist{{ID: "artist-seed", Name: "Seed Artist"}}},
+		},
+		tracksByISRC: map[string]*domain.Track{
+			candidateISRC: {ID: "rec", Name: "Recommended", ISRC: &candidateISRC, DurationMs: 240000, Artists: []domain.Artist{{ID: "artist-rec", Name: "Rec Artist"}}},
+		},
+	}
+	kkboxAPI := &mockKKBOXAPI{
+		tracks:      map[string]*external.KKBOXTrackInfo{isrc: {ID: "kkbox-seed", Name: "Seed", ISRC: isrc}},
+		recommended: []external.KKBOXTrackInfo{{ID: "kkbox-rec", Name: "Recommended", ISRC: candidateISRC}},
+	}
+	uc := NewRecommendUseCase(spotifyAPI, kkboxAPI, &mockDeezerAPI{}, &mockMusicBrainzAPI{})
+
+	tests := []struct {
+		name        string
+		seeds       []string
+		wantSkipped []string
+		wantErr     error
+	}{
+		{name: "正常系: 失敗したシードを飛ばす", seeds: []string{"seed", "missing"}, wantSkipped: []string{"missing"}},
+		{name: "正常系: すべて成功", seeds: []string{"seed"}},
+		{name: "異常系: すべてのシードが失敗", seeds: []string{"missing", "gone"}, wantErr: domain.ErrTrackNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := uc.GeneratePlaylist(context.Background(), tt.seeds, domain.RecommendModeBalanced, DefaultPlaylistConstraints())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(playlist.Seeds) != 1 || playlist.Seeds[0].ID != "seed" {
+				t.Errorf("expected seed only, got %v", playlist.Seeds)
+			}
+			if !reflect.DeepEqual(playlist.SkippedSeeds, tt.wantSkipped) {
+				t.Errorf("expected skipped %v, got %v", tt.wantSkipped, playlist.SkippedSeeds)
+			}
+			if playlist.Partial != (len(tt.wantSkipped) > 0) {
+				t.Errorf("expected partial %v, got %v", len(tt.wantSkipped) > 0, playlist.Partial)
+			}
+			if len(playlist.Tracks) != 1 || playlist.Tracks[0].Track.ID != "rec" {
+				t.Errorf("expected the seed's candidate, got %v", playlist.Tracks)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+const (
+	// defaultLinksTTL is how long stored links are served without asking the platforms again.
+	defaultLinksTTL = 7 * 24 * time.Hour
+	// ytmusicLinkSearchLimit is how many YouTube Music search results are compared.
+	ytmusicLinkSearchLimit = 5
+)
+
+// LinksUseCase finds a Spotify track on other platforms, for "open in your app" links.
+// Platforms are matched by ISRC where they support it and by title and artist otherwise.
+type LinksUseCase struct {
+	spotifyAPI     external.SpotifyAPI
+	kkboxAPI       external.KKBOXAPI
+	deezerAPI      external.DeezerAPI
+	musicBrainzAPI external.MusicBrainzAPI
+	ytmusicAPI     external.YouTubeMusicAPI // Optional: can be nil
+	store          repository.LinkStore     // Optional: can be nil
+	ttl            time.Duration
+}
+
+// NewLinksUseCase creates a new LinksUseCase.
+func NewLinksUseCase(
+	spotifyAPI external.SpotifyAPI,
+	kkboxAPI external.KKBOXAPI,
+	deezerAPI external.DeezerAPI,
+	musicBrainzAPI external.MusicBrainzAPI,
+) *LinksUseCase {
+	return &LinksUseCase{
+		spotifyAPI:     spotifyAPI,
+		kkboxAPI:       kkboxAPI,
+		deezerAPI:      deezerAPI,
+		musicBrainzAPI: musicBrainzAPI,
+		ttl:            defaultLinksTTL,
+	}
+}
+
+// WithYouTubeMusic adds YouTube Music links, found by search.
+func (uc *LinksUseCase) WithYouTubeMusic(api external.YouTubeMusicAPI) *LinksUseCase {
+	uc.ytmusicAPI = api
+	return uc
+}
+
+// WithStore caches links in store for ttl.
+func (uc *LinksUseCase) WithStore(store repository.LinkStore, ttl time.Duration) *LinksUseCase {
+	uc.store = store
+	uc.ttl = ttl
+	return uc
+}
+
+// FetchLinks returns the links of a Spotify track on every platform it was found on.
+// Links are cached per track and region; a result missing a platform because
+// the platform failed is returned but not cached.
+func (uc *LinksUseCase) FetchLinks(ctx context.Context, trackID string) (*domain.TrackLinks, error) {
+	if trackID == "" {
+		return nil, domain.ErrTrackNotFound
+	}
+	region := domain.RegionFrom(ctx)
+
+	if uc.store != nil {
+		cached, err := uc.store.GetLinks(ctx, trackID, region)
+		if err == nil && cached != nil && time.Since(cached.FetchedAt) < uc.ttl {
+			logger.DebugContext(ctx, "TrackLinks", "キャッシュからリンクを取得")
+			return cached, nil
+		}
+	}
+
+	track, err := uc.spotifyAPI.GetTrackByID(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are stored under the requested ID, which a relinked track does not have
+	result := &domain.TrackLinks{
+		TrackID:   trackID,
+		Region:    region,
+		Title:     track.Name,
+		FetchedAt: time.Now(),
+		Links: map[string]domain.PlatformLink{
+			domain.PlatformSpotify: {ID: track.ID, URL: track.URL, Confidence: 1, Method: domain.LinkMethodSource},
+		},
+	}
+	if track.ID != trackID {
+		result.RelinkedID = track.ID
+	}
+	if track.ISRC != nil {
+		result.ISRC = *track.ISRC
+	}
+	if len(track.Artists) > 0 {
+		result.Artist = track.Artists[0].Name
+	}
+
+	var (
+		mu     sync.Mutex
+		failed bool
+	)
+	collect := func(platform string, find func() (*domain.PlatformLink, error)) func() {
+		return func() {
+			// A lookup that does not finish (error or panic) makes the result incomplete
+			done := false
+			defer func() {
+				if !done {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}()
+
+			link, err := find()
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "TrackLinks", fmt.Sprintf("%s の検索に失敗: %v", platform, err))
+				return
+			}
+			mu.Lock()
+			if link != nil {
+				result.Links[platform] = *link
+			}
+			mu.Unlock()
+			done = true
+		}
+	}
+
+	g := safego.NewGroup("TrackLinks")
+	g.Go(domain.PlatformKKBOX, collect(domain.PlatformKKBOX, func() (*domain.PlatformLink, error) {
+		return uc.findOnKKBOX(ctx, result)
+	}))
+	g.Go(domain.PlatformDeezer, collect(domain.PlatformDeezer, func() (*domain.PlatformLink, error) {
+		return uc.findOnDeezer(ctx, result)
+	}))
+	g.Go(domain.PlatformMusicBrainz, collect(domain.PlatformMusicBrainz, func() (*domain.PlatformLink, error) {
+		return uc.findOnMusicBrainz(ctx, result)
+	}))
+	if uc.ytmusicAPI != nil {
+		g.Go(domain.PlatformYouTubeMusic, collect(domain.PlatformYouTubeMusic, func() (*domain.PlatformLink, error) {
+			return uc.findOnYouTubeMusic(ctx, result)
+		}))
+	}
+	g.Wait()
+
+	if uc.store != nil && !failed && ctx.Err() == nil {
+		if err := uc.store.SaveLinks(ctx, result); err != nil {
+			logger.WarningContext(ctx, "TrackLinks", "リンクの保存に失敗: "+err.Error())
+		}
+	}
+	logger.InfoContext(ctx, "TrackLinks", fmt.Sprintf("%d 件のリンクを取得", len(result.Links)))
+	return result, nil
+}
+
+// findOnKKBOX finds the track on KKBOX by ISRC.
+func (uc *LinksUseCase) findOnKKBOX(ctx context.Context, t *domain.TrackLinks) (*domain.PlatformLink, error) {
+	if t.ISRC == "" {
+		return nil, nil
+	}
+	info, err := uc.kkboxAPI.SearchByISRC(ctx, t.ISRC)
+	if err != nil || info == nil || info.ID == "" {
+		return nil, err
+	}
+	return &domain.PlatformLink{ID: info.ID, URL: info.URL, Confidence: 1, Method: domain.LinkMethodISRC}, nil
+}
+
+// findOnDeezer finds the track on Deezer by ISRC, falling back to title and artist.
+func (uc *LinksUseCase) findOnDeezer(ctx context.Context, t *domain.TrackLinks) (*domain.PlatformLink, error) {
+	if t.ISRC != "" {
+		found, err := uc.deezerAPI.GetTrackByISRC(ctx, t.ISRC)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if found != nil {
+			return deezerLink(found, 1, domain.LinkMethodISRC), nil
+		}
+	}
+
+	found, err := uc.deezerAPI.SearchTrack(ctx, t.Title, t.Artist)
+	if err != nil || found == nil {
+		return nil, err
+	}
+	confidence := usecase.MatchConfidence(t.Title, t.Artist, found.Title, found.ArtistName)
+	if confidence < usecase.MinMatchConfidence {
+		return nil, nil
+	}
+	return deezerLink(found, confidence, domain.LinkMethodFuzzyTitle), nil
+}
+
+func deezerLink(t *domain.DeezerTrack, confidence float64, method domain.LinkMethod) *domain.PlatformLink {
+	id := fmt.Sprintf("%d", t.ID)
+	return &domain.PlatformLink{ID: id, URL: "https://www.deezer.com/track/" + id, Confidence: confidence, Method: method}
+}
+
+// findOnMusicBrainz finds the recording on MusicBrainz by ISRC.
+func (uc *LinksUseCase) findOnMusicBrainz(ctx context.Context, t *domain.TrackLinks) (*domain.PlatformLink, error) {
+	if t.ISRC == "" {
+		return nil, nil
+	}
+	rec, err := uc.musicBrainzAPI.GetRecordingByISRC(ctx, t.ISRC)
+	if err != nil || rec == nil || rec.MBID == "" {
+		return nil, err
+	}
+	return &domain.PlatformLink{
+		ID:         rec.MBID,
+		URL:        "https://musicbrainz.org/recording/" + rec.MBID,
+		Confidence: 1,
+		Method:     domain.LinkMethodISRC,
+	}, nil
+}
+
+// findOnYouTubeMusic searches YouTube Music by title and artist and keeps the best match.
+func (uc *LinksUseCase) findOnYouTubeMusic(ctx context.Context, t *domain.TrackLinks) (*domain.PlatformLink, error) {
+	results, err := uc.ytmusicAPI.SearchTracks(ctx, t.Title+" "+t.Artist, ytmusicLinkSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *domain.PlatformLink
+	for _, r := range results {
+		// Auto-generated artist channels are named "<artist> - Topic"
+		artist := strings.TrimSuffix(r.Artist, " - Topic")
+		confidence := usecase.MatchConfidence(t.Title, t.Artist, r.Title, artist)
+		if confidence < usecase.MinMatchConfidence || (best != nil && confidence <= best.Confidence) {
+			continue
+		}
+		best = &domain.PlatformLink{
+			ID:         r.VideoID,
+			URL:        "https://music.youtube.com/watch?v=" + r.VideoID,
+			Confidence: confidence,
+			Method:     domain.LinkMethodFuzzyTitle,
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,230 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+)
+
+// mockDeezerAPIWithSearch returns found for every title search.
+type mockDeezerAPIWithSearch struct {
+	mockDeezerAPI
+	found *domain.DeezerTrack
+}
+
+func (m *mockDeezerAPIWithSearch) SearchTrack(ctx context.Context, title, artist string) (*domain.DeezerTrack, error) {
+	if m.found == nil {
+		return nil, domain.ErrNotFound
+	}
+	return m.found, nil
+}
+
+// mockLinkStore is an in-memory LinkStore.
+type mockLinkStore struct {
+	entries map[string]*domain.TrackLinks
+	saved   int
+}
+
+func newMockLinkStore() *mockLinkStore {
+	return &mockLinkStore{entries: make(map[string]*domain.TrackLinks)}
+}
+
+func (m *mockLinkStore) GetLinks(ctx context.Context, trackID, region string) (*domain.TrackLinks, error) {
+	return m.entries[region+":"+trackID], nil
+}
+
+func (m *mockLinkStore) SaveLinks(ctx context.Context, links *domain.TrackLinks) error {
+	m.saved++
+	m.entries[links.Region+":"+links.TrackID] = links
+	return nil
+}
+
+func linksSeedTrack(isrc string) *domain.Track {
+	track := &domain.Track{
+		ID:      "seed",
+		Name:    "Lemon",
+		URL:     "https://open.spotify.com/track/seed",
+		Artists: []domain.Artist{{ID: "a1", Name: "米津玄師"}},
+	}
+	if isrc != "" {
+		track.ISRC = &isrc
+	}
+	return track
+}
+
+func TestLinksUseCase_FetchLinks(t *testing.T) {
+	isrc := "JPU901800200"
+
+	tests := []struct {
+		name       string
+		seed       *domain.Track
+		kkbox      *mockKKBOXAPI
+		deezer     external.DeezerAPI
+		mb         *mockMusicBrainzAPI
+		ytmusic    *mockYTMusicAPI
+		wantLinks  map[string]domain.PlatformLink
+		wantSaved  int
+		wantNoLink []string
+	}{
+		{
+			name: "正常系: ISRCで各サービスに一致",
+			seed: linksSeedTrack(isrc),
+			kkbox: &mockKKBOXAPI{tracks: map[string]*external.KKBOXTrackInfo{
+				isrc: {ID: "kk1", Name: "Lemon", ISRC: isrc, URL: "https://www.kkbox.com/jp/ja/song/kk1"},
+			}},
+			deezer: &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+				isrc: {ID: 3135556, Title: "Lemon", ISRC: isrc, ArtistName: "米津玄師"},
+			}},
+			mb: &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{
+				isrc: {MBID: "b1a9c0e9-d987-4042-ae91-78d6a3267d69", Title: "Lemon", ISRC: isrc},
+			}},
+			ytmusic: &mockYTMusicAPI{searchResults: []domain.YTMusicTrack{
+				{VideoID: "other", Title: "Lemon (Cover)", Artist: "Someone"},
+				{VideoID: "SX_ViT4Ra7k", Title: "Lemon", Artist: "米津玄師 - Topic"},
+			}},
+			wantLinks: map[string]domain.PlatformLink{
+				domain.PlatformSpotify:      {ID: "seed", URL: "https://open.spotify.com/track/seed", Confidence: 1, Method: domain.LinkMethodSource},
+				domain.PlatformKKBOX:        {ID: "kk1", URL: "https://www.kkbox.com/jp/ja/song/kk1", Confidence: 1, Method: domain.LinkMethodISRC},
+				domain.PlatformDeezer:       {ID: "3135556", URL: "https://www.deezer.com/track/3135556", Confidence: 1, Method: domain.LinkMethodISRC},
+				domain.PlatformMusicBrainz:  {ID: "b1a9c0e9-d987-4042-ae91-78d6a3267d69", URL: "https://musicbrainz.org/recording/b1a9c0e9-d987-4042-ae91-78d6a3267d69", Confidence: 1, Method: domain.LinkMethodISRC},
+				domain.PlatformYouTubeMusic: {ID: "SX_ViT4Ra7k", URL: "https://music.youtube.com/watch?v=SX_ViT4Ra7k", Confidence: 0.95, Method: domain.LinkMethodFuzzyTitle},
+			},
+			wantSaved: 1,
+		},
+		{
+			name:  "正常系: ISRCなしは曲名とアーティスト名で検索",
+			seed:  linksSeedTrack(""),
+			kkbox: &mockKKBOXAPI{},
+			deezer: &mockDeezerAPIWithSearch{found: &domain.DeezerTrack{
+				ID: 3135556, Title: "Lemon", ArtistName: "米津玄師",
+			}},
+			mb: &mockMusicBrainzAPI{},
+			wantLinks: map[string]domain.PlatformLink{
+				domain.PlatformDeezer: {ID: "3135556", URL: "https://www.deezer.com/track/3135556", Confidence: 0.95, Method: domain.LinkMethodFuzzyTitle},
+			},
+			wantNoLink: []string{domain.PlatformKKBOX, domain.PlatformMusicBrainz},
+			wantSaved:  1,
+		},
+		{
+			name:  "正常系: 別アーティストの同名曲は除外",
+			seed:  linksSeedTrack(""),
+			kkbox: &mockKKBOXAPI{},
+			deezer: &mockDeezerAPIWithSearch{found: &domain.DeezerTrack{
+				ID: 1, Title: "Lemon", ArtistName: "U2",
+			}},
+			mb:         &mockMusicBrainzAPI{},
+			wantNoLink: []string{domain.PlatformDeezer},
+			wantSaved:  1,
+		},
+		{
+			name:  "異常系: サービスの障害時はキャッシュしない",
+			seed:  linksSeedTrack(isrc),
+			kkbox: &mockKKBOXAPI{},
+			deezer: &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+				isrc: {ID: 3135556, Title: "Lemon", ISRC: isrc},
+			}},
+			mb:      &mockMusicBrainzAPI{},
+			ytmusic: &mockYTMusicAPI{searchErr: errors.New("sidecar down")},
+			wantLinks: map[string]domain.PlatformLink{
+				domain.PlatformDeezer: {ID: "3135556", URL: "https://www.deezer.com/track/3135556", Confidence: 1, Method: domain.LinkMethodISRC},
+			},
+			wantNoLink: []string{domain.PlatformYouTubeMusic},
+			wantSaved:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotify := &mockSpotifyAPI{tracks: map[string]*domain.Track{"seed": tt.seed}}
+			store := newMockLinkStore()
+			uc := NewLinksUseCase(spotify, tt.kkbox, tt.deezer, tt.mb).WithStore(store, time.Hour)
+			if tt.ytmusic != nil {
+				uc.WithYouTubeMusic(tt.ytmusic)
+			}
+
+			got, err := uc.FetchLinks(context.Background(), "seed")
+			if err != nil {
+				t.Fatalf("FetchLinks() unexpected error = %v", err)
+			}
+			for platform, want := range tt.wantLinks {
+				if got.Links[platform] != want {
+					t.Errorf("Links[%s] = %+v, want %+v", platform, got.Links[platform], want)
+				}
+			}
+			for _, platform := range tt.wantNoLink {
+				if link, ok := got.Links[platform]; ok {
+					t.Errorf("Links[%s] = %+v, want none", platform, link)
+				}
+			}
+			if store.saved != tt.wantSaved {
+				t.Errorf("saved = %d, want %d", store.saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestLinksUseCase_FetchLinks_Cache(t *testing.T) {
+	store := newMockLinkStore()
+	cached := &domain.TrackLinks{
+		TrackID:   "seed",
+		Region:    "TW",
+		Title:     "Lemon",
+		FetchedAt: time.Now(),
+		Links:     map[string]domain.PlatformLink{domain.PlatformKKBOX: {ID: "cached"}},
+	}
+	_ = store.SaveLinks(context.Background(), cached)
+	store.saved = 0
+
+	// Spotify knows no track: only the cache can answer
+	uc := NewLinksUseCase(&mockSpotifyAPI{}, &mockKKBOXAPI{}, &mockDeezerAPI{}, &mockMusicBrainzAPI{}).WithStore(store, time.Hour)
+
+	got, err := uc.FetchLinks(domain.WithRegion(context.Background(), "TW"), "seed")
+	if err != nil {
+		t.Fatalf("FetchLinks() unexpected error = %v", err)
+	}
+	if got.Links[domain.PlatformKKBOX].ID != "cached" {
+		t.Errorf("FetchLinks() = %+v, want cached entry", got)
+	}
+
+	// Another region is a different entry
+	if _, err := uc.FetchLinks(domain.WithRegion(context.Background(), "JP"), "seed"); !errors.Is(err, domain.ErrTrackNotFound) {
+		t.Errorf("FetchLinks() error = %v, want %v", err, domain.ErrTrackNotFound)
+	}
+
+	// Expired entries are fetched again
+	cached.FetchedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := uc.FetchLinks(domain.WithRegion(context.Background(), "TW"), "seed"); !errors.Is(err, domain.ErrTrackNotFound) {
+		t.Errorf("FetchLinks() error = %v, want %v", err, domain.ErrTrackNotFound)
+	}
+}
+
+func TestLinksUseCase_FetchLinks_Relinked(t *testing.T) {
+	// Spotify answers for "seed" with its local version "relinked"
+	relinked := linksSeedTrack("")
+	relinked.ID = "relinked"
+	spotify := &mockSpotifyAPI{tracks: map[string]*domain.Track{"seed": relinked}}
+	store := newMockLinkStore()
+	uc := NewLinksUseCase(spotify, &mockKKBOXAPI{}, &mockDeezerAPI{}, &mockMusicBrainzAPI{}).WithStore(store, time.Hour)
+	ctx := domain.WithRegion(context.Background(), "TW")
+
+	got, err := uc.FetchLinks(ctx, "seed")
+	if err != nil {
+		t.Fatalf("FetchLinks() unexpected error = %v", err)
+	}
+	if got.TrackID != "seed" || got.RelinkedID != "relinked" || got.Links[domain.PlatformSpotify].ID != "relinked" {
+		t.Errorf("FetchLinks() = %+v, want seed relinked to relinked", got)
+	}
+
+	// The second request is served from the entry saved under the requested ID
+	delete(spotify.tracks, "seed")
+	if _, err := uc.FetchLinks(ctx, "seed"); err != nil {
+		t.Errorf("FetchLinks() unexpected error = %v", err)
+	}
+	if store.saved != 1 {
+		t.Errorf("saved = %d, want 1", store.saved)
+	}
+}
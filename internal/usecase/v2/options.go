@@ -0,0 +1,59 @@
+package v2
+
+import (
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+)
+
+// Options tunes the recommendation pipeline.
+type Options struct {
+	Timeout       time.Duration // Overall deadline for callers whose context has none
+	MaxResults    int           // Upper bound of the limit parameter
+	MaxCandidates int           // Candidates kept after the genre filter
+
+	// Candidates requested from each source
+	KKBOXCandidates       int
+	LastFMCandidates      int
+	MusicBrainzCandidates int
+	YTMusicCandidates     int
+
+	SpotifyConcurrency int // Concurrent Spotify calls while enriching candidates
+
+	// Weights per mode; a mode without an entry uses WeightsForMode
+	Weights map[domain.RecommendMode]FeatureWeights
+
+	Budget    StageBudget
+	Staleness StalenessPolicy
+
+	// Candidate sources turned off, keyed by domain.Source* name
+	DisabledSources map[string]bool
+
+	// Additional genres per genre group (see usecase.NewGenreMatcherWithGroups)
+	GenreGroups map[usecase.GenreGroup][]string
+}
+
+// DefaultOptions returns the default pipeline settings.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:               30 * time.Second,
+		MaxResults:            30,
+		MaxCandidates:         50,
+		KKBOXCandidates:       30,
+		LastFMCandidates:      30,
+		MusicBrainzCandidates: 20,
+		YTMusicCandidates:     25,
+		SpotifyConcurrency:    15,
+		Budget:                DefaultStageBudget(),
+		Staleness:             DefaultStalenessPolicy(),
+	}
+}
+
+// weightsFor returns the configured weights of mode.
+func (o Options) weightsFor(mode domain.RecommendMode) FeatureWeights {
+	if w, ok := o.Weights[mode]; ok {
+		return w
+	}
+	return WeightsForMode(mode)
+}
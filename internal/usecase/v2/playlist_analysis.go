@@ -0,0 +1,482 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/usecase"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+const (
+	// MaxPlaylistTracks is the most tracks of a playlist that are analysed.
+	MaxPlaylistTracks = MaxFeaturesBatchSize
+	// bpmBinWidth is the width of a BPM histogram bin.
+	bpmBinWidth = 10.0
+	// maxTopTags is the most tags reported in TopTags.
+	maxTopTags = 20
+	// clusterThreshold is the mean similarity two clusters need to be merged.
+	clusterThreshold = 0.7
+	// outlierStdDevs is how far below the mean similarity of all tracks an outlier lies.
+	outlierStdDevs = 1.5
+	// unusualZScore is how far from the mean a feature has to be to be reported as unusual.
+	unusualZScore = 2.0
+	// minOutlierTracks is the fewest analysed tracks for outliers to be meaningful.
+	minOutlierTracks = 3
+	// dominantGroupShare is the share of tracks a genre group needs to dominate a playlist.
+	dominantGroupShare = 0.5
+)
+
+// PlaylistUseCase reports the feature distributions, clusters and outliers of a
+// playlist or a list of tracks. Features come from FeaturesUseCase and are compared
+// with the balanced-mode SimilarityCalculator.
+type PlaylistUseCase struct {
+	spotifyAPI external.SpotifyAPI
+	features   *FeaturesUseCase
+	settings   atomic.Pointer[playlistSettings] // Latest settings, see UpdateOptions
+}
+
+// playlistSettings are the settings an analysis runs with.
+type playlistSettings struct {
+	calculator   *SimilarityCalculator
+	genreMatcher *usecase.GenreMatcher
+}
+
+// NewPlaylistUseCase creates a new PlaylistUseCase.
+func NewPlaylistUseCase(spotifyAPI external.SpotifyAPI, features *FeaturesUseCase) *PlaylistUseCase {
+	uc := &PlaylistUseCase{spotifyAPI: spotifyAPI, features: features}
+	uc.UpdateOptions(DefaultOptions())
+	return uc
+}
+
+// WithOptions uses the balanced weights and genre groups of opts.
+func (uc *PlaylistUseCase) WithOptions(opts Options) *PlaylistUseCase {
+	uc.UpdateOptions(opts)
+	return uc
+}
+
+// UpdateOptions replaces the weights and genre groups at runtime.
+func (uc *PlaylistUseCase) UpdateOptions(opts Options) {
+	matcher := usecase.NewGenreMatcherWithGroups(opts.GenreGroups)
+	uc.settings.Store(&playlistSettings{
+		calculator:   NewSimilarityCalculator(opts.weightsFor(domain.RecommendModeBalanced), matcher),
+		genreMatcher: matcher,
+	})
+}
+
+// AnalyzePlaylist analyses the first MaxPlaylistTracks tracks of a Spotify playlist.
+func (uc *PlaylistUseCase) AnalyzePlaylist(ctx context.Context, playlistID string) (*domain.PlaylistAnalysis, error) {
+	if playlistID == "" {
+		return nil, domain.ErrPlaylistNotFound
+	}
+
+	logger.InfoContext(ctx, "PlaylistAnalysis", "プレイリストを取得")
+	playlist, err := uc.spotifyAPI.GetPlaylist(ctx, playlistID, MaxPlaylistTracks)
+	if err != nil {
+		logger.ErrorContext(ctx, "PlaylistAnalysis", "プレイリスト取得エラー: "+err.Error())
+		return nil, err
+	}
+
+	sets := uc.features.FeaturesOf(ctx, playlist.Tracks)
+	analysis := uc.settings.Load().analyze(sets)
+	analysis.Playlist = playlist
+	logger.InfoContext(ctx, "PlaylistAnalysis", fmt.Sprintf("%d 曲を分析", len(sets)))
+	return analysis, nil
+}
+
+// AnalyzeTracks analyses up to MaxFeaturesBatchSize Spotify tracks.
+// Tracks that cannot be looked up keep their error in the returned feature sets.
+func (uc *PlaylistUseCase) AnalyzeTracks(ctx context.Context, trackIDs []string) (*domain.PlaylistAnalysis, error) {
+	sets, err := uc.features.FetchFeaturesBatch(ctx, trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	analysis := uc.settings.Load().analyze(sets)
+	logger.InfoContext(ctx, "PlaylistAnalysis", fmt.Sprintf("%d 曲を分析", len(sets)))
+	return analysis, nil
+}
+
+// analyzedTrack is a track with features, as compared during an analysis.
+type analyzedTrack struct {
+	id       string
+	features *domain.TrackFeatures // Tags include the Spotify genres
+	group    usecase.GenreGroup    // Only meaningful when features has tags
+}
+
+// analyze builds the report of the feature sets.
+func (s *playlistSettings) analyze(sets []domain.TrackFeatureSet) *domain.PlaylistAnalysis {
+	analysis := &domain.PlaylistAnalysis{Tracks: sets}
+
+	var tracks []analyzedTrack
+	for _, set := range sets {
+		if set.Track == nil || set.Err != nil {
+			continue
+		}
+		if !hasFeatures(&set) {
+			analysis.Unanalyzed = append(analysis.Unanalyzed, set.Track.ID)
+			continue
+		}
+		features := domain.TrackFeatures{TrackID: set.Track.ID}
+		if set.Features != nil {
+			features = *set.Features
+		}
+		features.Tags = mergeTagLists(features.Tags, set.Genres)
+		tracks = append(tracks, analyzedTrack{
+			id:       set.Track.ID,
+			features: &features,
+			group:    s.genreMatcher.Group(features.Tags),
+		})
+	}
+
+	bpms := featureValues(tracks, func(f *domain.TrackFeatures) float64 { return f.BPM })
+	analysis.BPMHistogram = histogram(bpms, bpmBinWidth)
+	analysis.BPM = spreadOf(bpms)
+	analysis.Gain = spreadOf(featureValues(tracks, func(f *domain.TrackFeatures) float64 { return f.Gain }))
+
+	analysis.TopTags = topTags(sets, maxTopTags)
+	analysis.GenreGroups = genreGroupCounts(tracks)
+
+	sim := s.similarities(tracks)
+	analysis.Clusters = clusters(tracks, sim)
+	analysis.Outliers = s.outliers(tracks, sim, analysis.GenreGroups)
+	return analysis
+}
+
+// hasFeatures reports whether any feature of the set was found.
+func hasFeatures(set *domain.TrackFeatureSet) bool {
+	f := set.Features
+	if f == nil {
+		return len(set.Genres) > 0
+	}
+	return f.BPM > 0 || f.DurationSeconds > 0 || f.Gain != 0 || len(f.Tags) > 0 || len(set.Genres) > 0
+}
+
+// featureValues returns the non-zero values of a feature, in track order.
+func featureValues(tracks []analyzedTrack, value func(*domain.TrackFeatures) float64) []float64 {
+	var values []float64
+	for _, t := range tracks {
+		if v := value(t.features); v != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// histogram counts values in bins of width, from the bin of the smallest value to
+// the bin of the largest one. Empty bins in between are kept.
+func histogram(values []float64, width float64) []domain.HistogramBin {
+	if len(values) == 0 {
+		return nil
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	start := math.Floor(lo/width) * width
+	bins := make([]domain.HistogramBin, int((hi-start)/width)+1)
+	for i := range bins {
+		bins[i].Min = start + float64(i)*width
+		bins[i].Max = bins[i].Min + width
+	}
+	for _, v := range values {
+		bins[int((v-start)/width)].Count++
+	}
+	return bins
+}
+
+// spreadOf summarises values, or returns nil if there are none.
+func spreadOf(values []float64) *domain.Spread {
+	if len(values) == 0 {
+		return nil
+	}
+	mean, stdDev := meanStdDev(values)
+	s := &domain.Spread{Min: values[0], Max: values[0], Mean: mean, StdDev: stdDev}
+	for _, v := range values {
+		s.Min, s.Max = math.Min(s.Min, v), math.Max(s.Max, v)
+	}
+	return s
+}
+
+// meanStdDev returns the mean and population standard deviation of values.
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stdDev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)))
+}
+
+// topTags counts the tracks per MusicBrainz tag, most common first and then by name.
+func topTags(sets []domain.TrackFeatureSet, limit int) []domain.TagCount {
+	counts := make(map[string]int)
+	for _, set := range sets {
+		if set.Features == nil || set.Err != nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, tag := range set.Features.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				counts[tag]++
+			}
+		}
+	}
+
+	tags := make([]domain.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, domain.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags
+}
+
+// genreGroupCounts counts the tracks with tags or genres per genre group, most common first.
+func genreGroupCounts(tracks []analyzedTrack) []domain.GenreGroupCount {
+	counts := make(map[usecase.GenreGroup]int)
+	for _, t := range tracks {
+		if len(t.features.Tags) > 0 {
+			counts[t.group]++
+		}
+	}
+
+	groups := make([]domain.GenreGroupCount, 0, len(counts))
+	for group, count := range counts {
+		groups = append(groups, domain.GenreGroupCount{Group: string(group), Count: count})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Group < groups[j].Group
+	})
+	return groups
+}
+
+// dominantGroup returns the genre group of at least dominantGroupShare of the tracks
+// with tags, or GenreGroupOther if there is none.
+func dominantGroup(groups []domain.GenreGroupCount) usecase.GenreGroup {
+	var total int
+	for _, g := range groups {
+		total += g.Count
+	}
+	if len(groups) == 0 || float64(groups[0].Count) < float64(total)*dominantGroupShare {
+		return usecase.GenreGroupOther
+	}
+	return usecase.GenreGroup(groups[0].Group)
+}
+
+// similarities returns the pairwise similarity of tracks.
+func (s *playlistSettings) similarities(tracks []analyzedTrack) [][]float64 {
+	sim := make([][]float64, len(tracks))
+	for i := range sim {
+		sim[i] = make([]float64, len(tracks))
+		sim[i][i] = 1
+	}
+	for i := range tracks {
+		for j := i + 1; j < len(tracks); j++ {
+			sim[i][j] = s.calculator.Calculate(tracks[i].features, tracks[j].features)
+			sim[j][i] = sim[i][j]
+		}
+	}
+	return sim
+}
+
+// clusters groups tracks by average-linkage agglomerative clustering: the two most
+// similar clusters are merged until no two have a mean similarity of clusterThreshold.
+// Clusters of a single track are not reported.
+func clusters(tracks []analyzedTrack, sim [][]float64) []domain.TrackCluster {
+	n := len(tracks)
+	members := make([][]int, n)
+	linkage := make([][]float64, n) // Mean similarity between clusters
+	for i := range members {
+		members[i] = []int{i}
+		linkage[i] = append([]float64(nil), sim[i]...)
+	}
+
+	for {
+		a, b, best := -1, -1, clusterThreshold
+		for i := range members {
+			if members[i] == nil {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if members[j] != nil && linkage[i][j] >= best {
+					a, b, best = i, j, linkage[i][j]
+				}
+			}
+		}
+		if a < 0 {
+			break
+		}
+
+		// Lance-Williams update for average linkage
+		na, nb := float64(len(members[a])), float64(len(members[b]))
+		for k := range members {
+			if members[k] != nil && k != a && k != b {
+				linkage[a][k] = (na*linkage[a][k] + nb*linkage[b][k]) / (na + nb)
+				linkage[k][a] = linkage[a][k]
+			}
+		}
+		members[a] = append(members[a], members[b]...)
+		members[b] = nil
+	}
+
+	var result []domain.TrackCluster
+	for _, m := range members {
+		if len(m) < 2 {
+			continue
+		}
+		sort.Ints(m)
+		cluster := domain.TrackCluster{TrackIDs: make([]string, len(m))}
+		var total float64
+		for i, x := range m {
+			cluster.TrackIDs[i] = tracks[x].id
+			for _, y := range m[i+1:] {
+				total += sim[x][y]
+			}
+		}
+		cluster.MeanSimilarity = total / float64(len(m)*(len(m)-1)/2)
+		cluster.CommonTags = commonTags(tracks, m)
+		result = append(result, cluster)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if len(result[i].TrackIDs) != len(result[j].TrackIDs) {
+			return len(result[i].TrackIDs) > len(result[j].TrackIDs)
+		}
+		return result[i].MeanSimilarity > result[j].MeanSimilarity
+	})
+	return result
+}
+
+// commonTags returns the tags of at least half of the members, most common first.
+func commonTags(tracks []analyzedTrack, members []int) []string {
+	counts := make(map[string]int)
+	for _, m := range members {
+		for _, tag := range tracks[m].features.Tags {
+			counts[tag]++
+		}
+	}
+	var tags []string
+	for tag, count := range counts {
+		if count*2 >= len(members) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// outliers returns the tracks whose mean similarity to the others is outlierStdDevs
+// below the average, or whose genre group is unrelated to the dominant one.
+// Unusual BPM, loudness and duration and the lack of shared tags are added as reasons.
+func (s *playlistSettings) outliers(tracks []analyzedTrack, sim [][]float64, groups []domain.GenreGroupCount) []domain.PlaylistOutlier {
+	n := len(tracks)
+	if n < minOutlierTracks {
+		return nil
+	}
+
+	meanSim := make([]float64, n)
+	for i := range tracks {
+		for j := range tracks {
+			if i != j {
+				meanSim[i] += sim[i][j]
+			}
+		}
+		meanSim[i] /= float64(n - 1)
+	}
+	simMean, simStdDev := meanStdDev(meanSim)
+	dominant := dominantGroup(groups)
+
+	bpm := newZScorer(featureValues(tracks, func(f *domain.TrackFeatures) float64 { return f.BPM }))
+	gain := newZScorer(featureValues(tracks, func(f *domain.TrackFeatures) float64 { return f.Gain }))
+	duration := newZScorer(featureValues(tracks, func(f *domain.TrackFeatures) float64 { return float64(f.DurationSeconds) }))
+
+	var result []domain.PlaylistOutlier
+	for i, t := range tracks {
+		var reasons []string
+		if simStdDev > 0 && meanSim[i] < simMean-outlierStdDevs*simStdDev {
+			reasons = append(reasons, domain.OutlierLowSimilarity)
+		}
+		if dominant != usecase.GenreGroupOther && len(t.features.Tags) > 0 && !s.genreMatcher.IsRelated(dominant, t.group) {
+			reasons = append(reasons, domain.OutlierGenreMismatch)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		if bpm.unusual(t.features.BPM) {
+			reasons = append(reasons, domain.OutlierUnusualBPM)
+		}
+		if gain.unusual(t.features.Gain) {
+			reasons = append(reasons, domain.OutlierUnusualLoudness)
+		}
+		if duration.unusual(float64(t.features.DurationSeconds)) {
+			reasons = append(reasons, domain.OutlierUnusualDuration)
+		}
+		if len(t.features.Tags) > 0 && !sharesTags(tracks, i) {
+			reasons = append(reasons, domain.OutlierNoSharedTags)
+		}
+		result = append(result, domain.PlaylistOutlier{TrackID: t.id, MeanSimilarity: meanSim[i], Reasons: reasons})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].MeanSimilarity < result[j].MeanSimilarity })
+	return result
+}
+
+// zScorer tells values far from the mean of a feature.
+type zScorer struct {
+	mean, stdDev float64
+}
+
+func newZScorer(values []float64) zScorer {
+	mean, stdDev := meanStdDev(values)
+	return zScorer{mean: mean, stdDev: stdDev}
+}
+
+// unusual reports whether v is more than unusualZScore deviations from the mean.
+// Zero means the feature is unknown and is never unusual.
+func (z zScorer) unusual(v float64) bool {
+	return v != 0 && z.stdDev > 0 && math.Abs(v-z.mean)/z.stdDev > unusualZScore
+}
+
+// sharesTags reports whether track i has a tag in common with any other track.
+func sharesTags(tracks []analyzedTrack, i int) bool {
+	tags := make(map[string]bool, len(tracks[i].features.Tags))
+	for _, tag := range tracks[i].features.Tags {
+		tags[tag] = true
+	}
+	for j, t := range tracks {
+		if j == i {
+			continue
+		}
+		for _, tag := range t.features.Tags {
+			if tags[tag] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// newPlaylistAnalysisFixture returns five similar anime tracks, one K-POP track
+// and one track without any features.
+func newPlaylistAnalysisFixture() (*mockSpotifyAPI, *mockDeezerAPI, *mockMusicBrainzAPI) {
+	spotify := &mockSpotifyAPI{
+		tracks:  map[string]*domain.Track{},
+		artists: map[string][]string{"a1": {"anime"}, "a2": {"k-pop"}},
+	}
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}}
+	mb := &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{}}
+
+	for i := 0; i < 5; i++ {
+		id, isrc := fmt.Sprintf("anime%d", i), fmt.Sprintf("JPA00000000%d", i)
+		spotify.tracks[id] = featuresTrack(id, isrc, "a1")
+		deezer.tracks[isrc] = &domain.DeezerTrack{BPM: float64(170 + i), DurationSeconds: 250, Gain: -8}
+		mb.recordings[isrc] = &domain.MBRecording{Tags: []domain.MBTag{{Name: "anime", Count: 5}}}
+	}
+	spotify.tracks["kpop"] = featuresTrack("kpop", "KRA000000001", "a2")
+	deezer.tracks["KRA000000001"] = &domain.DeezerTrack{BPM: 100, DurationSeconds: 200, Gain: -2}
+	mb.recordings["KRA000000001"] = &domain.MBRecording{Tags: []domain.MBTag{{Name: "k-pop", Count: 5}}}
+	spotify.tracks["unknown"] = featuresTrack("unknown", "", "a3")
+
+	return spotify, deezer, mb
+}
+
+func TestPlaylistUseCase_AnalyzePlaylist(t *testing.T) {
+	spotify, deezer, mb := newPlaylistAnalysisFixture()
+	playlist := &domain.Playlist{ID: "pl1", Name: "Mix", TotalTracks: 7}
+	for _, id := range []string{"anime0", "anime1", "kpop", "anime2", "anime3", "unknown", "anime4"} {
+		playlist.Tracks = append(playlist.Tracks, *spotify.tracks[id])
+	}
+	spotify.playlists = map[string]*domain.Playlist{"pl1": playlist}
+
+	uc := NewPlaylistUseCase(spotify, NewFeaturesUseCase(spotify, deezer, mb))
+
+	tests := []struct {
+		name       string
+		playlistID string
+		wantErr    error
+	}{
+		{name: "正常系: 分布・クラスタ・外れ値を集計", playlistID: "pl1"},
+		{name: "異常系: プレイリストが見つからない", playlistID: "missing", wantErr: domain.ErrPlaylistNotFound},
+		{name: "異常系: IDが空", playlistID: "", wantErr: domain.ErrPlaylistNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.AnalyzePlaylist(context.Background(), tt.playlistID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Playlist != playlist || len(got.Tracks) != 7 {
+				t.Fatalf("expected the playlist and its 7 tracks, got %v / %d", got.Playlist, len(got.Tracks))
+			}
+			if !reflect.DeepEqual(got.Unanalyzed, []string{"unknown"}) {
+				t.Errorf("expected unanalyzed [unknown], got %v", got.Unanalyzed)
+			}
+
+			// 100 BPM, five empty bins, then 170-174 BPM
+			if len(got.BPMHistogram) != 8 || got.BPMHistogram[0].Count != 1 || got.BPMHistogram[7].Count != 5 ||
+				got.BPMHistogram[0].Min != 100 || got.BPMHistogram[7].Max != 180 {
+				t.Errorf("unexpected histogram: %+v", got.BPMHistogram)
+			}
+			if got.BPM == nil || got.BPM.Min != 100 || got.BPM.Max != 174 || got.BPM.Mean != 160 {
+				t.Errorf("unexpected BPM spread: %+v", got.BPM)
+			}
+			if got.Gain == nil || got.Gain.Min != -8 || got.Gain.Max != -2 || got.Gain.Mean != -7 {
+				t.Errorf("unexpected gain spread: %+v", got.Gain)
+			}
+
+			wantTags := []domain.TagCount{{Tag: "anime", Count: 5}, {Tag: "k-pop", Count: 1}}
+			if !reflect.DeepEqual(got.TopTags, wantTags) {
+				t.Errorf("expected tags %v, got %v", wantTags, got.TopTags)
+			}
+			wantGroups := []domain.GenreGroupCount{{Group: "otaku", Count: 5}, {Group: "kpop", Count: 1}}
+			if !reflect.DeepEqual(got.GenreGroups, wantGroups) {
+				t.Errorf("expected groups %v, got %v", wantGroups, got.GenreGroups)
+			}
+
+			if len(got.Clusters) != 1 {
+				t.Fatalf("expected 1 cluster, got %+v", got.Clusters)
+			}
+			wantIDs := []string{"anime0", "anime1", "anime2", "anime3", "anime4"}
+			if !reflect.DeepEqual(got.Clusters[0].TrackIDs, wantIDs) || !reflect.DeepEqual(got.Clusters[0].CommonTags, []string{"anime"}) {
+				t.Errorf("unexpected cluster: %+v", got.Clusters[0])
+			}
+
+			if len(got.Outliers) != 1 || got.Outliers[0].TrackID != "kpop" {
+				t.Fatalf("expected kpop as the only outlier, got %+v", got.Outliers)
+			}
+			wantReasons := []string{
+				domain.OutlierLowSimilarity, domain.OutlierGenreMismatch, domain.OutlierUnusualBPM,
+				domain.OutlierUnusualLoudness, domain.OutlierUnusualDuration, domain.OutlierNoSharedTags,
+			}
+			if !reflect.DeepEqual(got.Outliers[0].Reasons, wantReasons) {
+				t.Errorf("expected reasons %v, got %v", wantReasons, got.Outliers[0].Reasons)
+			}
+		})
+	}
+}
+
+func TestPlaylistUseCase_AnalyzeTracks(t *testing.T) {
+	spotify, deezer, mb := newPlaylistAnalysisFixture()
+	uc := NewPlaylistUseCase(spotify, NewFeaturesUseCase(spotify, deezer, mb))
+
+	t.Run("正常系: 取得できない曲はエラーを保持し集計から除く", func(t *testing.T) {
+		got, err := uc.AnalyzeTracks(context.Background(), []string{"anime0", "missing", "anime1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !errors.Is(got.Tracks[1].Err, domain.ErrTrackNotFound) {
+			t.Errorf("expected TRACK_NOT_FOUND for the missing track, got %v", got.Tracks[1].Err)
+		}
+		if got.Playlist != nil || len(got.Unanalyzed) != 0 {
+			t.Errorf("unexpected playlist %v or unanalyzed %v", got.Playlist, got.Unanalyzed)
+		}
+		// Too few tracks for outliers, but the two form a cluster
+		if len(got.Outliers) != 0 || len(got.Clusters) != 1 {
+			t.Errorf("unexpected clusters %+v / outliers %+v", got.Clusters, got.Outliers)
+		}
+	})
+
+	t.Run("正常系: ジャンルだけの曲も集計する", func(t *testing.T) {
+		sets := []domain.TrackFeatureSet{
+			{Track: &domain.Track{ID: "genres"}, Genres: []string{"anime"}},
+			{Track: &domain.Track{ID: "nothing"}},
+		}
+		got := uc.settings.Load().analyze(sets)
+		if !reflect.DeepEqual(got.Unanalyzed, []string{"nothing"}) {
+			t.Errorf("expected unanalyzed [nothing], got %v", got.Unanalyzed)
+		}
+		wantGroups := []domain.GenreGroupCount{{Group: "otaku", Count: 1}}
+		if !reflect.DeepEqual(got.GenreGroups, wantGroups) {
+			t.Errorf("expected groups %v, got %v", wantGroups, got.GenreGroups)
+		}
+	})
+
+	t.Run("異常系: 曲数が上限を超える", func(t *testing.T) {
+		_, err := uc.AnalyzeTracks(context.Background(), make([]string, MaxFeaturesBatchSize+1))
+		if domain.AsError(err) == nil || domain.AsError(err).Code != "TOO_MANY_TRACKS" {
+			t.Errorf("expected TOO_MANY_TRACKS, got %v", err)
+		}
+	})
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []domain.HistogramBin
+	}{
+		{name: "empty", values: nil, want: nil},
+		{name: "single value", values: []float64{128}, want: []domain.HistogramBin{{Min: 120, Max: 130, Count: 1}}},
+		{
+			name:   "value on a bin edge",
+			values: []float64{120, 130},
+			want:   []domain.HistogramBin{{Min: 120, Max: 130, Count: 1}, {Min: 130, Max: 140, Count: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := histogram(tt.values, bpmBinWidth); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("histogram() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,29 +4,26 @@ package v2
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
 	"github.com/t1nyb0x/tracktaste/internal/usecase"
 	"github.com/t1nyb0x/tracktaste/internal/util/logger"
-)
-
-const (
-	recommendV2Timeout       = 30 * time.Second
-	maxRecommendedTracksV2   = 30
-	maxCandidatesV2          = 50 // After filtering (increased for multi-source)
-	kkboxCandidateLimitV2    = 30 // KKBOX candidates
-	lastfmCandidateLimitV2   = 30 // Last.fm candidates
-	mbArtistCandidateLimitV2 = 20 // MusicBrainz artist recordings
-	ytmusicCandidateLimitV2  = 25 // YouTube Music candidates
-	spotifyConcurrency       = 15 // Concurrent Spotify API calls
-	deezerConcurrency        = 15 // Concurrent Deezer API calls
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+	"github.com/t1nyb0x/tracktaste/internal/util/scheduler"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
 )
 
 // RecommendUseCase handles track recommendation using Deezer + MusicBrainz.
@@ -37,8 +34,23 @@ type RecommendUseCase struct {
 	musicBrainzAPI external.MusicBrainzAPI
 	lastfmAPI      external.LastFMAPI       // Optional: can be nil
 	ytmusicAPI     external.YouTubeMusicAPI // Optional: can be nil
-	calculator     *SimilarityCalculator
-	genreMatcher   *usecase.GenreMatcher
+	featureStore   repository.FeatureStore  // Optional: can be nil
+	featureWorker  *FeatureWorker           // Optional: can be nil
+
+	settings *atomic.Pointer[recommendSettings] // Latest settings, see UpdateOptions
+
+	// Settings of the running request, taken from settings by snapshot
+	opts         Options
+	policy       StalenessPolicy
+	budget       StageBudget
+	calculator   *SimilarityCalculator
+	genreMatcher *usecase.GenreMatcher
+}
+
+// recommendSettings are the settings a request runs with.
+type recommendSettings struct {
+	opts         Options
+	genreMatcher *usecase.GenreMatcher
 }
 
 // NewRecommendUseCase creates a new RecommendUseCase.
@@ -48,15 +60,16 @@ func NewRecommendUseCase(
 	deezerAPI external.DeezerAPI,
 	musicBrainzAPI external.MusicBrainzAPI,
 ) *RecommendUseCase {
-	genreMatcher := usecase.NewGenreMatcher()
-	return &RecommendUseCase{
+	uc := &RecommendUseCase{
 		spotifyAPI:     spotifyAPI,
 		kkboxAPI:       kkboxAPI,
 		deezerAPI:      deezerAPI,
 		musicBrainzAPI: musicBrainzAPI,
-		calculator:     NewSimilarityCalculator(DefaultWeights(), genreMatcher),
-		genreMatcher:   genreMatcher,
+		settings:       new(atomic.Pointer[recommendSettings]),
 	}
+	uc.WithOptions(DefaultOptions())
+	uc.calculator = NewSimilarityCalculator(DefaultWeights(), uc.genreMatcher)
+	return uc
 }
 
 // NewRecommendUseCaseWithLastFM creates a new RecommendUseCase with Last.fm support.
@@ -87,98 +100,213 @@ func NewRecommendUseCaseFull(
 	return uc
 }
 
+// WithOptions replaces the pipeline settings (see DefaultOptions).
+func (uc *RecommendUseCase) WithOptions(opts Options) *RecommendUseCase {
+	uc.UpdateOptions(opts)
+	uc.apply(uc.settings.Load())
+	return uc
+}
+
+// UpdateOptions replaces the pipeline settings at runtime.
+// Requests already running keep the settings they started with.
+func (uc *RecommendUseCase) UpdateOptions(opts Options) {
+	uc.settings.Store(&recommendSettings{
+		opts:         opts,
+		genreMatcher: usecase.NewGenreMatcherWithGroups(opts.GenreGroups),
+	})
+}
+
+func (uc *RecommendUseCase) apply(s *recommendSettings) {
+	uc.opts = s.opts
+	uc.policy = s.opts.Staleness
+	uc.budget = s.opts.Budget
+	uc.genreMatcher = s.genreMatcher
+}
+
+// snapshot returns a copy of uc bound to the latest settings, for one request.
+func (uc *RecommendUseCase) snapshot() *RecommendUseCase {
+	run := *uc
+	run.apply(uc.settings.Load())
+	return &run
+}
+
+// WithFeatureStore enables the persistent feature store.
+// Enrichment reads stored features before calling upstreams and writes fetched ones back.
+// If worker is non-nil, candidates without MusicBrainz tags are enqueued for background lookup.
+func (uc *RecommendUseCase) WithFeatureStore(store repository.FeatureStore, worker *FeatureWorker) *RecommendUseCase {
+	uc.featureStore = store
+	uc.featureWorker = worker
+	return uc
+}
+
 // GetRecommendations returns recommended tracks using Deezer + MusicBrainz features.
+// The time left before the request deadline is split between the pipeline stages
+// (see StageBudget). A stage that runs out of time passes on what it has, and the
+// result is marked partial.
 func (uc *RecommendUseCase) GetRecommendations(
 	ctx context.Context,
 	trackID string,
 	mode domain.RecommendMode,
 	limit int,
 ) (*domain.RecommendResult, error) {
-	ctx, cancel := context.WithTimeout(ctx, recommendV2Timeout)
-	defer cancel()
+	return uc.snapshot().getRecommendations(ctx, trackID, mode, limit)
+}
 
-	// Update calculator weights based on mode
-	uc.calculator = NewSimilarityCalculator(WeightsForMode(mode), uc.genreMatcher)
+func (uc *RecommendUseCase) getRecommendations(
+	ctx context.Context,
+	trackID string,
+	mode domain.RecommendMode,
+	limit int,
+) (result *domain.RecommendResult, err error) {
+	ctx, span := tracing.Start(ctx, "RecommendUseCase.GetRecommendations", trace.WithAttributes(
+		attribute.String("tracktaste.seed_track", trackID),
+		attribute.String("tracktaste.mode", string(mode)),
+	))
+	defer func() { tracing.End(span, err) }()
 
-	if limit <= 0 || limit > maxRecommendedTracksV2 {
-		limit = maxRecommendedTracksV2
+	// The request deadline (set per route by the server) drives the stage budgets;
+	// opts.Timeout only applies to callers without one
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.opts.Timeout)
+		defer cancel()
 	}
+	ctx = logger.WithSeedTrack(ctx, trackID)
+
+	if limit <= 0 || limit > uc.opts.MaxResults {
+		limit = uc.opts.MaxResults
+	}
+
+	result, err = uc.rank(ctx, trackID, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit results
+	if len(result.Items) > limit {
+		result.Items = result.Items[:limit]
+	}
+	return result, nil
+}
+
+// rank scores every candidate for the seed track, best first.
+// It changes uc.calculator, so concurrent calls need their own copy of uc.
+func (uc *RecommendUseCase) rank(ctx context.Context, trackID string, mode domain.RecommendMode) (*domain.RecommendResult, error) {
+	// Update calculator weights based on mode
+	uc.calculator = NewSimilarityCalculator(uc.opts.weightsFor(mode), uc.genreMatcher)
+
+	// Seed lookups are scheduled ahead of candidate enrichment
+	seedCtx, seedSpan := tracing.Start(scheduler.WithPriority(ctx, scheduler.PriorityHigh), "recommend.seed")
 
 	// Step 1: Get seed track info from Spotify
-	logger.Info("RecommendV2", "シードトラック情報を取得")
-	track, err := uc.spotifyAPI.GetTrackByID(ctx, trackID)
+	logger.InfoContext(ctx, "RecommendV2", "シードトラック情報を取得")
+	track, err := uc.spotifyAPI.GetTrackByID(seedCtx, trackID)
 	if err != nil {
-		logger.Error("RecommendV2", "シードトラック取得エラー: "+err.Error())
+		logger.ErrorContext(ctx, "RecommendV2", "シードトラック取得エラー: "+err.Error())
+		tracing.End(seedSpan, err)
 		return nil, err
 	}
 
 	// Step 2: Get seed track features from Deezer + MusicBrainz (parallel)
-	logger.Info("RecommendV2", "シードの特徴量を取得 (Deezer + MusicBrainz)")
-	seedFeatures, seedArtistInfo := uc.getSeedFeatures(ctx, track)
+	logger.InfoContext(ctx, "RecommendV2", "シードの特徴量を取得 (Deezer + MusicBrainz)")
+	var seedStored *domain.StoredFeatures
+	if track.ISRC != nil && *track.ISRC != "" {
+		seedStored = loadStoredFeatures(ctx, uc.featureStore, "RecommendV2", []string{*track.ISRC})[*track.ISRC]
+	}
+	seedFeatures, seedArtistInfo := uc.getSeedFeatures(seedCtx, track, seedStored)
 
 	// Get Spotify genres for seed artist
-	seedGenres := uc.getArtistGenres(ctx, track)
+	seedGenres := uc.getArtistGenres(seedCtx, track, seedStored)
 
 	// Merge tags from MusicBrainz and Spotify genres
 	if seedFeatures != nil && len(seedGenres) > 0 {
 		seedFeatures.Tags = uc.mergeTags(seedFeatures.Tags, seedGenres)
 	}
+	seedSpan.End()
+
+	plan := newStagePlan(ctx, uc.budget, time.Now())
 
 	// Step 3: Collect candidate tracks from multiple sources (KKBOX + Last.fm + MusicBrainz)
-	logger.Info("RecommendV2", "候補トラックを複数ソースから収集")
-	candidates := uc.collectCandidatesMultiSource(ctx, track, seedFeatures)
-	logger.Info("RecommendV2", fmt.Sprintf("候補トラック数: %d", len(candidates)))
+	logger.InfoContext(ctx, "RecommendV2", "候補トラックを複数ソースから収集")
+	collectCtx, cancelCollect := plan.context(ctx, domain.StageCollect)
+	candidates, degradedSources := uc.collectCandidatesMultiSource(collectCtx, track, seedFeatures)
+	plan.finish(collectCtx, domain.StageCollect)
+	cancelCollect()
+	if len(degradedSources) > 0 {
+		logger.WarningContext(ctx, "RecommendV2", fmt.Sprintf("縮退モード: %s", strings.Join(degradedSources, ", ")))
+	}
+	logger.InfoContext(ctx, "RecommendV2", fmt.Sprintf("候補トラック数: %d", len(candidates)))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("tracktaste.candidates", len(candidates)))
 
 	if len(candidates) == 0 {
-		logger.Info("RecommendV2", "レコメンドできる曲がありませんでした")
+		logger.InfoContext(ctx, "RecommendV2", "レコメンドできる曲がありませんでした")
 		return &domain.RecommendResult{
-			SeedTrack:    *track,
-			SeedFeatures: seedFeatures,
-			SeedGenres:   seedGenres,
-			Items:        []domain.RecommendedTrack{},
-			Mode:         mode,
+			SeedTrack:       *track,
+			SeedFeatures:    seedFeatures,
+			SeedGenres:      seedGenres,
+			Items:           []domain.RecommendedTrack{},
+			Mode:            mode,
+			DegradedSources: degradedSources,
+			Partial:         len(plan.partialStages()) > 0,
+			PartialStages:   plan.partialStages(),
 		}, nil
 	}
 
-	// Step 4: Enrich candidates with Spotify + Deezer in parallel (skip MusicBrainz for speed)
-	logger.Info("RecommendV2", "候補のSpotify/Deezer情報を並列取得")
-	candidates, candidateFeatures := uc.enrichCandidatesParallel(ctx, candidates)
+	// Step 4: Enrich candidates with Spotify + Deezer in parallel
+	// MusicBrainz tags are read from the feature store and filled in by the background worker
+	logger.InfoContext(ctx, "RecommendV2", "候補のSpotify/Deezer情報を並列取得")
+	enrichCtx, cancelEnrich := plan.context(ctx, domain.StageEnrich)
+	candidates, candidateFeatures := uc.enrichCandidatesParallel(scheduler.WithPriority(enrichCtx, scheduler.PriorityLow), candidates)
+	plan.finish(enrichCtx, domain.StageEnrich)
+	cancelEnrich()
 
 	// Step 4.5: Filter candidates by genre (remove unrelated genres)
-	logger.Info("RecommendV2", fmt.Sprintf("ジャンルフィルタ前: %d件", len(candidates)))
-	candidates, candidateFeatures = uc.filterByGenre(candidates, candidateFeatures, seedGenres)
-	logger.Info("RecommendV2", fmt.Sprintf("ジャンルフィルタ後: %d件", len(candidates)))
+	logger.InfoContext(ctx, "RecommendV2", fmt.Sprintf("ジャンルフィルタ前: %d件", len(candidates)))
+	filterCtx, cancelFilter := plan.context(ctx, domain.StageFilter)
+	candidates, candidateFeatures = uc.filterByGenre(filterCtx, candidates, candidateFeatures, seedGenres)
+	plan.finish(filterCtx, domain.StageFilter)
+	cancelFilter()
+	logger.InfoContext(ctx, "RecommendV2", fmt.Sprintf("ジャンルフィルタ後: %d件", len(candidates)))
 
 	// Step 5: Calculate similarity scores and rank
-	logger.Info("RecommendV2", "類似度を計算")
+	logger.InfoContext(ctx, "RecommendV2", "類似度を計算")
+	scoreCtx, cancelScore := plan.context(ctx, domain.StageScore)
 	recommendedTracks := uc.calculateScores(
+		scoreCtx,
 		seedFeatures, seedArtistInfo, seedGenres,
 		candidates, candidateFeatures, nil, track, // Pass seed track for same-artist/series detection
 	)
+	plan.finish(scoreCtx, domain.StageScore)
+	cancelScore()
+
+	partialStages := plan.partialStages()
+	if len(partialStages) > 0 {
+		logger.WarningContext(ctx, "RecommendV2", fmt.Sprintf("時間切れで打ち切ったステージ: %v", partialStages))
+	}
 
 	// Sort by final score (descending)
 	sort.Slice(recommendedTracks, func(i, j int) bool {
 		return recommendedTracks[i].FinalScore > recommendedTracks[j].FinalScore
 	})
 
-	// Limit results
-	if len(recommendedTracks) > limit {
-		recommendedTracks = recommendedTracks[:limit]
-	}
-
 	return &domain.RecommendResult{
-		SeedTrack:    *track,
-		SeedFeatures: seedFeatures,
-		SeedGenres:   seedGenres,
-		Items:        recommendedTracks,
-		Mode:         mode,
+		SeedTrack:       *track,
+		SeedFeatures:    seedFeatures,
+		SeedGenres:      seedGenres,
+		Items:           recommendedTracks,
+		Mode:            mode,
+		DegradedSources: degradedSources,
+		Partial:         len(partialStages) > 0,
+		PartialStages:   partialStages,
 	}, nil
 }
 
 // getSeedFeatures retrieves features for the seed track from Deezer and MusicBrainz.
+// Fresh groups in stored are used as-is; missing or stale groups are fetched and saved.
 func (uc *RecommendUseCase) getSeedFeatures(
 	ctx context.Context,
 	track *domain.Track,
+	stored *domain.StoredFeatures,
 ) (*domain.TrackFeatures, *domain.ArtistInfo) {
 	features := &domain.TrackFeatures{
 		TrackID: track.ID,
@@ -186,90 +314,127 @@ func (uc *RecommendUseCase) getSeedFeatures(
 	var artistInfo *domain.ArtistInfo
 
 	if track.ISRC == nil || *track.ISRC == "" {
-		logger.Warning("RecommendV2", "シードトラックにISRCがありません")
+		logger.WarningContext(ctx, "RecommendV2", "シードトラックにISRCがありません")
 		return features, artistInfo
 	}
-	features.ISRC = *track.ISRC
+	isrc := *track.ISRC
+	features.ISRC = isrc
+
+	now := time.Now()
+	var deezerGroup *domain.DeezerFeatureGroup
+	var mbGroup *domain.MusicBrainzFeatureGroup
+	if stored != nil {
+		if !uc.policy.IsStale(domain.FeatureSourceDeezer, deezerMeta(stored), now) {
+			deezerGroup = stored.Deezer
+		}
+		if !uc.policy.IsStale(domain.FeatureSourceMusicBrainz, musicBrainzMeta(stored), now) {
+			mbGroup = stored.MusicBrainz
+		}
+	}
+	fetched := &domain.StoredFeatures{ISRC: isrc}
 
-	var wg sync.WaitGroup
+	g := safego.NewGroup("RecommendV2")
 	var mu sync.Mutex
 
 	// Get Deezer features
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		deezerTrack, err := uc.deezerAPI.GetTrackByISRC(ctx, *track.ISRC)
-		if err != nil {
-			if err != domain.ErrNotFound {
-				logger.Warning("RecommendV2", "Deezer取得エラー: "+err.Error())
+	if deezerGroup == nil {
+		g.Go("Deezer", func() {
+			deezerTrack, err := uc.deezerAPI.GetTrackByISRC(ctx, isrc)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "RecommendV2", "Deezer取得エラー: "+err.Error())
+				return
 			}
-			return
-		}
-		mu.Lock()
-		features.BPM = deezerTrack.BPM
-		features.DurationSeconds = deezerTrack.DurationSeconds
-		features.Gain = deezerTrack.Gain
-		mu.Unlock()
-	}()
+			mu.Lock()
+			fetched.Deezer = newDeezerGroup(deezerTrack, now)
+			mu.Unlock()
+		})
+	}
 
 	// Get MusicBrainz features
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		recording, err := uc.musicBrainzAPI.GetRecordingByISRC(ctx, *track.ISRC)
-		if err != nil {
-			if err != domain.ErrNotFound {
-				logger.Warning("RecommendV2", "MusicBrainz取得エラー: "+err.Error())
+	if mbGroup == nil {
+		g.Go("MusicBrainz", func() {
+			recording, err := uc.musicBrainzAPI.GetRecordingByISRC(ctx, isrc)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				logger.WarningContext(ctx, "RecommendV2", "MusicBrainz取得エラー: "+err.Error())
+				return
 			}
-			return
-		}
-		mu.Lock()
-		features.ArtistMBID = recording.ArtistMBID
-		// Convert MBTag to string slice
-		tags := make([]string, len(recording.Tags))
-		for i, tag := range recording.Tags {
-			tags[i] = tag.Name
-		}
-		features.Tags = tags
-		mu.Unlock()
+			mu.Lock()
+			fetched.MusicBrainz = newMusicBrainzGroup(recording, now)
+			mu.Unlock()
+		})
+	}
 
-		// Get artist relations if we have artist MBID
-		if recording.ArtistMBID != "" {
-			artist, err := uc.musicBrainzAPI.GetArtistWithRelations(ctx, recording.ArtistMBID)
-			if err == nil {
-				mu.Lock()
-				artistInfo = &domain.ArtistInfo{
-					MBID:      artist.MBID,
-					Name:      artist.Name,
-					Tags:      artist.Tags,
-					Relations: artist.Relations,
-				}
-				mu.Unlock()
+	g.Wait()
+
+	if fetched.Deezer != nil || fetched.MusicBrainz != nil {
+		saveStoredFeatures(ctx, uc.featureStore, "RecommendV2", fetched)
+	}
+	if fetched.Deezer != nil {
+		deezerGroup = fetched.Deezer
+	}
+	if fetched.MusicBrainz != nil {
+		mbGroup = fetched.MusicBrainz
+	}
+
+	if deezerGroup != nil && deezerGroup.Found {
+		features.BPM = deezerGroup.BPM
+		features.DurationSeconds = deezerGroup.DurationSeconds
+		features.Gain = deezerGroup.Gain
+	}
+	if mbGroup != nil && mbGroup.Found {
+		features.ArtistMBID = mbGroup.ArtistMBID
+		features.Tags = append([]string(nil), mbGroup.Tags...)
+	}
+
+	// Get artist relations if we have artist MBID
+	if features.ArtistMBID != "" {
+		artist, err := uc.musicBrainzAPI.GetArtistWithRelations(ctx, features.ArtistMBID)
+		if err == nil {
+			artistInfo = &domain.ArtistInfo{
+				MBID:      artist.MBID,
+				Name:      artist.Name,
+				Tags:      artist.Tags,
+				Relations: artist.Relations,
 			}
 		}
-	}()
+	}
 
-	wg.Wait()
 	return features, artistInfo
 }
 
 // getArtistGenres gets Spotify genres for the track's primary artist.
-func (uc *RecommendUseCase) getArtistGenres(ctx context.Context, track *domain.Track) []string {
+// Fresh genres in stored are used without calling Spotify.
+func (uc *RecommendUseCase) getArtistGenres(ctx context.Context, track *domain.Track, stored *domain.StoredFeatures) []string {
 	if len(track.Artists) == 0 {
 		return nil
 	}
+	if stored != nil && !uc.policy.IsStale(domain.FeatureSourceSpotifyGenres, spotifyGenresMeta(stored), time.Now()) {
+		return stored.SpotifyGenres.Genres
+	}
 
 	artistID := track.Artists[0].ID
 	genres, err := uc.spotifyAPI.GetArtistGenres(ctx, artistID)
 	if err != nil {
-		logger.Warning("RecommendV2", "Spotifyジャンル取得エラー: "+err.Error())
+		logger.WarningContext(ctx, "RecommendV2", "Spotifyジャンル取得エラー: "+err.Error())
 		return nil
 	}
+
+	if track.ISRC != nil && *track.ISRC != "" {
+		saveStoredFeatures(ctx, uc.featureStore, "RecommendV2", &domain.StoredFeatures{
+			ISRC:          *track.ISRC,
+			SpotifyGenres: newSpotifyGenresGroup(genres, time.Now()),
+		})
+	}
 	return genres
 }
 
 // mergeTags merges MusicBrainz tags and Spotify genres, removing duplicates.
 func (uc *RecommendUseCase) mergeTags(mbTags, spotifyGenres []string) []string {
+	return mergeTagLists(mbTags, spotifyGenres)
+}
+
+// mergeTagLists returns mbTags followed by the spotifyGenres not among them.
+func mergeTagLists(mbTags, spotifyGenres []string) []string {
 	seen := make(map[string]bool)
 	result := make([]string, 0, len(mbTags)+len(spotifyGenres))
 
@@ -297,25 +462,25 @@ func (uc *RecommendUseCase) collectCandidatesV2(
 	seedTrack *domain.Track,
 ) []domain.Track {
 	if seedTrack.ISRC == nil || *seedTrack.ISRC == "" {
-		logger.Warning("RecommendV2", "ISRCがないため候補を収集できません")
+		logger.WarningContext(ctx, "RecommendV2", "ISRCがないため候補を収集できません")
 		return nil
 	}
 
 	// Get KKBOX recommendations
 	kkboxTrack, err := uc.kkboxAPI.SearchByISRC(ctx, *seedTrack.ISRC)
 	if err != nil {
-		logger.Warning("RecommendV2", "KKBOX ISRC検索エラー: "+err.Error())
+		logger.WarningContext(ctx, "RecommendV2", "KKBOX ISRC検索エラー: "+err.Error())
 		return nil
 	}
 	if kkboxTrack == nil {
 		// Track not found in KKBOX catalog (not an error)
-		logger.Info("RecommendV2", "KKBOX: 曲が見つかりませんでした")
+		logger.InfoContext(ctx, "RecommendV2", "KKBOX: 曲が見つかりませんでした")
 		return nil
 	}
 
 	similarTracks, err := uc.kkboxAPI.GetRecommendedTracks(ctx, kkboxTrack.ID)
 	if err != nil {
-		logger.Warning("RecommendV2", "KKBOXレコメンド取得エラー: "+err.Error())
+		logger.WarningContext(ctx, "RecommendV2", "KKBOXレコメンド取得エラー: "+err.Error())
 		return nil
 	}
 
@@ -347,9 +512,9 @@ func (uc *RecommendUseCase) collectCandidatesV2(
 		})
 	}
 
-	// Keep up to kkboxCandidateLimitV2 (30) - will be filtered by genre later
-	if len(candidates) > kkboxCandidateLimitV2 {
-		candidates = candidates[:kkboxCandidateLimitV2]
+	// Keep up to opts.KKBOXCandidates - will be filtered by genre later
+	if len(candidates) > uc.opts.KKBOXCandidates {
+		candidates = candidates[:uc.opts.KKBOXCandidates]
 	}
 
 	return candidates
@@ -357,13 +522,15 @@ func (uc *RecommendUseCase) collectCandidatesV2(
 
 // collectCandidatesMultiSource collects candidate tracks from multiple sources in parallel.
 // Sources: (1) KKBOX recommendations (2) Last.fm similar tracks (3) MusicBrainz artist recordings (4) YouTube Music
+// It also returns the sources that failed (or were skipped by their circuit breaker), sorted by name.
 func (uc *RecommendUseCase) collectCandidatesMultiSource(
 	ctx context.Context,
 	seedTrack *domain.Track,
 	seedFeatures *domain.TrackFeatures,
-) []domain.Track {
-	var wg sync.WaitGroup
+) ([]domain.Track, []string) {
+	g := safego.NewGroup("RecommendV2")
 	var mu sync.Mutex
+	var degraded []string
 
 	// Map to deduplicate by ISRC or name+artist
 	seen := make(map[string]bool)
@@ -375,9 +542,17 @@ func (uc *RecommendUseCase) collectCandidatesMultiSource(
 	}
 
 	// Helper to add candidates with deduplication
-	addCandidates := func(candidates []domain.Track, source string) {
+	addCandidates := func(candidates []domain.Track, source string, err error) {
 		mu.Lock()
 		defer mu.Unlock()
+		if isSourceFailure(err) {
+			degraded = append(degraded, source)
+			if errors.Is(err, domain.ErrUpstreamUnavailable) {
+				logger.WarningContext(ctx, "RecommendV2", fmt.Sprintf("[%s] サーキットブレーカー作動中のためスキップ", source))
+			}
+			return
+		}
+		metrics.RecommendCandidates.Observe(float64(len(candidates)), source)
 		added := 0
 		for _, c := range candidates {
 			key := ""
@@ -399,79 +574,113 @@ func (uc *RecommendUseCase) collectCandidatesMultiSource(
 			allCandidates = append(allCandidates, c)
 			added++
 		}
-		logger.Info("RecommendV2", fmt.Sprintf("[%s] %d件追加 (重複除外後)", source, added))
+		logger.InfoContext(ctx, "RecommendV2", fmt.Sprintf("[%s] %d件追加 (重複除外後)", source, added))
+	}
+
+	// Helper to run one source in its own span; a panic marks only that source as failed
+	collect := func(source string, fn func(ctx context.Context) ([]domain.Track, error)) {
+		if uc.opts.DisabledSources[source] {
+			logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("[%s] 設定で無効のためスキップ", source))
+			return
+		}
+		g.Go(source, func() {
+			sourceCtx, span := tracing.Start(ctx, "recommend.collect."+source)
+			var candidates []domain.Track
+			err := safego.Run("RecommendV2", source, func() (err error) {
+				candidates, err = fn(sourceCtx)
+				return err
+			})
+			span.SetAttributes(attribute.Int("tracktaste.candidates", len(candidates)))
+			if isSourceFailure(err) {
+				tracing.End(span, err)
+			} else {
+				span.End()
+			}
+			addCandidates(candidates, source, err)
+		})
 	}
 
 	// (1) KKBOX recommendations
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		candidates := uc.collectFromKKBOX(ctx, seedTrack)
-		addCandidates(candidates, "KKBOX")
-	}()
+	collect(domain.SourceKKBOX, func(ctx context.Context) ([]domain.Track, error) {
+		return uc.collectFromKKBOX(ctx, seedTrack)
+	})
 
 	// (2) Last.fm similar tracks
 	if uc.lastfmAPI != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			candidates := uc.collectFromLastFM(ctx, seedTrack)
-			addCandidates(candidates, "Last.fm")
-		}()
+		collect(domain.SourceLastFM, func(ctx context.Context) ([]domain.Track, error) {
+			return uc.collectFromLastFM(ctx, seedTrack)
+		})
 	}
 
 	// (3) MusicBrainz artist recordings (same artist's other tracks)
 	if seedFeatures != nil && seedFeatures.ArtistMBID != "" {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			candidates := uc.collectFromMusicBrainzArtist(ctx, seedFeatures.ArtistMBID, seedTrack)
-			addCandidates(candidates, "MusicBrainz")
-		}()
+		collect(domain.SourceMusicBrainz, func(ctx context.Context) ([]domain.Track, error) {
+			return uc.collectFromMusicBrainzArtist(ctx, seedFeatures.ArtistMBID, seedTrack)
+		})
 	}
 
 	// (4) YouTube Music similar tracks
 	if uc.ytmusicAPI != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			candidates := uc.collectFromYouTubeMusic(ctx, seedTrack)
-			addCandidates(candidates, "YouTubeMusic")
-		}()
+		collect(domain.SourceYouTubeMusic, func(ctx context.Context) ([]domain.Track, error) {
+			return uc.collectFromYouTubeMusic(ctx, seedTrack)
+		})
 	}
 
-	wg.Wait()
+	// Stop waiting when the stage budget runs out; late sources are dropped
+	done := make(chan struct{})
+	go func() {
+		g.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.WarningContext(ctx, "RecommendV2", "候補収集の時間切れ: 取得済みの候補で続行")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Copy so that sources finishing late cannot modify the result
+	result := append([]domain.Track(nil), allCandidates...)
+	degradedSources := append([]string(nil), degraded...)
+	sort.Strings(degradedSources)
 
-	logger.Info("RecommendV2", fmt.Sprintf("全ソースから合計 %d件の候補を収集", len(allCandidates)))
-	return allCandidates
+	logger.InfoContext(ctx, "RecommendV2", fmt.Sprintf("全ソースから合計 %d件の候補を収集", len(result)))
+	return result, degradedSources
+}
+
+// isSourceFailure reports whether a candidate source failed, as opposed to
+// simply having nothing for the seed track. ErrNotFound matches every not-found error.
+func isSourceFailure(err error) bool {
+	return err != nil && !errors.Is(err, domain.ErrNotFound)
 }
 
 // collectFromKKBOX collects candidates from KKBOX recommendations.
-func (uc *RecommendUseCase) collectFromKKBOX(ctx context.Context, seedTrack *domain.Track) []domain.Track {
+func (uc *RecommendUseCase) collectFromKKBOX(ctx context.Context, seedTrack *domain.Track) ([]domain.Track, error) {
 	if seedTrack.ISRC == nil || *seedTrack.ISRC == "" {
-		return nil
+		return nil, nil
 	}
 
 	kkboxTrack, err := uc.kkboxAPI.SearchByISRC(ctx, *seedTrack.ISRC)
 	if err != nil {
-		logger.Warning("RecommendV2", "KKBOX ISRC検索エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "KKBOX ISRC検索エラー: "+err.Error())
+		return nil, err
 	}
 	if kkboxTrack == nil {
 		// Track not found in KKBOX catalog (not an error)
-		logger.Info("RecommendV2", "KKBOX: 曲が見つかりませんでした")
-		return nil
+		logger.InfoContext(ctx, "RecommendV2", "KKBOX: 曲が見つかりませんでした")
+		return nil, nil
 	}
 
 	similarTracks, err := uc.kkboxAPI.GetRecommendedTracks(ctx, kkboxTrack.ID)
 	if err != nil {
-		logger.Warning("RecommendV2", "KKBOXレコメンド取得エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "KKBOXレコメンド取得エラー: "+err.Error())
+		return nil, err
 	}
 
 	candidates := make([]domain.Track, 0, len(similarTracks))
 	for i, st := range similarTracks {
-		if i >= kkboxCandidateLimitV2 {
+		if i >= uc.opts.KKBOXCandidates {
 			break
 		}
 		isrc := st.ISRC
@@ -481,13 +690,13 @@ func (uc *RecommendUseCase) collectFromKKBOX(ctx context.Context, seedTrack *dom
 			ISRC: &isrc,
 		})
 	}
-	return candidates
+	return candidates, nil
 }
 
 // collectFromLastFM collects candidates from Last.fm track.getSimilar.
-func (uc *RecommendUseCase) collectFromLastFM(ctx context.Context, seedTrack *domain.Track) []domain.Track {
+func (uc *RecommendUseCase) collectFromLastFM(ctx context.Context, seedTrack *domain.Track) ([]domain.Track, error) {
 	if uc.lastfmAPI == nil {
-		return nil
+		return nil, nil
 	}
 
 	// Get artist name
@@ -496,19 +705,19 @@ func (uc *RecommendUseCase) collectFromLastFM(ctx context.Context, seedTrack *do
 		artistName = seedTrack.Artists[0].Name
 	}
 	if artistName == "" {
-		logger.Warning("RecommendV2", "Last.fm: アーティスト名が不明")
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "Last.fm: アーティスト名が不明")
+		return nil, nil
 	}
 
 	// Get similar tracks from Last.fm
-	similarTracks, err := uc.lastfmAPI.GetSimilarTracks(ctx, artistName, seedTrack.Name, lastfmCandidateLimitV2)
+	similarTracks, err := uc.lastfmAPI.GetSimilarTracks(ctx, artistName, seedTrack.Name, uc.opts.LastFMCandidates)
 	if err != nil {
-		logger.Warning("RecommendV2", "Last.fm類似曲取得エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "Last.fm類似曲取得エラー: "+err.Error())
+		return nil, err
 	}
 	if len(similarTracks) == 0 {
-		logger.Info("RecommendV2", "Last.fm: 類似曲が見つかりませんでした")
-		return nil
+		logger.InfoContext(ctx, "RecommendV2", "Last.fm: 類似曲が見つかりませんでした")
+		return nil, nil
 	}
 
 	// Convert to domain.Track (will be enriched with Spotify later)
@@ -524,19 +733,19 @@ func (uc *RecommendUseCase) collectFromLastFM(ctx context.Context, seedTrack *do
 			},
 		})
 	}
-	return candidates
+	return candidates, nil
 }
 
 // collectFromMusicBrainzArtist collects other tracks by the same artist from MusicBrainz.
-func (uc *RecommendUseCase) collectFromMusicBrainzArtist(ctx context.Context, artistMBID string, seedTrack *domain.Track) []domain.Track {
-	recordings, err := uc.musicBrainzAPI.GetArtistRecordings(ctx, artistMBID, mbArtistCandidateLimitV2)
+func (uc *RecommendUseCase) collectFromMusicBrainzArtist(ctx context.Context, artistMBID string, seedTrack *domain.Track) ([]domain.Track, error) {
+	recordings, err := uc.musicBrainzAPI.GetArtistRecordings(ctx, artistMBID, uc.opts.MusicBrainzCandidates)
 	if err != nil {
-		logger.Warning("RecommendV2", "MusicBrainzアーティスト曲取得エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "MusicBrainzアーティスト曲取得エラー: "+err.Error())
+		return nil, err
 	}
 	if len(recordings) == 0 {
-		logger.Info("RecommendV2", "MusicBrainz: アーティストの曲が見つかりませんでした")
-		return nil
+		logger.InfoContext(ctx, "RecommendV2", "MusicBrainz: アーティストの曲が見つかりませんでした")
+		return nil, nil
 	}
 
 	candidates := make([]domain.Track, 0, len(recordings))
@@ -557,13 +766,13 @@ func (uc *RecommendUseCase) collectFromMusicBrainzArtist(ctx context.Context, ar
 			ISRC: &isrc,
 		})
 	}
-	return candidates
+	return candidates, nil
 }
 
 // collectFromYouTubeMusic collects candidates from YouTube Music similar tracks.
-func (uc *RecommendUseCase) collectFromYouTubeMusic(ctx context.Context, seedTrack *domain.Track) []domain.Track {
+func (uc *RecommendUseCase) collectFromYouTubeMusic(ctx context.Context, seedTrack *domain.Track) ([]domain.Track, error) {
 	if uc.ytmusicAPI == nil {
-		return nil
+		return nil, nil
 	}
 
 	// First, search for the seed track on YouTube Music to get video ID
@@ -572,33 +781,33 @@ func (uc *RecommendUseCase) collectFromYouTubeMusic(ctx context.Context, seedTra
 		artistName = seedTrack.Artists[0].Name
 	}
 	if artistName == "" {
-		logger.Warning("RecommendV2", "YouTube Music: アーティスト名が不明")
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "YouTube Music: アーティスト名が不明")
+		return nil, nil
 	}
 
 	query := fmt.Sprintf("%s %s", artistName, seedTrack.Name)
 	searchResults, err := uc.ytmusicAPI.SearchTracks(ctx, query, 1)
 	if err != nil {
-		logger.Warning("RecommendV2", "YouTube Music検索エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "YouTube Music検索エラー: "+err.Error())
+		return nil, err
 	}
 	if len(searchResults) == 0 {
-		logger.Warning("RecommendV2", "YouTube Music: 曲が見つかりません")
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "YouTube Music: 曲が見つかりません")
+		return nil, nil
 	}
 
 	videoID := searchResults[0].VideoID
-	logger.Debug("RecommendV2", fmt.Sprintf("YouTube Music: found video ID=%s for seed track", videoID))
+	logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("YouTube Music: found video ID=%s for seed track", videoID))
 
 	// Get similar tracks
-	similarTracks, err := uc.ytmusicAPI.GetSimilarTracks(ctx, videoID, ytmusicCandidateLimitV2)
+	similarTracks, err := uc.ytmusicAPI.GetSimilarTracks(ctx, videoID, uc.opts.YTMusicCandidates)
 	if err != nil {
-		logger.Warning("RecommendV2", "YouTube Music類似曲取得エラー: "+err.Error())
-		return nil
+		logger.WarningContext(ctx, "RecommendV2", "YouTube Music類似曲取得エラー: "+err.Error())
+		return nil, err
 	}
 	if len(similarTracks) == 0 {
-		logger.Info("RecommendV2", "YouTube Music: 類似曲が見つかりませんでした")
-		return nil
+		logger.InfoContext(ctx, "RecommendV2", "YouTube Music: 類似曲が見つかりませんでした")
+		return nil, nil
 	}
 
 	// Convert to domain.Track (will be enriched via Spotify name search later)
@@ -612,7 +821,7 @@ func (uc *RecommendUseCase) collectFromYouTubeMusic(ctx context.Context, seedTra
 			},
 		})
 	}
-	return candidates
+	return candidates, nil
 }
 
 // enrichCandidatesParallel fetches Spotify track details and Deezer features in parallel.
@@ -644,20 +853,16 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 	enrichedTracks := make(map[string]*domain.Track)   // ISRC -> Track
 	features := make(map[string]*domain.TrackFeatures) // ISRC -> Features (temporary)
 	var mu sync.Mutex
-	var wg sync.WaitGroup
+	g := safego.NewGroup("RecommendV2")
 
 	// 1. Fetch Spotify tracks by ISRC (parallel with semaphore)
 	if len(isrcs) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			sem := make(chan struct{}, spotifyConcurrency)
-			var innerWg sync.WaitGroup
+		g.Go("Spotify ISRC", func() {
+			sem := make(chan struct{}, uc.opts.SpotifyConcurrency)
+			inner := safego.NewGroup("RecommendV2")
 
 			for _, isrc := range isrcs {
-				innerWg.Add(1)
-				go func(isrc string) {
-					defer innerWg.Done()
+				inner.Go("Spotify ISRC", func() {
 					sem <- struct{}{}
 					defer func() { <-sem }()
 
@@ -665,34 +870,38 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 					if err != nil || track == nil {
 						return
 					}
+					if !track.Playable() {
+						logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("指定地域で再生できないため除外: %s", isrc))
+						return
+					}
 
 					mu.Lock()
 					enrichedTracks[isrc] = track
 					mu.Unlock()
-				}(isrc)
+				})
 			}
-			innerWg.Wait()
-		}()
+			inner.Wait()
+		})
 	}
 
 	// 2. Fetch Spotify tracks by name (Last.fm candidates)
 	if len(nameCandidates) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			sem := make(chan struct{}, spotifyConcurrency)
-			var innerWg sync.WaitGroup
-
-			for _, c := range nameCandidates {
-				innerWg.Add(1)
-				go func(candidate domain.Track) {
-					defer innerWg.Done()
+		g.Go("Spotify search", func() {
+			sem := make(chan struct{}, uc.opts.SpotifyConcurrency)
+			inner := safego.NewGroup("RecommendV2")
+
+			for _, candidate := range nameCandidates {
+				inner.Go("Spotify search", func() {
 					sem <- struct{}{}
 					defer func() { <-sem }()
 
 					track := uc.searchSpotifyWithFallback(ctx, candidate.Name, candidate.Artists[0].Name)
 					if track == nil {
-						logger.Debug("RecommendV2", fmt.Sprintf("Spotifyで見つかりませんでした: %s - %s", candidate.Artists[0].Name, candidate.Name))
+						logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("Spotifyで見つかりませんでした: %s - %s", candidate.Artists[0].Name, candidate.Name))
+						return
+					}
+					if !track.Playable() {
+						logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("指定地域で再生できないため除外: %s - %s", candidate.Artists[0].Name, candidate.Name))
 						return
 					}
 					// Use the found track's ISRC as key
@@ -701,37 +910,27 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 						enrichedTracks[*track.ISRC] = track
 						mu.Unlock()
 					}
-				}(c)
+				})
 			}
-			innerWg.Wait()
-		}()
+			inner.Wait()
+		})
 	}
 
 	// 3. Fetch Deezer features (parallel batch) - only for ISRC candidates
+	stored := loadStoredFeatures(ctx, uc.featureStore, "RecommendV2", isrcs)
 	if len(isrcs) > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			deezerTracks, err := uc.deezerAPI.GetTracksByISRCBatch(ctx, isrcs)
-			if err != nil {
-				logger.Warning("RecommendV2", "Deezerバッチ取得エラー: "+err.Error())
-				return
-			}
+		g.Go("Deezer", func() {
+			deezerFeatures := uc.getDeezerFeatures(ctx, isrcs, stored)
 
 			mu.Lock()
-			for isrc, dt := range deezerTracks {
-				features[isrc] = &domain.TrackFeatures{
-					ISRC:            isrc,
-					BPM:             dt.BPM,
-					DurationSeconds: dt.DurationSeconds,
-					Gain:            dt.Gain,
-				}
+			for isrc, f := range deezerFeatures {
+				features[isrc] = f
 			}
 			mu.Unlock()
-		}()
+		})
 	}
 
-	wg.Wait()
+	g.Wait()
 
 	// Also fetch Deezer features for Last.fm candidates that were resolved
 	if len(nameCandidates) > 0 {
@@ -750,16 +949,11 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 		}
 
 		if len(resolvedISRCs) > 0 {
-			deezerTracks, err := uc.deezerAPI.GetTracksByISRCBatch(ctx, resolvedISRCs)
-			if err == nil {
-				for isrc, dt := range deezerTracks {
-					features[isrc] = &domain.TrackFeatures{
-						ISRC:            isrc,
-						BPM:             dt.BPM,
-						DurationSeconds: dt.DurationSeconds,
-						Gain:            dt.Gain,
-					}
-				}
+			for isrc, sf := range loadStoredFeatures(ctx, uc.featureStore, "RecommendV2", resolvedISRCs) {
+				stored[isrc] = sf
+			}
+			for isrc, f := range uc.getDeezerFeatures(ctx, resolvedISRCs, stored) {
+				features[isrc] = f
 			}
 		}
 	}
@@ -767,6 +961,8 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 	// Build final results - match Spotify tracks with Deezer features
 	result := make([]domain.Track, 0, len(enrichedTracks))
 	finalFeatures := make(map[string]*domain.TrackFeatures)
+	var missingTags []string
+	now := time.Now()
 
 	for isrc, track := range enrichedTracks {
 		result = append(result, *track)
@@ -776,30 +972,100 @@ func (uc *RecommendUseCase) enrichCandidatesParallel(
 			f.TrackID = track.ID
 			finalFeatures[track.ID] = f
 
-			// Use Spotify artist genres as tags (faster than MusicBrainz)
-			if len(track.Artists) > 0 {
-				genres, err := uc.spotifyAPI.GetArtistGenres(ctx, track.Artists[0].ID)
-				if err == nil && len(genres) > 0 {
-					f.Tags = genres
+			// MusicBrainz tags come only from the store; the worker fills in missing ones
+			var mbTags []string
+			sf := stored[isrc]
+			if !uc.policy.IsStale(domain.FeatureSourceMusicBrainz, musicBrainzMeta(sf), now) {
+				if sf.MusicBrainz.Found {
+					mbTags = sf.MusicBrainz.Tags
+					f.ArtistMBID = sf.MusicBrainz.ArtistMBID
 				}
+			} else {
+				missingTags = append(missingTags, isrc)
+			}
+
+			// Use Spotify artist genres as tags (faster than MusicBrainz)
+			genres := uc.getArtistGenres(ctx, track, sf)
+			if tags := uc.mergeTags(mbTags, genres); len(tags) > 0 {
+				f.Tags = tags
 			}
 		}
 	}
 
+	if uc.featureWorker != nil && len(missingTags) > 0 {
+		uc.featureWorker.Enqueue(missingTags...)
+	}
+
 	return result, finalFeatures
 }
 
+// getDeezerFeatures returns Deezer features for the given ISRCs, keyed by ISRC.
+// Fresh stored groups are reused; the rest are fetched in one batch and saved,
+// including the ISRCs Deezer does not know. Those are omitted from the result.
+func (uc *RecommendUseCase) getDeezerFeatures(
+	ctx context.Context,
+	isrcs []string,
+	stored map[string]*domain.StoredFeatures,
+) map[string]*domain.TrackFeatures {
+	features := make(map[string]*domain.TrackFeatures, len(isrcs))
+	now := time.Now()
+
+	missing := make([]string, 0, len(isrcs))
+	for _, isrc := range isrcs {
+		sf := stored[isrc]
+		if uc.policy.IsStale(domain.FeatureSourceDeezer, deezerMeta(sf), now) {
+			missing = append(missing, isrc)
+			continue
+		}
+		if sf.Deezer.Found {
+			features[isrc] = &domain.TrackFeatures{
+				ISRC:            isrc,
+				BPM:             sf.Deezer.BPM,
+				DurationSeconds: sf.Deezer.DurationSeconds,
+				Gain:            sf.Deezer.Gain,
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return features
+	}
+
+	deezerTracks, err := uc.deezerAPI.GetTracksByISRCBatch(ctx, missing)
+	if err != nil {
+		logger.WarningContext(ctx, "RecommendV2", "Deezerバッチ取得エラー: "+err.Error())
+		return features
+	}
+
+	for isrc, dt := range deezerTracks {
+		if dt != nil {
+			features[isrc] = &domain.TrackFeatures{
+				ISRC:            isrc,
+				BPM:             dt.BPM,
+				DurationSeconds: dt.DurationSeconds,
+				Gain:            dt.Gain,
+			}
+		}
+		saveStoredFeatures(ctx, uc.featureStore, "RecommendV2", &domain.StoredFeatures{
+			ISRC:   isrc,
+			Deezer: newDeezerGroup(dt, now),
+		})
+	}
+	return features
+}
+
 // filterByGenre removes candidates with unrelated genres to improve recommendation quality.
 // Keeps candidates where genre bonus >= 1.0 (exact match, same group, or related).
+// It stops early when ctx is done, keeping the candidates checked so far.
 func (uc *RecommendUseCase) filterByGenre(
+	ctx context.Context,
 	candidates []domain.Track,
 	features map[string]*domain.TrackFeatures,
 	seedGenres []string,
 ) ([]domain.Track, map[string]*domain.TrackFeatures) {
 	if len(seedGenres) == 0 {
-		// No seed genres to filter by, return as-is but limit to maxCandidatesV2
-		if len(candidates) > maxCandidatesV2 {
-			return candidates[:maxCandidatesV2], features
+		// No seed genres to filter by, return as-is but limit to opts.MaxCandidates
+		if len(candidates) > uc.opts.MaxCandidates {
+			return candidates[:uc.opts.MaxCandidates], features
 		}
 		return candidates, features
 	}
@@ -808,8 +1074,12 @@ func (uc *RecommendUseCase) filterByGenre(
 	filteredFeatures := make(map[string]*domain.TrackFeatures)
 
 	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
 		f := features[c.ID]
 		if f == nil {
+			metrics.RecommendGenreFiltered.Inc("no_features")
 			continue
 		}
 
@@ -820,12 +1090,13 @@ func (uc *RecommendUseCase) filterByGenre(
 			filtered = append(filtered, c)
 			filteredFeatures[c.ID] = f
 		} else {
+			metrics.RecommendGenreFiltered.Inc("genre_mismatch")
 			// Log filtered out candidates for debugging
-			logger.Debug("RecommendV2", fmt.Sprintf("ジャンルフィルタで除外: %s (bonus=%.2f, genres=%v)", c.Name, bonus, f.Tags))
+			logger.DebugContext(ctx, "RecommendV2", fmt.Sprintf("ジャンルフィルタで除外: %s (bonus=%.2f, genres=%v)", c.Name, bonus, f.Tags))
 		}
 
 		// Stop if we have enough candidates
-		if len(filtered) >= maxCandidatesV2 {
+		if len(filtered) >= uc.opts.MaxCandidates {
 			break
 		}
 	}
@@ -858,20 +1129,21 @@ func (uc *RecommendUseCase) getCandidateFeatures(
 		return features, artistInfos
 	}
 
-	var wg sync.WaitGroup
+	g := safego.NewGroup("RecommendV2")
 	var mu sync.Mutex
 
 	// Get Deezer features (batch)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	g.Go("Deezer", func() {
 		deezerTracks, err := uc.deezerAPI.GetTracksByISRCBatch(ctx, isrcs)
 		if err != nil {
-			logger.Warning("RecommendV2", "Deezerバッチ取得エラー: "+err.Error())
+			logger.WarningContext(ctx, "RecommendV2", "Deezerバッチ取得エラー: "+err.Error())
 			return
 		}
 		mu.Lock()
 		for isrc, dt := range deezerTracks {
+			if dt == nil {
+				continue
+			}
 			trackID := isrcToID[isrc]
 			if features[trackID] == nil {
 				features[trackID] = &domain.TrackFeatures{TrackID: trackID, ISRC: isrc}
@@ -881,19 +1153,20 @@ func (uc *RecommendUseCase) getCandidateFeatures(
 			features[trackID].Gain = dt.Gain
 		}
 		mu.Unlock()
-	}()
+	})
 
 	// Get MusicBrainz features (batch - sequential due to rate limit)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	g.Go("MusicBrainz", func() {
 		recordings, err := uc.musicBrainzAPI.GetRecordingsByISRCBatch(ctx, isrcs)
 		if err != nil {
-			logger.Warning("RecommendV2", "MusicBrainzバッチ取得エラー: "+err.Error())
+			logger.WarningContext(ctx, "RecommendV2", "MusicBrainzバッチ取得エラー: "+err.Error())
 			return
 		}
 		mu.Lock()
 		for isrc, rec := range recordings {
+			if rec == nil {
+				continue
+			}
 			trackID := isrcToID[isrc]
 			if features[trackID] == nil {
 				features[trackID] = &domain.TrackFeatures{TrackID: trackID, ISRC: isrc}
@@ -906,14 +1179,16 @@ func (uc *RecommendUseCase) getCandidateFeatures(
 			features[trackID].Tags = tags
 		}
 		mu.Unlock()
-	}()
+	})
 
-	wg.Wait()
+	g.Wait()
 	return features, artistInfos
 }
 
 // calculateScores calculates similarity scores for all candidates.
+// It stops early when ctx is done, returning the scores computed so far.
 func (uc *RecommendUseCase) calculateScores(
+	ctx context.Context,
 	seedFeatures *domain.TrackFeatures,
 	seedArtistInfo *domain.ArtistInfo,
 	seedGenres []string,
@@ -924,17 +1199,13 @@ func (uc *RecommendUseCase) calculateScores(
 ) []domain.RecommendedTrack {
 	recommendedTracks := make([]domain.RecommendedTrack, 0, len(candidates))
 
-	// Extract seed artist IDs for same-artist detection
-	seedArtistIDs := make(map[string]bool)
-	seedArtistNames := make(map[string]bool)
-	if seedTrack != nil {
-		for _, a := range seedTrack.Artists {
-			seedArtistIDs[a.ID] = true
-			seedArtistNames[strings.ToLower(a.Name)] = true
-		}
-	}
+	// Extract seed artists for same-artist detection
+	seedArtists := newArtistSet(seedTrack)
 
 	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
 		candidateFeature := candidateFeatures[candidate.ID]
 		candidateArtist := candidateArtistInfos[candidate.ID]
 
@@ -961,12 +1232,9 @@ func (uc *RecommendUseCase) calculateScores(
 
 		// Same artist bonus (strong boost)
 		sameArtistBonus := 1.0
-		for _, a := range candidate.Artists {
-			if seedArtistIDs[a.ID] || seedArtistNames[strings.ToLower(a.Name)] {
-				sameArtistBonus = 2.5 // Strong bonus for same artist
-				matchReasons = append(matchReasons, "same_artist")
-				break
-			}
+		if seedArtists.matches(&candidate) {
+			sameArtistBonus = sameArtistMultiplier
+			matchReasons = append(matchReasons, "same_artist")
 		}
 
 		// Series/franchise bonus (detect related works)
@@ -992,8 +1260,51 @@ func (uc *RecommendUseCase) calculateScores(
 	return recommendedTracks
 }
 
+// sameArtistMultiplier is the strong bonus of a candidate sharing an artist with the seed.
+const sameArtistMultiplier = 2.5
+
+// artistSet holds a track's artists for same-artist detection.
+type artistSet struct {
+	ids   map[string]bool
+	names map[string]bool // Lowercased
+}
+
+// newArtistSet returns the artists of t; t may be nil.
+func newArtistSet(t *domain.Track) artistSet {
+	s := artistSet{ids: make(map[string]bool), names: make(map[string]bool)}
+	if t == nil {
+		return s
+	}
+	for _, a := range t.Artists {
+		s.ids[a.ID] = true
+		s.names[strings.ToLower(a.Name)] = true
+	}
+	return s
+}
+
+// matches reports whether any artist of t is in s, by ID or name.
+func (s artistSet) matches(t *domain.Track) bool {
+	for _, a := range t.Artists {
+		if s.ids[a.ID] || s.names[strings.ToLower(a.Name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesMultiplier is the bonus of a candidate from the same series/franchise as the seed.
+const seriesMultiplier = 2.0
+
 // detectSeriesMatch detects if two tracks belong to the same series/franchise.
 func (uc *RecommendUseCase) detectSeriesMatch(seedName, candidateName string, reasons []string) (float64, []string) {
+	if series := matchSeries(seedName, candidateName); series != "" {
+		return seriesMultiplier, append(reasons, "same_series:"+series)
+	}
+	return 1.0, reasons
+}
+
+// matchSeries returns the series/franchise both track names belong to, or "" if none.
+func matchSeries(seedName, candidateName string) string {
 	seedLower := strings.ToLower(seedName)
 	candidateLower := strings.ToLower(candidateName)
 
@@ -1046,19 +1357,19 @@ func (uc *RecommendUseCase) detectSeriesMatch(seedName, candidateName string, re
 		}
 
 		if seedMatch && candidateMatch {
-			return 2.0, append(reasons, "same_series:"+fp.name)
+			return fp.name
 		}
 	}
 
-	return 1.0, reasons
+	return ""
 }
 
 // searchSpotifyWithFallback searches Spotify for a track with multiple fallback strategies.
 // It tries progressively simpler queries if exact search fails.
 func (uc *RecommendUseCase) searchSpotifyWithFallback(ctx context.Context, trackName, artistName string) *domain.Track {
 	// Strategy 1: Exact search with track: and artist: filters
-	sanitizedTrack := sanitizeSearchQuery(trackName)
-	sanitizedArtist := sanitizeSearchQuery(artistName)
+	sanitizedTrack := usecase.SanitizeSearchQuery(trackName)
+	sanitizedArtist := usecase.SanitizeSearchQuery(artistName)
 
 	query := fmt.Sprintf("track:%s artist:%s", sanitizedTrack, sanitizedArtist)
 	tracks, err := uc.spotifyAPI.SearchTracks(ctx, query)
@@ -1067,7 +1378,7 @@ func (uc *RecommendUseCase) searchSpotifyWithFallback(ctx context.Context, track
 	}
 
 	// Strategy 2: Simplified track name (remove parentheses, brackets content)
-	simplifiedTrack := simplifyTrackName(trackName)
+	simplifiedTrack := usecase.SimplifyTrackName(trackName)
 	if simplifiedTrack != sanitizedTrack {
 		query = fmt.Sprintf("track:%s artist:%s", simplifiedTrack, sanitizedArtist)
 		tracks, err = uc.spotifyAPI.SearchTracks(ctx, query)
@@ -1082,7 +1393,7 @@ func (uc *RecommendUseCase) searchSpotifyWithFallback(ctx context.Context, track
 	if err == nil && len(tracks) > 0 {
 		// Verify the result matches the artist (fuzzy match)
 		for _, t := range tracks {
-			if len(t.Artists) > 0 && fuzzyMatchArtist(t.Artists[0].Name, artistName) {
+			if len(t.Artists) > 0 && usecase.FuzzyMatchArtist(t.Artists[0].Name, artistName) {
 				return &t
 			}
 		}
@@ -1101,60 +1412,3 @@ func (uc *RecommendUseCase) searchSpotifyWithFallback(ctx context.Context, track
 
 	return nil
 }
-
-// sanitizeSearchQuery removes special characters that may cause search issues.
-var specialCharsRegex = regexp.MustCompile(`[～〜「」『』【】（）()[\]<>《》、。・"'：:；;！!？?＆&＃#＄$％%＠@＊*＋+＝=｜|＼\\／/]`)
-
-func sanitizeSearchQuery(s string) string {
-	// Remove special characters
-	s = specialCharsRegex.ReplaceAllString(s, " ")
-	// Collapse multiple spaces
-	s = regexp.MustCompile(`\s+`).ReplaceAllString(s, " ")
-	// Trim
-	return strings.TrimSpace(s)
-}
-
-// simplifyTrackName removes common suffixes like (feat. ...), [Remix], etc.
-var subtitlePatterns = []*regexp.Regexp{
-	regexp.MustCompile(`\s*[\(（【\[].+$`),               // Remove everything after opening bracket
-	regexp.MustCompile(`\s*[-－ー]\s*.+$`),               // Remove everything after dash (common in Japanese titles)
-	regexp.MustCompile(`(?i)\s*(feat\.?|ft\.?).+$`),    // Remove feat. and everything after
-	regexp.MustCompile(`(?i)\s*(remix|ver\.|version)`), // Remove remix/version indicators
-}
-
-func simplifyTrackName(name string) string {
-	result := name
-	for _, pattern := range subtitlePatterns {
-		simplified := pattern.ReplaceAllString(result, "")
-		if simplified != "" && len(simplified) >= 3 {
-			result = simplified
-		}
-	}
-	return sanitizeSearchQuery(result)
-}
-
-// fuzzyMatchArtist checks if two artist names are similar.
-func fuzzyMatchArtist(a, b string) bool {
-	a = strings.ToLower(strings.TrimSpace(a))
-	b = strings.ToLower(strings.TrimSpace(b))
-
-	if a == b {
-		return true
-	}
-
-	// Check if one contains the other
-	if strings.Contains(a, b) || strings.Contains(b, a) {
-		return true
-	}
-
-	// Remove common suffixes/prefixes for comparison
-	normalize := func(s string) string {
-		s = strings.ReplaceAll(s, "the ", "")
-		s = strings.ReplaceAll(s, " the", "")
-		s = strings.ReplaceAll(s, "&", "and")
-		s = strings.ReplaceAll(s, "＆", "and")
-		return strings.TrimSpace(s)
-	}
-
-	return normalize(a) == normalize(b)
-}
@@ -2,10 +2,18 @@ package v2
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/t1nyb0x/tracktaste/internal/domain"
 	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/util/tracing"
 )
 
 // Mock implementations for testing
@@ -21,6 +29,10 @@ func (m *mockDeezerAPI) GetTrackByISRC(ctx context.Context, isrc string) (*domai
 	return nil, domain.ErrNotFound
 }
 
+func (m *mockDeezerAPI) GetTrackByID(ctx context.Context, id string) (*domain.DeezerTrack, error) {
+	return nil, domain.ErrNotFound
+}
+
 func (m *mockDeezerAPI) SearchTrack(ctx context.Context, title, artist string) (*domain.DeezerTrack, error) {
 	return nil, domain.ErrNotFound
 }
@@ -28,9 +40,7 @@ func (m *mockDeezerAPI) SearchTrack(ctx context.Context, title, artist string) (
 func (m *mockDeezerAPI) GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error) {
 	result := make(map[string]*domain.DeezerTrack)
 	for _, isrc := range isrcs {
-		if track, ok := m.tracks[isrc]; ok {
-			result[isrc] = track
-		}
+		result[isrc] = m.tracks[isrc]
 	}
 	return result, nil
 }
@@ -61,9 +71,7 @@ func (m *mockMusicBrainzAPI) GetArtistWithRelations(ctx context.Context, mbid st
 func (m *mockMusicBrainzAPI) GetRecordingsByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.MBRecording, error) {
 	result := make(map[string]*domain.MBRecording)
 	for _, isrc := range isrcs {
-		if rec, ok := m.recordings[isrc]; ok {
-			result[isrc] = rec
-		}
+		result[isrc] = m.recordings[isrc]
 	}
 	return result, nil
 }
@@ -76,6 +84,7 @@ type mockSpotifyAPI struct {
 	tracks       map[string]*domain.Track
 	tracksByISRC map[string]*domain.Track
 	artists      map[string][]string
+	playlists    map[string]*domain.Playlist
 }
 
 func (m *mockSpotifyAPI) GetTrackByID(ctx context.Context, id string) (*domain.Track, error) {
@@ -106,6 +115,17 @@ func (m *mockSpotifyAPI) SearchByISRC(ctx context.Context, isrc string) (*domain
 	return nil, domain.ErrNotFound
 }
 
+func (m *mockSpotifyAPI) SearchAlbumByUPC(ctx context.Context, upc string) (*domain.Album, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (m *mockSpotifyAPI) GetPlaylist(ctx context.Context, id string, limit int) (*domain.Playlist, error) {
+	if playlist, ok := m.playlists[id]; ok {
+		return playlist, nil
+	}
+	return nil, domain.ErrPlaylistNotFound
+}
+
 func (m *mockSpotifyAPI) GetAudioFeatures(ctx context.Context, trackID string) (*domain.AudioFeatures, error) {
 	return nil, domain.ErrNotFound
 }
@@ -479,148 +499,15 @@ func TestRecommendUseCase_GetRecommendations_KKBOXTrackNotFound(t *testing.T) {
 	}
 }
 
-func TestSanitizeSearchQuery(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  string
-	}{
-		{
-			name:  "removes Japanese brackets",
-			input: "聖戦と死神 第1部「銀色の死神」",
-			want:  "聖戦と死神 第1部 銀色の死神",
-		},
-		{
-			name:  "removes parentheses",
-			input: "Track (Remix)",
-			want:  "Track Remix",
-		},
-		{
-			name:  "removes special symbols",
-			input: "Track～Version",
-			want:  "Track Version",
-		},
-		{
-			name:  "collapses multiple spaces",
-			input: "Track    Name",
-			want:  "Track Name",
-		},
-		{
-			name:  "preserves normal text",
-			input: "Normal Track Name",
-			want:  "Normal Track Name",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := sanitizeSearchQuery(tt.input)
-			if got != tt.want {
-				t.Errorf("sanitizeSearchQuery(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestSimplifyTrackName(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  string
-	}{
-		{
-			name:  "removes parenthesized suffix",
-			input: "Track Name (feat. Artist)",
-			want:  "Track Name",
-		},
-		{
-			name:  "removes Japanese bracketed suffix",
-			input: "Chronicle 2nd 聖戦と死神 第1部「銀色の死神」 ～戦場を駈ける者～",
-			want:  "Chronicle 2nd 聖戦と死神 第1部 銀色の死神 戦場を駈ける者",
-		},
-		{
-			name:  "removes remix indicator",
-			input: "Track Name - Remix Version",
-			want:  "Track Name",
-		},
-		{
-			name:  "preserves short names",
-			input: "AB",
-			want:  "AB",
-		},
-		{
-			name:  "handles normal names",
-			input: "Normal Track",
-			want:  "Normal Track",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := simplifyTrackName(tt.input)
-			if got != tt.want {
-				t.Errorf("simplifyTrackName(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestFuzzyMatchArtist(t *testing.T) {
-	tests := []struct {
-		name string
-		a    string
-		b    string
-		want bool
-	}{
-		{
-			name: "exact match",
-			a:    "Artist Name",
-			b:    "Artist Name",
-			want: true,
-		},
-		{
-			name: "case insensitive",
-			a:    "ARTIST NAME",
-			b:    "artist name",
-			want: true,
-		},
-		{
-			name: "one contains other",
-			a:    "The Artist",
-			b:    "Artist",
-			want: true,
-		},
-		{
-			name: "ampersand normalization",
-			a:    "Artist & Band",
-			b:    "Artist and Band",
-			want: true,
-		},
-		{
-			name: "different artists",
-			a:    "Artist A",
-			b:    "Artist B",
-			want: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := fuzzyMatchArtist(tt.a, tt.b)
-			if got != tt.want {
-				t.Errorf("fuzzyMatchArtist(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
-			}
-		})
-	}
-}
-
 // Mock for Last.fm API
 type mockLastFMAPI struct {
 	similarTracks []domain.LastFMTrack
 	err           error
+	lastLimit     int
 }
 
 func (m *mockLastFMAPI) GetSimilarTracks(ctx context.Context, artist, track string, limit int) ([]domain.LastFMTrack, error) {
+	m.lastLimit = limit
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -653,6 +540,10 @@ func (m *mockYTMusicAPI) GetSimilarTracks(ctx context.Context, videoID string, l
 	return m.similarTracks, nil
 }
 
+func (m *mockYTMusicAPI) GetTrack(ctx context.Context, videoID string) (*domain.YTMusicTrack, error) {
+	return nil, domain.ErrTrackNotFound
+}
+
 func TestNewRecommendUseCaseWithLastFM(t *testing.T) {
 	spotifyAPI := &mockSpotifyAPI{}
 	kkboxAPI := &mockKKBOXAPI{}
@@ -744,6 +635,73 @@ func TestRecommendUseCase_CollectFromLastFM(t *testing.T) {
 	}
 }
 
+func TestRecommendUseCase_WithOptions(t *testing.T) {
+	isrc := "JPAB12345678"
+	trackID := "spotify-track-123"
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			trackID: {ID: trackID, Name: "Test Track", ISRC: &isrc, Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}}},
+		},
+	}
+	lastfmAPI := &mockLastFMAPI{}
+
+	opts := DefaultOptions()
+	opts.LastFMCandidates = 7
+	opts.MaxResults = 5
+	uc := NewRecommendUseCaseWithLastFM(spotifyAPI, &mockKKBOXAPI{returnNilOnMiss: true}, &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}},
+		&mockMusicBrainzAPI{}, lastfmAPI).WithOptions(opts)
+
+	if _, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastfmAPI.lastLimit != 7 {
+		t.Errorf("Last.fm limit = %d, want 7", lastfmAPI.lastLimit)
+	}
+
+	opts.Weights = map[domain.RecommendMode]FeatureWeights{domain.RecommendModeRelated: {BPM: 9}}
+	uc.WithOptions(opts)
+	if got := uc.opts.weightsFor(domain.RecommendModeRelated); got.BPM != 9 {
+		t.Errorf("configured weights not used, got %+v", got)
+	}
+	if got := uc.opts.weightsFor(domain.RecommendModeSimilar); got != WeightsForMode(domain.RecommendModeSimilar) {
+		t.Errorf("unconfigured mode should use WeightsForMode, got %+v", got)
+	}
+}
+
+func TestRecommendUseCase_UpdateOptions(t *testing.T) {
+	isrc := "JPAB12345678"
+	trackID := "spotify-track-123"
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			trackID: {ID: trackID, Name: "Test Track", ISRC: &isrc, Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}}},
+		},
+	}
+	lastfmAPI := &mockLastFMAPI{}
+	uc := NewRecommendUseCaseWithLastFM(spotifyAPI, &mockKKBOXAPI{returnNilOnMiss: true}, &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}},
+		&mockMusicBrainzAPI{}, lastfmAPI)
+
+	opts := DefaultOptions()
+	opts.DisabledSources = map[string]bool{domain.SourceLastFM: true}
+	uc.UpdateOptions(opts)
+
+	if _, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastfmAPI.lastLimit != 0 {
+		t.Error("disabled source should not be called")
+	}
+
+	// Re-enabling takes effect on the next request
+	opts.DisabledSources = nil
+	uc.UpdateOptions(opts)
+	if _, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastfmAPI.lastLimit != opts.LastFMCandidates {
+		t.Errorf("Last.fm limit = %d, want %d", lastfmAPI.lastLimit, opts.LastFMCandidates)
+	}
+}
+
 func TestRecommendUseCase_CollectFromLastFM_NoArtist(t *testing.T) {
 	isrc := "JPAB12345678"
 	trackID := "spotify-track-123"
@@ -981,6 +939,174 @@ func TestRecommendUseCase_CollectFromYouTubeMusic_SimilarError(t *testing.T) {
 	}
 }
 
+func TestRecommendUseCase_GetRecommendations_DegradedSources(t *testing.T) {
+	isrc := "JPAB12345678"
+	trackID := "spotify-track-123"
+
+	tests := []struct {
+		name       string
+		lastfmErr  error
+		ytmusicErr error
+		want       []string
+	}{
+		{
+			name: "正常系: 全ソース成功",
+			want: nil,
+		},
+		{
+			name:       "正常系: 見つからないだけなら縮退扱いしない",
+			lastfmErr:  domain.ErrNotFound,
+			ytmusicErr: domain.ErrNotFound,
+			want:       nil,
+		},
+		{
+			name:       "異常系: サーキットブレーカー作動中とAPIエラー",
+			lastfmErr:  fmt.Errorf("lastfm: %w", domain.ErrUpstreamUnavailable),
+			ytmusicErr: errors.New("sidecar timeout"),
+			want:       []string{domain.SourceLastFM, domain.SourceYouTubeMusic},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spotifyAPI := &mockSpotifyAPI{
+				tracks: map[string]*domain.Track{
+					trackID: {
+						ID:      trackID,
+						Name:    "Test Track",
+						ISRC:    &isrc,
+						Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}},
+					},
+				},
+			}
+			kkboxAPI := &mockKKBOXAPI{returnNilOnMiss: true}
+			deezerAPI := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}}
+			mbAPI := &mockMusicBrainzAPI{}
+			lastfmAPI := &mockLastFMAPI{err: tt.lastfmErr}
+			ytmusicAPI := &mockYTMusicAPI{searchErr: tt.ytmusicErr}
+
+			uc := NewRecommendUseCaseFull(spotifyAPI, kkboxAPI, deezerAPI, mbAPI, lastfmAPI, ytmusicAPI)
+
+			result, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.DegradedSources, tt.want) {
+				t.Errorf("DegradedSources = %v, want %v", result.DegradedSources, tt.want)
+			}
+		})
+	}
+}
+
+// blockingLastFMAPI never answers before ctx is done.
+type blockingLastFMAPI struct{}
+
+func (blockingLastFMAPI) GetSimilarTracks(ctx context.Context, artist, track string, limit int) ([]domain.LastFMTrack, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingLastFMAPI) GetSimilarTracksByMBID(ctx context.Context, mbid string, limit int) ([]domain.LastFMTrack, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRecommendUseCase_GetRecommendations_Partial(t *testing.T) {
+	isrc := "JPAB12345678"
+	candidateISRC := "JPAB00000001"
+	trackID := "spotify-track-123"
+
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			trackID: {
+				ID:      trackID,
+				Name:    "Test Track",
+				ISRC:    &isrc,
+				Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}},
+			},
+		},
+	}
+	kkboxAPI := &mockKKBOXAPI{
+		tracks: map[string]*external.KKBOXTrackInfo{
+			isrc: {ID: "kkbox-1", Name: "Test Track", ISRC: isrc},
+		},
+		recommended: []external.KKBOXTrackInfo{
+			{ID: "kkbox-2", Name: "Candidate", ISRC: candidateISRC},
+		},
+	}
+	deezerAPI := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}}
+	mbAPI := &mockMusicBrainzAPI{}
+
+	uc := NewRecommendUseCaseWithLastFM(spotifyAPI, kkboxAPI, deezerAPI, mbAPI, blockingLastFMAPI{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := uc.GetRecommendations(ctx, trackID, domain.RecommendModeBalanced, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Collection gives up on Last.fm at the end of its budget (40%), well before the deadline
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("GetRecommendations took %v, should stop waiting at the collect budget", elapsed)
+	}
+	if !result.Partial {
+		t.Error("result should be partial")
+	}
+	if !reflect.DeepEqual(result.PartialStages, []domain.PipelineStage{domain.StageCollect}) {
+		t.Errorf("PartialStages = %v, want [collect]", result.PartialStages)
+	}
+}
+
+// panickingYTMusicAPI panics like a gateway decoding a malformed payload.
+type panickingYTMusicAPI struct{}
+
+func (panickingYTMusicAPI) SearchTracks(ctx context.Context, query string, limit int) ([]domain.YTMusicTrack, error) {
+	var artists []*domain.Artist
+	_ = artists[0].Name
+	return nil, nil
+}
+
+func (panickingYTMusicAPI) GetSimilarTracks(ctx context.Context, videoID string, limit int) ([]domain.YTMusicTrack, error) {
+	return nil, nil
+}
+
+func (panickingYTMusicAPI) GetTrack(ctx context.Context, videoID string) (*domain.YTMusicTrack, error) {
+	return nil, nil
+}
+
+func TestRecommendUseCase_GetRecommendations_SourcePanic(t *testing.T) {
+	isrc := "JPAB12345678"
+	trackID := "spotify-track-123"
+
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			trackID: {
+				ID:      trackID,
+				Name:    "Test Track",
+				ISRC:    &isrc,
+				Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}},
+			},
+		},
+	}
+	kkboxAPI := &mockKKBOXAPI{returnNilOnMiss: true}
+	deezerAPI := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}}
+	mbAPI := &mockMusicBrainzAPI{}
+	lastfmAPI := &mockLastFMAPI{}
+
+	uc := NewRecommendUseCaseFull(spotifyAPI, kkboxAPI, deezerAPI, mbAPI, lastfmAPI, panickingYTMusicAPI{})
+
+	result, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.DegradedSources, []string{domain.SourceYouTubeMusic}) {
+		t.Errorf("DegradedSources = %v, want [youtube_music]", result.DegradedSources)
+	}
+}
+
 func TestSearchSpotifyWithFallback(t *testing.T) {
 	isrc := "JPAB12345678"
 
@@ -1050,3 +1176,47 @@ type mockSpotifyAPIWithSearch struct {
 func (m *mockSpotifyAPIWithSearch) SearchTracks(ctx context.Context, query string) ([]domain.Track, error) {
 	return m.searchResults, nil
 }
+
+func TestRecommendUseCase_GetRecommendations_Tracing(t *testing.T) {
+	exporter, restore := tracing.SetupInMemory()
+	defer restore()
+
+	isrc := "JPAB12345678"
+	trackID := "spotify-track-123"
+	spotifyAPI := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			trackID: {ID: trackID, Name: "Test Track", ISRC: &isrc, Artists: []domain.Artist{{ID: "artist-1", Name: "Test Artist"}}},
+		},
+	}
+	uc := NewRecommendUseCaseFull(spotifyAPI, &mockKKBOXAPI{returnNilOnMiss: true}, &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{}},
+		&mockMusicBrainzAPI{}, &mockLastFMAPI{}, &mockYTMusicAPI{searchErr: errors.New("sidecar timeout")})
+
+	if _, err := uc.GetRecommendations(context.Background(), trackID, domain.RecommendModeBalanced, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range exporter.GetSpans().Snapshots() {
+		spans[s.Name()] = s
+	}
+	root, ok := spans["RecommendUseCase.GetRecommendations"]
+	if !ok {
+		t.Fatalf("root span missing, got %v", reflect.ValueOf(spans).MapKeys())
+	}
+	for _, name := range []string{"recommend.seed", "recommend.collect", "recommend.collect." + domain.SourceKKBOX, "recommend.collect." + domain.SourceYouTubeMusic} {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("span %s missing", name)
+			continue
+		}
+		if s.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("span %s is not in the request trace", name)
+		}
+	}
+	if s := spans["recommend.collect."+domain.SourceYouTubeMusic]; s != nil && s.Status().Code != codes.Error {
+		t.Errorf("failed source span status = %v, want Error", s.Status().Code)
+	}
+	if s := spans["recommend.collect."+domain.SourceKKBOX]; s != nil && s.Parent().SpanID() != spans["recommend.collect"].SpanContext().SpanID() {
+		t.Errorf("source span is not a child of the collect stage")
+	}
+}
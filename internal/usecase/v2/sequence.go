@@ -0,0 +1,69 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// SequenceUseCase orders a user's tracks or a recommendation for smooth transitions.
+type SequenceUseCase struct {
+	features  *FeaturesUseCase
+	sequencer *Sequencer
+}
+
+// NewSequenceUseCase creates a new SequenceUseCase with the default weights.
+func NewSequenceUseCase(features *FeaturesUseCase) *SequenceUseCase {
+	return &SequenceUseCase{features: features, sequencer: NewSequencer(DefaultSequencerWeights())}
+}
+
+// WithWeights replaces the transition weights.
+func (uc *SequenceUseCase) WithWeights(weights SequencerWeights) *SequenceUseCase {
+	uc.sequencer = NewSequencer(weights)
+	return uc
+}
+
+// SequenceTracks orders up to MaxFeaturesBatchSize Spotify tracks. The feature sets are
+// returned in the order of trackIDs; tracks that cannot be looked up keep their error
+// there and are left out of the sequence. Index of a sequenced track is its position
+// in trackIDs. A first track that cannot be looked up is ignored.
+func (uc *SequenceUseCase) SequenceTracks(
+	ctx context.Context,
+	trackIDs []string,
+	opts domain.SequenceOptions,
+) ([]domain.TrackFeatureSet, *domain.Sequence, error) {
+	sets, err := uc.features.FetchFeaturesBatch(ctx, trackIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var inputs []domain.SequenceInput
+	var positions []int
+	for i, set := range sets {
+		if set.Err != nil || set.Track == nil {
+			continue
+		}
+		inputs = append(inputs, domain.SequenceInput{Track: *set.Track, Features: set.Features})
+		positions = append(positions, i)
+	}
+
+	seq := uc.sequencer.Sequence(inputs, opts)
+	for i := range seq.Tracks {
+		seq.Tracks[i].Index = positions[seq.Tracks[i].Index]
+	}
+	logger.InfoContext(ctx, "Sequence", fmt.Sprintf("%d 曲を並べ替え（%s）", len(inputs), opts.Arc.Name))
+	return sets, seq, nil
+}
+
+// SequenceRecommendations orders the items of a recommendation to follow the seed track,
+// which stays first. Index is 0 for the seed and i+1 for result.Items[i].
+func (uc *SequenceUseCase) SequenceRecommendations(result *domain.RecommendResult, arc domain.SequenceArc) *domain.Sequence {
+	inputs := make([]domain.SequenceInput, 0, len(result.Items)+1)
+	inputs = append(inputs, domain.SequenceInput{Track: result.SeedTrack, Features: result.SeedFeatures})
+	for _, item := range result.Items {
+		inputs = append(inputs, domain.SequenceInput{Track: item.Track, Features: item.Features})
+	}
+	return uc.sequencer.Sequence(inputs, domain.SequenceOptions{Arc: arc, FirstTrackID: result.SeedTrack.ID})
+}
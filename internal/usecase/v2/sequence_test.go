@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func TestSequenceUseCase_SequenceTracks(t *testing.T) {
+	isrcs := []string{"JPA000000001", "JPA000000002", "JPA000000003"}
+	spotify := &mockSpotifyAPI{tracks: map[string]*domain.Track{
+		"fast":   featuresTrack("fast", isrcs[0], "a1"),
+		"slow":   featuresTrack("slow", isrcs[1], "a1"),
+		"medium": featuresTrack("medium", isrcs[2], "a1"),
+	}}
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		isrcs[0]: {BPM: 150, Gain: -9},
+		isrcs[1]: {BPM: 90, Gain: -6},
+		isrcs[2]: {BPM: 120, Gain: -8},
+	}}
+	uc := NewSequenceUseCase(NewFeaturesUseCase(spotify, deezer, &mockMusicBrainzAPI{}))
+
+	sets, seq, err := uc.SequenceTracks(context.Background(), []string{"fast", "missing", "slow", "medium"},
+		domain.SequenceOptions{Arc: domain.SequenceArc{Name: domain.SequenceArcWarmUp, Points: []float64{0, 1}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sets) != 4 || !errors.Is(sets[1].Err, domain.ErrTrackNotFound) {
+		t.Fatalf("expected the missing track to keep its error, got %+v", sets)
+	}
+	if got := sequenceOrder(seq); !reflect.DeepEqual(got, []string{"slow", "medium", "fast"}) {
+		t.Errorf("unexpected order %v", got)
+	}
+	if seq.Tracks[0].Index != 2 || seq.Tracks[2].Index != 0 {
+		t.Errorf("expected indexes into the track IDs, got %d and %d", seq.Tracks[0].Index, seq.Tracks[2].Index)
+	}
+
+	if _, _, err := uc.SequenceTracks(context.Background(), make([]string, MaxFeaturesBatchSize+1), domain.SequenceOptions{}); domain.AsError(err) == nil {
+		t.Errorf("expected TOO_MANY_TRACKS, got %v", err)
+	}
+}
+
+func TestSequenceUseCase_SequenceRecommendations(t *testing.T) {
+	result := &domain.RecommendResult{
+		SeedTrack:    domain.Track{ID: "seed"},
+		SeedFeatures: &domain.TrackFeatures{BPM: 150, Gain: -9},
+		Items: []domain.RecommendedTrack{
+			{Track: domain.Track{ID: "slow"}, Features: &domain.TrackFeatures{BPM: 90, Gain: -6}},
+			{Track: domain.Track{ID: "fast"}, Features: &domain.TrackFeatures{BPM: 148, Gain: -9}},
+			{Track: domain.Track{ID: "medium"}, Features: &domain.TrackFeatures{BPM: 120, Gain: -8}},
+		},
+	}
+
+	seq := NewSequenceUseCase(nil).SequenceRecommendations(result, domain.SequenceArc{Name: domain.SequenceArcSmooth})
+	if got := sequenceOrder(seq); !reflect.DeepEqual(got, []string{"seed", "fast", "medium", "slow"}) {
+		t.Errorf("expected the seed first and a smooth descent, got %v", got)
+	}
+	if seq.Tracks[1].Index != 2 {
+		t.Errorf("expected index 2 for items[1], got %d", seq.Tracks[1].Index)
+	}
+}
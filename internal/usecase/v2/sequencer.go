@@ -0,0 +1,323 @@
+package v2
+
+import (
+	"math"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+const (
+	// sequenceBPMTolerance is the relative tempo change that costs 1 (about a DJ's pitch range).
+	sequenceBPMTolerance = 0.06
+	// sequenceGainTolerance is the loudness change (dB) that costs 1.
+	sequenceGainTolerance = 3.0
+	// sequenceArcTolerance is the distance from the arc's target energy that costs 1.
+	sequenceArcTolerance = 0.2
+	// halfTimePenalty is added when tempos only match at half or double speed.
+	halfTimePenalty = 0.25
+	// unknownFeatureCost is the cost of a transition whose feature is unknown on either side.
+	unknownFeatureCost = 0.5
+	// maxSequencePasses bounds the local search of Sequence.
+	maxSequencePasses = 20
+)
+
+// SequencerWeights weighs the costs of a transition.
+type SequencerWeights struct {
+	BPM  float64
+	Gain float64
+	Arc  float64
+}
+
+// DefaultSequencerWeights returns the default transition weights.
+func DefaultSequencerWeights() SequencerWeights {
+	return SequencerWeights{BPM: 1.0, Gain: 0.5, Arc: 1.0}
+}
+
+// Sequencer orders tracks for smooth BPM and loudness transitions along an energy arc.
+type Sequencer struct {
+	weights SequencerWeights
+}
+
+// NewSequencer creates a new Sequencer.
+func NewSequencer(weights SequencerWeights) *Sequencer {
+	return &Sequencer{weights: weights}
+}
+
+// Sequence orders inputs to minimise the total cost: the weighted BPM and gain change
+// of each transition plus how far each track's energy is from the arc at its position.
+// The order is built greedily and then improved by moving and reversing runs of tracks,
+// so it is good rather than optimal. The first track stays first if opts names one.
+func (s *Sequencer) Sequence(inputs []domain.SequenceInput, opts domain.SequenceOptions) *domain.Sequence {
+	result := &domain.Sequence{Arc: opts.Arc}
+	n := len(inputs)
+	if n == 0 {
+		return result
+	}
+
+	p := s.newProblem(inputs, opts.Arc)
+	first := -1
+	if opts.FirstTrackID != "" {
+		for i, in := range inputs {
+			if in.Track.ID == opts.FirstTrackID {
+				first = i
+				break
+			}
+		}
+	}
+
+	order := p.greedy(first)
+	p.improve(order, first >= 0)
+
+	for pos, i := range order {
+		track := domain.SequencedTrack{
+			Index:    i,
+			Track:    inputs[i].Track,
+			Features: inputs[i].Features,
+			ArcCost:  p.arcCost[i][pos],
+		}
+		if p.known[i] {
+			energy := p.energy[i]
+			track.Energy = &energy
+		}
+		if p.targets != nil {
+			target := p.targets[pos]
+			track.TargetEnergy = &target
+		}
+		result.Tracks = append(result.Tracks, track)
+	}
+	result.TotalCost = result.Tracks[0].ArcCost
+	for pos := 1; pos < n; pos++ {
+		from, to := order[pos-1], order[pos]
+		t := p.transitions[from][to]
+		t.FromTrackID = inputs[from].Track.ID
+		t.ToTrackID = inputs[to].Track.ID
+		t.ArcCost = p.arcCost[to][pos]
+		t.Cost += t.ArcCost
+		result.Transitions = append(result.Transitions, t)
+		result.TotalCost += t.Cost
+	}
+	return result
+}
+
+// sequenceProblem holds the precomputed costs of one Sequence call.
+type sequenceProblem struct {
+	n           int
+	energy      []float64
+	known       []bool                // Whether energy is known
+	targets     []float64             // Target energy per position; nil without an arc
+	transitions [][]domain.Transition // Costs without arc, by from and to track
+	arcCost     [][]float64           // Weighted arc cost, by track and position
+}
+
+func (s *Sequencer) newProblem(inputs []domain.SequenceInput, arc domain.SequenceArc) *sequenceProblem {
+	n := len(inputs)
+	p := &sequenceProblem{n: n, energy: make([]float64, n), known: make([]bool, n)}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, in := range inputs {
+		if e, ok := trackEnergy(in.Features); ok {
+			p.energy[i], p.known[i] = e, true
+			lo, hi = math.Min(lo, e), math.Max(hi, e)
+		}
+	}
+
+	// Targets span the energies of the set, so a calm set still gets a curve
+	if len(arc.Points) > 0 && !math.IsInf(lo, 1) {
+		p.targets = make([]float64, n)
+		for pos := range p.targets {
+			x := 0.0
+			if n > 1 {
+				x = float64(pos) / float64(n-1)
+			}
+			p.targets[pos] = lo + (hi-lo)*interpolateArc(arc.Points, x)
+		}
+	}
+
+	p.arcCost = make([][]float64, n)
+	p.transitions = make([][]domain.Transition, n)
+	for i := range inputs {
+		p.arcCost[i] = make([]float64, n)
+		if p.targets != nil && p.known[i] {
+			for pos, target := range p.targets {
+				p.arcCost[i][pos] = s.weights.Arc * math.Abs(p.energy[i]-target) / sequenceArcTolerance
+			}
+		}
+		p.transitions[i] = make([]domain.Transition, n)
+		for j := range inputs {
+			if i != j {
+				p.transitions[i][j] = s.transition(inputs[i].Features, inputs[j].Features)
+			}
+		}
+	}
+	return p
+}
+
+// transition returns the BPM and gain costs of moving from a to b.
+func (s *Sequencer) transition(a, b *domain.TrackFeatures) domain.Transition {
+	t := domain.Transition{BPMCost: unknownFeatureCost, GainCost: unknownFeatureCost}
+	if a != nil && b != nil && a.BPM > 0 && b.BPM > 0 {
+		t.BPMCost, t.HalfTime = bpmTransitionCost(a.BPM, b.BPM)
+	}
+	if a != nil && b != nil && a.Gain != 0 && b.Gain != 0 {
+		t.GainCost = math.Abs(a.Gain-b.Gain) / sequenceGainTolerance
+	}
+	t.Cost = s.weights.BPM*t.BPMCost + s.weights.Gain*t.GainCost
+	return t
+}
+
+// bpmTransitionCost compares tempos octave-aware: 87 BPM mixes into 174 BPM at half time.
+func bpmTransitionCost(a, b float64) (cost float64, halfTime bool) {
+	best := math.Abs(math.Log(a / b))
+	for _, c := range []float64{b * 2, b / 2} {
+		if d := math.Abs(math.Log(a / c)); d < best {
+			best, halfTime = d, true
+		}
+	}
+	cost = best / math.Log(1+sequenceBPMTolerance)
+	if halfTime {
+		cost += halfTimePenalty
+	}
+	return cost, halfTime
+}
+
+// trackEnergy estimates a track's energy (0-1) from its tempo and loudness.
+// ReplayGain is negative for loud tracks, so a lower gain means more energy.
+func trackEnergy(f *domain.TrackFeatures) (float64, bool) {
+	if f == nil {
+		return 0, false
+	}
+	var sum, weight float64
+	if f.BPM > 0 {
+		sum += 0.6 * clamp01((f.BPM-70)/110) // 70-180 BPM
+		weight += 0.6
+	}
+	if f.Gain != 0 {
+		sum += 0.4 * clamp01((-2-f.Gain)/12) // -2 to -14 dB
+		weight += 0.4
+	}
+	if weight == 0 {
+		return 0, false
+	}
+	return sum / weight, true
+}
+
+// interpolateArc returns the arc's energy at x (0-1), the points being spread evenly.
+func interpolateArc(points []float64, x float64) float64 {
+	if len(points) == 1 {
+		return points[0]
+	}
+	pos := x * float64(len(points)-1)
+	i := min(int(pos), len(points)-2)
+	return points[i] + (points[i+1]-points[i])*(pos-float64(i))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// cost returns the total cost of order.
+func (p *sequenceProblem) cost(order []int) float64 {
+	total := p.arcCost[order[0]][0]
+	for pos := 1; pos < len(order); pos++ {
+		total += p.transitions[order[pos-1]][order[pos]].Cost + p.arcCost[order[pos]][pos]
+	}
+	return total
+}
+
+// greedy builds an order by repeatedly appending the cheapest next track.
+// Without a first track it starts from the one closest to the arc's start,
+// or without an arc from the calmest one.
+func (p *sequenceProblem) greedy(first int) []int {
+	used := make([]bool, p.n)
+	if first < 0 {
+		first = 0
+		for i := 1; i < p.n; i++ {
+			if p.startsBefore(i, first) {
+				first = i
+			}
+		}
+	}
+	order := []int{first}
+	used[first] = true
+
+	for pos := 1; pos < p.n; pos++ {
+		last, next, best := order[pos-1], -1, math.Inf(1)
+		for j := range used {
+			if used[j] {
+				continue
+			}
+			if c := p.transitions[last][j].Cost + p.arcCost[j][pos]; c < best {
+				next, best = j, c
+			}
+		}
+		order = append(order, next)
+		used[next] = true
+	}
+	return order
+}
+
+// startsBefore reports whether track i makes a better opening than track j.
+func (p *sequenceProblem) startsBefore(i, j int) bool {
+	if p.targets != nil {
+		return p.arcCost[i][0] < p.arcCost[j][0]
+	}
+	return p.known[i] && (!p.known[j] || p.energy[i] < p.energy[j])
+}
+
+// improve moves single tracks and reverses runs of tracks while that lowers the cost.
+func (p *sequenceProblem) improve(order []int, fixedFirst bool) {
+	start := 0
+	if fixedFirst {
+		start = 1
+	}
+	best := p.cost(order)
+	for pass := 0; pass < maxSequencePasses; pass++ {
+		improved := false
+
+		for i := start; i < p.n-1; i++ {
+			for j := i + 1; j < p.n; j++ {
+				reverse(order[i : j+1])
+				if c := p.cost(order); c < best-1e-9 {
+					best, improved = c, true
+				} else {
+					reverse(order[i : j+1])
+				}
+			}
+		}
+
+		for i := start; i < p.n; i++ {
+			for j := start; j < p.n; j++ {
+				if i == j {
+					continue
+				}
+				move(order, i, j)
+				if c := p.cost(order); c < best-1e-9 {
+					best, improved = c, true
+				} else {
+					move(order, j, i)
+				}
+			}
+		}
+
+		if !improved {
+			return
+		}
+	}
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// move moves s[from] to index to, shifting the elements in between.
+func move(s []int, from, to int) {
+	v := s[from]
+	if from < to {
+		copy(s[from:to], s[from+1:to+1])
+	} else {
+		copy(s[to+1:from+1], s[to:from])
+	}
+	s[to] = v
+}
@@ -0,0 +1,159 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+// sequenceInputs returns one track per BPM, named by its BPM, all equally loud.
+func sequenceInputs(bpms ...float64) []domain.SequenceInput {
+	inputs := make([]domain.SequenceInput, len(bpms))
+	for i, bpm := range bpms {
+		id := fmt.Sprint(bpm)
+		inputs[i] = domain.SequenceInput{
+			Track:    domain.Track{ID: id},
+			Features: &domain.TrackFeatures{TrackID: id, BPM: bpm, Gain: -8},
+		}
+	}
+	return inputs
+}
+
+func sequenceOrder(seq *domain.Sequence) []string {
+	ids := make([]string, len(seq.Tracks))
+	for i, t := range seq.Tracks {
+		ids[i] = t.Track.ID
+	}
+	return ids
+}
+
+func TestSequencer_Sequence(t *testing.T) {
+	arc := func(name string) domain.SequenceArc {
+		a, err := domain.ParseSequenceArc(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		inputs []domain.SequenceInput
+		opts   domain.SequenceOptions
+		want   []string
+	}{
+		{
+			name:   "正常系: 曲線なしは落ち着いた曲から滑らかに",
+			inputs: sequenceInputs(128, 90, 120, 100, 110),
+			opts:   domain.SequenceOptions{Arc: arc("smooth")},
+			want:   []string{"90", "100", "110", "120", "128"},
+		},
+		{
+			name:   "正常系: warm_upは盛り上がっていく",
+			inputs: sequenceInputs(128, 90, 120, 100, 110),
+			opts:   domain.SequenceOptions{Arc: arc("warm_up")},
+			want:   []string{"90", "100", "110", "120", "128"},
+		},
+		{
+			name:   "正常系: cool_downは落ち着いていく",
+			inputs: sequenceInputs(128, 90, 120, 100, 110),
+			opts:   domain.SequenceOptions{Arc: arc("cool_down")},
+			want:   []string{"128", "120", "110", "100", "90"},
+		},
+		{
+			name:   "正常系: 最初の曲を固定",
+			inputs: sequenceInputs(90, 128, 120, 100, 110),
+			opts:   domain.SequenceOptions{Arc: arc("smooth"), FirstTrackID: "128"},
+			want:   []string{"128", "120", "110", "100", "90"},
+		},
+	}
+
+	s := NewSequencer(DefaultSequencerWeights())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq := s.Sequence(tt.inputs, tt.opts)
+			if got := sequenceOrder(seq); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected order %v, got %v", tt.want, got)
+			}
+			if len(seq.Transitions) != len(tt.inputs)-1 {
+				t.Fatalf("expected %d transitions, got %d", len(tt.inputs)-1, len(seq.Transitions))
+			}
+
+			total := seq.Tracks[0].ArcCost
+			for i, tr := range seq.Transitions {
+				if tr.FromTrackID != seq.Tracks[i].Track.ID || tr.ToTrackID != seq.Tracks[i+1].Track.ID {
+					t.Errorf("transition %d: unexpected tracks %s -> %s", i, tr.FromTrackID, tr.ToTrackID)
+				}
+				total += tr.Cost
+			}
+			if math.Abs(total-seq.TotalCost) > 1e-9 {
+				t.Errorf("total cost %f is not the sum of its transitions %f", seq.TotalCost, total)
+			}
+			for _, track := range seq.Tracks {
+				if tt.inputs[track.Index].Track.ID != track.Track.ID {
+					t.Errorf("index %d does not point to %s", track.Index, track.Track.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSequencer_Sequence_Peak(t *testing.T) {
+	seq := NewSequencer(DefaultSequencerWeights()).Sequence(
+		sequenceInputs(90, 100, 110, 120, 130, 140, 150),
+		domain.SequenceOptions{Arc: domain.SequenceArc{Name: domain.SequenceArcPeak, Points: []float64{0, 1, 1, 0}}},
+	)
+
+	first, last := *seq.Tracks[0].Energy, *seq.Tracks[len(seq.Tracks)-1].Energy
+	middle := *seq.Tracks[len(seq.Tracks)/2].Energy
+	if middle <= first || middle <= last {
+		t.Errorf("expected the peak in the middle, got %v", sequenceOrder(seq))
+	}
+	if seq.Tracks[0].TargetEnergy == nil || *seq.Tracks[3].TargetEnergy <= *seq.Tracks[0].TargetEnergy {
+		t.Errorf("unexpected target energies: %v / %v", seq.Tracks[0].TargetEnergy, seq.Tracks[3].TargetEnergy)
+	}
+}
+
+func TestSequencer_Sequence_UnknownFeatures(t *testing.T) {
+	inputs := append(sequenceInputs(120), domain.SequenceInput{Track: domain.Track{ID: "unknown"}})
+	seq := NewSequencer(DefaultSequencerWeights()).Sequence(inputs, domain.SequenceOptions{})
+
+	if len(seq.Tracks) != 2 || len(seq.Transitions) != 1 {
+		t.Fatalf("expected both tracks to be sequenced, got %v", sequenceOrder(seq))
+	}
+	if seq.Tracks[1].Track.ID != "unknown" || seq.Tracks[1].Energy != nil {
+		t.Errorf("expected the unknown track last without energy, got %+v", seq.Tracks[1])
+	}
+	if tr := seq.Transitions[0]; tr.BPMCost != unknownFeatureCost || tr.GainCost != unknownFeatureCost {
+		t.Errorf("expected neutral costs, got %+v", tr)
+	}
+
+	if empty := NewSequencer(DefaultSequencerWeights()).Sequence(nil, domain.SequenceOptions{}); len(empty.Tracks) != 0 {
+		t.Errorf("expected an empty sequence, got %+v", empty)
+	}
+}
+
+func TestBPMTransitionCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         float64
+		wantHalfTime bool
+		wantCost     float64
+	}{
+		{name: "正常系: 同じテンポ", a: 120, b: 120, wantCost: 0},
+		{name: "正常系: 倍のテンポはハーフタイム", a: 87, b: 174, wantHalfTime: true, wantCost: halfTimePenalty},
+		{name: "正常系: 半分のテンポはハーフタイム", a: 174, b: 87, wantHalfTime: true, wantCost: halfTimePenalty},
+		{name: "正常系: ピッチ範囲の変化", a: 100, b: 106, wantCost: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, halfTime := bpmTransitionCost(tt.a, tt.b)
+			if halfTime != tt.wantHalfTime || math.Abs(cost-tt.wantCost) > 1e-9 {
+				t.Errorf("bpmTransitionCost(%v, %v) = %v, %v; want %v, %v", tt.a, tt.b, cost, halfTime, tt.wantCost, tt.wantHalfTime)
+			}
+		})
+	}
+}
@@ -72,40 +72,64 @@ func (c *SimilarityCalculator) Calculate(seed, candidate *domain.TrackFeatures)
 
 	var totalWeight float64
 	var weightedSum float64
+	for _, comp := range c.Components(seed, candidate) {
+		weightedSum += comp.Weight * comp.Similarity
+		totalWeight += comp.Weight
+	}
+
+	if totalWeight == 0 {
+		return 0.5 // Neutral score when no features available
+	}
+
+	return weightedSum / totalWeight
+}
+
+// Components returns the similarity and weight of each feature both tracks have,
+// in the order BPM, duration, gain and tags. Calculate is their weighted mean.
+func (c *SimilarityCalculator) Components(seed, candidate *domain.TrackFeatures) []domain.SimilarityComponent {
+	if seed == nil || candidate == nil {
+		return nil
+	}
+
+	components := make([]domain.SimilarityComponent, 0, 4)
 
 	// BPM similarity
 	if seed.BPM > 0 && candidate.BPM > 0 {
-		bpmSim := c.bpmSimilarity(seed.BPM, candidate.BPM)
-		weightedSum += c.weights.BPM * bpmSim
-		totalWeight += c.weights.BPM
+		components = append(components, domain.SimilarityComponent{
+			Name:       domain.ComponentBPM,
+			Similarity: c.bpmSimilarity(seed.BPM, candidate.BPM),
+			Weight:     c.weights.BPM,
+		})
 	}
 
 	// Duration similarity
 	if seed.DurationSeconds > 0 && candidate.DurationSeconds > 0 {
-		durSim := c.durationSimilarity(seed.DurationSeconds, candidate.DurationSeconds)
-		weightedSum += c.weights.Duration * durSim
-		totalWeight += c.weights.Duration
+		components = append(components, domain.SimilarityComponent{
+			Name:       domain.ComponentDuration,
+			Similarity: c.durationSimilarity(seed.DurationSeconds, candidate.DurationSeconds),
+			Weight:     c.weights.Duration,
+		})
 	}
 
 	// Gain similarity
 	if seed.Gain != 0 || candidate.Gain != 0 {
-		gainSim := c.gainSimilarity(seed.Gain, candidate.Gain)
-		weightedSum += c.weights.Gain * gainSim
-		totalWeight += c.weights.Gain
+		components = append(components, domain.SimilarityComponent{
+			Name:       domain.ComponentGain,
+			Similarity: c.gainSimilarity(seed.Gain, candidate.Gain),
+			Weight:     c.weights.Gain,
+		})
 	}
 
 	// Tag similarity (Jaccard coefficient)
 	if len(seed.Tags) > 0 || len(candidate.Tags) > 0 {
-		tagSim := c.tagSimilarity(seed.Tags, candidate.Tags)
-		weightedSum += c.weights.TagSimilarity * tagSim
-		totalWeight += c.weights.TagSimilarity
+		components = append(components, domain.SimilarityComponent{
+			Name:       domain.ComponentTags,
+			Similarity: c.tagSimilarity(seed.Tags, candidate.Tags),
+			Weight:     c.weights.TagSimilarity,
+		})
 	}
 
-	if totalWeight == 0 {
-		return 0.5 // Neutral score when no features available
-	}
-
-	return weightedSum / totalWeight
+	return components
 }
 
 // CalculateWithBonus computes the final score including genre and artist bonuses.
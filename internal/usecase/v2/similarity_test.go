@@ -315,6 +315,63 @@ func TestSimilarityCalculator_MatchReasons(t *testing.T) {
 	}
 }
 
+func TestSimilarityCalculator_Components(t *testing.T) {
+	calc := NewSimilarityCalculator(DefaultWeights(), nil)
+
+	tests := []struct {
+		name      string
+		seed      *domain.TrackFeatures
+		candidate *domain.TrackFeatures
+		wantNames []string
+	}{
+		{
+			name:      "nil features",
+			seed:      nil,
+			candidate: &domain.TrackFeatures{BPM: 120},
+			wantNames: nil,
+		},
+		{
+			name:      "all features",
+			seed:      &domain.TrackFeatures{BPM: 175, DurationSeconds: 245, Gain: -7.2, Tags: []string{"anime"}},
+			candidate: &domain.TrackFeatures{BPM: 180, DurationSeconds: 250, Gain: -6.0, Tags: []string{"anime", "rock"}},
+			wantNames: []string{domain.ComponentBPM, domain.ComponentDuration, domain.ComponentGain, domain.ComponentTags},
+		},
+		{
+			name:      "missing BPM on one side",
+			seed:      &domain.TrackFeatures{BPM: 175, DurationSeconds: 245},
+			candidate: &domain.TrackFeatures{DurationSeconds: 250},
+			wantNames: []string{domain.ComponentDuration},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calc.Components(tt.seed, tt.candidate)
+			var names []string
+			var weightedSum, totalWeight float64
+			for _, c := range got {
+				names = append(names, c.Name)
+				weightedSum += c.Weight * c.Similarity
+				totalWeight += c.Weight
+			}
+			if len(names) != len(tt.wantNames) {
+				t.Fatalf("Components() = %v, want %v", names, tt.wantNames)
+			}
+			for i := range names {
+				if names[i] != tt.wantNames[i] {
+					t.Errorf("Components()[%d] = %s, want %s", i, names[i], tt.wantNames[i])
+				}
+			}
+			// Calculate is the weighted mean of the components
+			if totalWeight > 0 {
+				if want := calc.Calculate(tt.seed, tt.candidate); math.Abs(weightedSum/totalWeight-want) > 1e-9 {
+					t.Errorf("weighted mean %f, Calculate() = %f", weightedSum/totalWeight, want)
+				}
+			}
+		})
+	}
+}
+
 func TestWeightsForMode(t *testing.T) {
 	tests := []struct {
 		mode     domain.RecommendMode
@@ -0,0 +1,384 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+	"github.com/t1nyb0x/tracktaste/internal/port/external"
+	"github.com/t1nyb0x/tracktaste/internal/port/repository"
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+const (
+	// MaxFeaturesBatchSize is the most tracks one batch lookup accepts.
+	MaxFeaturesBatchSize = 100
+	// featuresSpotifyConcurrency bounds concurrent Spotify track lookups of a batch.
+	featuresSpotifyConcurrency = 10
+	// spotifyArtistBatchSize is the most artists one Spotify genres call accepts.
+	spotifyArtistBatchSize = 50
+)
+
+// FeaturesUseCase returns the merged Deezer, MusicBrainz and Spotify genre features of tracks.
+// It shares the feature store with RecommendUseCase.
+type FeaturesUseCase struct {
+	spotifyAPI     external.SpotifyAPI
+	deezerAPI      external.DeezerAPI
+	musicBrainzAPI external.MusicBrainzAPI
+	featureStore   repository.FeatureStore // Optional: can be nil
+	featureWorker  *FeatureWorker          // Optional: can be nil
+
+	mu     sync.Mutex // Guards policy and budget
+	policy StalenessPolicy
+	budget StageBudget
+}
+
+// NewFeaturesUseCase creates a new FeaturesUseCase.
+func NewFeaturesUseCase(
+	spotifyAPI external.SpotifyAPI,
+	deezerAPI external.DeezerAPI,
+	musicBrainzAPI external.MusicBrainzAPI,
+) *FeaturesUseCase {
+	return &FeaturesUseCase{
+		spotifyAPI:     spotifyAPI,
+		deezerAPI:      deezerAPI,
+		musicBrainzAPI: musicBrainzAPI,
+		policy:         DefaultStalenessPolicy(),
+		budget:         DefaultStageBudget(),
+	}
+}
+
+// WithFeatureStore reads stored features before calling upstreams and writes fetched ones back.
+// If worker is non-nil, tracks whose MusicBrainz lookup did not finish are enqueued for it.
+func (uc *FeaturesUseCase) WithFeatureStore(store repository.FeatureStore, worker *FeatureWorker) *FeaturesUseCase {
+	uc.featureStore = store
+	uc.featureWorker = worker
+	return uc
+}
+
+// WithPolicy replaces the staleness policy of stored features.
+func (uc *FeaturesUseCase) WithPolicy(policy StalenessPolicy) *FeaturesUseCase {
+	uc.SetPolicy(policy)
+	return uc
+}
+
+// SetPolicy replaces the staleness policy at runtime.
+func (uc *FeaturesUseCase) SetPolicy(policy StalenessPolicy) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.policy = policy
+}
+
+// WithBudget replaces the stage budget. Deezer, MusicBrainz and Spotify genre lookups
+// run as the enrich stage; they may use the time the collect and enrich shares allow.
+func (uc *FeaturesUseCase) WithBudget(budget StageBudget) *FeaturesUseCase {
+	uc.SetBudget(budget)
+	return uc
+}
+
+// SetBudget replaces the stage budget at runtime.
+func (uc *FeaturesUseCase) SetBudget(budget StageBudget) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.budget = budget
+}
+
+// FetchFeatures returns the features of one Spotify track.
+func (uc *FeaturesUseCase) FetchFeatures(ctx context.Context, trackID string) (*domain.TrackFeatureSet, error) {
+	if trackID == "" {
+		return nil, domain.ErrTrackNotFound
+	}
+	sets, err := uc.FetchFeaturesBatch(ctx, []string{trackID})
+	if err != nil {
+		return nil, err
+	}
+	if sets[0].Err != nil {
+		return nil, sets[0].Err
+	}
+	return &sets[0], nil
+}
+
+// FetchFeaturesBatch returns the features of up to MaxFeaturesBatchSize Spotify tracks,
+// in the order of trackIDs. A track that cannot be looked up gets its error in Err
+// instead of failing the batch.
+func (uc *FeaturesUseCase) FetchFeaturesBatch(ctx context.Context, trackIDs []string) ([]domain.TrackFeatureSet, error) {
+	if len(trackIDs) > MaxFeaturesBatchSize {
+		return nil, domain.NewTooManyTracksError(MaxFeaturesBatchSize)
+	}
+	sets := make([]domain.TrackFeatureSet, len(trackIDs))
+	uc.fetchTracks(ctx, trackIDs, sets)
+	uc.enrichSets(ctx, sets)
+	logger.InfoContext(ctx, "TrackFeatures", fmt.Sprintf("%d 曲の特徴量を取得", len(trackIDs)))
+	return sets, nil
+}
+
+// FeaturesOf returns the features of tracks that were already looked up, such as the
+// tracks of a playlist, in the same order. Unlike FetchFeaturesBatch it does not limit
+// the number of tracks; callers bound it themselves.
+func (uc *FeaturesUseCase) FeaturesOf(ctx context.Context, tracks []domain.Track) []domain.TrackFeatureSet {
+	sets := make([]domain.TrackFeatureSet, len(tracks))
+	for i := range tracks {
+		sets[i].Track = &tracks[i]
+	}
+	uc.enrichSets(ctx, sets)
+	logger.InfoContext(ctx, "TrackFeatures", fmt.Sprintf("%d 曲の特徴量を取得", len(tracks)))
+	return sets
+}
+
+// enrichSets fills in the features of the looked-up tracks of sets.
+func (uc *FeaturesUseCase) enrichSets(ctx context.Context, sets []domain.TrackFeatureSet) {
+	uc.mu.Lock()
+	policy, budget := uc.policy, uc.budget
+	uc.mu.Unlock()
+
+	// Features are stored per ISRC, genres per track's primary artist
+	var isrcs, artistIDs []string
+	seenISRC := make(map[string]bool)
+	seenArtist := make(map[string]bool)
+	for _, set := range sets {
+		if set.Track == nil {
+			continue
+		}
+		if isrc := trackISRC(set.Track); isrc != "" && !seenISRC[isrc] {
+			seenISRC[isrc] = true
+			isrcs = append(isrcs, isrc)
+		}
+		if len(set.Track.Artists) > 0 && !seenArtist[set.Track.Artists[0].ID] {
+			seenArtist[set.Track.Artists[0].ID] = true
+			artistIDs = append(artistIDs, set.Track.Artists[0].ID)
+		}
+	}
+
+	stored := loadStoredFeatures(ctx, uc.featureStore, "TrackFeatures", isrcs)
+	plan := newStagePlan(ctx, budget, time.Now())
+	groups := uc.enrich(ctx, plan, policy, isrcs, artistIDs, sets, stored)
+
+	for i := range sets {
+		if sets[i].Track != nil {
+			groups.fill(&sets[i])
+		}
+	}
+}
+
+// fetchTracks looks up each Spotify track into sets.
+func (uc *FeaturesUseCase) fetchTracks(ctx context.Context, trackIDs []string, sets []domain.TrackFeatureSet) {
+	sem := make(chan struct{}, featuresSpotifyConcurrency)
+	g := safego.NewGroup("TrackFeatures")
+	for i, id := range trackIDs {
+		sets[i].Err = domain.ErrTrackNotFound // Until the lookup succeeds, also if it panics
+		if id == "" {
+			continue
+		}
+		g.Go("Spotify track", func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			track, err := uc.spotifyAPI.GetTrackByID(ctx, id)
+			switch {
+			case err != nil:
+				sets[i].Err = err
+			case track != nil:
+				sets[i].Track = track
+				sets[i].Err = nil
+			}
+		})
+	}
+	g.Wait()
+}
+
+// featureGroups holds the feature groups of a batch, fresh from the store or just fetched.
+type featureGroups struct {
+	deezer      map[string]*domain.DeezerFeatureGroup      // By ISRC
+	musicBrainz map[string]*domain.MusicBrainzFeatureGroup // By ISRC
+	genres      map[string][]string                        // By Spotify artist ID
+}
+
+// enrich returns the feature groups of isrcs and artistIDs.
+// Fresh stored groups are reused; the rest are fetched with the batch APIs and saved.
+func (uc *FeaturesUseCase) enrich(
+	ctx context.Context,
+	plan *stagePlan,
+	policy StalenessPolicy,
+	isrcs, artistIDs []string,
+	sets []domain.TrackFeatureSet,
+	stored map[string]*domain.StoredFeatures,
+) *featureGroups {
+	groups := &featureGroups{
+		deezer:      make(map[string]*domain.DeezerFeatureGroup),
+		musicBrainz: make(map[string]*domain.MusicBrainzFeatureGroup),
+		genres:      make(map[string][]string),
+	}
+	now := time.Now()
+
+	var staleDeezer, staleMB []string
+	for _, isrc := range isrcs {
+		sf := stored[isrc]
+		if policy.IsStale(domain.FeatureSourceDeezer, deezerMeta(sf), now) {
+			staleDeezer = append(staleDeezer, isrc)
+		} else {
+			groups.deezer[isrc] = sf.Deezer
+		}
+		if policy.IsStale(domain.FeatureSourceMusicBrainz, musicBrainzMeta(sf), now) {
+			staleMB = append(staleMB, isrc)
+		} else {
+			groups.musicBrainz[isrc] = sf.MusicBrainz
+		}
+	}
+
+	// Genres are stored with the features of the tracks by the artist
+	artistISRC := make(map[string]string)
+	for _, set := range sets {
+		if set.Track == nil || len(set.Track.Artists) == 0 {
+			continue
+		}
+		artistID := set.Track.Artists[0].ID
+		isrc := trackISRC(set.Track)
+		if _, ok := groups.genres[artistID]; ok || isrc == "" {
+			continue
+		}
+		if sf := stored[isrc]; !policy.IsStale(domain.FeatureSourceSpotifyGenres, spotifyGenresMeta(sf), now) {
+			groups.genres[artistID] = sf.SpotifyGenres.Genres
+		} else if _, ok := artistISRC[artistID]; !ok {
+			artistISRC[artistID] = isrc
+		}
+	}
+	var staleArtists []string
+	for _, id := range artistIDs {
+		if _, ok := groups.genres[id]; !ok {
+			staleArtists = append(staleArtists, id)
+		}
+	}
+
+	enrichCtx, cancel := plan.bound(ctx, domain.StageEnrich)
+	defer cancel()
+
+	fetched := make(map[string]*domain.StoredFeatures)
+	fetchedFor := func(isrc string) *domain.StoredFeatures {
+		if fetched[isrc] == nil {
+			fetched[isrc] = &domain.StoredFeatures{ISRC: isrc}
+		}
+		return fetched[isrc]
+	}
+	var mu sync.Mutex
+	g := safego.NewGroup("TrackFeatures")
+
+	if len(staleDeezer) > 0 {
+		g.Go("Deezer", func() {
+			tracks, err := uc.deezerAPI.GetTracksByISRCBatch(enrichCtx, staleDeezer)
+			if err != nil {
+				logger.WarningContext(ctx, "TrackFeatures", "Deezerバッチ取得エラー: "+err.Error())
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for isrc, dt := range tracks {
+				group := newDeezerGroup(dt, now)
+				groups.deezer[isrc] = group
+				fetchedFor(isrc).Deezer = group
+			}
+		})
+	}
+
+	if len(staleMB) > 0 {
+		g.Go("MusicBrainz", func() {
+			recordings, err := uc.musicBrainzAPI.GetRecordingsByISRCBatch(enrichCtx, staleMB)
+			if err != nil {
+				logger.WarningContext(ctx, "TrackFeatures", "MusicBrainzバッチ取得エラー: "+err.Error())
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for isrc, rec := range recordings {
+				group := newMusicBrainzGroup(rec, now)
+				groups.musicBrainz[isrc] = group
+				fetchedFor(isrc).MusicBrainz = group
+			}
+		})
+	}
+
+	for start := 0; start < len(staleArtists); start += spotifyArtistBatchSize {
+		chunk := staleArtists[start:min(start+spotifyArtistBatchSize, len(staleArtists))]
+		g.Go("Spotify genres", func() {
+			genres, err := uc.spotifyAPI.GetArtistGenresBatch(enrichCtx, chunk)
+			if err != nil {
+				logger.WarningContext(ctx, "TrackFeatures", "Spotifyジャンル取得エラー: "+err.Error())
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for artistID, artistGenres := range genres {
+				groups.genres[artistID] = artistGenres
+				if isrc := artistISRC[artistID]; isrc != "" {
+					fetchedFor(isrc).SpotifyGenres = newSpotifyGenresGroup(artistGenres, now)
+				}
+			}
+		})
+	}
+
+	g.Wait()
+
+	for _, features := range fetched {
+		saveStoredFeatures(ctx, uc.featureStore, "TrackFeatures", features)
+	}
+
+	// MusicBrainz allows 1 req/s, so large batches rarely finish in time
+	if uc.featureWorker != nil {
+		var missing []string
+		for _, isrc := range staleMB {
+			if _, ok := groups.musicBrainz[isrc]; !ok {
+				missing = append(missing, isrc)
+			}
+		}
+		uc.featureWorker.Enqueue(missing...)
+	}
+	return groups
+}
+
+// fill sets the features, genres and sources of a looked-up track.
+func (g *featureGroups) fill(set *domain.TrackFeatureSet) {
+	isrc := trackISRC(set.Track)
+	set.Features = &domain.TrackFeatures{TrackID: set.Track.ID, ISRC: isrc}
+	set.Sources = make(map[string]domain.FeatureSource)
+
+	if dz := g.deezer[isrc]; dz != nil && dz.Found {
+		set.Features.BPM = dz.BPM
+		set.Features.DurationSeconds = dz.DurationSeconds
+		set.Features.Gain = dz.Gain
+		if dz.BPM > 0 {
+			set.Sources["bpm"] = domain.FeatureSourceDeezer
+		}
+		if dz.DurationSeconds > 0 {
+			set.Sources["duration_seconds"] = domain.FeatureSourceDeezer
+		}
+		if dz.Gain != 0 {
+			set.Sources["gain"] = domain.FeatureSourceDeezer
+		}
+	}
+	if mb := g.musicBrainz[isrc]; mb != nil && mb.Found {
+		set.Features.Tags = append([]string(nil), mb.Tags...)
+		set.Features.ArtistMBID = mb.ArtistMBID
+		if len(mb.Tags) > 0 {
+			set.Sources["tags"] = domain.FeatureSourceMusicBrainz
+		}
+		if mb.ArtistMBID != "" {
+			set.Sources["artist_mbid"] = domain.FeatureSourceMusicBrainz
+		}
+	}
+	if len(set.Track.Artists) > 0 {
+		if genres := g.genres[set.Track.Artists[0].ID]; len(genres) > 0 {
+			set.Genres = append([]string(nil), genres...)
+			set.Sources["genres"] = domain.FeatureSourceSpotifyGenres
+		}
+	}
+}
+
+// trackISRC returns the ISRC of track, or "".
+func trackISRC(track *domain.Track) string {
+	if track.ISRC == nil {
+		return ""
+	}
+	return *track.ISRC
+}
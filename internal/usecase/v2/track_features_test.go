@@ -0,0 +1,176 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/domain"
+)
+
+func featuresTrack(id, isrc, artistID string) *domain.Track {
+	track := &domain.Track{ID: id, Name: "Track " + id, Artists: []domain.Artist{{ID: artistID, Name: "Artist"}}}
+	if isrc != "" {
+		track.ISRC = &isrc
+	}
+	return track
+}
+
+func TestFeaturesUseCase_FetchFeatures(t *testing.T) {
+	spotify := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			"t1": featuresTrack("t1", "JPU901800200", "a1"),
+			"t2": featuresTrack("t2", "", "a1"),
+		},
+		artists: map[string][]string{"a1": {"j-pop"}},
+	}
+	deezer := &mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		"JPU901800200": {ID: 1, BPM: 87, DurationSeconds: 256, Gain: -7.5},
+	}}
+	mb := &mockMusicBrainzAPI{recordings: map[string]*domain.MBRecording{
+		"JPU901800200": {MBID: "rec1", ArtistMBID: "art1", Tags: []domain.MBTag{{Name: "pop", Count: 3}}},
+	}}
+
+	tests := []struct {
+		name        string
+		trackID     string
+		wantFeature domain.TrackFeatures
+		wantGenres  []string
+		wantSources map[string]domain.FeatureSource
+		wantErr     error
+	}{
+		{
+			name:    "正常系: 全ソースの特徴量を統合",
+			trackID: "t1",
+			wantFeature: domain.TrackFeatures{
+				TrackID: "t1", ISRC: "JPU901800200", BPM: 87, DurationSeconds: 256, Gain: -7.5,
+				Tags: []string{"pop"}, ArtistMBID: "art1",
+			},
+			wantGenres: []string{"j-pop"},
+			wantSources: map[string]domain.FeatureSource{
+				"bpm":              domain.FeatureSourceDeezer,
+				"duration_seconds": domain.FeatureSourceDeezer,
+				"gain":             domain.FeatureSourceDeezer,
+				"tags":             domain.FeatureSourceMusicBrainz,
+				"artist_mbid":      domain.FeatureSourceMusicBrainz,
+				"genres":           domain.FeatureSourceSpotifyGenres,
+			},
+		},
+		{
+			name:        "正常系: ISRCなしはジャンルのみ",
+			trackID:     "t2",
+			wantFeature: domain.TrackFeatures{TrackID: "t2"},
+			wantGenres:  []string{"j-pop"},
+			wantSources: map[string]domain.FeatureSource{"genres": domain.FeatureSourceSpotifyGenres},
+		},
+		{
+			name:    "異常系: 存在しない曲",
+			trackID: "missing",
+			wantErr: domain.ErrTrackNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewFeaturesUseCase(spotify, deezer, mb)
+			got, err := uc.FetchFeatures(context.Background(), tt.trackID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FetchFeatures() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchFeatures() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(*got.Features, tt.wantFeature) {
+				t.Errorf("Features = %+v, want %+v", *got.Features, tt.wantFeature)
+			}
+			if !reflect.DeepEqual(got.Genres, tt.wantGenres) {
+				t.Errorf("Genres = %v, want %v", got.Genres, tt.wantGenres)
+			}
+			if !reflect.DeepEqual(got.Sources, tt.wantSources) {
+				t.Errorf("Sources = %v, want %v", got.Sources, tt.wantSources)
+			}
+		})
+	}
+}
+
+// countingDeezerAPI records the ISRCs asked in batch calls.
+type countingDeezerAPI struct {
+	mockDeezerAPI
+	asked []string
+}
+
+func (m *countingDeezerAPI) GetTracksByISRCBatch(ctx context.Context, isrcs []string) (map[string]*domain.DeezerTrack, error) {
+	m.asked = append(m.asked, isrcs...)
+	return m.mockDeezerAPI.GetTracksByISRCBatch(ctx, isrcs)
+}
+
+func TestFeaturesUseCase_FetchFeaturesBatch(t *testing.T) {
+	spotify := &mockSpotifyAPI{
+		tracks: map[string]*domain.Track{
+			"t1": featuresTrack("t1", "ISRC00000001", "a1"),
+			"t2": featuresTrack("t2", "ISRC00000002", "a2"),
+		},
+		artists: map[string][]string{"a1": {"rock"}},
+	}
+	deezer := &countingDeezerAPI{mockDeezerAPI: mockDeezerAPI{tracks: map[string]*domain.DeezerTrack{
+		"ISRC00000001": {ID: 1, BPM: 120},
+		"ISRC00000002": {ID: 2, BPM: 140},
+	}}}
+	store := newMockFeatureStore()
+	_ = store.SaveFeatures(context.Background(), &domain.StoredFeatures{
+		ISRC:   "ISRC00000002",
+		Deezer: newDeezerGroup(&domain.DeezerTrack{BPM: 99}, time.Now()),
+	})
+
+	uc := NewFeaturesUseCase(spotify, deezer, &mockMusicBrainzAPI{}).WithFeatureStore(store, nil)
+	got, err := uc.FetchFeaturesBatch(context.Background(), []string{"t1", "missing", "t2"})
+	if err != nil {
+		t.Fatalf("FetchFeaturesBatch() unexpected error = %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3", len(got))
+	}
+	if got[0].Track.ID != "t1" || got[0].Features.BPM != 120 {
+		t.Errorf("got[0] = %+v, want t1 with BPM 120", got[0].Features)
+	}
+	if !errors.Is(got[1].Err, domain.ErrTrackNotFound) {
+		t.Errorf("got[1].Err = %v, want %v", got[1].Err, domain.ErrTrackNotFound)
+	}
+	// Fresh stored features are used without asking Deezer
+	if got[2].Features.BPM != 99 {
+		t.Errorf("got[2].BPM = %v, want stored 99", got[2].Features.BPM)
+	}
+	if !reflect.DeepEqual(deezer.asked, []string{"ISRC00000001"}) {
+		t.Errorf("Deezer asked for %v, want [ISRC00000001]", deezer.asked)
+	}
+
+	// Fetched groups are saved for the next lookup
+	stored, _ := store.GetFeatures(context.Background(), "ISRC00000001")
+	if stored == nil || stored.Deezer == nil || stored.Deezer.BPM != 120 {
+		t.Errorf("stored Deezer = %+v, want BPM 120", stored)
+	}
+	if stored == nil || stored.SpotifyGenres == nil || !reflect.DeepEqual(stored.SpotifyGenres.Genres, []string{"rock"}) {
+		t.Errorf("stored genres = %+v, want [rock]", stored)
+	}
+}
+
+func TestFeaturesUseCase_FetchFeaturesBatch_TooMany(t *testing.T) {
+	ids := make([]string, MaxFeaturesBatchSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("t%d", i)
+	}
+
+	uc := NewFeaturesUseCase(&mockSpotifyAPI{}, &mockDeezerAPI{}, &mockMusicBrainzAPI{})
+	_, err := uc.FetchFeaturesBatch(context.Background(), ids)
+	var e *domain.Error
+	if !errors.As(err, &e) || e.Kind != domain.KindInvalidInput || e.Code != "TOO_MANY_TRACKS" {
+		t.Errorf("FetchFeaturesBatch() error = %v, want TOO_MANY_TRACKS", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package health actively probes the dependencies of the application
+// (upstream APIs, the sidecar and Redis) and keeps the latest result of each,
+// for the readiness and detailed health endpoints.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/safego"
+)
+
+const (
+	defaultInterval         = 30 * time.Second
+	defaultTimeout          = 5 * time.Second
+	defaultFailureThreshold = 3
+)
+
+// Check is one dependency to probe.
+type Check struct {
+	Name     string // Service name, e.g. "spotify"
+	Critical bool   // The application is not ready while a critical check keeps failing
+	Ping     func(ctx context.Context) error
+}
+
+// Status is the latest probe result of one dependency.
+// The zero times mean "never".
+type Status struct {
+	Name        string
+	Critical    bool
+	Checked     bool          // At least one probe has finished
+	Healthy     bool          // The last probe succeeded
+	Failures    int           // Probes failed in a row since the last success
+	Latency     time.Duration // Of the last probe
+	LastError   string
+	LastErrorAt time.Time
+	LastSuccess time.Time
+	LastChecked time.Time
+}
+
+// Prober probes every check periodically, in parallel.
+type Prober struct {
+	checks           []Check
+	interval         time.Duration
+	timeout          time.Duration
+	failureThreshold int
+
+	mu     sync.RWMutex
+	status map[string]Status
+}
+
+// NewProber creates a prober. interval is the time between probe rounds and
+// timeout bounds a single probe; zero means the default (30s and 5s).
+func NewProber(checks []Check, interval, timeout time.Duration) *Prober {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	status := make(map[string]Status, len(checks))
+	for _, c := range checks {
+		status[c.Name] = Status{Name: c.Name, Critical: c.Critical}
+	}
+	return &Prober{checks: checks, interval: interval, timeout: timeout, failureThreshold: defaultFailureThreshold, status: status}
+}
+
+// WithFailureThreshold sets how many probes of a critical check must fail in a
+// row before the application is not ready, so that a short outage of a shared
+// upstream does not take every replica out of rotation at once. Zero means the default (3).
+func (p *Prober) WithFailureThreshold(n int) *Prober {
+	if n <= 0 {
+		n = defaultFailureThreshold
+	}
+	p.failureThreshold = n
+	return p
+}
+
+// Run probes every check right away and then once per interval, until ctx is done.
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.ProbeAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeAll runs one probe round and waits for it to finish.
+func (p *Prober) ProbeAll(ctx context.Context) {
+	g := safego.NewGroup("Health")
+	for _, c := range p.checks {
+		g.Go(c.Name, func() {
+			p.probe(ctx, c)
+		})
+	}
+	g.Wait()
+}
+
+// probe runs one check and records its result. A panicking check counts as failed.
+func (p *Prober) probe(ctx context.Context, c Check) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := safego.Run("Health", c.Name, func() error { return c.Ping(ctx) })
+	latency := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.status[c.Name]
+	wasHealthy := s.Healthy || !s.Checked
+	now := time.Now()
+	s.Checked = true
+	s.Latency = latency
+	s.LastChecked = now
+	if err != nil {
+		s.Healthy = false
+		s.Failures++
+		s.LastError = err.Error()
+		s.LastErrorAt = now
+		if wasHealthy {
+			logger.WarningContext(ctx, "Health", fmt.Sprintf("%s probe failed: %v", c.Name, err))
+		}
+	} else {
+		s.Healthy = true
+		s.Failures = 0
+		s.LastSuccess = now
+		if !wasHealthy {
+			logger.InfoContext(ctx, "Health", fmt.Sprintf("%s recovered", c.Name))
+		}
+	}
+	p.status[c.Name] = s
+}
+
+// Statuses returns the latest result of every check, in check order.
+func (p *Prober) Statuses() []Status {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result := make([]Status, 0, len(p.checks))
+	for _, c := range p.checks {
+		result = append(result, p.status[c.Name])
+	}
+	return result
+}
+
+// Failing returns the critical checks that keep the application from being ready:
+// those that have never succeeded and those that failed the threshold of probes in a row.
+func (p *Prober) Failing() []string {
+	var failing []string
+	for _, s := range p.Statuses() {
+		if s.Critical && (s.LastSuccess.IsZero() || s.Failures >= p.failureThreshold) {
+			failing = append(failing, s.Name)
+		}
+	}
+	return failing
+}
+
+// Ready reports whether no critical check is failing.
+func (p *Prober) Ready() bool {
+	return len(p.Failing()) == 0
+}
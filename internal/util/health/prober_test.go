@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProber_ProbeAll(t *testing.T) {
+	boom := errors.New("token endpoint returned 401")
+
+	tests := []struct {
+		name        string
+		checks      []Check
+		wantReady   bool
+		wantHealthy map[string]bool
+	}{
+		{
+			name: "正常系: 全サービス正常",
+			checks: []Check{
+				{Name: "spotify", Critical: true, Ping: func(context.Context) error { return nil }},
+				{Name: "redis", Ping: func(context.Context) error { return nil }},
+			},
+			wantReady:   true,
+			wantHealthy: map[string]bool{"spotify": true, "redis": true},
+		},
+		{
+			name: "正常系: 任意サービスの失敗ではreadyのまま",
+			checks: []Check{
+				{Name: "spotify", Critical: true, Ping: func(context.Context) error { return nil }},
+				{Name: "redis", Ping: func(context.Context) error { return boom }},
+			},
+			wantReady:   true,
+			wantHealthy: map[string]bool{"spotify": true, "redis": false},
+		},
+		{
+			name: "異常系: 必須サービスの失敗",
+			checks: []Check{
+				{Name: "spotify", Critical: true, Ping: func(context.Context) error { return boom }},
+			},
+			wantReady:   false,
+			wantHealthy: map[string]bool{"spotify": false},
+		},
+		{
+			name: "異常系: panicは失敗として扱う",
+			checks: []Check{
+				{Name: "kkbox", Critical: true, Ping: func(context.Context) error { panic("nil gateway") }},
+			},
+			wantReady:   false,
+			wantHealthy: map[string]bool{"kkbox": false},
+		},
+		{
+			name: "異常系: タイムアウト",
+			checks: []Check{
+				{Name: "kkbox", Critical: true, Ping: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}},
+			},
+			wantReady:   false,
+			wantHealthy: map[string]bool{"kkbox": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProber(tt.checks, time.Hour, 20*time.Millisecond)
+			p.ProbeAll(context.Background())
+
+			if got := p.Ready(); got != tt.wantReady {
+				t.Errorf("Ready() = %v, want %v", got, tt.wantReady)
+			}
+			for _, s := range p.Statuses() {
+				if !s.Checked || s.LastChecked.IsZero() {
+					t.Errorf("%s: not marked as checked", s.Name)
+				}
+				if s.Healthy != tt.wantHealthy[s.Name] {
+					t.Errorf("%s: Healthy = %v, want %v", s.Name, s.Healthy, tt.wantHealthy[s.Name])
+				}
+				if s.Healthy && s.LastSuccess.IsZero() {
+					t.Errorf("%s: LastSuccess not set", s.Name)
+				}
+				if !s.Healthy && (s.LastError == "" || s.LastErrorAt.IsZero()) {
+					t.Errorf("%s: LastError not set", s.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestProber_NotReadyBeforeFirstProbe(t *testing.T) {
+	p := NewProber([]Check{{Name: "spotify", Critical: true, Ping: func(context.Context) error { return nil }}}, 0, 0)
+	if p.Ready() {
+		t.Error("expected not ready before the first probe")
+	}
+}
+
+func TestProber_KeepsLastSuccessAfterFailure(t *testing.T) {
+	var fail bool
+	p := NewProber([]Check{{Name: "deezer", Ping: func(context.Context) error {
+		if fail {
+			return errors.New("status 503")
+		}
+		return nil
+	}}}, time.Hour, time.Second)
+
+	p.ProbeAll(context.Background())
+	fail = true
+	p.ProbeAll(context.Background())
+
+	s := p.Statuses()[0]
+	if s.Healthy {
+		t.Error("expected unhealthy after the failed probe")
+	}
+	if s.LastSuccess.IsZero() || s.LastError != "status 503" {
+		t.Errorf("unexpected status %+v", s)
+	}
+}
+
+func TestProber_Run(t *testing.T) {
+	probed := make(chan struct{}, 10)
+	p := NewProber([]Check{{Name: "redis", Ping: func(context.Context) error {
+		probed <- struct{}{}
+		return nil
+	}}}, 10*time.Millisecond, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-probed:
+		case <-time.After(time.Second):
+			t.Fatal("expected periodic probes")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop when the context was cancelled")
+	}
+}
+
+func TestProber_FailureThreshold(t *testing.T) {
+	var fail bool
+	p := NewProber([]Check{{Name: "spotify", Critical: true, Ping: func(context.Context) error {
+		if fail {
+			return errors.New("token endpoint returned 503")
+		}
+		return nil
+	}}}, time.Hour, time.Second).WithFailureThreshold(2)
+
+	p.ProbeAll(context.Background())
+	fail = true
+
+	// One failure after a success keeps the replica in rotation
+	p.ProbeAll(context.Background())
+	if !p.Ready() || p.Statuses()[0].Healthy {
+		t.Errorf("expected ready but unhealthy after one failure, got %+v", p.Statuses()[0])
+	}
+
+	p.ProbeAll(context.Background())
+	if p.Ready() || len(p.Failing()) != 1 || p.Statuses()[0].Failures != 2 {
+		t.Errorf("expected not ready after two failures, got %+v", p.Statuses()[0])
+	}
+
+	fail = false
+	p.ProbeAll(context.Background())
+	if !p.Ready() || p.Statuses()[0].Failures != 0 {
+		t.Errorf("expected ready after recovering, got %+v", p.Statuses()[0])
+	}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Attribute keys set from request context.
+const (
+	RequestIDKey = "request_id"
+	SeedTrackKey = "seed_track"
+	UpstreamKey  = "upstream"
+)
+
+type attrsKey struct{}
+
+// WithAttrs returns a context whose log records carry attrs.
+// An attribute replaces one with the same key already in ctx.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := attrsFrom(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, a := range existing {
+		if !hasKey(attrs, a.Key) {
+			merged = append(merged, a)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// WithRequestID attaches the incoming request's ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return WithAttrs(ctx, slog.String(RequestIDKey, id))
+}
+
+// WithSeedTrack attaches the Spotify ID of the track a request is about.
+func WithSeedTrack(ctx context.Context, trackID string) context.Context {
+	return WithAttrs(ctx, slog.String(SeedTrackKey, trackID))
+}
+
+// WithUpstream attaches the name of the upstream API being called.
+func WithUpstream(ctx context.Context, name string) context.Context {
+	return WithAttrs(ctx, slog.String(UpstreamKey, name))
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+func hasKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// contextHandler adds the attributes stored in the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// legacyHandler writes "[LEVEL] YYYY-MM-DD HH:mm:ss [feature] message",
+// followed by any other attributes as " key=value".
+type legacyHandler struct {
+	out   io.Writer
+	level slog.Level
+	mu    *sync.Mutex
+
+	attrs  []slog.Attr
+	prefix string // Group prefix of later attributes
+}
+
+func newLegacyHandler(out io.Writer, level slog.Level) *legacyHandler {
+	return &legacyHandler{out: out, level: level, mu: &sync.Mutex{}}
+}
+
+func (h *legacyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *legacyHandler) Handle(_ context.Context, r slog.Record) error {
+	feature := ""
+	var rest bytes.Buffer
+	appendAttr := func(a slog.Attr) {
+		if a.Key == FeatureKey && feature == "" {
+			feature = a.Value.String()
+			return
+		}
+		fmt.Fprintf(&rest, " %s=%s", a.Key, a.Value)
+	}
+	for _, a := range h.attrs {
+		appendAttr(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		a.Key = h.prefix + a.Key
+		appendAttr(a)
+		return true
+	})
+
+	line := fmt.Sprintf("[%s] %s [%s] %s%s\n",
+		levelOf(r.Level), r.Time.Format("2006-01-02 15:04:05"), feature, r.Message, rest.String())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, line)
+	return err
+}
+
+func (h *legacyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		c.attrs = append(c.attrs, a)
+	}
+	return &c
+}
+
+func (h *legacyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.prefix = h.prefix + name + "."
+	return &c
+}
@@ -1,10 +1,23 @@
 // Package logger provides logging utilities for the TrackTaste application.
-// Log format: [LEVEL] YYYY-MM-DD HH:mm:ss [Feature] Message
+//
+// It is built on log/slog. The default "legacy" format keeps the original
+// line layout:
+//
+//	[LEVEL] YYYY-MM-DD HH:mm:ss [Feature] Message
+//
+// The "text" and "json" formats use slog's own handlers. Attributes stored in
+// a context with WithRequestID, WithSeedTrack, WithUpstream or WithAttrs are
+// added to every record logged through one of the *Context functions.
 package logger
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
 )
 
 // Level represents log severity levels.
@@ -24,11 +37,153 @@ const (
 	LevelDebug Level = "DEBUG"
 )
 
+// slogLevelFatal is the slog level of LevelFatal.
+const slogLevelFatal = slog.LevelError + 4
+
+// slogLevel returns the slog level of l.
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelFatal:
+		return slogLevelFatal
+	case LevelError:
+		return slog.LevelError
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelDebug:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// levelOf returns the Level name of a slog level.
+func levelOf(l slog.Level) Level {
+	switch {
+	case l >= slogLevelFatal:
+		return LevelFatal
+	case l >= slog.LevelError:
+		return LevelError
+	case l >= slog.LevelWarn:
+		return LevelWarning
+	case l >= slog.LevelInfo:
+		return LevelInfo
+	default:
+		return LevelDebug
+	}
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "WARN", "WARNING":
+		return LevelWarning, nil
+	case "ERROR":
+		return LevelError, nil
+	case "FATAL":
+		return LevelFatal, nil
+	default:
+		return "", fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+// Format is the output format of log records.
+type Format string
+
+const (
+	// FormatLegacy is the original "[LEVEL] time [Feature] Message" line.
+	FormatLegacy Format = "legacy"
+	// FormatText is slog's key=value text format.
+	FormatText Format = "text"
+	// FormatJSON is slog's JSON format, one object per line.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatLegacy, FormatText, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q", s)
+	}
+}
+
+// Config configures the process-wide logger.
+type Config struct {
+	Format Format
+	Level  Level     // Records below this level are dropped
+	Output io.Writer // Defaults to os.Stdout
+}
+
+// FeatureKey is the attribute key of the feature name.
+const FeatureKey = "feature"
+
+var current atomic.Pointer[slog.Logger]
+
+func init() {
+	Configure(Config{Format: FormatLegacy, Level: LevelInfo})
+}
+
+// Configure replaces the process-wide logger.
+// An unknown format falls back to FormatLegacy.
+func Configure(cfg Config) {
+	out := cfg.Output
+	if out == nil {
+		out = stdout{}
+	}
+	opts := &slog.HandlerOptions{Level: cfg.Level.slogLevel(), ReplaceAttr: replaceLevel}
+
+	var h slog.Handler
+	switch cfg.Format {
+	case FormatText:
+		h = slog.NewTextHandler(out, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(out, opts)
+	default:
+		h = newLegacyHandler(out, opts.Level.Level())
+	}
+	current.Store(slog.New(contextHandler{h}))
+}
+
+// Slog returns the process-wide logger, e.g. for libraries that take a *slog.Logger.
+func Slog() *slog.Logger {
+	return current.Load()
+}
+
+// replaceLevel names levels as this package does (WARNING, FATAL).
+func replaceLevel(_ []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey {
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(string(levelOf(l)))
+		}
+	}
+	return a
+}
+
+// stdout writes to the current os.Stdout, so tests that swap it see the output.
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
 // Log outputs a log message with the specified level and feature name.
-// Format: [LEVEL] YYYY-MM-DD HH:mm:ss [feature] message
 func Log(level Level, feature string, message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("[%s] %s [%s] %s\n", level, timestamp, feature, message)
+	LogContext(context.Background(), level, feature, message)
+}
+
+// LogContext outputs a log message with the attributes stored in ctx.
+func LogContext(ctx context.Context, level Level, feature string, message string) {
+	l := current.Load()
+	lvl := level.slogLevel()
+	if !l.Enabled(ctx, lvl) {
+		return
+	}
+	l.LogAttrs(ctx, lvl, message, slog.String(FeatureKey, feature))
 }
 
 // Fatal outputs a fatal log message and should be followed by application termination.
@@ -55,3 +210,23 @@ func Info(feature string, message string) {
 func Debug(feature string, message string) {
 	Log(LevelDebug, feature, message)
 }
+
+// ErrorContext is Error with the attributes stored in ctx.
+func ErrorContext(ctx context.Context, feature string, message string) {
+	LogContext(ctx, LevelError, feature, message)
+}
+
+// WarningContext is Warning with the attributes stored in ctx.
+func WarningContext(ctx context.Context, feature string, message string) {
+	LogContext(ctx, LevelWarning, feature, message)
+}
+
+// InfoContext is Info with the attributes stored in ctx.
+func InfoContext(ctx context.Context, feature string, message string) {
+	LogContext(ctx, LevelInfo, feature, message)
+}
+
+// DebugContext is Debug with the attributes stored in ctx.
+func DebugContext(ctx context.Context, feature string, message string) {
+	LogContext(ctx, LevelDebug, feature, message)
+}
@@ -2,6 +2,8 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -163,6 +165,9 @@ func TestInfo(t *testing.T) {
 }
 
 func TestDebug(t *testing.T) {
+	Configure(Config{Format: FormatLegacy, Level: LevelDebug})
+	defer Configure(Config{Format: FormatLegacy, Level: LevelInfo})
+
 	old := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
@@ -184,6 +189,23 @@ func TestDebug(t *testing.T) {
 	}
 }
 
+func TestDebug_DroppedByDefault(t *testing.T) {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	Debug("DebugFeature", "Debug message")
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	if buf.Len() != 0 {
+		t.Errorf("expected debug logs to be dropped before Setup, got %q", buf.String())
+	}
+}
+
 func TestLogFormat(t *testing.T) {
 	old := os.Stdout
 	r, w, _ := os.Pipe()
@@ -205,3 +227,87 @@ func TestLogFormat(t *testing.T) {
 		t.Error("expected output to contain time separator")
 	}
 }
+
+func TestConfigure_JSONWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	Configure(Config{Format: FormatJSON, Level: LevelInfo, Output: &buf})
+	defer Configure(Config{Format: FormatLegacy, Level: LevelDebug})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithSeedTrack(ctx, "track-1")
+	ctx = WithUpstream(WithUpstream(ctx, "spotify"), "kkbox")
+	InfoContext(ctx, "Recommend", "started")
+	DebugContext(ctx, "Recommend", "dropped")
+	Warning("Main", "no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d: %s", len(lines), buf.String())
+	}
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"level":      "INFO",
+		"msg":        "started",
+		"feature":    "Recommend",
+		"request_id": "req-1",
+		"seed_track": "track-1",
+		"upstream":   "kkbox",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("expected %s=%s, got %v", k, v, rec[k])
+		}
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["level"] != "WARNING" {
+		t.Errorf("expected WARNING, got %v", rec["level"])
+	}
+}
+
+func TestConfigure_LegacyWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	Configure(Config{Format: FormatLegacy, Level: LevelInfo, Output: &buf})
+	defer Configure(Config{Format: FormatLegacy, Level: LevelDebug})
+
+	InfoContext(WithRequestID(context.Background(), "req-1"), "TrackFetch", "リクエスト開始")
+	Debug("TrackFetch", "dropped")
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "[INFO] ") || !strings.HasSuffix(output, " [TrackFetch] リクエスト開始 request_id=req-1\n") {
+		t.Errorf("unexpected output: %q", output)
+	}
+	if strings.Contains(output, "dropped") {
+		t.Error("expected debug record to be dropped")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{in: "debug", want: LevelDebug},
+		{in: "INFO", want: LevelInfo},
+		{in: "warn", want: LevelWarning},
+		{in: "warning", want: LevelWarning},
+		{in: "error", want: LevelError},
+		{in: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseLevel(%q) = %s, %v", tt.in, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// countBuckets are buckets for numbers of items, e.g. candidates per source.
+var countBuckets = []float64{0, 1, 5, 10, 20, 50, 100, 200}
+
+// HTTP server metrics. route is the chi route pattern, e.g. "/v1/track/fetch".
+var (
+	HTTPRequests = Default.NewCounterVec("tracktaste_http_requests_total",
+		"HTTP requests handled, by route, method and status code.", "route", "method", "status")
+	HTTPDuration = Default.NewHistogramVec("tracktaste_http_request_duration_seconds",
+		"HTTP request latency, by route and method.", DefBuckets, "route", "method")
+)
+
+// Upstream (gateway) metrics, recorded by the shared transport for every gateway.
+var (
+	UpstreamRequests = Default.NewCounterVec("tracktaste_upstream_requests_total",
+		"Upstream API calls, by upstream and status class (2xx, 4xx, 5xx, error, circuit_open).", "upstream", "status_class")
+	UpstreamDuration = Default.NewHistogramVec("tracktaste_upstream_request_duration_seconds",
+		"Upstream API call latency including retries, by upstream.", DefBuckets, "upstream")
+	UpstreamRetries = Default.NewCounterVec("tracktaste_upstream_retries_total",
+		"Upstream API retries, by upstream and reason (status code or error).", "upstream", "reason")
+	RateLimiterWait = Default.NewHistogramVec("tracktaste_rate_limiter_wait_seconds",
+		"Time spent waiting for an upstream rate limiter token, by upstream.", DefBuckets, "upstream")
+)
+
+// CacheRequests counts cache lookups. cache is "token", "features" or "links",
+// tier is "l1" (memory) or "l2" (Redis), and result is "hit" or "miss".
+var CacheRequests = Default.NewCounterVec("tracktaste_cache_requests_total",
+	"Cache lookups, by cache, tier and result.", "cache", "tier", "result")
+
+// Recommendation pipeline metrics.
+var (
+	RecommendCandidates = Default.NewHistogramVec("tracktaste_recommend_candidates",
+		"Candidates returned by each source for one recommendation, before deduplication.", countBuckets, "source")
+	RecommendGenreFiltered = Default.NewCounterVec("tracktaste_recommend_genre_filtered_total",
+		"Candidates dropped by the genre filter, by reason (genre_mismatch, no_features).", "reason")
+)
+
+// StatusClass returns the class of an HTTP status code, e.g. "2xx".
+func StatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// Since returns the seconds elapsed since start, for histogram observations.
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// CacheResult returns "hit" or "miss".
+func CacheResult(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "miss"
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Requests.", "route", "status")
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+
+	requests.Inc("/v1/track/fetch", "200")
+	requests.Add(2, "/v1/track/fetch", "200")
+	requests.Inc(`/a"b`, "503")
+	latency.Observe(0.05, "/x")
+	latency.Observe(0.1, "/x")
+	latency.Observe(3, "/x")
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %s", ct)
+	}
+	want := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{route="/a\"b",status="503"} 1
+test_requests_total{route="/v1/track/fetch",status="200"} 3
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{route="/x",le="0.1"} 2
+test_latency_seconds_bucket{route="/x",le="1"} 2
+test_latency_seconds_bucket{route="/x",le="+Inf"} 3
+test_latency_seconds_sum{route="/x"} 3.15
+test_latency_seconds_count{route="/x"} 3
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_DuplicatePanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("dup_total", "")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a duplicate metric")
+		}
+	}()
+	r.NewCounterVec("dup_total", "")
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{200: "2xx", 404: "4xx", 503: "5xx", 0: "unknown"}
+	for code, want := range tests {
+		if got := StatusClass(code); got != want {
+			t.Errorf("StatusClass(%d) = %s, want %s", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package safego runs goroutines that cannot crash the process.
+//
+// middleware.Recoverer only protects the handler goroutine. A panic in any
+// goroutine started by a handler (e.g. one per candidate source) would
+// otherwise terminate the whole server. The helpers here recover such panics,
+// log them with a stack trace, and report them as errors where the caller
+// needs to know that the work failed.
+package safego
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	Name  string      // What was running, e.g. a source name
+	Value interface{} // The value passed to panic
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Name, e.Value)
+}
+
+// Run calls fn. If fn panics, the panic is logged under feature and
+// returned as a *PanicError.
+func Run(feature, name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(feature, name, r)
+		}
+	}()
+	return fn()
+}
+
+// Go runs fn in a new goroutine. A panic is logged under feature and dropped.
+func Go(feature, name string, fn func()) {
+	go func() {
+		defer recoverAndLog(feature, name)
+		fn()
+	}()
+}
+
+// Group is a sync.WaitGroup whose goroutines recover panics.
+// The zero value is not usable; create one with NewGroup.
+type Group struct {
+	feature string
+	wg      sync.WaitGroup
+}
+
+// NewGroup creates a Group that logs panics under feature.
+func NewGroup(feature string) *Group {
+	return &Group{feature: feature}
+}
+
+// Go runs fn in a new goroutine of the group.
+// A panic is logged and ends only that goroutine.
+func (g *Group) Go(name string, fn func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer recoverAndLog(g.feature, name)
+		fn()
+	}()
+}
+
+// Wait blocks until every goroutine of the group has returned.
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
+
+// recoverAndLog recovers a panic, if any, and logs it.
+// It must be called directly by a deferred function call.
+func recoverAndLog(feature, name string) {
+	if r := recover(); r != nil {
+		recovered(feature, name, r)
+	}
+}
+
+// recovered logs a recovered panic with its stack trace.
+func recovered(feature, name string, value interface{}) *PanicError {
+	e := &PanicError{Name: name, Value: value, Stack: debug.Stack()}
+	logger.Error(feature, fmt.Sprintf("%v\n%s", e, e.Stack))
+	return e
+}
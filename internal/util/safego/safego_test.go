@@ -0,0 +1,89 @@
+package safego
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		fn        func() error
+		wantErr   error
+		wantPanic bool
+	}{
+		{
+			name: "正常系: エラーなし",
+			fn:   func() error { return nil },
+		},
+		{
+			name:    "正常系: エラーをそのまま返す",
+			fn:      func() error { return errBoom },
+			wantErr: errBoom,
+		},
+		{
+			name: "異常系: panicをPanicErrorに変換",
+			fn: func() error {
+				var m map[string]int
+				m["x"] = 1 // nil map write panics
+				return nil
+			},
+			wantPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Run("Test", "source", tt.fn)
+
+			var pe *PanicError
+			if tt.wantPanic {
+				if !errors.As(err, &pe) {
+					t.Fatalf("expected *PanicError, got %v", err)
+				}
+				if pe.Name != "source" || len(pe.Stack) == 0 {
+					t.Errorf("unexpected panic error: %+v", pe)
+				}
+				if !strings.Contains(err.Error(), "panic in source") {
+					t.Errorf("unexpected message: %s", err.Error())
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || errors.As(err, &pe) {
+				t.Errorf("Run() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	g := NewGroup("Test")
+	var done int32
+
+	for i := 0; i < 5; i++ {
+		g.Go("worker", func() {
+			if i == 2 {
+				panic("worker failed")
+			}
+			atomic.AddInt32(&done, 1)
+		})
+	}
+	g.Wait()
+
+	if got := atomic.LoadInt32(&done); got != 4 {
+		t.Errorf("expected 4 workers to finish, got %d", got)
+	}
+}
+
+func TestGo(t *testing.T) {
+	finished := make(chan struct{})
+	Go("Test", "worker", func() {
+		defer close(finished)
+		panic("worker failed")
+	})
+	<-finished
+}
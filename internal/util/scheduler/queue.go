@@ -0,0 +1,94 @@
+package scheduler
+
+import "context"
+
+// Priority is the scheduling class of a call. Higher values are served first.
+type Priority int
+
+const (
+	// PriorityBackground is for background jobs such as feature backfill.
+	PriorityBackground Priority = iota
+	// PriorityLow is for bulk work inside a request, e.g. candidate enrichment.
+	PriorityLow
+	// PriorityNormal is the default.
+	PriorityNormal
+	// PriorityHigh is for calls a response depends on directly, e.g. seed lookups.
+	PriorityHigh
+
+	numPriorities = int(PriorityHigh) + 1
+)
+
+type priorityKey struct{}
+type flowKey struct{}
+
+// WithPriority returns a context whose upstream calls are scheduled at p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority stored in ctx, or PriorityNormal.
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= 0 && int(p) < numPriorities {
+		return p
+	}
+	return PriorityNormal
+}
+
+// WithFlow returns a context whose upstream calls belong to flow id.
+// Capacity is shared round-robin between flows of the same priority;
+// typically the flow is the HTTP request ID.
+func WithFlow(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, flowKey{}, id)
+}
+
+// FlowFrom returns the flow stored in ctx, or "".
+func FlowFrom(ctx context.Context) string {
+	if id, ok := ctx.Value(flowKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// ticket is a single waiting call.
+type ticket struct {
+	flow      string
+	ready     chan struct{}
+	cancelled bool
+}
+
+// priorityQueue holds the tickets of one priority, grouped by flow.
+// Flows are served round-robin so one large request cannot starve others.
+type priorityQueue struct {
+	flows map[string][]*ticket
+	order []string // flows with waiting tickets, in service order
+}
+
+func newPriorityQueue() *priorityQueue {
+	return &priorityQueue{flows: make(map[string][]*ticket)}
+}
+
+func (q *priorityQueue) push(t *ticket) {
+	if len(q.flows[t.flow]) == 0 {
+		q.order = append(q.order, t.flow)
+	}
+	q.flows[t.flow] = append(q.flows[t.flow], t)
+}
+
+// pop returns the head ticket of the next flow and moves that flow to the back.
+func (q *priorityQueue) pop() *ticket {
+	if len(q.order) == 0 {
+		return nil
+	}
+	flow := q.order[0]
+	q.order = q.order[1:]
+
+	tickets := q.flows[flow]
+	t := tickets[0]
+	if len(tickets) == 1 {
+		delete(q.flows, flow)
+	} else {
+		q.flows[flow] = tickets[1:]
+		q.order = append(q.order, flow)
+	}
+	return t
+}
@@ -0,0 +1,356 @@
+// Package scheduler provides a process-wide request scheduler for upstream APIs.
+//
+// Each upstream gets one Scheduler shared by every gateway call. It enforces a
+// token-bucket rate limit, serves higher priority calls first, shares capacity
+// fairly between concurrent HTTP requests (flows), and collapses identical
+// in-flight calls into one.
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/t1nyb0x/tracktaste/internal/util/logger"
+	"github.com/t1nyb0x/tracktaste/internal/util/metrics"
+)
+
+// Limiter blocks until a request may proceed.
+// *rate.Limiter satisfies this interface.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateSetter is a Limiter whose rate can change at runtime.
+type RateSetter interface {
+	SetRate(rate float64, burst int)
+}
+
+// SetLimiterRate changes the rate of l, which must be a *rate.Limiter or a
+// RateSetter. It reports whether l supports the change.
+// A non-positive rate disables rate limiting, as in NewTokenBucket.
+func SetLimiterRate(l Limiter, ratePerSec float64, burst int) bool {
+	switch l := l.(type) {
+	case *rate.Limiter:
+		limit := rate.Limit(ratePerSec)
+		if ratePerSec <= 0 {
+			limit = rate.Inf
+		}
+		if burst <= 0 {
+			burst = 1
+		}
+		l.SetLimit(limit)
+		l.SetBurst(burst)
+		return true
+	case RateSetter:
+		l.SetRate(ratePerSec, burst)
+		return true
+	}
+	return false
+}
+
+// Config holds the token-bucket settings of one upstream.
+type Config struct {
+	Name  string  // Upstream name used in logs
+	Rate  float64 // Sustained requests per second
+	Burst int     // Maximum burst size
+}
+
+// Scheduler orders calls to a single upstream.
+// A nil *Scheduler is valid and performs calls immediately.
+type Scheduler struct {
+	name    string
+	limiter Limiter
+
+	mu         sync.Mutex
+	queues     [numPriorities]*priorityQueue
+	pending    int // Tickets waiting and not cancelled
+	running    bool
+	cancelWait context.CancelFunc // Stops the dispatcher's wait for a token
+	inflight   map[string]*call
+}
+
+// call is an in-flight deduplicated call.
+type call struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+	// abandoned is true when the call ended because its caller's context was done,
+	// so the callers waiting on it should try again rather than share the error.
+	abandoned bool
+}
+
+// dispatchRetryDelay is how long the dispatcher backs off when the limiter fails.
+const dispatchRetryDelay = 100 * time.Millisecond
+
+// New creates a Scheduler with a local token-bucket limiter.
+// A non-positive Rate disables rate limiting.
+func New(cfg Config) *Scheduler {
+	return NewWithLimiter(cfg.Name, NewTokenBucket(cfg))
+}
+
+// NewTokenBucket creates the local token-bucket limiter for cfg.
+func NewTokenBucket(cfg Config) *rate.Limiter {
+	limit := rate.Limit(cfg.Rate)
+	if cfg.Rate <= 0 {
+		limit = rate.Inf
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return rate.NewLimiter(limit, burst)
+}
+
+// NewWithLimiter creates a Scheduler that takes tokens from limiter.
+func NewWithLimiter(name string, limiter Limiter) *Scheduler {
+	s := &Scheduler{
+		name:     name,
+		limiter:  limiter,
+		inflight: make(map[string]*call),
+	}
+	for i := range s.queues {
+		s.queues[i] = newPriorityQueue()
+	}
+	return s
+}
+
+// Name returns the upstream name.
+func (s *Scheduler) Name() string {
+	if s == nil {
+		return ""
+	}
+	return s.name
+}
+
+// SetRate changes the rate limit. Calls already waiting for a token are
+// granted at the new rate. It reports whether the limiter supports the change.
+func (s *Scheduler) SetRate(ratePerSec float64, burst int) bool {
+	if s == nil {
+		return false
+	}
+	return SetLimiterRate(s.limiter, ratePerSec, burst)
+}
+
+// Acquire blocks until the caller may send one request.
+// The priority and flow are read from ctx (see WithPriority and WithFlow).
+func (s *Scheduler) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	t := &ticket{flow: FlowFrom(ctx), ready: make(chan struct{})}
+
+	s.mu.Lock()
+	s.queues[PriorityFrom(ctx)].push(t)
+	s.pending++
+	if !s.running {
+		s.running = true
+		go s.dispatch()
+	}
+	s.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-t.ready:
+		metrics.RateLimiterWait.Observe(metrics.Since(start), s.name)
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-t.ready:
+			// Granted while we were cancelled; the token is simply unused
+		default:
+			t.cancelled = true
+			s.pending--
+			if s.pending == 0 && s.cancelWait != nil {
+				// Nobody is left to use the token being waited for
+				s.cancelWait()
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// Do runs fn once a token is granted.
+// If key is non-empty and an identical call is already in flight, Do waits for
+// that call and returns its result instead of calling fn again.
+// fn runs with the context of the caller that started it; if that caller is
+// cancelled, the callers waiting on the call run fn again themselves.
+func (s *Scheduler) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if s == nil {
+		return fn(ctx)
+	}
+	if key == "" {
+		if err := s.Acquire(ctx); err != nil {
+			return nil, err
+		}
+		return fn(ctx)
+	}
+
+	for {
+		s.mu.Lock()
+		c, ok := s.inflight[key]
+		if !ok {
+			break // Keeps s.mu locked for the new call
+		}
+		s.mu.Unlock()
+		select {
+		case <-c.done:
+			if !c.abandoned {
+				return c.val, c.err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	s.inflight[key] = c
+	s.mu.Unlock()
+
+	// Release the waiting callers even if fn panics; the panic is passed on
+	defer func() {
+		r := recover()
+		if r != nil {
+			c.val, c.err, c.abandoned = nil, fmt.Errorf("scheduler: %s call panicked: %v", s.name, r), false
+		}
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		close(c.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	if err := s.Acquire(ctx); err != nil {
+		c.err = err
+	} else {
+		c.val, c.err = fn(ctx)
+	}
+	c.abandoned = c.err != nil && ctx.Err() != nil
+
+	return c.val, c.err
+}
+
+// bufferedResponse is a response whose body has been read into memory,
+// so it can be handed to every caller of a deduplicated request.
+type bufferedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// DoRequest sends req through the scheduler using client.
+// GET requests for the same URL are deduplicated. The response body is
+// buffered, so callers still close it as usual.
+// A nil scheduler calls client.Do directly.
+func (s *Scheduler) DoRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	if s == nil {
+		return client.Do(req)
+	}
+
+	key := ""
+	if req.Method == http.MethodGet {
+		key = req.Method + " " + req.URL.String()
+	}
+
+	val, err := s.Do(req.Context(), key, func(ctx context.Context) (interface{}, error) {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &bufferedResponse{resp: resp, body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	br := val.(*bufferedResponse)
+	resp := *br.resp
+	resp.Header = br.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(br.body))
+	return &resp, nil
+}
+
+// dispatch hands out tokens to queued tickets until the queues are empty.
+func (s *Scheduler) dispatch() {
+	for {
+		s.mu.Lock()
+		if s.pending == 0 {
+			s.clearLocked()
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		// Take the token first, then pick the best waiter at that moment,
+		// so a high priority call arriving meanwhile is served first.
+		// The wait is cancelled when every waiter gives up, so no token is
+		// taken for abandoned calls.
+		ctx, cancel := context.WithCancel(context.Background())
+		s.mu.Lock()
+		s.cancelWait = cancel
+		s.mu.Unlock()
+		err := s.limiter.Wait(ctx)
+
+		s.mu.Lock()
+		s.cancelWait = nil
+		cancel()
+		if err != nil {
+			s.mu.Unlock()
+			if ctx.Err() == nil {
+				// The waiters keep their place and their own deadlines
+				logger.Warning("Scheduler", fmt.Sprintf("%s: rate limiter error: %v", s.name, err))
+				time.Sleep(dispatchRetryDelay)
+			}
+			continue
+		}
+		if t := s.popLocked(); t != nil {
+			close(t.ready)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// popLocked removes the next ticket to serve, dropping cancelled ones.
+// Callers must hold s.mu.
+func (s *Scheduler) popLocked() *ticket {
+	for p := numPriorities - 1; p >= 0; p-- {
+		q := s.queues[p]
+		for {
+			t := q.pop()
+			if t == nil {
+				break
+			}
+			if !t.cancelled {
+				s.pending--
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// clearLocked drops the cancelled tickets left once no ticket is waiting.
+// Callers must hold s.mu.
+func (s *Scheduler) clearLocked() {
+	for i := range s.queues {
+		s.queues[i] = newPriorityQueue()
+	}
+}
@@ -0,0 +1,375 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// manualLimiter grants one token per value sent on tokens.
+type manualLimiter struct {
+	tokens    chan struct{}
+	cancelled chan struct{} // Receives a value whenever a Wait is cancelled
+}
+
+func newManualLimiter() *manualLimiter {
+	return &manualLimiter{tokens: make(chan struct{}), cancelled: make(chan struct{}, 10)}
+}
+
+func (l *manualLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		l.cancelled <- struct{}{}
+		return ctx.Err()
+	}
+}
+
+// queued waits until n tickets are waiting in s.
+func queued(t *testing.T, s *Scheduler, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		pending := s.pending
+		s.mu.Unlock()
+		if pending == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued tickets", n)
+}
+
+func TestScheduler_Nil(t *testing.T) {
+	var s *Scheduler
+	if err := s.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() error = %v", err)
+	}
+	got, err := s.Do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || got != "ok" {
+		t.Errorf("Do() = %v, %v", got, err)
+	}
+}
+
+func TestScheduler_PriorityAndFairness(t *testing.T) {
+	limiter := newManualLimiter()
+	s := NewWithLimiter("test", limiter)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	start := func(name string, p Priority, flow string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithFlow(WithPriority(context.Background(), p), flow)
+			if err := s.Acquire(ctx); err != nil {
+				t.Errorf("Acquire(%s) error = %v", name, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}()
+	}
+
+	// Flow A queues three low priority calls before flow B queues one.
+	start("a1", PriorityLow, "A")
+	queued(t, s, 1)
+	start("a2", PriorityLow, "A")
+	queued(t, s, 2)
+	start("a3", PriorityLow, "A")
+	queued(t, s, 3)
+	start("b1", PriorityLow, "B")
+	queued(t, s, 4)
+	start("seed", PriorityHigh, "C")
+	queued(t, s, 5)
+
+	for i := 0; i < 5; i++ {
+		limiter.tokens <- struct{}{}
+		// Let the granted goroutine record itself before the next token
+		deadline := time.Now().Add(time.Second)
+		for {
+			mu.Lock()
+			n := len(order)
+			mu.Unlock()
+			if n == i+1 || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wg.Wait()
+
+	want := []string{"seed", "a1", "b1", "a2", "a3"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestScheduler_AcquireCancelled(t *testing.T) {
+	limiter := newManualLimiter()
+	s := NewWithLimiter("test", limiter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Acquire(ctx) }()
+	queued(t, s, 1)
+	cancel()
+
+	if err := <-errCh; err != context.Canceled {
+		t.Errorf("Acquire() error = %v, want context.Canceled", err)
+	}
+
+	// No token is taken for the cancelled ticket
+	select {
+	case <-limiter.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the wait for a token was not cancelled")
+	}
+	queued(t, s, 0)
+
+	// The cancelled ticket must not receive the next token
+	done := make(chan struct{})
+	go func() {
+		_ = s.Acquire(context.Background())
+		close(done)
+	}()
+	queued(t, s, 1)
+	limiter.tokens <- struct{}{}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("live ticket was not served")
+	}
+}
+
+func TestScheduler_DoDedup(t *testing.T) {
+	s := New(Config{Name: "test", Rate: 1000, Burst: 10})
+
+	var calls int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "result", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = s.Do(context.Background(), "same", fn)
+		}(i)
+	}
+
+	// Wait until the leader is running and the followers have joined
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fn called %d times, want 1", got)
+	}
+	for i, r := range results {
+		if r != "result" {
+			t.Errorf("results[%d] = %v, want result", i, r)
+		}
+	}
+}
+
+func TestScheduler_DoLeaderCancelled(t *testing.T) {
+	s := New(Config{Name: "test", Rate: 1000, Burst: 10})
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := s.Do(leaderCtx, "same", func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	followerResult := make(chan interface{}, 1)
+	go func() {
+		val, err := s.Do(context.Background(), "same", func(ctx context.Context) (interface{}, error) {
+			return "retried", nil
+		})
+		if err != nil {
+			val = err
+		}
+		followerResult <- val
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	select {
+	case got := <-followerResult:
+		if got != "retried" {
+			t.Errorf("follower got %v, want retried", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("follower did not finish")
+	}
+}
+
+func TestScheduler_DoPanic(t *testing.T) {
+	s := New(Config{Name: "test", Rate: 1000, Burst: 10})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	recovered := make(chan interface{}, 1)
+	go func() {
+		defer func() { recovered <- recover() }()
+		_, _ = s.Do(context.Background(), "same", func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := s.Do(context.Background(), "same", func(ctx context.Context) (interface{}, error) {
+			return "unexpected", nil
+		})
+		followerErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if r := <-recovered; r != "boom" {
+		t.Errorf("leader panic = %v, want boom", r)
+	}
+	select {
+	case err := <-followerErr:
+		if err == nil {
+			t.Error("follower should get the panic as an error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("follower did not finish")
+	}
+
+	// The key is free again, so a later call runs fn
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	val, err := s.Do(ctx, "same", func(ctx context.Context) (interface{}, error) {
+		return "again", nil
+	})
+	if err != nil || val != "again" {
+		t.Errorf("Do() after panic = %v, %v, want again", val, err)
+	}
+}
+
+func TestScheduler_DoRequest(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("X-Test", "1")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	s := New(Config{Name: "test", Rate: 1000, Burst: 10})
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+
+	resp, err := s.DoRequest(srv.Client(), req)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello" || resp.Header.Get("X-Test") != "1" {
+		t.Errorf("DoRequest() body = %q, header = %q", body, resp.Header.Get("X-Test"))
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+}
+
+func TestPriorityFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Priority
+	}{
+		{name: "未設定", ctx: context.Background(), want: PriorityNormal},
+		{name: "High", ctx: WithPriority(context.Background(), PriorityHigh), want: PriorityHigh},
+		{name: "Background", ctx: WithPriority(context.Background(), PriorityBackground), want: PriorityBackground},
+		{name: "範囲外", ctx: WithPriority(context.Background(), Priority(99)), want: PriorityNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PriorityFrom(tt.ctx); got != tt.want {
+				t.Errorf("PriorityFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_SetRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		sched     *Scheduler
+		rate      float64
+		burst     int
+		wantOK    bool
+		wantLimit rate.Limit
+		wantBurst int
+	}{
+		{name: "トークンバケット", sched: New(Config{Name: "deezer", Rate: 10, Burst: 50}), rate: 5, burst: 10, wantOK: true, wantLimit: 5, wantBurst: 10},
+		{name: "無制限にする", sched: New(Config{Name: "deezer", Rate: 10, Burst: 50}), rate: 0, burst: 0, wantOK: true, wantLimit: rate.Inf, wantBurst: 1},
+		{name: "変更できないリミッター", sched: NewWithLimiter("test", newManualLimiter()), rate: 5, burst: 10, wantOK: false},
+		{name: "nil", sched: nil, rate: 5, burst: 10, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sched.SetRate(tt.rate, tt.burst); got != tt.wantOK {
+				t.Fatalf("SetRate() = %v, want %v", got, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			l := tt.sched.limiter.(*rate.Limiter)
+			if l.Limit() != tt.wantLimit || l.Burst() != tt.wantBurst {
+				t.Errorf("limiter = %v/%d, want %v/%d", l.Limit(), l.Burst(), tt.wantLimit, tt.wantBurst)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup_NoEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	_, span := Start(context.Background(), "noop")
+	defer span.End()
+	if span.SpanContext().IsValid() {
+		t.Error("expected a no-op span without an endpoint")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+		wantEvents int
+	}{
+		{name: "正常系: エラーなし", err: nil, wantStatus: codes.Unset, wantEvents: 0},
+		{name: "異常系: エラーを記録", err: errors.New("boom"), wantStatus: codes.Error, wantEvents: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, restore := SetupInMemory()
+			defer restore()
+
+			_, span := Start(context.Background(), "op")
+			End(span, tt.err)
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			if spans[0].Status.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", spans[0].Status.Code, tt.wantStatus)
+			}
+			if len(spans[0].Events) != tt.wantEvents {
+				t.Errorf("events = %d, want %d", len(spans[0].Events), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	exporter, restore := SetupInMemory()
+	defer restore()
+
+	ctx, parent := Start(context.Background(), "client")
+	header := http.Header{}
+	Inject(ctx, propagation.HeaderCarrier(header))
+	parent.End()
+	if header.Get("traceparent") == "" {
+		t.Fatal("expected a traceparent header")
+	}
+
+	_, child := Start(Extract(context.Background(), propagation.HeaderCarrier(header)), "server")
+	child.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[1].Parent.SpanID() != spans[0].SpanContext.SpanID() {
+		t.Error("extracted span is not a child of the injected one")
+	}
+}
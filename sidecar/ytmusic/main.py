@@ -159,6 +159,55 @@ async def search_tracks(
         raise HTTPException(status_code=500, detail=f"Search failed: {str(e)}")
 
 
+@app.get("/track/{video_id}", response_model=Track)
+async def get_track(video_id: str):
+    """
+    Get a single track by video ID.
+
+    Used to resolve shared YouTube Music links to their title and artist.
+    """
+    if ytmusic is None:
+        raise HTTPException(status_code=503, detail="YTMusic client not initialized")
+
+    try:
+        logger.info(f"Getting track for video_id={video_id}")
+        song = ytmusic.get_song(videoId=video_id)
+    except Exception as e:
+        logger.error(f"Error getting track {video_id}: {e}")
+        raise HTTPException(status_code=500, detail=f"Failed to get track: {str(e)}")
+
+    track = _parse_song(song)
+    if track is None:
+        raise HTTPException(status_code=404, detail=f"Track not found: {video_id}")
+    return track
+
+
+def _parse_song(song: dict) -> Optional[Track]:
+    """Parse track data from get_song response."""
+    try:
+        details = (song or {}).get("videoDetails") or {}
+        video_id = details.get("videoId")
+        title = details.get("title")
+
+        if not video_id or not title:
+            return None
+
+        length = details.get("lengthSeconds")
+        thumbnails = (details.get("thumbnail") or {}).get("thumbnails", [])
+
+        return Track(
+            video_id=video_id,
+            title=title,
+            artist=details.get("author") or "Unknown",
+            artist_id=details.get("channelId"),
+            duration_seconds=int(length) if length else None,
+            thumbnail_url=thumbnails[-1].get("url") if thumbnails else None,
+        )
+    except Exception as e:
+        logger.warning(f"Failed to parse song: {e}")
+        return None
+
+
 def _parse_track(track_data: dict) -> Optional[Track]:
     """Parse track data from watch playlist response."""
     try: